@@ -1,10 +1,12 @@
 package client
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/webapi/model"
 	"github.com/iotaledger/wasp/packages/webapi/routes"
 )
 
@@ -16,3 +18,23 @@ func (c *WaspClient) CallView(contractID coretypes.ContractID, fname string, arg
 	}
 	return res, nil
 }
+
+// CallViewAt calls a view function of a given contract on the past state of the chain after the given block
+func (c *WaspClient) CallViewAt(contractID coretypes.ContractID, fname string, arguments dict.Dict, blockIndex uint32) (dict.Dict, error) {
+	var res dict.Dict
+	route := fmt.Sprintf("%s?blockIndex=%d", routes.CallView(contractID.Base58(), fname), blockIndex)
+	if err := c.do(http.MethodGet, route, arguments, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// CallViewWithState calls a view function of a given contract on the solid state of the chain,
+// and returns the result together with the index and hash of the state
+func (c *WaspClient) CallViewWithState(contractID coretypes.ContractID, fname string, arguments dict.Dict) (*model.ViewResult, error) {
+	res := &model.ViewResult{}
+	if err := c.do(http.MethodGet, routes.CallViewWithState(contractID.Base58(), fname), arguments, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,23 @@
+package chainclient
+
+import (
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/vm/contract"
+	"github.com/iotaledger/wasp/packages/vm/core/root"
+)
+
+// GetContractABI fetches the ABI of the contract from the 'root' contract. Returns nil if the ABI is not known
+func (c *Client) GetContractABI(contractHname coretypes.Hname) (*contract.ABI, error) {
+	ret, err := c.CallView(root.Interface.Hname(), root.FuncGetContractABI, codec.MakeDict(map[string]interface{}{
+		root.ParamHname: contractHname,
+	}))
+	if err != nil {
+		return nil, err
+	}
+	data := ret.MustGet(root.ParamData)
+	if data == nil {
+		return nil, nil
+	}
+	return contract.ABIFromBytes(data)
+}
@@ -9,3 +9,8 @@ import (
 func (c *Client) CallView(contractHname coretypes.Hname, fname string, arguments dict.Dict) (dict.Dict, error) {
 	return c.WaspClient.CallView(coretypes.NewContractID(c.ChainID, contractHname), fname, arguments)
 }
+
+// CallViewAt calls a view function of a given contract on the past state of the chain after the given block
+func (c *Client) CallViewAt(contractHname coretypes.Hname, fname string, arguments dict.Dict, blockIndex uint32) (dict.Dict, error) {
+	return c.WaspClient.CallViewAt(coretypes.NewContractID(c.ChainID, contractHname), fname, arguments, blockIndex)
+}
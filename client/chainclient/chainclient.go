@@ -1,6 +1,8 @@
 package chainclient
 
 import (
+	"fmt"
+
 	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/kv/dict"
 
@@ -40,6 +42,11 @@ type PostRequestParams struct {
 	Mint     map[address.Address]int64 // TODO
 	Transfer map[balance.Color]int64
 	Args     dict.Dict
+	GasLimit uint32
+	// Sealed makes the Args readable only by the committee of the chain
+	Sealed bool
+	// OffTangle uploads the Args to the node and only commits the hash of Args in the request transaction
+	OffTangle bool
 }
 
 // PostRequest sends a request transaction to the chain
@@ -53,6 +60,36 @@ func (c *Client) PostRequest(
 		par = params[0]
 	}
 
+	// the request is sent to the current address of the chain, which differs from the chain ID
+	// after the committee of the chain was rotated
+	chainAddr, err := apilib.GetChainAddress(c.WaspClient, &c.ChainID)
+	if err != nil {
+		return nil, err
+	}
+	args := par.Args
+	if par.Sealed {
+		sealingKey, err := apilib.GetSealingKey(c.WaspClient, &c.ChainID)
+		if err != nil {
+			return nil, err
+		}
+		sealed, err := sctransaction.SealArgs(args, apilib.CommitteeSuite, sealingKey)
+		if err != nil {
+			return nil, err
+		}
+		args = sealed.Dict()
+	}
+	if par.OffTangle {
+		if args == nil {
+			args = dict.New()
+		}
+		h, err := c.WaspClient.PutOffTangleArgs(&c.ChainID, args)
+		if err != nil {
+			return nil, err
+		}
+		if h != sctransaction.HashOffTangleArgs(args) {
+			return nil, fmt.Errorf("hash of the uploaded off-tangle args mismatch")
+		}
+	}
 	return apilib.CreateRequestTransaction(apilib.CreateRequestTransactionParams{
 		Level1Client:    c.Level1Client,
 		SenderSigScheme: c.SigScheme,
@@ -61,7 +98,10 @@ func (c *Client) PostRequest(
 			TargetContractID: coretypes.NewContractID(c.ChainID, contractHname),
 			EntryPointCode:   entryPoint,
 			Transfer:         par.Transfer,
-			Vars:             par.Args,
+			Vars:             args,
+			GasLimit:         par.GasLimit,
+			OffTangle:        par.OffTangle,
+			TargetAddress:    &chainAddr,
 		}},
 		Post: true,
 	})
@@ -0,0 +1,17 @@
+package chainclient
+
+import (
+	"github.com/iotaledger/wasp/client"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+)
+
+// SubscribeEvents streams the events of the chain of given types (model.ChainEvent*). All events if none given
+func (c *Client) SubscribeEvents(types ...string) (*client.EventSubscription, error) {
+	return c.WaspClient.SubscribeChainEvents(&c.ChainID, nil, types...)
+}
+
+// SubscribeContractEvents streams the messages published by the contract with Sandbox.Event
+func (c *Client) SubscribeContractEvents(contractHname coretypes.Hname) (*client.EventSubscription, error) {
+	return c.WaspClient.SubscribeChainEvents(&c.ChainID, &contractHname, model.ChainEventContract)
+}
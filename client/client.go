@@ -16,6 +16,7 @@ import (
 type WaspClient struct {
 	httpClient http.Client
 	baseURL    string
+	token      string
 }
 
 // NewWaspClient returns a new *WaspClient with the given baseURL and httpClient.
@@ -78,6 +79,7 @@ func (c *WaspClient) do(method string, route string, reqObj interface{}, resObj
 	if data != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
+	c.setAuthorization(req.Header)
 
 	// make the request
 	res, err := c.httpClient.Do(req)
@@ -89,6 +91,19 @@ func (c *WaspClient) do(method string, route string, reqObj interface{}, resObj
 	return processResponse(res, resObj)
 }
 
+// WithToken sets the API key or JWT sent with each request, needed for the admin endpoints
+// when the node is configured with token authentication
+func (c *WaspClient) WithToken(token string) *WaspClient {
+	c.token = token
+	return c
+}
+
+func (c *WaspClient) setAuthorization(header http.Header) {
+	if c.token != "" {
+		header.Set("Authorization", "Bearer "+c.token)
+	}
+}
+
 // BaseURL returns the baseURL of the client.
 func (c *WaspClient) BaseURL() string {
 	return c.baseURL
@@ -0,0 +1,76 @@
+package client
+
+import (
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/packages/webapi/routes"
+	"golang.org/x/net/websocket"
+)
+
+// EventSubscription is the open stream of the chain events
+type EventSubscription struct {
+	// Events is closed when the connection is closed
+	Events    <-chan *model.ChainEvent
+	ws        *websocket.Conn
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// SubscribeChainEvents opens the WebSocket stream of the chain events of given types (all if none given).
+// If contract is not nil, only contract events of that contract are streamed
+func (c *WaspClient) SubscribeChainEvents(chainID *coretypes.ChainID, contract *coretypes.Hname, types ...string) (*EventSubscription, error) {
+	query := url.Values{}
+	if len(types) > 0 {
+		query.Set("types", strings.Join(types, ","))
+	}
+	if contract != nil {
+		query.Set("contract", contract.String())
+	}
+	wsURL := strings.TrimRight(c.baseURL, "/") + routes.ChainEvents(chainID.String())
+	if len(query) > 0 {
+		wsURL += "?" + query.Encode()
+	}
+	wsURL = "ws" + strings.TrimPrefix(wsURL, "http")
+
+	config, err := websocket.NewConfig(wsURL, c.baseURL)
+	if err != nil {
+		return nil, err
+	}
+	c.setAuthorization(config.Header)
+	ws, err := websocket.DialConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	events := make(chan *model.ChainEvent)
+	sub := &EventSubscription{Events: events, ws: ws, done: make(chan struct{})}
+	go func() {
+		defer close(events)
+		for {
+			ev := &model.ChainEvent{}
+			if err := websocket.JSON.Receive(ws, ev); err != nil {
+				return
+			}
+			select {
+			case events <- ev:
+			case <-sub.done:
+				// nobody reads the events after Close
+				return
+			}
+		}
+	}()
+	return sub, nil
+}
+
+// Close closes the stream. Events is closed after the reader stops
+func (s *EventSubscription) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		close(s.done)
+		err = s.ws.Close()
+	})
+	return err
+}
@@ -13,6 +13,9 @@ type MultiClient struct {
 	nodes []*client.WaspClient
 
 	Timeout time.Duration
+	// Quorum is the number of nodes which must agree on the result of a quorum read.
+	// By default all but the tolerated faulty nodes, (N-1)/3 of N
+	Quorum int
 }
 
 // New creates a new instance of MultiClient
@@ -28,19 +31,32 @@ func New(hosts []string, httpClient ...func() http.Client) *MultiClient {
 		}
 	}
 	m.Timeout = 30 * time.Second
+	m.Quorum = len(hosts) - (len(hosts)-1)/3
+	return m
+}
+
+// WithToken sets the API key or JWT sent to all nodes
+func (m *MultiClient) WithToken(token string) *MultiClient {
+	for _, node := range m.nodes {
+		node.WithToken(token)
+	}
 	return m
 }
 
 // Do executes a callback once for each node in parallel, then wraps all error results into a single one
 func (m *MultiClient) Do(f func(int, *client.WaspClient) error) error {
+	ok, errs := multicall.MultiCall(m.funs(f), m.Timeout)
+	if !ok {
+		return multicall.WrapErrors(errs)
+	}
+	return nil
+}
+
+func (m *MultiClient) funs(f func(int, *client.WaspClient) error) []func() error {
 	funs := make([]func() error, len(m.nodes))
 	for i := range m.nodes {
 		j := i // duplicate variable for closure
 		funs[j] = func() error { return f(j, m.nodes[j]) }
 	}
-	ok, errs := multicall.MultiCall(funs, m.Timeout)
-	if !ok {
-		return multicall.WrapErrors(errs)
-	}
-	return nil
+	return funs
 }
@@ -0,0 +1,180 @@
+package multiclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/iotaledger/wasp/client"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/util"
+	"github.com/iotaledger/wasp/packages/util/multicall"
+	"github.com/iotaledger/wasp/packages/webapi/model/statequery"
+)
+
+// QuorumResult tells how the nodes answered a quorum read
+type QuorumResult struct {
+	// StateIndex and StateHash identify the state the agreed value was read from
+	StateIndex uint32
+	StateHash  hashing.HashValue
+	// Agreed are the indices of the nodes which returned the agreed value
+	Agreed []int
+	// Disagreed are the indices of the nodes which returned another value or read another state
+	Disagreed []int
+	// Failed are the errors of the nodes which did not answer
+	Failed map[int]error
+}
+
+// QuorumError is returned by the quorum reads when less than Quorum nodes agree
+type QuorumError struct {
+	Quorum int
+	// Result is the largest group of the nodes which agreed
+	Result *QuorumResult
+}
+
+func (e *QuorumError) Error() string {
+	return fmt.Sprintf("no quorum of %d nodes: %d nodes agreed on state #%d, disagreed: %v, failed: %v",
+		e.Quorum, len(e.Result.Agreed), e.Result.StateIndex, e.Result.Disagreed, e.Result.Failed)
+}
+
+// CallView calls the view on all nodes and returns the result which at least Quorum nodes agree on,
+// read from the same state
+func (m *MultiClient) CallView(contractID coretypes.ContractID, fname string, arguments dict.Dict) (dict.Dict, *QuorumResult, error) {
+	data, res, err := m.quorumRead(func(i int, w *client.WaspClient) (*readResult, error) {
+		r, err := w.CallViewWithState(contractID, fname, arguments)
+		if err != nil {
+			return nil, err
+		}
+		if r.StateHash == nil {
+			return nil, fmt.Errorf("state hash is not known")
+		}
+		return &readResult{
+			value:      util.MustBytes(r.Result),
+			stateIndex: r.StateIndex,
+			stateHash:  *r.StateHash,
+		}, nil
+	})
+	if err != nil {
+		return nil, res, err
+	}
+	ret := dict.New()
+	if err := ret.Read(bytes.NewReader(data)); err != nil {
+		return nil, res, err
+	}
+	return ret, res, nil
+}
+
+// StateQuery queries the state of the chain on all nodes and returns the results which at least Quorum nodes agree on.
+// The general data of the state is always queried
+func (m *MultiClient) StateQuery(chainID *coretypes.ChainID, query *statequery.Request) (*statequery.Results, *QuorumResult, error) {
+	q := *query
+	q.QueryGeneralData = true
+	data, res, err := m.quorumRead(func(i int, w *client.WaspClient) (*readResult, error) {
+		r, err := w.StateQuery(chainID, &q)
+		if err != nil {
+			return nil, err
+		}
+		if r.StateHash == nil {
+			return nil, fmt.Errorf("state hash is not known")
+		}
+		data, err := json.Marshal(r)
+		if err != nil {
+			return nil, err
+		}
+		return &readResult{
+			value:      data,
+			stateIndex: r.StateIndex,
+			stateHash:  *r.StateHash,
+		}, nil
+	})
+	if err != nil {
+		return nil, res, err
+	}
+	ret := &statequery.Results{}
+	if err := json.Unmarshal(data, ret); err != nil {
+		return nil, res, err
+	}
+	return ret, res, nil
+}
+
+// readResult is the answer of one node. The value is serialized, so the answers can be compared exactly
+type readResult struct {
+	value      []byte
+	stateIndex uint32
+	stateHash  hashing.HashValue
+}
+
+// quorumRead reads from all nodes in parallel and groups the nodes by the exact value and the state they returned.
+// The value of the largest group is returned if the group has at least Quorum nodes, otherwise QuorumError.
+// The callers decode the returned value, so all of them get the same value of the agreed group
+func (m *MultiClient) quorumRead(read func(int, *client.WaspClient) (*readResult, error)) ([]byte, *QuorumResult, error) {
+	var mutex sync.Mutex
+	results := make([]*readResult, len(m.nodes))
+	_, errs := multicall.MultiCall(m.funs(func(i int, w *client.WaspClient) error {
+		r, err := read(i, w)
+		mutex.Lock()
+		defer mutex.Unlock()
+		results[i] = r
+		return err
+	}), m.Timeout)
+
+	// the calls which timed out may still write to results
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	failed := make(map[int]error)
+	groups := make(map[string][]int)
+	var best string
+	for i, r := range results {
+		if errs[i] != nil {
+			failed[i] = errs[i]
+			continue
+		}
+		key := string(util.Uint32To4Bytes(r.stateIndex)) + string(r.stateHash[:]) + string(r.value)
+		groups[key] = append(groups[key], i)
+		// on a tie, the group which reached the size first wins
+		if len(groups[key]) > len(groups[best]) {
+			best = key
+		}
+	}
+	res := &QuorumResult{
+		Agreed:    groups[best],
+		Disagreed: make([]int, 0),
+		Failed:    failed,
+	}
+	for i := range results {
+		if errs[i] == nil && !contains(res.Agreed, i) {
+			res.Disagreed = append(res.Disagreed, i)
+		}
+	}
+	if len(res.Agreed) == 0 {
+		return nil, res, &QuorumError{Quorum: m.quorum(), Result: res}
+	}
+	agreed := results[res.Agreed[0]]
+	res.StateIndex = agreed.stateIndex
+	res.StateHash = agreed.stateHash
+	if len(res.Agreed) < m.quorum() {
+		return nil, res, &QuorumError{Quorum: m.quorum(), Result: res}
+	}
+	return agreed.value, res, nil
+}
+
+// quorum returns the number of nodes which must agree on a read
+func (m *MultiClient) quorum() int {
+	if m.Quorum <= 0 || m.Quorum > len(m.nodes) {
+		return len(m.nodes)
+	}
+	return m.Quorum
+}
+
+func contains(indices []int, i int) bool {
+	for _, j := range indices {
+		if j == i {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+package multiclient
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/iotaledger/wasp/client"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/util"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeNodes returns the read function which answers with the given values instead of calling the nodes.
+// A nil value makes the node fail
+func fakeNodes(stateIndex uint32, values ...dict.Dict) func(int, *client.WaspClient) (*readResult, error) {
+	stateHash := hashing.HashStrings(fmt.Sprintf("state #%d", stateIndex))
+	return func(i int, _ *client.WaspClient) (*readResult, error) {
+		if values[i] == nil {
+			return nil, fmt.Errorf("node #%d failed", i)
+		}
+		return &readResult{
+			value:      util.MustBytes(values[i]),
+			stateIndex: stateIndex,
+			stateHash:  stateHash,
+		}, nil
+	}
+}
+
+func newFakeMultiClient(n int) *MultiClient {
+	return New(make([]string, n))
+}
+
+func TestQuorumReadAgreement(t *testing.T) {
+	m := newFakeMultiClient(4)
+	a := dict.Dict{"a": []byte{1}}
+	b := dict.Dict{"a": []byte{2}}
+
+	data, res, err := m.quorumRead(fakeNodes(5, a, b, a, a))
+	require.NoError(t, err)
+	require.EqualValues(t, util.MustBytes(a), data)
+	require.EqualValues(t, []int{0, 2, 3}, res.Agreed)
+	require.EqualValues(t, []int{1}, res.Disagreed)
+	require.Empty(t, res.Failed)
+	require.EqualValues(t, 5, res.StateIndex)
+
+	// a failed node doesn't prevent the quorum
+	data, res, err = m.quorumRead(fakeNodes(5, a, a, nil, a))
+	require.NoError(t, err)
+	require.EqualValues(t, util.MustBytes(a), data)
+	require.EqualValues(t, []int{0, 1, 3}, res.Agreed)
+	require.Empty(t, res.Disagreed)
+	require.Len(t, res.Failed, 1)
+	require.Error(t, res.Failed[2])
+}
+
+func TestQuorumReadDisagreement(t *testing.T) {
+	m := newFakeMultiClient(4)
+	a := dict.Dict{"a": []byte{1}}
+	b := dict.Dict{"a": []byte{2}}
+
+	data, res, err := m.quorumRead(fakeNodes(5, a, b, a, b))
+	require.Nil(t, data)
+	require.Error(t, err)
+	qerr, ok := err.(*QuorumError)
+	require.True(t, ok)
+	require.EqualValues(t, 3, qerr.Quorum)
+	require.Len(t, res.Agreed, 2)
+	require.Len(t, res.Disagreed, 2)
+
+	_, _, err = m.quorumRead(fakeNodes(5, a, nil, nil, a))
+	require.Error(t, err)
+	require.IsType(t, &QuorumError{}, err)
+}
+
+func TestQuorumReadCollidingHash(t *testing.T) {
+	m := newFakeMultiClient(4)
+	// different values with the same Dict.Hash() must not be counted as agreeing
+	a := dict.Dict{"ab": []byte("c")}
+	b := dict.Dict{"a": []byte("bc")}
+	require.EqualValues(t, a.Hash(), b.Hash())
+
+	_, res, err := m.quorumRead(fakeNodes(5, a, b, a, b))
+	require.Error(t, err)
+	require.IsType(t, &QuorumError{}, err)
+	require.Len(t, res.Agreed, 2)
+
+	data, res, err := m.quorumRead(fakeNodes(5, a, a, b, a))
+	require.NoError(t, err)
+	require.EqualValues(t, util.MustBytes(a), data)
+	require.EqualValues(t, []int{0, 1, 3}, res.Agreed)
+	require.EqualValues(t, []int{2}, res.Disagreed)
+}
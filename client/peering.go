@@ -0,0 +1,60 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+// This API is used to maintain the list of trusted peering nodes.
+// The public keys of the nodes are base58 encoded.
+
+import (
+	"net/http"
+
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/packages/webapi/routes"
+)
+
+// PeeringSelfGet returns the public key and the NetID of the node itself.
+func (c *WaspClient) PeeringSelfGet() (*model.PeeringTrustedNode, error) {
+	var response model.PeeringTrustedNode
+	if err := c.do(http.MethodGet, routes.PeeringSelfGet(), nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// PeeringTrustedList returns the list of the peers trusted by the node.
+func (c *WaspClient) PeeringTrustedList() ([]*model.PeeringTrustedNode, error) {
+	var response []*model.PeeringTrustedNode
+	if err := c.do(http.MethodGet, routes.PeeringTrustedList(), nil, &response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// PeeringTrustedGet returns the trusted peer with the specified public key.
+func (c *WaspClient) PeeringTrustedGet(pubKey string) (*model.PeeringTrustedNode, error) {
+	var response model.PeeringTrustedNode
+	if err := c.do(http.MethodGet, routes.PeeringTrustedGet(pubKey), nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// PeeringTrustedPut trusts the peer with the specified public key and NetID.
+func (c *WaspClient) PeeringTrustedPut(pubKey, netID string) (*model.PeeringTrustedNode, error) {
+	request := model.PeeringTrustedNode{PubKey: pubKey, NetID: netID}
+	var response model.PeeringTrustedNode
+	if err := c.do(http.MethodPut, routes.PeeringTrustedPut(), &request, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// PeeringTrustedDelete distrusts the peer with the specified public key.
+func (c *WaspClient) PeeringTrustedDelete(pubKey string) (*model.PeeringTrustedNode, error) {
+	var response model.PeeringTrustedNode
+	if err := c.do(http.MethodDelete, routes.PeeringTrustedDelete(pubKey), nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
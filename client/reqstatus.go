@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/iotaledger/wasp/packages/sctransaction"
 	"github.com/iotaledger/wasp/packages/webapi/model"
 	"github.com/iotaledger/wasp/packages/webapi/routes"
@@ -19,6 +21,15 @@ func (c *WaspClient) RequestStatus(chainId *coretypes.ChainID, reqId *coretypes.
 	return res, nil
 }
 
+// RequestBacklog fetches the requests waiting in the backlog of the chain, in the order of priority
+func (c *WaspClient) RequestBacklog(chainId *coretypes.ChainID) (*model.BacklogResponse, error) {
+	res := &model.BacklogResponse{}
+	if err := c.do(http.MethodGet, routes.RequestBacklog(chainId.String()), nil, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
 // WaitUntilRequestProcessed blocks until the request has been processed by the node
 func (c *WaspClient) WaitUntilRequestProcessed(chainId *coretypes.ChainID, reqId *coretypes.RequestID, timeout time.Duration) error {
 	if timeout == 0 {
@@ -47,3 +58,13 @@ func (c *WaspClient) WaitUntilAllRequestsProcessed(tx *sctransaction.Transaction
 	}
 	return nil
 }
+
+// PutOffTangleArgs uploads off-tangle args of a request to the node. The node gossips them to the committee.
+// Returns the hash of the args to be committed in the request
+func (c *WaspClient) PutOffTangleArgs(chainId *coretypes.ChainID, args dict.Dict) (hashing.HashValue, error) {
+	res := &model.OffTangleArgsResponse{}
+	if err := c.do(http.MethodPost, routes.PutOffTangleArgs(chainId.String()), args, res); err != nil {
+		return hashing.HashValue{}, err
+	}
+	return hashing.HashValueFromBase58(res.Hash)
+}
@@ -0,0 +1,19 @@
+package client
+
+import (
+	"net/http"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/packages/webapi/routes"
+)
+
+// GetSealingKey fetches the public key of the committee of the chain.
+// The key is not trusted: it must be verified against the chain address before sealing args to it
+func (c *WaspClient) GetSealingKey(chainID *coretypes.ChainID) (*model.SealingKey, error) {
+	res := &model.SealingKey{}
+	if err := c.do(http.MethodGet, routes.SealingKey(chainID.String()), nil, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
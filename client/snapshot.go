@@ -0,0 +1,23 @@
+package client
+
+import (
+	"net/http"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/packages/webapi/routes"
+)
+
+// GetStateSnapshot exports the snapshot of the solid state of the chain
+func (c *WaspClient) GetStateSnapshot(chainID *coretypes.ChainID) (*model.StateSnapshot, error) {
+	res := &model.StateSnapshot{}
+	if err := c.do(http.MethodGet, routes.StateSnapshot(chainID.String()), nil, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// PutStateSnapshot imports the snapshot of the solid state of the chain to the node
+func (c *WaspClient) PutStateSnapshot(chainID *coretypes.ChainID, snapshot *model.StateSnapshot) error {
+	return c.do(http.MethodPut, routes.StateSnapshot(chainID.String()), snapshot, nil)
+}
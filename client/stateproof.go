@@ -0,0 +1,33 @@
+package client
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/http"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/packages/webapi/routes"
+)
+
+// StateProof fetches the value of the state variable together with its Merkle proof.
+// Call Verify on the result and compare StateHash with the anchor transaction to check it.
+func (c *WaspClient) StateProof(chainID *coretypes.ChainID, key kv.Key) (*model.StateProof, error) {
+	res := &model.StateProof{}
+	if err := c.do(http.MethodGet, routes.StateProof(chainID.String(), hex.EncodeToString([]byte(key))), nil, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// StateProofAt fetches the value of the state variable in the past state after the given block
+// together with its Merkle proof
+func (c *WaspClient) StateProofAt(chainID *coretypes.ChainID, key kv.Key, blockIndex uint32) (*model.StateProof, error) {
+	res := &model.StateProof{}
+	route := fmt.Sprintf("%s?blockIndex=%d", routes.StateProof(chainID.String(), hex.EncodeToString([]byte(key))), blockIndex)
+	if err := c.do(http.MethodGet, route, nil, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
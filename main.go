@@ -15,6 +15,7 @@ import (
 	"github.com/iotaledger/wasp/plugins/globals"
 	"github.com/iotaledger/wasp/plugins/gracefulshutdown"
 	"github.com/iotaledger/wasp/plugins/logger"
+	"github.com/iotaledger/wasp/plugins/metrics"
 	"github.com/iotaledger/wasp/plugins/nodeconn"
 	"github.com/iotaledger/wasp/plugins/peering"
 	"github.com/iotaledger/wasp/plugins/publisher"
@@ -46,6 +47,7 @@ func main() {
 		dispatcher.Init(),
 		chains.Init(),
 		publisher.Init(),
+		metrics.Init(),
 		dashboard.Init(),
 		wasmtimevm.Init(),
 		globals.Init(),
@@ -34,6 +34,12 @@ type CreateChainParams struct {
 	Description           string
 	Textout               io.Writer
 	Prefix                string
+	// AccessApiHosts and AccessPeeringHosts are the nodes which follow the chain without
+	// taking part in the consensus. Optional
+	AccessApiHosts     []string
+	AccessPeeringHosts []string
+	// AuthToken is the API key or JWT for the admin endpoints of the committee and access nodes. Optional
+	AuthToken string
 }
 
 type ActivateChainParams struct {
@@ -42,6 +48,8 @@ type ActivateChainParams struct {
 	WaitForCompletion bool
 	PublisherHosts    []string
 	Timeout           time.Duration
+	// AuthToken is the API key or JWT for the admin endpoints. Optional
+	AuthToken string
 }
 
 func ActivateChain(par ActivateChainParams) error {
@@ -49,7 +57,7 @@ func ActivateChain(par ActivateChainParams) error {
 	for _, host := range par.ApiHosts {
 		h := host
 		funs = append(funs, func() error {
-			return client.NewWaspClient(h).ActivateChain(&par.ChainID)
+			return client.NewWaspClient(h).WithToken(par.AuthToken).ActivateChain(&par.ChainID)
 		})
 	}
 	if !par.WaitForCompletion {
@@ -80,7 +88,7 @@ func DeactivateChain(par ActivateChainParams) error {
 	for _, host := range par.ApiHosts {
 		h := host
 		funs = append(funs, func() error {
-			return client.NewWaspClient(h).DeactivateChain(&par.ChainID)
+			return client.NewWaspClient(h).WithToken(par.AuthToken).DeactivateChain(&par.ChainID)
 		})
 	}
 	if !par.WaitForCompletion {
@@ -125,7 +133,7 @@ func DeployChain(par CreateChainParams) (*coretypes.ChainID, *address.Address, *
 	// ----------- run DKG on committee nodes
 	var dkgInitiatorIndex = rand.Intn(len(par.CommitteeApiHosts))
 	var dkShares *model.DKSharesInfo
-	dkShares, err = client.NewWaspClient(par.CommitteeApiHosts[dkgInitiatorIndex]).DKSharesPost(&model.DKSharesPostRequest{
+	dkShares, err = client.NewWaspClient(par.CommitteeApiHosts[dkgInitiatorIndex]).WithToken(par.AuthToken).DKSharesPost(&model.DKSharesPostRequest{
 		PeerNetIDs:  par.CommitteePeeringHosts,
 		PeerPubKeys: nil,
 		Threshold:   par.T,
@@ -181,12 +189,14 @@ func DeployChain(par CreateChainParams) (*coretypes.ChainID, *address.Address, *
 	}
 
 	chainColor := balance.Color(originTx.ID())
-	committee := multiclient.New(par.CommitteeApiHosts)
+	committee := multiclient.New(par.CommitteeApiHosts).WithToken(par.AuthToken)
+	allNodes := multiclient.New(append(append([]string{}, par.CommitteeApiHosts...), par.AccessApiHosts...)).WithToken(par.AuthToken)
 	// ------------ put chain records to hosts
-	err = committee.PutChainRecord(&registry.ChainRecord{
+	err = allNodes.PutChainRecord(&registry.ChainRecord{
 		ChainID:        chainID,
 		Color:          chainColor,
 		CommitteeNodes: par.CommitteePeeringHosts,
+		AccessNodes:    par.AccessPeeringHosts,
 	})
 
 	fmt.Fprint(textout, par.Prefix)
@@ -197,7 +207,7 @@ func DeployChain(par CreateChainParams) (*coretypes.ChainID, *address.Address, *
 	fmt.Fprint(textout, "sending smart contract metadata to Wasp nodes.. OK.\n")
 
 	// ------------- activate chain
-	err = committee.ActivateChain(&chainID)
+	err = allNodes.ActivateChain(&chainID)
 
 	fmt.Fprint(textout, par.Prefix)
 	if err != nil {
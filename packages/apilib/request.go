@@ -16,14 +16,26 @@ import (
 	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/iotaledger/wasp/packages/sctransaction"
 	"github.com/iotaledger/wasp/packages/sctransaction/txbuilder"
+	"go.dedis.ch/kyber/v3"
 )
 
 type RequestSectionParams struct {
 	TargetContractID coretypes.ContractID
 	EntryPointCode   coretypes.Hname
 	Timelock         uint32
+	GasLimit         uint32                  // 0 means maximum gas allowed by the chain
 	Transfer         map[balance.Color]int64 // should not not include request token. It is added automatically
 	Vars             dict.Dict
+	// TargetAddress is the current address of the target chain (see GetChainAddress). If nil, the request is sent
+	// to the address of the chain ID. After the committee of the chain was rotated, such request reaches the chain
+	// only when the original committee forwards the tokens of the request to the current address
+	TargetAddress *address.Address
+	// SealingKey is the shared public key of the committee of the target chain (see GetSealingKey).
+	// If provided, Vars are sealed: encrypted so that only the committee can read them
+	SealingKey kyber.Point
+	// OffTangle makes the request commit only to the hash of Vars. Vars themselves must be uploaded
+	// to the committee nodes separately (see client.WaspClient.PutOffTangleArgs)
+	OffTangle bool
 }
 
 type CreateRequestTransactionParams struct {
@@ -58,11 +70,25 @@ func CreateRequestTransaction(par CreateRequestTransactionParams) (*sctransactio
 	for _, sectPar := range par.RequestSectionParams {
 		reqSect := sctransaction.NewRequestSectionByWallet(sectPar.TargetContractID, sectPar.EntryPointCode).
 			WithTimelock(sectPar.Timelock).
+			WithGasLimit(sectPar.GasLimit).
 			WithTransfer(cbalances.NewFromMap(sectPar.Transfer))
 
-		reqSect.WithArgs(sectPar.Vars)
+		if sectPar.SealingKey != nil {
+			if _, err = reqSect.WithSealedArgs(sectPar.Vars, CommitteeSuite, sectPar.SealingKey); err != nil {
+				return nil, err
+			}
+		} else {
+			reqSect.WithArgs(sectPar.Vars)
+		}
+		if sectPar.OffTangle {
+			reqSect.WithOffTangleArgs(reqSect.Args())
+		}
 
-		err = txb.AddRequestSection(reqSect)
+		if sectPar.TargetAddress != nil {
+			err = txb.AddRequestSectionToAddress(reqSect, *sectPar.TargetAddress)
+		} else {
+			err = txb.AddRequestSection(reqSect)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,202 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package apilib
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"time"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address/signaturescheme"
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	"github.com/iotaledger/wasp/client"
+	"github.com/iotaledger/wasp/client/level1"
+	"github.com/iotaledger/wasp/client/multiclient"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/collections"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/registry"
+	"github.com/iotaledger/wasp/packages/vm/core/root"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+)
+
+type RotateCommitteeParams struct {
+	Node    level1.Level1Client
+	ChainID coretypes.ChainID
+	// ApiHost is the API host of one of the nodes of the current committee.
+	// The state of the chain is exported from it
+	ApiHost               string
+	CommitteeApiHosts     []string
+	CommitteePeeringHosts []string
+	N                     uint16
+	T                     uint16
+	OwnerSigScheme        signaturescheme.SignatureScheme
+	Timeout               time.Duration
+	Textout               io.Writer
+	Prefix                string
+	// AuthToken is the API key or JWT for the admin endpoints of the nodes. Optional
+	AuthToken string
+}
+
+// RotateCommittee moves the running chain to the new committee:
+//   - runs DKG on the new committee nodes
+//   - posts the 'rotateCommittee' request to the root contract, signed by the chain owner.
+//     The anchor transaction of the request moves the chain token and all funds of the chain to the new address
+//   - exports the state of the chain from the current committee and imports it to the new committee nodes
+//   - puts chain records to the new committee nodes and activates the chain
+//
+// Returns the new address of the chain
+func RotateCommittee(par RotateCommitteeParams) (*address.Address, error) {
+	textout := ioutil.Discard
+	if par.Textout != nil {
+		textout = par.Textout
+	}
+	if par.Timeout == 0 {
+		par.Timeout = 30 * time.Second
+	}
+	source := client.NewWaspClient(par.ApiHost).WithToken(par.AuthToken)
+
+	chainRecord, err := source.GetChainRecord(par.ChainID)
+	if err != nil {
+		return nil, fmt.Errorf("GetChainRecord: %v", err)
+	}
+	// the nodes of the current committee already have the chain record and the state of the chain
+	for _, peer := range par.CommitteePeeringHosts {
+		for _, prevPeer := range chainRecord.CommitteeNodes {
+			if peer == prevPeer {
+				return nil, fmt.Errorf("node %s is in the current committee. The new committee must consist of other nodes", peer)
+			}
+		}
+	}
+	prevAddr, err := GetChainAddress(source, &par.ChainID)
+	if err != nil {
+		return nil, err
+	}
+
+	// ----------- run DKG on the new committee nodes
+	dkgInitiatorIndex := rand.Intn(len(par.CommitteeApiHosts))
+	dkShares, err := client.NewWaspClient(par.CommitteeApiHosts[dkgInitiatorIndex]).WithToken(par.AuthToken).DKSharesPost(&model.DKSharesPostRequest{
+		PeerNetIDs:  par.CommitteePeeringHosts,
+		PeerPubKeys: nil,
+		Threshold:   par.T,
+		TimeoutMS:   60000, // 1 min
+	})
+	fmt.Fprint(textout, par.Prefix)
+	if err != nil {
+		fmt.Fprintf(textout, "generating distributed key set.. FAILED: %v\n", err)
+		return nil, err
+	}
+	fmt.Fprintf(textout, "generating distributed key set.. OK. Generated address = %s\n", dkShares.Address)
+	newAddr, err := address.FromBase58(dkShares.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	// ----------- post the rotateCommittee request to the current address of the chain
+	// one iota per chain in the directory of chain addresses pays for the announcement of the new address
+	transfer, err := announcementTransfer(source, &par.ChainID)
+	if err != nil {
+		return nil, err
+	}
+	reqTx, err := CreateRequestTransaction(CreateRequestTransactionParams{
+		Level1Client:    par.Node,
+		SenderSigScheme: par.OwnerSigScheme,
+		RequestSectionParams: []RequestSectionParams{{
+			TargetContractID: coretypes.NewContractID(par.ChainID, root.Interface.Hname()),
+			EntryPointCode:   coretypes.Hn(root.FuncRotateCommittee),
+			Vars:             dict.Dict{root.ParamChainAddress: codec.EncodeAddress(newAddr)},
+			Transfer:         transfer,
+			TargetAddress:    &prevAddr,
+		}},
+		Post:                true,
+		WaitForConfirmation: true,
+	})
+	fmt.Fprint(textout, par.Prefix)
+	if err != nil {
+		fmt.Fprintf(textout, "posting rotateCommittee request.. FAILED: %v\n", err)
+		return nil, err
+	}
+	fmt.Fprintf(textout, "posting rotateCommittee request.. OK. Txid = %s\n", reqTx.ID().String())
+
+	if err = source.WaitUntilAllRequestsProcessed(reqTx, par.Timeout); err != nil {
+		fmt.Fprintf(textout, "waiting for rotateCommittee request.. FAILED: %v\n", err)
+		return nil, err
+	}
+	chainAddr, err := GetChainAddress(source, &par.ChainID)
+	if err != nil {
+		return nil, err
+	}
+	if chainAddr != newAddr {
+		return nil, fmt.Errorf("rotateCommittee request failed: the chain is still controlled by %s. See the event log of the root contract", chainAddr.String())
+	}
+
+	// ----------- move the state of the chain to the new committee
+	snapshot, err := source.GetStateSnapshot(&par.ChainID)
+	fmt.Fprint(textout, par.Prefix)
+	if err != nil {
+		fmt.Fprintf(textout, "exporting state snapshot.. FAILED: %v\n", err)
+		return nil, err
+	}
+	fmt.Fprintf(textout, "exporting state snapshot.. OK. Block index = %d\n", snapshot.BlockIndex)
+
+	committee := multiclient.New(par.CommitteeApiHosts).WithToken(par.AuthToken)
+	err = committee.Do(func(i int, w *client.WaspClient) error {
+		return w.PutStateSnapshot(&par.ChainID, snapshot)
+	})
+	fmt.Fprint(textout, par.Prefix)
+	if err != nil {
+		fmt.Fprintf(textout, "importing state snapshot to the new committee.. FAILED: %v\n", err)
+		return nil, err
+	}
+	fmt.Fprint(textout, "importing state snapshot to the new committee.. OK\n")
+
+	// ----------- activate the chain on the new committee
+	err = committee.PutChainRecord(&registry.ChainRecord{
+		ChainID:        par.ChainID,
+		Color:          chainRecord.Color,
+		CommitteeNodes: par.CommitteePeeringHosts,
+	})
+	if err == nil {
+		err = committee.ActivateChain(&par.ChainID)
+	}
+	fmt.Fprint(textout, par.Prefix)
+	if err != nil {
+		fmt.Fprintf(textout, "activating chain on the new committee.. FAILED: %v\n", err)
+		return nil, err
+	}
+	fmt.Fprintf(textout, "activating chain on the new committee.. OK. Chain address: %s\n", newAddr.String())
+	return &newAddr, nil
+}
+
+// GetChainAddress returns the current address of the chain as recorded in the root contract.
+// It differs from the chain ID after the committee of the chain was rotated
+func GetChainAddress(c *client.WaspClient, chainID *coretypes.ChainID) (address.Address, error) {
+	res, err := c.CallView(coretypes.NewContractID(*chainID, root.Interface.Hname()), root.FuncGetChainInfo, nil)
+	if err != nil {
+		return address.Address{}, fmt.Errorf("getChainInfo: %v", err)
+	}
+	ret, ok, err := codec.DecodeAddress(res.MustGet(root.VarChainAddress))
+	if err != nil || !ok {
+		return address.Address{}, fmt.Errorf("getChainInfo: can't decode chain address: %v", err)
+	}
+	return ret, nil
+}
+
+// announcementTransfer returns the tokens needed by the root contract to announce the new address of the chain
+// to the chains in its directory of chain addresses
+func announcementTransfer(c *client.WaspClient, chainID *coretypes.ChainID) (map[balance.Color]int64, error) {
+	res, err := c.CallView(coretypes.NewContractID(*chainID, root.Interface.Hname()), root.FuncGetChainAddresses, nil)
+	if err != nil {
+		return nil, fmt.Errorf("getChainAddresses: %v", err)
+	}
+	n := collections.NewMapReadOnly(res, root.VarChainAddresses).MustLen()
+	if n == 0 {
+		return nil, nil
+	}
+	return map[balance.Color]int64{balance.ColorIOTA: int64(n)}, nil
+}
@@ -0,0 +1,47 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package apilib
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/iotaledger/wasp/client"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/sctransaction"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// CommitteeSuite is the suite of the distributed keys of committees. Must be the same as the one used by Wasp nodes
+var CommitteeSuite = pairing.NewSuiteBn256()
+
+// GetSealingKey returns the shared public key of the committee, which currently controls the chain.
+// It is needed to seal (encrypt to the committee) the arguments of requests.
+// The key published by the node is checked against the chain address recorded in the state of the chain
+func GetSealingKey(c *client.WaspClient, chainID *coretypes.ChainID) (kyber.Point, error) {
+	chainAddr, err := GetChainAddress(c, chainID)
+	if err != nil {
+		return nil, err
+	}
+	key, err := c.GetSealingKey(chainID)
+	if err != nil {
+		return nil, fmt.Errorf("GetSealingKey: %v", err)
+	}
+	if key.Address != chainAddr.String() {
+		return nil, fmt.Errorf("sealing key of the address %s was returned, chain address is %s", key.Address, chainAddr.String())
+	}
+	data, err := base64.StdEncoding.DecodeString(key.SharedPubKey)
+	if err != nil {
+		return nil, err
+	}
+	ret := CommitteeSuite.Point()
+	if err := ret.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	if err := sctransaction.VerifySealingKey(&chainAddr, ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
@@ -8,8 +8,11 @@ import (
 
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	valuetransaction "github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
 	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/hive.go/kvstore"
 	"github.com/iotaledger/hive.go/logger"
+	"github.com/iotaledger/wasp/packages/chain/mempool"
 	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/peering"
 	"github.com/iotaledger/wasp/packages/registry"
@@ -39,7 +42,11 @@ type Chain interface {
 	IsDismissed() bool
 	// requests
 	GetRequestProcessingStatus(*coretypes.RequestID) RequestProcessingStatus
+	// GetBacklog returns requests waiting in the backlog in the order of priority. Empty if the node is not in the committee
+	GetBacklog() []*mempool.Entry
 	EventRequestProcessed() *events.Event
+	// EventRotated is triggered with the previous address of the chain when the committee of the chain was rotated
+	EventRotated() *events.Event
 	// chain processors
 	Processors() *processors.ProcessorCache
 }
@@ -55,6 +62,16 @@ func (p *PeerStatus) String() string {
 	return fmt.Sprintf("%+v", *p)
 }
 
+// Forwarder forwards the tokens, which arrive to the previous address of the chain, to the current address
+// of the chain. It is run by the previous committee after the committee of the chain was rotated, so requests
+// sent to the stale address still reach the chain. Balances of the address come with ReceiveMessage
+type Forwarder interface {
+	ChainID() *coretypes.ChainID
+	From() address.Address
+	ReceiveMessage(msg interface{})
+	Close()
+}
+
 type RequestProcessingStatus int
 
 const (
@@ -89,6 +106,15 @@ type Operator interface {
 	Close()
 	//
 	IsRequestInBacklog(*coretypes.RequestID) bool
+	GetBacklog() []*mempool.Entry
+}
+
+// NodeConnection is the connection of the chain to the IOTA node (Level 1).
+// Responses and updates from the node come back to the chain with ReceiveMessage
+type NodeConnection interface {
+	PostTransaction(tx *valuetransaction.Transaction, fromSc *address.Address, fromLeader uint16) error
+	RequestConfirmedTransaction(txid *valuetransaction.ID) error
+	RequestInclusionLevel(txid *valuetransaction.ID, addr *address.Address) error
 }
 
 var ConstructorNew func(
@@ -96,15 +122,45 @@ var ConstructorNew func(
 	log *logger.Logger,
 	netProvider peering.NetworkProvider,
 	dksProvider tcrypto.RegistryProvider,
+	nodeConn NodeConnection,
+	db kvstore.KVStore,
 	onActivation func(),
 ) Chain
 
+// New creates the chain object. The state of the chain is kept in db, the partition of the chain in the database
 func New(
 	chr *registry.ChainRecord,
 	log *logger.Logger,
 	netProvider peering.NetworkProvider,
 	dksProvider tcrypto.RegistryProvider,
+	nodeConn NodeConnection,
+	db kvstore.KVStore,
 	onActivation func(),
 ) Chain {
-	return ConstructorNew(chr, log, netProvider, dksProvider, onActivation)
+	return ConstructorNew(chr, log, netProvider, dksProvider, nodeConn, db, onActivation)
+}
+
+var ConstructorNewForwarder func(
+	chr *registry.ChainRecord,
+	from address.Address,
+	log *logger.Logger,
+	netProvider peering.NetworkProvider,
+	dksProvider tcrypto.RegistryProvider,
+	nodeConn NodeConnection,
+	db kvstore.KVStore,
+) Forwarder
+
+// NewForwarder creates the forwarder of the tokens from the previous address of the chain to the current one,
+// as recorded in the state of the chain in db. Returns nil if the address is the current address of the chain
+// or the own node is not in the committee which controls it
+func NewForwarder(
+	chr *registry.ChainRecord,
+	from address.Address,
+	log *logger.Logger,
+	netProvider peering.NetworkProvider,
+	dksProvider tcrypto.RegistryProvider,
+	nodeConn NodeConnection,
+	db kvstore.KVStore,
+) Forwarder {
+	return ConstructorNewForwarder(chr, from, log, netProvider, dksProvider, nodeConn, db)
 }
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/hive.go/kvstore"
 	"github.com/iotaledger/wasp/packages/tcrypto"
 	"github.com/iotaledger/wasp/packages/vm/processors"
 
@@ -20,7 +21,9 @@ import (
 	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/peering"
 	"github.com/iotaledger/wasp/packages/registry"
+	"github.com/iotaledger/wasp/packages/state"
 	"github.com/iotaledger/wasp/packages/util"
+	"github.com/iotaledger/wasp/packages/vm/core/root"
 	"go.uber.org/atomic"
 )
 
@@ -31,11 +34,13 @@ type chainObj struct {
 	isQuorumOfConnectionsReached bool
 	mutexIsReady                 sync.Mutex
 	isOpenQueue                  atomic.Bool
+	mutexQueue                   sync.RWMutex // guards sending to chMsg against closing it
 	dismissed                    atomic.Bool
 	dismissOnce                  sync.Once
 	onActivation                 func()
 	//
 	chainID         coretypes.ChainID
+	address         address.Address
 	procset         *processors.ProcessorCache
 	color           balance.Color
 	peers           peering.GroupProvider
@@ -48,10 +53,13 @@ type chainObj struct {
 	isCommitteeNode atomic.Bool
 	//
 	eventRequestProcessed *events.Event
+	eventRotated          *events.Event
 	log                   *logger.Logger
 	netProvider           peering.NetworkProvider
 	peersAttachRef        interface{}
 	dksProvider           tcrypto.RegistryProvider
+	nodeConn              chain.NodeConnection
+	db                    kvstore.KVStore
 }
 
 func requestIDCaller(handler interface{}, params ...interface{}) {
@@ -63,34 +71,44 @@ func newCommitteeObj(
 	log *logger.Logger,
 	netProvider peering.NetworkProvider,
 	dksProvider tcrypto.RegistryProvider,
+	nodeConn chain.NodeConnection,
+	db kvstore.KVStore,
 	onActivation func(),
 ) chain.Chain {
 	var err error
 	log.Debugw("creating committee", "addr", chr.ChainID.String())
 
-	addr := address.Address(chr.ChainID)
-	if util.ContainsDuplicates(chr.CommitteeNodes) {
-		log.Errorf("can't create chain object for %s: chain record contains duplicate node addresses. Chain nodes: %+v",
-			addr.String(), chr.CommitteeNodes)
-		return nil
-	}
-	dkshare, err := dksProvider.LoadDKShare(&addr)
+	addr, err := loadChainAddress(db, &chr.ChainID)
 	if err != nil {
-		log.Error(err)
+		log.Errorf("can't create chain object for %s: %v", chr.ChainID.String(), err)
 		return nil
 	}
-	if dkshare.Index == nil || !iAmInTheCommittee(chr.CommitteeNodes, dkshare.N, *dkshare.Index, netProvider) {
-		log.Errorf(
-			"chain record inconsistency: the own node %s is not in the committee for %s: %+v",
-			netProvider.Self().NetID(), addr.String(), chr.CommitteeNodes,
-		)
+	allNodes := append(append([]string{}, chr.CommitteeNodes...), chr.AccessNodes...)
+	if util.ContainsDuplicates(allNodes) {
+		log.Errorf("can't create chain object for %s: chain record contains duplicate node addresses. Chain nodes: %+v",
+			addr.String(), allNodes)
 		return nil
 	}
+	var dkshare *tcrypto.DKShare
+	accessIndex, isAccessNode := accessNodeIndex(chr, netProvider)
+	if !isAccessNode {
+		if dkshare, err = dksProvider.LoadDKShare(&addr); err != nil {
+			log.Error(err)
+			return nil
+		}
+		if dkshare.Index == nil || !iAmInTheCommittee(chr.CommitteeNodes, dkshare.N, *dkshare.Index, netProvider) {
+			log.Errorf(
+				"chain record inconsistency: the own node %s is not in the committee for %s: %+v",
+				netProvider.Self().NetID(), addr.String(), chr.CommitteeNodes,
+			)
+			return nil
+		}
+	}
 	var peers peering.GroupProvider
-	if peers, err = netProvider.Group(chr.CommitteeNodes); err != nil {
+	if peers, err = netProvider.Group(allNodes); err != nil {
 		log.Errorf(
 			"node %s failed to setup committee communication with %+v, reason=%+v",
-			netProvider.Self().NetID(), chr.CommitteeNodes, err,
+			netProvider.Self().NetID(), allNodes, err,
 		)
 		return nil
 	}
@@ -99,27 +117,43 @@ func newCommitteeObj(
 		procset:      processors.MustNew(),
 		chMsg:        make(chan interface{}, 100),
 		chainID:      chr.ChainID,
+		address:      addr,
 		color:        chr.Color,
 		peers:        peers,
 		onActivation: onActivation,
 		eventRequestProcessed: events.NewEvent(func(handler interface{}, params ...interface{}) {
 			handler.(func(_ coretypes.RequestID))(params[0].(coretypes.RequestID))
 		}),
+		eventRotated: events.NewEvent(func(handler interface{}, params ...interface{}) {
+			handler.(func(_ address.Address))(params[0].(address.Address))
+		}),
 		log:         chainLog,
 		netProvider: netProvider,
 		dksProvider: dksProvider,
+		nodeConn:    nodeConn,
+		db:          db,
 	}
 	ret.peersAttachRef = peers.Attach(&ret.chainID, func(recv *peering.RecvEvent) {
 		ret.ReceiveMessage(recv.Msg)
 	})
 
-	ret.ownIndex = *dkshare.Index
-	ret.size = dkshare.N
-	ret.quorum = dkshare.T
+	if isAccessNode {
+		// the access node follows the chain without taking part in the consensus:
+		// it syncs the state from any alive committee node and never signs
+		ret.ownIndex = accessIndex
+		ret.size = uint16(len(chr.CommitteeNodes))
+		ret.quorum = 1
+		ret.stateMgr = statemgr.New(ret, nodeConn, db, ret.log)
+		ret.isReadyConsensus = true
+	} else {
+		ret.ownIndex = *dkshare.Index
+		ret.size = dkshare.N
+		ret.quorum = dkshare.T
 
-	ret.stateMgr = statemgr.New(ret, ret.log)
-	ret.operator = consensus.NewOperator(ret, dkshare, ret.log)
-	ret.isCommitteeNode.Store(true)
+		ret.stateMgr = statemgr.New(ret, nodeConn, db, ret.log)
+		ret.operator = consensus.NewOperator(ret, dkshare, nodeConn, db, ret.log)
+		ret.isCommitteeNode.Store(true)
+	}
 	go func() {
 		for msg := range ret.chMsg {
 			ret.dispatchMessage(msg)
@@ -144,6 +178,38 @@ func newCommitteeObj(
 	return ret
 }
 
+// loadChainAddress returns the current address of the chain, as recorded in the solid state.
+// The address is equal to the chain ID unless the committee of the chain was rotated
+func loadChainAddress(db kvstore.KVStore, chainID *coretypes.ChainID) (address.Address, error) {
+	solidState, _, ok, err := state.LoadSolidStateFromDB(db, chainID)
+	if err != nil {
+		return address.Address{}, err
+	}
+	if !ok {
+		return address.Address(*chainID), nil
+	}
+	addr, ok, err := root.GetChainAddress(solidState.Variables())
+	if err != nil {
+		return address.Address{}, err
+	}
+	if !ok {
+		// origin state, the chain is not initialized yet
+		return address.Address(*chainID), nil
+	}
+	return addr, nil
+}
+
+// accessNodeIndex returns the peer index of the own node if it is an access node of the chain.
+// Access nodes follow the committee nodes in the peering group of the chain
+func accessNodeIndex(chr *registry.ChainRecord, netProvider peering.NetworkProvider) (uint16, bool) {
+	for i, netID := range chr.AccessNodes {
+		if netID == netProvider.Self().NetID() {
+			return uint16(len(chr.CommitteeNodes) + i), true
+		}
+	}
+	return 0, false
+}
+
 // iAmInTheCommittee checks if NetIDs makes sense
 func iAmInTheCommittee(committeeNodes []string, n, index uint16, netProvider peering.NetworkProvider) bool {
 	if len(committeeNodes) != int(n) {
@@ -59,6 +59,10 @@ func (c *chainObj) dispatchMessage(msg interface{}) {
 			c.operator.EventResultCalculated(msgt)
 		}
 
+	case *chain.OffTangleArgsMsg:
+		// off-tangle args uploaded through the web API
+		c.uploadOffTangleArgs(msgt)
+
 	case chain.TimerTick:
 
 		if msgt%2 == 0 {
@@ -191,6 +195,16 @@ func (c *chainObj) processPeerMessage(msg *peering.PeerMessage) {
 		msgt.SenderIndex = msg.SenderIndex
 		c.testTrace(msgt)
 
+	case chain.MsgOffTangleArgs:
+		msgt := &chain.OffTangleArgsMsg{}
+		if err := msgt.Read(rdr); err != nil {
+			c.log.Error(err)
+			return
+		}
+
+		msgt.SenderIndex = msg.SenderIndex
+		c.saveOffTangleArgs(msgt)
+
 	default:
 		c.log.Errorf("processPeerMessage: wrong msg type")
 	}
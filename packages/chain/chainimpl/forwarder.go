@@ -0,0 +1,301 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package chainimpl
+
+import (
+	"bytes"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	valuetransaction "github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/hive.go/logger"
+	"github.com/iotaledger/wasp/packages/chain"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/peering"
+	"github.com/iotaledger/wasp/packages/registry"
+	"github.com/iotaledger/wasp/packages/tcrypto"
+	"github.com/iotaledger/wasp/packages/tcrypto/tbdn"
+	"github.com/iotaledger/wasp/packages/txutil"
+	"github.com/iotaledger/wasp/packages/txutil/vtxbuilder"
+	"github.com/iotaledger/wasp/packages/util"
+	"go.uber.org/atomic"
+)
+
+// forwarder moves all tokens from the previous address of the chain to the current one.
+// The nodes of the previous committee sign the forwarding transaction with the threshold signature
+// of the previous address. The signature shares are exchanged among them
+type forwarder struct {
+	chainID   coretypes.ChainID
+	color     balance.Color
+	from      address.Address
+	to        address.Address
+	dkshare   *tcrypto.DKShare
+	peers     peering.GroupProvider
+	peeringID coretypes.ChainID
+	attachRef interface{}
+	nodeConn  chain.NodeConnection
+	log       *logger.Logger
+	closed    atomic.Bool
+	mutex     sync.Mutex
+	// the transaction which forwards the last known balances of the address
+	tx       *valuetransaction.Transaction
+	txHash   hashing.HashValue
+	ownShare tbdn.SigShare
+	posted   bool
+	// signature shares of the peers by the hash of the essence
+	sigShares map[hashing.HashValue]map[uint16]tbdn.SigShare
+}
+
+func newForwarder(
+	chr *registry.ChainRecord,
+	from address.Address,
+	log *logger.Logger,
+	netProvider peering.NetworkProvider,
+	dksProvider tcrypto.RegistryProvider,
+	nodeConn chain.NodeConnection,
+	db kvstore.KVStore,
+) chain.Forwarder {
+	to, err := loadChainAddress(db, &chr.ChainID)
+	if err != nil {
+		log.Errorf("can't create forwarder for %s: %v", chr.ChainID.String(), err)
+		return nil
+	}
+	if to == from {
+		return nil
+	}
+	dkshare, err := dksProvider.LoadDKShare(&from)
+	if err != nil {
+		// the own node doesn't control the address
+		return nil
+	}
+	if dkshare.Index == nil || !iAmInTheCommittee(chr.CommitteeNodes, dkshare.N, *dkshare.Index, netProvider) {
+		return nil
+	}
+	peers, err := netProvider.Group(chr.CommitteeNodes)
+	if err != nil {
+		log.Errorf("node %s failed to setup forwarder communication with %+v, reason=%+v",
+			netProvider.Self().NetID(), chr.CommitteeNodes, err)
+		return nil
+	}
+	ret := &forwarder{
+		chainID:   chr.ChainID,
+		color:     chr.Color,
+		from:      from,
+		to:        to,
+		dkshare:   dkshare,
+		peers:     peers,
+		peeringID: forwarderPeeringID(from),
+		nodeConn:  nodeConn,
+		log:       log.Named("fwd-" + util.Short(from.String())),
+		sigShares: make(map[hashing.HashValue]map[uint16]tbdn.SigShare),
+	}
+	ret.attachRef = peers.Attach(&ret.peeringID, func(recv *peering.RecvEvent) {
+		ret.ReceiveMessage(recv.Msg)
+	})
+	go func() {
+		for !ret.closed.Load() {
+			time.Sleep(chain.ForwardSigShareResendPeriod)
+			ret.sendSigShare()
+		}
+	}()
+	ret.log.Infof("forwarding tokens of the chain %s from %s to %s", chr.ChainID.String(), from.String(), to.String())
+	return ret
+}
+
+// forwarderPeeringID is the peering ID of the messages among the forwarders of the address.
+// It never collides with the peering ID of the chain, so the same nodes can run both
+func forwarderPeeringID(from address.Address) coretypes.ChainID {
+	var ret coretypes.ChainID
+	h := hashing.HashData([]byte("forwarder"), from[:])
+	copy(ret[:], h[:])
+	return ret
+}
+
+func (f *forwarder) ChainID() *coretypes.ChainID {
+	return &f.chainID
+}
+
+func (f *forwarder) From() address.Address {
+	return f.from
+}
+
+func (f *forwarder) ReceiveMessage(msg interface{}) {
+	if f.closed.Load() {
+		return
+	}
+	switch msgt := msg.(type) {
+	case chain.BalancesMsg:
+		f.forwardBalances(msgt.Balances)
+
+	case *peering.PeerMessage:
+		if msgt.MsgType != chain.MsgForwardSigShare {
+			f.log.Errorf("ReceiveMessage: wrong msg type")
+			return
+		}
+		sigShareMsg := &chain.ForwardSigShareMsg{}
+		if err := sigShareMsg.Read(bytes.NewReader(msgt.MsgData)); err != nil {
+			f.log.Error(err)
+			return
+		}
+		sigShareMsg.SenderIndex = msgt.SenderIndex
+		f.receiveSigShare(sigShareMsg)
+	}
+}
+
+func (f *forwarder) Close() {
+	if f.closed.Swap(true) {
+		return
+	}
+	f.peers.Detach(f.attachRef)
+	f.peers.Close()
+}
+
+// forwardBalances builds the transaction which forwards the balances of the address and signs it with the own share
+func (f *forwarder) forwardBalances(balances map[valuetransaction.ID][]*balance.Balance) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	tx, err := f.buildForwardTx(balances)
+	if err != nil {
+		f.log.Errorf("forwardBalances: %v", err)
+		return
+	}
+	if tx == nil {
+		// nothing to forward
+		f.tx = nil
+		return
+	}
+	essence := tx.EssenceBytes()
+	essenceHash := hashing.HashData(essence)
+	if f.tx != nil && essenceHash == f.txHash {
+		return
+	}
+	ownShare, err := f.dkshare.SignShare(essence)
+	if err != nil {
+		f.log.Errorf("forwardBalances: %v", err)
+		return
+	}
+	if f.tx != nil {
+		delete(f.sigShares, f.txHash)
+	}
+	f.tx = tx
+	f.txHash = essenceHash
+	f.ownShare = ownShare
+	f.posted = false
+
+	go f.sendSigShare()
+	f.postIfQuorum()
+}
+
+// buildForwardTx builds the transaction, which moves all tokens of the address except the chain token
+// to the current address of the chain. Every node of the committee builds the same transaction from the same balances
+func (f *forwarder) buildForwardTx(balances map[valuetransaction.ID][]*balance.Balance) (*valuetransaction.Transaction, error) {
+	byColor, _ := txutil.BalancesByColor(balances)
+	colors := make([]balance.Color, 0, len(byColor))
+	for col, amount := range byColor {
+		if col == f.color || amount <= 0 {
+			continue
+		}
+		colors = append(colors, col)
+	}
+	if len(colors) == 0 {
+		return nil, nil
+	}
+	sort.Slice(colors, func(i, j int) bool {
+		return bytes.Compare(colors[i][:], colors[j][:]) < 0
+	})
+	txb, err := vtxbuilder.NewFromAddressBalances(&f.from, balances)
+	if err != nil {
+		return nil, err
+	}
+	for _, col := range colors {
+		if err := txb.MoveTokensToAddress(f.to, col, byColor[col]); err != nil {
+			return nil, err
+		}
+	}
+	return txb.Build(false), nil
+}
+
+func (f *forwarder) sendSigShare() {
+	f.mutex.Lock()
+	if f.tx == nil || f.closed.Load() {
+		f.mutex.Unlock()
+		return
+	}
+	msgData := util.MustBytes(&chain.ForwardSigShareMsg{
+		EssenceHash: f.txHash,
+		SigShare:    f.ownShare,
+	})
+	f.mutex.Unlock()
+
+	f.peers.Broadcast(&peering.PeerMessage{
+		ChainID:     f.peeringID,
+		SenderIndex: *f.dkshare.Index,
+		Timestamp:   time.Now().UnixNano(),
+		MsgType:     chain.MsgForwardSigShare,
+		MsgData:     msgData,
+	}, false)
+}
+
+func (f *forwarder) receiveSigShare(msg *chain.ForwardSigShareMsg) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	shares, ok := f.sigShares[msg.EssenceHash]
+	if !ok {
+		shares = make(map[uint16]tbdn.SigShare)
+		f.sigShares[msg.EssenceHash] = shares
+	}
+	shares[msg.SenderIndex] = msg.SigShare
+	f.postIfQuorum()
+}
+
+// postIfQuorum recovers the signature of the forwarding transaction and posts it
+// when signature shares of a quorum of nodes are collected
+func (f *forwarder) postIfQuorum() {
+	if f.tx == nil || f.posted {
+		return
+	}
+	essence := f.tx.EssenceBytes()
+	sigShares := [][]byte{f.ownShare}
+	for idx, sigShare := range f.sigShares[f.txHash] {
+		if idx == *f.dkshare.Index {
+			continue
+		}
+		if err := f.dkshare.VerifySigShare(essence, sigShare); err != nil {
+			f.log.Warnf("postIfQuorum: invalid signature share from peer #%d: %v", idx, err)
+			delete(f.sigShares[f.txHash], idx)
+			continue
+		}
+		sigShares = append(sigShares, sigShare)
+	}
+	if len(sigShares) < int(f.dkshare.T) {
+		return
+	}
+	signature, err := f.dkshare.RecoverFullSignature(sigShares, essence)
+	if err != nil {
+		f.log.Errorf("postIfQuorum: %v", err)
+		return
+	}
+	if err := f.tx.PutSignature(signature); err != nil {
+		f.log.Errorf("postIfQuorum: %v", err)
+		return
+	}
+	f.posted = true
+	tx := f.tx
+	// posted outside of the lock, the balances of the address may be updated in the same call
+	go func() {
+		if err := f.nodeConn.PostTransaction(tx, &f.from, *f.dkshare.Index); err != nil {
+			f.log.Errorf("postIfQuorum: %v", err)
+			return
+		}
+		f.log.Infof("posted transaction %s forwarding tokens from %s to %s",
+			tx.ID().String(), f.from.String(), f.to.String())
+	}()
+}
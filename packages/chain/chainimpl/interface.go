@@ -11,7 +11,9 @@ import (
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
 	"github.com/iotaledger/hive.go/events"
 	"github.com/iotaledger/wasp/packages/chain"
+	"github.com/iotaledger/wasp/packages/chain/mempool"
 	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/metrics"
 	"github.com/iotaledger/wasp/packages/peering"
 	"github.com/iotaledger/wasp/packages/state"
 	"github.com/iotaledger/wasp/packages/util"
@@ -21,6 +23,7 @@ import (
 
 func init() {
 	chain.ConstructorNew = newCommitteeObj
+	chain.ConstructorNewForwarder = newForwarder
 }
 
 func (c *chainObj) IsOpenQueue() bool {
@@ -121,15 +124,20 @@ func (c *chainObj) Dismiss() {
 	c.log.Infof("Dismiss committee for %s", c.chainID.String())
 
 	c.dismissOnce.Do(func() {
+		c.mutexQueue.Lock()
 		c.isOpenQueue.Store(false)
 		c.dismissed.Store(true)
-
 		close(c.chMsg)
+		c.mutexQueue.Unlock()
+
 		c.peers.Detach(c.peersAttachRef)
 		c.peers.Close()
 
 		c.stateMgr.Close()
-		c.operator.Close()
+		if c.operator != nil {
+			c.operator.Close()
+		}
+		metrics.ForgetChain(&c.chainID)
 	})
 
 	publisher.Publish("dismissed_committee", c.chainID.String())
@@ -148,7 +156,7 @@ func (c *chainObj) Color() *balance.Color {
 }
 
 func (c *chainObj) Address() address.Address {
-	return address.Address(c.chainID)
+	return c.address
 }
 
 func (c *chainObj) Size() uint16 {
@@ -160,6 +168,9 @@ func (c *chainObj) Quorum() uint16 {
 }
 
 func (c *chainObj) ReceiveMessage(msg interface{}) {
+	c.mutexQueue.RLock()
+	defer c.mutexQueue.RUnlock()
+
 	if c.isOpenQueue.Load() {
 		select {
 		case c.chMsg <- msg:
@@ -196,8 +207,17 @@ func (c *chainObj) SendMsgToCommitteePeers(msgType byte, msgData []byte, ts int6
 		MsgType:     msgType,
 		MsgData:     msgData,
 	}
-	c.peers.Broadcast(msg, false)
-	return uint16(len(c.peers.OtherNodes())) // TODO: [KP] Reconsider this, we cannot guaranty if they are actually sent.
+	// access peers follow the committee peers in the group and are not addressed here
+	committee := c.committeePeers()
+	numSent := uint16(0)
+	for i, peer := range committee {
+		if i == c.ownIndex {
+			continue
+		}
+		peer.SendMsg(msg)
+		numSent++
+	}
+	return numSent // TODO: [KP] Reconsider this, we cannot guaranty if they are actually sent.
 }
 
 // sends message to the peer seq[seqIndex]. If receives error, seqIndex = (seqIndex+1) % size and repeats
@@ -246,7 +266,13 @@ func (c *chainObj) NumPeers() uint16 {
 
 // first N peers are committee peers, the rest are access peers in any
 func (c *chainObj) committeePeers() map[uint16]peering.PeerSender {
-	return c.peers.AllNodes()
+	ret := make(map[uint16]peering.PeerSender)
+	for i, peer := range c.peers.AllNodes() {
+		if i < c.size {
+			ret[i] = peer
+		}
+	}
+	return ret
 }
 
 func (c *chainObj) HasQuorum() bool {
@@ -297,13 +323,20 @@ func (c *chainObj) GetRequestProcessingStatus(reqID *coretypes.RequestID) chain.
 			return chain.RequestProcessingStatusBacklog
 		}
 	}
-	processed, err := state.IsRequestCompleted(c.ID(), reqID)
+	processed, err := state.IsRequestCompletedInDB(c.db, reqID)
 	if err != nil || !processed {
 		return chain.RequestProcessingStatusUnknown
 	}
 	return chain.RequestProcessingStatusCompleted
 }
 
+func (c *chainObj) GetBacklog() []*mempool.Entry {
+	if c.IsDismissed() || !c.isCommitteeNode.Load() {
+		return nil
+	}
+	return c.operator.GetBacklog()
+}
+
 func (c *chainObj) Processors() *processors.ProcessorCache {
 	return c.procset
 }
@@ -311,3 +344,7 @@ func (c *chainObj) Processors() *processors.ProcessorCache {
 func (c *chainObj) EventRequestProcessed() *events.Event {
 	return c.eventRequestProcessed
 }
+
+func (c *chainObj) EventRotated() *events.Event {
+	return c.eventRotated
+}
@@ -0,0 +1,33 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package chainimpl
+
+import (
+	"time"
+
+	"github.com/iotaledger/wasp/packages/chain"
+	"github.com/iotaledger/wasp/packages/registry"
+	"github.com/iotaledger/wasp/packages/util"
+)
+
+// uploadOffTangleArgs stores off-tangle args, uploaded to the node, and gossips them to other committee nodes
+func (c *chainObj) uploadOffTangleArgs(msg *chain.OffTangleArgsMsg) {
+	msg.SenderIndex = c.ownIndex
+	if !c.saveOffTangleArgs(msg) {
+		return
+	}
+	c.SendMsgToCommitteePeers(chain.MsgOffTangleArgs, util.MustBytes(msg), time.Now().UnixNano())
+}
+
+// saveOffTangleArgs stores off-tangle args in the registry. Args are stored by hash,
+// so the request which commits to the hash will pick exactly the same args on each node
+func (c *chainObj) saveOffTangleArgs(msg *chain.OffTangleArgsMsg) bool {
+	h, err := registry.SaveOffTangleArgs(msg.Args)
+	if err != nil {
+		c.log.Errorf("saveOffTangleArgs: %v", err)
+		return false
+	}
+	c.log.Debugf("saved off-tangle args %s received from #%d", h.String(), msg.SenderIndex)
+	return true
+}
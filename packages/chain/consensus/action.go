@@ -11,7 +11,6 @@ import (
 	"github.com/iotaledger/wasp/packages/state"
 	"github.com/iotaledger/wasp/packages/util"
 	"github.com/iotaledger/wasp/packages/vm"
-	"github.com/iotaledger/wasp/plugins/nodeconn"
 )
 
 // takeAction analyzes the state and updates it and takes action such as sending of message,
@@ -37,7 +36,7 @@ func (op *operator) pullInclusionLevel() {
 	}
 	if time.Now().After(op.nextPullInclusionLevel) {
 		addr := op.chain.Address()
-		if err := nodeconn.RequestInclusionLevelFromNode(op.postedResultTxid, &addr); err != nil {
+		if err := op.nodeConn.RequestInclusionLevel(op.postedResultTxid, &addr); err != nil {
 			op.log.Errorf("RequestInclusionLevelFromNode: %v", err)
 		}
 		op.setNextPullInclusionStageDeadline()
@@ -95,15 +94,21 @@ func (op *operator) startCalculationsAsLeader() {
 	op.log.Debugf("requests selected to process. Current state: %d, Reqs: %+v", op.mustStateIndex(), reqIdsStr)
 	rewardAddress := op.getFeeDestination()
 
+	// peers recover the keys of sealed args from the same decryption shares
+	decShares := make([][][]byte, len(reqs))
+	for i, req := range reqs {
+		decShares[i] = req.unsealShares
+	}
 	// send to subordinated peers requests to process the batch
 	msgData := util.MustBytes(&chain.StartProcessingBatchMsg{
 		PeerMsgHeader: chain.PeerMsgHeader{
 			// timestamp is set by SendMsgToCommitteePeers
 			BlockIndex: op.stateTx.MustState().BlockIndex(),
 		},
-		FeeDestination: rewardAddress,
-		Balances:       op.balances,
-		RequestIds:     reqIds,
+		FeeDestination:   rewardAddress,
+		Balances:         op.balances,
+		RequestIds:       reqIds,
+		DecryptionShares: decShares,
 	})
 
 	// determine timestamp. Must be max(local clock, prev timestamp+1).
@@ -229,7 +234,7 @@ func (op *operator) checkQuorum() {
 
 	// posting finalized transaction to goshimmer
 	addr := op.chain.Address()
-	err = nodeconn.PostTransactionToNode(op.leaderStatus.resultTx.Transaction, &addr, op.chain.OwnPeerIndex())
+	err = op.nodeConn.PostTransaction(op.leaderStatus.resultTx.Transaction, &addr, op.chain.OwnPeerIndex())
 	if err != nil {
 		op.log.Warnf("PostTransactionToNode failed: %v", err)
 		return
@@ -10,6 +10,7 @@ import (
 
 	"github.com/iotaledger/goshimmer/dapps/waspconn/packages/waspconn"
 	"github.com/iotaledger/wasp/packages/chain"
+	"github.com/iotaledger/wasp/packages/metrics"
 	"github.com/iotaledger/wasp/packages/txutil"
 	"github.com/iotaledger/wasp/packages/vm"
 )
@@ -147,6 +148,21 @@ func (op *operator) eventStartProcessingBatchMsg(msg *chain.StartProcessingBatch
 		op.log.Warn("node is not ready to process the batch")
 		return
 	}
+	// the leader must provide the quorum of valid decryption shares for each request with sealed args.
+	// Otherwise the node doesn't start, so the leader is rotated
+	for i, req := range reqs {
+		if op.decryptionShare(req) == nil {
+			continue
+		}
+		var decShares [][]byte
+		if i < len(msg.DecryptionShares) {
+			decShares = msg.DecryptionShares[i]
+		}
+		if err := op.recoverUnsealKey(req, decShares); err != nil {
+			op.log.Warnf("node can't process the batch: can't unseal args of the request %s: %v", req.reqId.Short(), err)
+			return
+		}
+	}
 	// check timestamp. If the local clock is different from the timestamp from the leader more
 	// tha threshold, ignore command from the leader.
 	// Note that if leader's clock is ot synced with the peers clock significantly, committee
@@ -163,14 +179,18 @@ func (op *operator) eventStartProcessingBatchMsg(msg *chain.StartProcessingBatch
 		return
 	}
 
-	// start async calculation as requested by the leader
-	op.runCalculationsAsync(runCalculationsParams{
+	// start async calculation as requested by the leader.
+	// If the inputs from the leader are invalid, the node stays in the stage with timeout, so the leader is rotated
+	started := op.runCalculationsAsync(runCalculationsParams{
 		requests:        reqs,
 		timestamp:       msg.Timestamp,
 		balances:        msg.Balances,
 		accrueFeesTo:    msg.FeeDestination,
 		leaderPeerIndex: msg.SenderIndex,
 	})
+	if !started {
+		return
+	}
 	op.setNextConsensusStage(consensusStageSubCalculationsStarted)
 	op.takeAction()
 }
@@ -200,7 +220,8 @@ func (op *operator) eventResultCalculated(ctx *chain.VMResultMsg) {
 	// from confirmation of it from the tangle
 	go func() {
 		op.chain.ReceiveMessage(chain.PendingBlockMsg{
-			Block: ctx.Task.ResultBlock,
+			Block:   ctx.Task.ResultBlock,
+			Results: ctx.Task.ResultRequests,
 		})
 	}()
 
@@ -302,6 +323,8 @@ func (op *operator) EventTimerMsg(msg chain.TimerTick) {
 
 // eventTimerMsg internal handler
 func (op *operator) eventTimerMsg(msg chain.TimerTick) {
+	metrics.SetBacklogLength(op.chain.ID(), len(op.requests))
+	op.refreshBacklogConcurrent()
 	if msg%40 == 0 {
 		blockIndex, ok := op.blockIndex()
 		si := int32(-1)
@@ -38,6 +38,13 @@ func (op *operator) checkInclusionLevel(txid *valuetransaction.ID, level byte) {
 		} else {
 			op.setNextPullInclusionStageDeadline()
 		}
+	case waspconn.TransactionInclusionLevelConfirmed:
+		// the result transaction of the committee rotation moves the chain token to the new address,
+		// which the node is not subscribed to. The confirmed transaction is requested explicitly
+		if err := op.nodeConn.RequestConfirmedTransaction(txid); err != nil {
+			op.log.Errorf("RequestConfirmedTransaction: %v", err)
+		}
+		op.setNextPullInclusionStageDeadline()
 	case waspconn.TransactionInclusionLevelRejected:
 		// cannot move to the next leader because funds are locked forever
 		// TODO not clear what to do. Need proper specs from Goshimmer
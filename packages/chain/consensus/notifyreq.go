@@ -28,6 +28,9 @@ func (op *operator) sendRequestNotificationsToLeader() {
 	currentLeaderPeerIndex, _ := op.currentLeader()
 	reqs := op.requestCandidateList()
 	reqs = op.filterOutRequestsWithoutTokens(reqs)
+	// the node doesn't notify about requests it can't process yet. The leader only selects requests
+	// notified by a quorum, so the off-tangle args of the batch are available to the quorum of nodes
+	reqs = op.filterOffTangleArgsMissing(reqs)
 
 	// get not time-locked requests with the message known
 	if len(reqs) == 0 {
@@ -38,11 +41,17 @@ func (op *operator) sendRequestNotificationsToLeader() {
 		currentLeaderPeerIndex, len(op.requests), len(reqs))
 
 	reqIds := takeIds(reqs)
+	// the leader needs decryption shares of a quorum of nodes to unseal the args
+	decShares := make([][]byte, len(reqs))
+	for i, req := range reqs {
+		decShares[i] = op.decryptionShare(req)
+	}
 	msgData := util.MustBytes(&chain.NotifyReqMsg{
 		PeerMsgHeader: chain.PeerMsgHeader{
 			BlockIndex: op.mustStateIndex(),
 		},
-		RequestIDs: reqIds,
+		RequestIDs:       reqIds,
+		DecryptionShares: decShares,
 	})
 
 	// send until first success, but no more than number of nodes in the committee
@@ -67,6 +76,7 @@ func (op *operator) storeNotification(msg *chain.NotifyReqMsg) {
 }
 
 // markRequestsNotified stores information about notification in the current currentState
+// Decryption shares of the sender are kept independently of the state
 func (op *operator) markRequestsNotified(msgs []*chain.NotifyReqMsg) {
 	stateIndex, stateDefined := op.blockIndex()
 	if !stateDefined {
@@ -76,13 +86,16 @@ func (op *operator) markRequestsNotified(msgs []*chain.NotifyReqMsg) {
 		if msg.BlockIndex != stateIndex {
 			continue
 		}
-		for _, reqid := range msg.RequestIDs {
+		for i, reqid := range msg.RequestIDs {
 			req, ok := op.requestFromId(reqid)
 			if !ok {
 				continue
 			}
 			// mark request was seen by sender
 			req.notifications[msg.SenderIndex] = true
+			if i < len(msg.DecryptionShares) && len(msg.DecryptionShares[i]) > 0 {
+				req.decryptionShares[msg.SenderIndex] = msg.DecryptionShares[i]
+			}
 		}
 	}
 }
@@ -9,8 +9,11 @@ import (
 	"time"
 
 	"github.com/iotaledger/wasp/packages/chain"
+	"github.com/iotaledger/wasp/packages/chain/mempool"
 	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/iotaledger/wasp/packages/publisher"
+	"github.com/iotaledger/wasp/packages/registry"
 	"github.com/iotaledger/wasp/packages/sctransaction"
 	"github.com/iotaledger/wasp/packages/state"
 	"github.com/iotaledger/wasp/packages/util"
@@ -19,9 +22,10 @@ import (
 func (op *operator) newRequest(reqId coretypes.RequestID) *request {
 	reqLog := op.log.Named(reqId.Short())
 	ret := &request{
-		reqId:         reqId,
-		log:           reqLog,
-		notifications: make([]bool, op.size()),
+		reqId:            reqId,
+		log:              reqLog,
+		notifications:    make([]bool, op.size()),
+		decryptionShares: make([][]byte, op.size()),
 	}
 	return ret
 }
@@ -98,8 +102,92 @@ func (req *request) isTimelocked(nowis time.Time) bool {
 	return req.timelock() > uint32(nowis.Unix())
 }
 
+// offTangleArgs returns args of the request delivered off-tangle.
+// Returns nil if the request is not off-tangle or args are not received by the node yet
+func (req *request) offTangleArgs() dict.Dict {
+	reqSect := req.reqTx.Requests()[req.reqId.Index()]
+	if !reqSect.IsOffTangle() {
+		return nil
+	}
+	h, err := reqSect.OffTangleArgsHash()
+	if err != nil {
+		req.log.Errorf("offTangleArgs: %v", err)
+		return nil
+	}
+	ret, err := registry.GetOffTangleArgs(&h)
+	if err != nil {
+		req.log.Errorf("offTangleArgs: %v", err)
+		return nil
+	}
+	return ret
+}
+
+func (req *request) isOffTangleArgsMissing() bool {
+	return req.reqTx.Requests()[req.reqId.Index()].IsOffTangle() && req.offTangleArgs() == nil
+}
+
+// sealedArgs returns sealed args of the request, taken from the request section or from the off-tangle args
+// the same way the VM takes them. Returns nil if args are not sealed or can't be decoded
+func (req *request) sealedArgs() *sctransaction.SealedArgs {
+	reqSect := req.reqTx.Requests()[req.reqId.Index()]
+	args := reqSect.Args()
+	if reqSect.IsOffTangle() {
+		args = req.offTangleArgs()
+	}
+	if !sctransaction.IsSealedArgs(args) {
+		return nil
+	}
+	ret, err := sctransaction.SealedArgsFromDict(args)
+	if err != nil {
+		req.log.Warnf("sealedArgs: %v", err)
+		return nil
+	}
+	return ret
+}
+
+// decryptionShare returns the own decryption share of the sealed args of the request.
+// Returns nil if args are not sealed or the ephemeral key is invalid. The VM fails such requests
+// in the same way on all nodes, so the committee doesn't need to agree on the key of them.
+// Must be called only when the request transaction and off-tangle args, if any, are known to the node
+func (op *operator) decryptionShare(req *request) []byte {
+	if req.ownDecryptionShareDone {
+		return req.ownDecryptionShare
+	}
+	req.ownDecryptionShareDone = true
+	sealed := req.sealedArgs()
+	if sealed == nil {
+		return nil
+	}
+	var err error
+	if req.ownDecryptionShare, err = op.dkshare.DecryptionShare(sealed.EphemeralKey); err != nil {
+		req.log.Warnf("can't produce decryption share: %v", err)
+		req.ownDecryptionShare = nil
+	}
+	return req.ownDecryptionShare
+}
+
+// recoverUnsealKey recovers the key of the sealed args of the request from the decryption shares.
+// Every share is verified, so any quorum of valid shares recovers the same key on every node
+func (op *operator) recoverUnsealKey(req *request, decShares [][]byte) error {
+	sealed := req.sealedArgs()
+	if sealed == nil {
+		return fmt.Errorf("args of the request %s are not sealed", req.reqId.Short())
+	}
+	secret, err := op.dkshare.RecoverDecryptionSecret(sealed.EphemeralKey, decShares)
+	if err != nil {
+		return err
+	}
+	key, err := sealed.Key(secret)
+	if err != nil {
+		return err
+	}
+	req.unsealShares = decShares
+	req.unsealKey = key
+	return nil
+}
+
 func (op *operator) isRequestProcessed(reqid *coretypes.RequestID) bool {
-	processed, err := state.IsRequestCompleted(op.chain.ID(), reqid)
+	processed, err := state.IsRequestCompletedInDB(op.db, reqid)
 	if err != nil {
 		panic(err)
 	}
@@ -111,7 +199,7 @@ func (op *operator) deleteCompletedRequests() error {
 	toDelete := make([]*coretypes.RequestID, 0)
 
 	for _, req := range op.requests {
-		if completed, err := state.IsRequestCompleted(op.chain.ID(), &req.reqId); err != nil {
+		if completed, err := state.IsRequestCompletedInDB(op.db, &req.reqId); err != nil {
 			return err
 		} else {
 			if completed {
@@ -163,7 +251,9 @@ func takeRefs(reqs []*request) []vm.RequestRefWithFreeTokens {
 				Tx:    reqs[i].reqTx,
 				Index: reqs[i].reqId.Index(),
 			},
-			FreeTokens: reqs[i].freeTokens,
+			FreeTokens:    reqs[i].freeTokens,
+			OffTangleArgs: reqs[i].offTangleArgs(),
+			UnsealKey:     reqs[i].unsealKey,
 		}
 	}
 	return ret
@@ -194,3 +284,27 @@ func (op *operator) hasRequestIdConcurrent(reqId *coretypes.RequestID) bool {
 func (op *operator) IsRequestInBacklog(reqId *coretypes.RequestID) bool {
 	return op.hasRequestIdConcurrent(reqId)
 }
+
+// refreshBacklogConcurrent takes the snapshot of the backlog in the order of priority for the APIs
+func (op *operator) refreshBacklogConcurrent() {
+	reqs := make([]*request, 0, len(op.requests))
+	for _, req := range op.requests {
+		if req.reqTx != nil {
+			reqs = append(reqs, req)
+		}
+	}
+	entries := op.backlogEntries(op.sortByPriority(reqs))
+
+	op.concurrentAccessMutex.Lock()
+	defer op.concurrentAccessMutex.Unlock()
+
+	op.backlogProtected = entries
+}
+
+// GetBacklog returns requests of the backlog known to the node in the order of priority
+func (op *operator) GetBacklog() []*mempool.Entry {
+	op.concurrentAccessMutex.RLock()
+	defer op.concurrentAccessMutex.RUnlock()
+
+	return op.backlogProtected
+}
@@ -25,16 +25,17 @@ type runCalculationsParams struct {
 	timestamp       int64
 }
 
-// runs the VM for requests and posts result to committee's queue
-func (op *operator) runCalculationsAsync(par runCalculationsParams) {
+// runs the VM for requests and posts result to committee's queue. Returns false if the calculations were not started
+func (op *operator) runCalculationsAsync(par runCalculationsParams) bool {
 	if op.currentState == nil {
 		op.log.Debugf("runCalculationsAsync: variable currentState is not known")
-		return
+		return false
 	}
 	ctx := &vm.VMTask{
 		Processors:         op.chain.Processors(),
 		ChainID:            *op.chain.ID(),
 		Color:              *op.chain.Color(),
+		Address:            op.chain.Address(),
 		Entropy:            (hashing.HashValue)(op.stateTx.ID()),
 		Balances:           par.balances,
 		ValidatorFeeTarget: par.accrueFeesTo,
@@ -55,7 +56,9 @@ func (op *operator) runCalculationsAsync(par runCalculationsParams) {
 	}
 	if err := runvm.RunComputationsAsync(ctx); err != nil {
 		op.log.Errorf("RunComputationsAsync: %v", err)
+		return false
 	}
+	return true
 }
 
 func (op *operator) sendResultToTheLeader(result *vm.VMTask, leader uint16) {
@@ -128,8 +131,9 @@ func (op *operator) saveOwnResult(result *vm.VMTask) {
 	if bh != op.leaderStatus.batchHash {
 		panic("bh != op.leaderStatus.batchHash")
 	}
-	if len(result.Requests) != int(result.ResultBlock.Size()) {
-		panic("len(result.RequestIDs) != int(result.ResultBlock.Size())")
+	// the block may be closed before all requests of the batch are processed, when it reaches the mutation size limit
+	if len(result.Requests) < int(result.ResultBlock.Size()) {
+		panic("len(result.RequestIDs) < int(result.ResultBlock.Size())")
 	}
 
 	essenceHash := hashing.HashData(result.ResultTransaction.EssenceBytes())
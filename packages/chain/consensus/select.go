@@ -6,18 +6,23 @@ package consensus
 
 import (
 	"fmt"
-	"sort"
 	"time"
 
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
 	valuetransaction "github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
+	"github.com/iotaledger/wasp/packages/chain/mempool"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/sctransaction"
 	"github.com/iotaledger/wasp/packages/txutil"
 )
 
 // selectRequestsToProcess select requests to process in the batch.
-// 1. it filters out candidates which was seen less than quorum times.
+// 1. it filters out candidates which was seen less than quorum times. Peers notify only about requests
+// with off-tangle args available to them, so the quorum of nodes is able to calculate the batch
 // 2. the requests which are not ready yet to process in the current context are filtered out
-// 3. selects maximum possible set of those which were seen by same quorum of peers
+// 3. the requests with sealed args are filtered out until the key is recovered from a quorum of decryption shares
+// 4. takes requests in the order of priority (attached fee, then arrival time) within the block limits
+// set by the chain owner, as long as all of them were seen by the same quorum of peers
 // only requests in "full batches" are selected, it means request is in the selection together with ALL other requests
 // from the same request transaction, or it is not selected
 func (op *operator) selectRequestsToProcess() []*request {
@@ -41,28 +46,44 @@ func (op *operator) selectRequestsToProcess() []*request {
 	if candidates = op.filterNotReadyYet(candidates); len(candidates) == 0 {
 		return nil
 	}
+	if candidates = op.filterSealedWithoutKey(candidates); len(candidates) == 0 {
+		return nil
+	}
 
-	ret := []*request{candidates[0]}
-	intersection := make([]bool, op.size())
-	copy(intersection, candidates[0].notifications)
-
-	for i := uint16(1); int(i) < len(candidates); i++ {
+	byID := make(map[coretypes.RequestID]*request, len(candidates))
+	for _, req := range candidates {
+		byID[req.reqId] = req
+	}
+	var intersection []bool
+	selected := mempool.Select(op.backlogEntries(candidates), op.getBlockLimits(), func(e *mempool.Entry) bool {
+		req := byID[e.RequestID]
+		if intersection == nil {
+			intersection = make([]bool, op.size())
+			copy(intersection, req.notifications)
+			return true
+		}
+		next := make([]bool, len(intersection))
 		for j := range intersection {
-			intersection[j] = intersection[j] && candidates[i].notifications[j]
+			next[j] = intersection[j] && req.notifications[j]
 		}
-		if numTrue(intersection) < op.quorum() {
-			break
+		if numTrue(next) < op.quorum() {
+			return false
 		}
-		ret = append(ret, candidates[i])
-	}
-	if len(ret) == 0 {
+		intersection = next
+		return true
+	})
+	if len(selected) == 0 {
 		return nil
 	}
+	ret := make([]*request, len(selected))
+	for i, e := range selected {
+		ret[i] = byID[e.RequestID]
+	}
 	return ret
 }
 
 // all requests from the backlog which has known messages and are not timelocked
-// sort by arrival time
+// sort by priority
 func (op *operator) requestCandidateList() []*request {
 	ret := make([]*request, 0, len(op.requests))
 
@@ -76,9 +97,33 @@ func (op *operator) requestCandidateList() []*request {
 		}
 		ret = append(ret, req)
 	}
-	sort.Slice(ret, func(i, j int) bool {
-		return ret[i].whenMsgReceived.Before(ret[j].whenMsgReceived)
-	})
+	return op.sortByPriority(ret)
+}
+
+// sortByPriority orders requests by the attached fee, then by the arrival time
+func (op *operator) sortByPriority(reqs []*request) []*request {
+	byID := make(map[coretypes.RequestID]*request, len(reqs))
+	for _, req := range reqs {
+		byID[req.reqId] = req
+	}
+	entries := op.backlogEntries(reqs)
+	mempool.SortByPriority(entries)
+	for i, e := range entries {
+		reqs[i] = byID[e.RequestID]
+	}
+	return reqs
+}
+
+// backlogEntries returns the backlog entries of the requests, in the same order
+func (op *operator) backlogEntries(reqs []*request) []*mempool.Entry {
+	feeColor := op.getFeeColor()
+	ret := make([]*mempool.Entry, len(reqs))
+	for i, req := range reqs {
+		ret[i] = mempool.NewEntry(&sctransaction.RequestRef{
+			Tx:    req.reqTx,
+			Index: req.reqId.Index(),
+		}, feeColor, req.whenMsgReceived)
+	}
 	return ret
 }
 
@@ -98,32 +143,17 @@ func (op *operator) requestTimelocked() []*request {
 	return ret
 }
 
-type requestWithVotes struct {
-	*request
-	seenTimes uint16
-}
-
+// filterRequestsNotSeenQuorumTimes leaves requests seen by at least quorum of peers. The order is preserved
 func (op *operator) filterRequestsNotSeenQuorumTimes(candidates []*request) []*request {
 	if len(candidates) == 0 {
 		return nil
 	}
-	ret1 := make([]*requestWithVotes, 0)
+	ret := candidates[:0] // same underlying array
 	for _, req := range candidates {
-		votes := numTrue(req.notifications)
-		if votes >= op.quorum() {
-			ret1 = append(ret1, &requestWithVotes{
-				request:   req,
-				seenTimes: votes,
-			})
+		if numTrue(req.notifications) >= op.quorum() {
+			ret = append(ret, req)
 		}
 	}
-	sort.Slice(ret1, func(i, j int) bool {
-		return ret1[i].seenTimes > ret1[j].seenTimes
-	})
-	ret := candidates[:0] // same underlying array
-	for _, req := range ret1 {
-		ret = append(ret, req.request)
-	}
 	return ret
 }
 
@@ -134,6 +164,7 @@ func (op *operator) filterRequestsNotSeenQuorumTimes(candidates []*request) []*r
 //  - which has not received message with request transaction yet (the ID is known from peer only)
 //  - the user defined request while processor is not ready yet
 //  - the request is timelocked yet
+//  - the off-tangle args of the request are not received by the node yet
 func (op *operator) filterNotReadyYet(reqs []*request) []*request {
 	if len(reqs) == 0 {
 		return nil
@@ -145,6 +176,10 @@ func (op *operator) filterNotReadyYet(reqs []*request) []*request {
 			op.log.Debugf("request %s not yet known to the node: can't be processed", req.reqId.Short())
 			continue
 		}
+		if req.isOffTangleArgsMissing() {
+			op.log.Debugf("off-tangle args of the request %s not yet received by the node: can't be processed", req.reqId.Short())
+			continue
+		}
 		ret = append(ret, req)
 	}
 	before := len(ret)
@@ -155,6 +190,56 @@ func (op *operator) filterNotReadyYet(reqs []*request) []*request {
 	return ret
 }
 
+// filterOffTangleArgsMissing leaves requests which off-tangle args, if any, are received by the node. The order is preserved
+func (op *operator) filterOffTangleArgsMissing(reqs []*request) []*request {
+	ret := reqs[:0] // same underlying array
+	for _, req := range reqs {
+		if req.isOffTangleArgsMissing() {
+			op.log.Debugf("off-tangle args of the request %s not yet received by the node", req.reqId.Short())
+			continue
+		}
+		ret = append(ret, req)
+	}
+	return ret
+}
+
+// filterSealedWithoutKey leaves requests with args not sealed and requests with sealed args which
+// key is recovered from the quorum of valid decryption shares, the own one and the ones notified by peers.
+// The order is preserved
+func (op *operator) filterSealedWithoutKey(reqs []*request) []*request {
+	ret := reqs[:0] // same underlying array
+	for _, req := range reqs {
+		ownShare := op.decryptionShare(req)
+		if ownShare == nil || req.unsealKey != nil {
+			ret = append(ret, req)
+			continue
+		}
+		sealed := req.sealedArgs()
+		decShares := [][]byte{ownShare}
+		for i, decShare := range req.decryptionShares {
+			if decShare == nil || uint16(i) == op.peerIndex() || len(decShares) >= int(op.quorum()) {
+				continue
+			}
+			if sh, err := op.dkshare.VerifyDecryptionShare(sealed.EphemeralKey, decShare); err != nil || sh.I != i {
+				op.log.Warnf("invalid decryption share of the request %s from peer #%d: %v", req.reqId.Short(), i, err)
+				req.decryptionShares[i] = nil
+				continue
+			}
+			decShares = append(decShares, decShare)
+		}
+		if len(decShares) < int(op.quorum()) {
+			op.log.Debugf("not enough decryption shares of the request %s: %d", req.reqId.Short(), len(decShares))
+			continue
+		}
+		if err := op.recoverUnsealKey(req, decShares); err != nil {
+			op.log.Errorf("recoverUnsealKey: request %s: %v", req.reqId.Short(), err)
+			continue
+		}
+		ret = append(ret, req)
+	}
+	return ret
+}
+
 // filterOutRequestsWithoutTokens leaves only those first requests
 // which has corresponding request tokens.
 func (op *operator) filterOutRequestsWithoutTokens(reqs []*request) []*request {
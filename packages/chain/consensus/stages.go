@@ -6,6 +6,8 @@ package consensus
 import (
 	"fmt"
 	"time"
+
+	"github.com/iotaledger/wasp/packages/metrics"
 )
 
 // consensus goes through stages on the leader and on the subordinate side
@@ -30,6 +32,12 @@ const (
 	consensusStageSubResultFinalized
 )
 
+// vmCalculationsTimeout is the time for the VM to calculate the batch before the leader is rotated
+const vmCalculationsTimeout = 5 * time.Minute
+
+// DefaultPeerTimeout is the time the node waits for the peers in the consensus before the leader is rotated
+const DefaultPeerTimeout = 30 * time.Second
+
 type stageParams struct {
 	name               string
 	isLeaderState      bool          // can be leader stage
@@ -67,9 +75,9 @@ var stages = map[int]*stageParams{
 			consensusStageLeaderCalculationsStarted,
 		},
 	},
-	// unlimited time for VM TODO VM timeouts/gas budget to prevent loops
+	// the VM is bounded by the gas budgets of the requests. The calculations which take longer were failed by the VM watchdog
 	consensusStageLeaderCalculationsStarted: {"LeaderCalculationsStarted",
-		true, false, false, 0,
+		true, false, true, vmCalculationsTimeout,
 		[]int{
 			consensusStageNoSync,
 			consensusStageLeaderStarting,
@@ -79,7 +87,7 @@ var stages = map[int]*stageParams{
 	},
 	// 30 sec for subordinates sent their signatures and finalize the result
 	consensusStageLeaderCalculationsFinished: {"LeaderCalculationsFinished",
-		true, false, true, 30 * time.Second,
+		true, false, true, DefaultPeerTimeout,
 		[]int{
 			consensusStageNoSync,
 			consensusStageLeaderStarting,
@@ -112,7 +120,7 @@ var stages = map[int]*stageParams{
 	},
 	// 30 seconds for the leader to collect notifications and send back batch of requests
 	consensusStageSubNotificationsSent: {"SubNotificationsSent",
-		false, true, true, 30 * time.Second,
+		false, true, true, DefaultPeerTimeout,
 		[]int{
 			consensusStageNoSync,
 			consensusStageSubStarting,
@@ -121,9 +129,9 @@ var stages = map[int]*stageParams{
 			consensusStageSubResultFinalized,
 		},
 	},
-	// unlimited time for VM TODO VM timeouts/gas budget to prevent loops
+	// the VM is bounded by the gas budgets of the requests. The calculations which take longer were failed by the VM watchdog
 	consensusStageSubCalculationsStarted: {"SubCalculationsStarted",
-		false, true, false, 0,
+		false, true, true, vmCalculationsTimeout,
 		[]int{
 			consensusStageNoSync,
 			consensusStageSubStarting,
@@ -134,7 +142,7 @@ var stages = map[int]*stageParams{
 	},
 	// 30 sec for the leader to finalize the result
 	consensusStageSubCalculationsFinished: {"SubCalculationsFinished",
-		false, true, true, 30 * time.Second,
+		false, true, true, DefaultPeerTimeout,
 		[]int{
 			consensusStageNoSync,
 			consensusStageSubStarting,
@@ -175,6 +183,21 @@ func (op *operator) mustNextStageParams(stage int) *stageParams {
 	return ret
 }
 
+// SetPeerTimeout sets the time the node waits for the peers in the consensus before the leader is rotated.
+// It applies to all chains of the process and must be called before the chains are activated
+func SetPeerTimeout(d time.Duration) {
+	if d <= 0 {
+		d = DefaultPeerTimeout
+	}
+	for _, st := range []int{
+		consensusStageLeaderCalculationsFinished,
+		consensusStageSubNotificationsSent,
+		consensusStageSubCalculationsFinished,
+	} {
+		stages[st].timeout = d
+	}
+}
+
 // setNextConsensusStage validates and sets the next stage, including timeout
 func (op *operator) setNextConsensusStage(nextStage int) {
 	currentStageParams := op.mustStageParams(op.consensusStage)
@@ -186,8 +209,13 @@ func (op *operator) setNextConsensusStage(nextStage int) {
 			stages[op.consensusStage].name, nextStageParams.name, leader, op.iAmCurrentLeader())
 	}
 	saveStage := op.consensusStage
+	now := time.Now()
+	if !op.consensusStageStarted.IsZero() {
+		metrics.ObserveConsensusStage(op.chain.ID(), stages[saveStage].name, now.Sub(op.consensusStageStarted))
+	}
 	op.consensusStage = nextStage
-	op.consensusStageDeadline = time.Now().Add(nextStageParams.timeout)
+	op.consensusStageStarted = now
+	op.consensusStageDeadline = now.Add(nextStageParams.timeout)
 	timeout := "timeout: not set"
 	if nextStageParams.timeoutSet {
 		timeout = fmt.Sprintf("timeout: %v", nextStageParams.timeout)
@@ -9,22 +9,30 @@ import (
 
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
 	valuetransaction "github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
+	"github.com/iotaledger/hive.go/kvstore"
 	"github.com/iotaledger/hive.go/logger"
 	"github.com/iotaledger/wasp/packages/chain"
+	"github.com/iotaledger/wasp/packages/chain/mempool"
 	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv"
 	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/subrealm"
 	"github.com/iotaledger/wasp/packages/sctransaction"
 	"github.com/iotaledger/wasp/packages/state"
 	"github.com/iotaledger/wasp/packages/tcrypto"
 	"github.com/iotaledger/wasp/packages/tcrypto/tbdn"
 	"github.com/iotaledger/wasp/packages/util"
 	"github.com/iotaledger/wasp/packages/vm/core/accounts"
+	"github.com/iotaledger/wasp/packages/vm/core/root"
 	"github.com/iotaledger/wasp/packages/vm/vmconst"
 )
 
 type operator struct {
-	chain chain.Chain
+	chain    chain.Chain
+	nodeConn chain.NodeConnection
+	// partition of the chain in the database
+	db kvstore.KVStore
 
 	dkshare *tcrypto.DKShare
 	//currentState
@@ -35,6 +43,7 @@ type operator struct {
 	// consensus stage
 	consensusStage         int
 	consensusStageDeadline time.Time
+	consensusStageStarted  time.Time
 	//
 	requestBalancesDeadline time.Time
 
@@ -58,6 +67,7 @@ type operator struct {
 	// data for concurrent access, from APIs mostly
 	concurrentAccessMutex sync.RWMutex
 	requestIdsProtected   map[coretypes.RequestID]bool
+	backlogProtected      []*mempool.Entry
 
 	// Channels for accepting external events.
 	eventStateTransitionMsgCh           chan *chain.StateTransitionMsg
@@ -101,15 +111,26 @@ type request struct {
 	whenMsgReceived time.Time
 	// notification vector for the current currentState
 	notifications []bool
+	// decryption shares of the sealed args, received from peers by the peer index. Verified when used
+	decryptionShares [][]byte
+	// own decryption share of the sealed args. Nil if args of the request are not sealed
+	ownDecryptionShare []byte
+	// true if ownDecryptionShare has been computed
+	ownDecryptionShareDone bool
+	// quorum of valid decryption shares and the key of the sealed args, recovered from them. Nil until recovered
+	unsealShares [][]byte
+	unsealKey    []byte
 
 	log *logger.Logger
 }
 
-func NewOperator(committee chain.Chain, dkshare *tcrypto.DKShare, log *logger.Logger) *operator {
+func NewOperator(committee chain.Chain, dkshare *tcrypto.DKShare, nodeConn chain.NodeConnection, db kvstore.KVStore, log *logger.Logger) *operator {
 	defer committee.SetReadyConsensus()
 
 	ret := &operator{
 		chain:                               committee,
+		nodeConn:                            nodeConn,
+		db:                                  db,
 		dkshare:                             dkshare,
 		requests:                            make(map[coretypes.RequestID]*request),
 		requestIdsProtected:                 make(map[coretypes.RequestID]bool),
@@ -231,3 +252,26 @@ func (op *operator) getMinimumReward() int64 {
 	}
 	return vt
 }
+
+// rootState returns the partition of the root contract in the current state
+func (op *operator) rootState() kv.KVStoreReader {
+	return subrealm.New(op.currentState.Variables(), kv.Key(root.Interface.Hname().Bytes()))
+}
+
+func (op *operator) getFeeColor() balance.Color {
+	if _, ok := op.blockIndex(); !ok {
+		return balance.ColorIOTA
+	}
+	feeColor, _, _, err := root.GetDefaultFeeInfo(op.rootState())
+	if err != nil {
+		panic(err)
+	}
+	return feeColor
+}
+
+func (op *operator) getBlockLimits() *root.BlockLimits {
+	if _, ok := op.blockIndex(); !ok {
+		return &root.BlockLimits{}
+	}
+	return root.GetBlockLimits(op.rootState())
+}
@@ -34,4 +34,8 @@ const (
 
 	// maximum time difference allowed between leader and local clocks for consensus
 	MaxClockDifferenceAllowed = 3 * time.Second
+
+	// the forwarder of the previous address of the chain repeats its signature share of the forwarding transaction
+	// to the peers, so each of them can recover the signature of the transaction
+	ForwardSigShareResendPeriod = 2 * time.Second
 )
@@ -0,0 +1,75 @@
+// Package mempool implements the policy of selecting requests from the backlog of the chain into the next block
+package mempool
+
+import (
+	"bytes"
+	"sort"
+	"time"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/sctransaction"
+	"github.com/iotaledger/wasp/packages/vm/core/root"
+)
+
+// Entry is the request waiting in the backlog
+type Entry struct {
+	RequestID coretypes.RequestID
+	Sender    coretypes.AgentID
+	// tokens of the fee color attached to the request
+	Fee int64
+	// time when the request was received by the node
+	Arrival time.Time
+	// hash of the off-tangle args the request commits to, nil if args of the request are on-tangle
+	OffTangleArgsHash *hashing.HashValue
+}
+
+// NewEntry creates the backlog entry of the request
+func NewEntry(ref *sctransaction.RequestRef, feeColor balance.Color, arrival time.Time) *Entry {
+	ret := &Entry{
+		RequestID: *ref.RequestID(),
+		Sender:    ref.SenderAgentID(),
+		Fee:       ref.RequestSection().Transfer().Balance(feeColor),
+		Arrival:   arrival,
+	}
+	if h, err := ref.RequestSection().OffTangleArgsHash(); err == nil {
+		ret.OffTangleArgsHash = &h
+	}
+	return ret
+}
+
+// SortByPriority orders the entries by the attached fee, highest first, then by the arrival time, earliest first
+func SortByPriority(entries []*Entry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Fee != entries[j].Fee {
+			return entries[i].Fee > entries[j].Fee
+		}
+		if !entries[i].Arrival.Equal(entries[j].Arrival) {
+			return entries[i].Arrival.Before(entries[j].Arrival)
+		}
+		return bytes.Compare(entries[i].RequestID[:], entries[j].RequestID[:]) < 0
+	})
+}
+
+// Select takes the entries, already sorted by priority, while they fit into the limits of the block.
+// An entry over the limit of its sender is skipped, so that requests of other senders can take its place.
+// 'accept' is an additional criterion of the caller, it is only called for entries within the limits. May be nil
+func Select(entries []*Entry, limits *root.BlockLimits, accept func(*Entry) bool) []*Entry {
+	ret := make([]*Entry, 0, len(entries))
+	perSender := make(map[coretypes.AgentID]int64)
+	for _, e := range entries {
+		if limits.MaxRequests > 0 && int64(len(ret)) >= limits.MaxRequests {
+			break
+		}
+		if limits.MaxRequestsPerSender > 0 && perSender[e.Sender] >= limits.MaxRequestsPerSender {
+			continue
+		}
+		if accept != nil && !accept(e) {
+			continue
+		}
+		perSender[e.Sender]++
+		ret = append(ret, e)
+	}
+	return ret
+}
@@ -0,0 +1,61 @@
+package mempool
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/vm/core/root"
+	"github.com/stretchr/testify/require"
+)
+
+func newEntry(n byte, sender byte, fee int64, arrival int) *Entry {
+	return &Entry{
+		RequestID: coretypes.RequestID{n},
+		Sender:    coretypes.AgentID{sender},
+		Fee:       fee,
+		Arrival:   time.Unix(int64(arrival), 0),
+	}
+}
+
+func ids(entries []*Entry) []byte {
+	ret := make([]byte, len(entries))
+	for i, e := range entries {
+		ret[i] = e.RequestID[0]
+	}
+	return ret
+}
+
+func TestSortByPriority(t *testing.T) {
+	entries := []*Entry{
+		newEntry(1, 1, 0, 1),
+		newEntry(2, 1, 5, 3),
+		newEntry(3, 2, 5, 2),
+		newEntry(4, 2, 0, 0),
+		newEntry(5, 3, 10, 4),
+		newEntry(6, 3, 0, 0),
+	}
+	SortByPriority(entries)
+	require.EqualValues(t, []byte{5, 3, 2, 4, 6, 1}, ids(entries))
+}
+
+func TestSelect(t *testing.T) {
+	entries := []*Entry{
+		newEntry(1, 1, 10, 0),
+		newEntry(2, 1, 10, 1),
+		newEntry(3, 1, 10, 2),
+		newEntry(4, 2, 5, 3),
+		newEntry(5, 3, 0, 4),
+	}
+	require.EqualValues(t, []byte{1, 2, 3, 4, 5}, ids(Select(entries, &root.BlockLimits{}, nil)))
+	require.EqualValues(t, []byte{1, 2, 3}, ids(Select(entries, &root.BlockLimits{MaxRequests: 3}, nil)))
+	require.EqualValues(t, []byte{1, 2, 4, 5}, ids(Select(entries, &root.BlockLimits{MaxRequestsPerSender: 2}, nil)))
+	require.EqualValues(t, []byte{1, 4, 5}, ids(Select(entries, &root.BlockLimits{MaxRequests: 3, MaxRequestsPerSender: 1}, nil)))
+
+	// rejected entries don't count towards the limits
+	accept := func(e *Entry) bool {
+		return e.RequestID[0] != 1
+	}
+	require.EqualValues(t, []byte{2, 3, 4}, ids(Select(entries, &root.BlockLimits{MaxRequests: 3}, accept)))
+	require.EqualValues(t, []byte{2, 4, 5}, ids(Select(entries, &root.BlockLimits{MaxRequestsPerSender: 1}, accept)))
+}
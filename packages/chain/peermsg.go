@@ -9,6 +9,7 @@ import (
 
 	"github.com/iotaledger/goshimmer/dapps/waspconn/packages/waspconn"
 	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/iotaledger/wasp/packages/state"
 	"github.com/iotaledger/wasp/packages/util"
 )
@@ -39,6 +40,15 @@ func (msg *NotifyReqMsg) Write(w io.Writer) error {
 			return err
 		}
 	}
+	for i := range msg.RequestIDs {
+		var decShare []byte
+		if i < len(msg.DecryptionShares) {
+			decShare = msg.DecryptionShares[i]
+		}
+		if err := util.WriteBytes16(w, decShare); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -62,6 +72,12 @@ func (msg *NotifyReqMsg) Read(r io.Reader) error {
 			return err
 		}
 	}
+	msg.DecryptionShares = make([][]byte, arrLen)
+	for i := range msg.DecryptionShares {
+		if msg.DecryptionShares[i], err = util.ReadBytes16(r); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -104,6 +120,20 @@ func (msg *StartProcessingBatchMsg) Write(w io.Writer) error {
 	if err := waspconn.WriteBalances(w, msg.Balances); err != nil {
 		return err
 	}
+	for i := range msg.RequestIds {
+		var decShares [][]byte
+		if i < len(msg.DecryptionShares) {
+			decShares = msg.DecryptionShares[i]
+		}
+		if err := util.WriteUint16(w, uint16(len(decShares))); err != nil {
+			return err
+		}
+		for _, decShare := range decShares {
+			if err := util.WriteBytes16(w, decShare); err != nil {
+				return err
+			}
+		}
+	}
 	return nil
 }
 
@@ -128,6 +158,19 @@ func (msg *StartProcessingBatchMsg) Read(r io.Reader) error {
 	if msg.Balances, err = waspconn.ReadBalances(r); err != nil {
 		return err
 	}
+	msg.DecryptionShares = make([][][]byte, size)
+	for i := range msg.DecryptionShares {
+		var n uint16
+		if err := util.ReadUint16(r, &n); err != nil {
+			return err
+		}
+		msg.DecryptionShares[i] = make([][]byte, n)
+		for j := range msg.DecryptionShares[i] {
+			if msg.DecryptionShares[i][j], err = util.ReadBytes16(r); err != nil {
+				return err
+			}
+		}
+	}
 	return nil
 }
 
@@ -285,3 +328,33 @@ func (msg *TestTraceMsg) Read(r io.Reader) error {
 	}
 	return nil
 }
+
+func (msg *OffTangleArgsMsg) Write(w io.Writer) error {
+	return msg.Args.Write(w)
+}
+
+func (msg *OffTangleArgsMsg) Read(r io.Reader) error {
+	msg.Args = dict.New()
+	return msg.Args.Read(r)
+}
+
+func (msg *ForwardSigShareMsg) Write(w io.Writer) error {
+	if _, err := w.Write(msg.EssenceHash[:]); err != nil {
+		return err
+	}
+	if err := util.WriteBytes16(w, msg.SigShare); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (msg *ForwardSigShareMsg) Read(r io.Reader) error {
+	if err := util.ReadHashValue(r, &msg.EssenceHash); err != nil {
+		return err
+	}
+	var err error
+	if msg.SigShare, err = util.ReadBytes16(r); err != nil {
+		return err
+	}
+	return nil
+}
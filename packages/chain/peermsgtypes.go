@@ -8,6 +8,7 @@ import (
 	valuetransaction "github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
 	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/iotaledger/wasp/packages/peering"
 	"github.com/iotaledger/wasp/packages/sctransaction"
 	"github.com/iotaledger/wasp/packages/state"
@@ -26,6 +27,8 @@ const (
 	MsgStateUpdate             = 6 + peering.FirstUserMsgCode
 	MsgBatchHeader             = 7 + peering.FirstUserMsgCode
 	MsgTestTrace               = 8 + peering.FirstUserMsgCode
+	MsgOffTangleArgs           = 9 + peering.FirstUserMsgCode
+	MsgForwardSigShare         = 10 + peering.FirstUserMsgCode
 )
 
 type TimerTick int
@@ -51,6 +54,9 @@ type NotifyReqMsg struct {
 	PeerMsgHeader
 	// list of request ids ordered by the time of arrival
 	RequestIDs []coretypes.RequestID
+	// decryption shares of the sender for the requests with sealed args, in the order of RequestIDs.
+	// Empty for requests with args not sealed
+	DecryptionShares [][]byte
 }
 
 // message is sent by the leader to all peers immediately after the final transaction is posted
@@ -75,6 +81,9 @@ type StartProcessingBatchMsg struct {
 	FeeDestination coretypes.AgentID
 	// balances/outputs
 	Balances map[valuetransaction.ID][]*balance.Balance
+	// quorum of decryption shares for each request with sealed args, in the order of RequestIds.
+	// Empty for requests with args not sealed
+	DecryptionShares [][][]byte
 }
 
 // after calculations the result peer responds to the start processing msg
@@ -124,6 +133,23 @@ type TestTraceMsg struct {
 	NumHops       uint16
 }
 
+// off-tangle args of requests, gossiped among committee nodes.
+// Is also sent internally by the web API when args are uploaded to the node
+type OffTangleArgsMsg struct {
+	PeerMsgHeader
+	Args dict.Dict
+}
+
+// signature share of the transaction which forwards the tokens from the previous address of the chain
+// to the current one. Exchanged among the nodes of the previous committee
+type ForwardSigShareMsg struct {
+	PeerMsgHeader
+	// hash of the signed data (essence)
+	EssenceHash hashing.HashValue
+	// signature
+	SigShare tbdn.SigShare
+}
+
 // state manager notifies consensus operator about changed state
 // only sent internally within committee
 // state transition is always from state N to state N+1
@@ -142,6 +168,8 @@ type StateTransitionMsg struct {
 // - state manager to itself when batch is completed after syncing
 type PendingBlockMsg struct {
 	Block state.Block
+	// results of the requests of the block. Nil if the node did not run the block on the VM
+	Results []vm.RequestResult
 }
 
 // message is sent to the consensus manager after it receives state transaction
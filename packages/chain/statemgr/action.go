@@ -10,11 +10,14 @@ import (
 
 	valuetransaction "github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
 	"github.com/iotaledger/wasp/packages/chain"
+	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/metrics"
 	"github.com/iotaledger/wasp/packages/publisher"
 	"github.com/iotaledger/wasp/packages/state"
 	"github.com/iotaledger/wasp/packages/util"
-	"github.com/iotaledger/wasp/plugins/nodeconn"
+	"github.com/iotaledger/wasp/packages/vm"
+	"github.com/iotaledger/wasp/packages/vm/core/root"
 )
 
 func (sm *stateManager) takeAction() {
@@ -145,6 +148,8 @@ func (sm *stateManager) checkStateApproval() bool {
 	sm.syncMessageDeadline = time.Now() // if not synced then immediately
 	sm.consensusNotifiedOnStateTransition = false
 
+	metrics.SetStateIndex(sm.chain.ID(), sm.solidState.BlockIndex())
+
 	// publish state transition
 	publisher.Publish("state",
 		sm.chain.ID().String(),
@@ -154,11 +159,34 @@ func (sm *stateManager) checkStateApproval() bool {
 		varStateHash.String(),
 		fmt.Sprintf("%d", pending.block.Timestamp()),
 	)
+	publisher.PublishChainEvent(&publisher.StateTransition{
+		ChainID:    *sm.chain.ID(),
+		BlockIndex: sm.solidState.BlockIndex(),
+		BlockSize:  pending.block.Size(),
+		StateTxID:  sm.approvingTransaction.ID(),
+		StateHash:  varStateHash,
+		Timestamp:  pending.block.Timestamp(),
+	})
 	// publish processed requests
 	for i, reqid := range pending.block.RequestIDs() {
 
 		sm.chain.EventRequestProcessed().Trigger(*reqid)
 
+		ev := &publisher.RequestCompleted{
+			ChainID:    *sm.chain.ID(),
+			RequestID:  *reqid,
+			BlockIndex: sm.solidState.BlockIndex(),
+		}
+		if res, ok := pending.results[*reqid]; ok {
+			ev.Result = res.Result
+			ev.Error = res.Error
+			// the events of the contracts are published only when the block with the request is committed
+			for _, cev := range res.Events {
+				publisher.PublishChainEvent(cev)
+			}
+		}
+		publisher.PublishChainEvent(ev)
+
 		publisher.Publish("request_out",
 			sm.chain.ID().String(),
 			reqid.TransactionID().String(),
@@ -168,9 +196,26 @@ func (sm *stateManager) checkStateApproval() bool {
 			strconv.Itoa(int(pending.block.Size())),
 		)
 	}
+	sm.dismissIfRotated()
 	return true
 }
 
+// dismissIfRotated dismisses the committee if the chain was handed over to another committee
+// by the last state transition. The anchor transaction of such a state moves the chain to the new address,
+// so the committee can't continue the chain anymore. The committee still controls the previous address of the chain,
+// so it is notified to forward the tokens sent to that address
+func (sm *stateManager) dismissIfRotated() {
+	addr, ok, err := root.GetChainAddress(sm.solidState.Variables())
+	if err != nil || !ok || addr == sm.chain.Address() {
+		return
+	}
+	sm.log.Infof("COMMITTEE ROTATED: the chain is moved to the address %s at state #%d. Dismiss the committee",
+		addr.String(), sm.solidState.BlockIndex())
+	prevAddress := sm.chain.Address()
+	sm.chain.Dismiss()
+	sm.chain.EventRotated().Trigger(prevAddress)
+}
+
 func (sm *stateManager) requestStateUpdateFromPeerIfNeeded() {
 	if !sm.solidStateValid || sm.isSynchronized() {
 		// no need for more info when state is synced or solid state still needs validation by the anchor tx
@@ -234,7 +279,7 @@ func (sm *stateManager) isSynchronized() bool {
 var niltxid valuetransaction.ID
 
 // adding block of state updates to the 'pending' map
-func (sm *stateManager) addPendingBlock(block state.Block) bool {
+func (sm *stateManager) addPendingBlock(block state.Block, results []vm.RequestResult) bool {
 	sm.log.Debugw("addPendingBlock",
 		"block index", block.StateIndex(),
 		"timestamp", block.Timestamp(),
@@ -289,6 +334,12 @@ func (sm *stateManager) addPendingBlock(block state.Block) bool {
 		}
 		sm.pendingBlocks[*vh] = pb
 	}
+	if pb.results == nil && results != nil {
+		pb.results = make(map[coretypes.RequestID]*vm.RequestResult)
+		for i := range results {
+			pb.results[results[i].RequestID] = &results[i]
+		}
+	}
 
 	sm.log.Debugw("added new pending block",
 		"state index", pb.block.StateIndex(),
@@ -304,7 +355,7 @@ func (sm *stateManager) addPendingBlock(block state.Block) bool {
 
 func (sm *stateManager) createStateToApprove() state.VirtualState {
 	if sm.solidState == nil {
-		return state.NewEmptyVirtualState(sm.chain.ID())
+		return state.NewVirtualState(sm.db, sm.chain.ID())
 	}
 	return sm.solidState.Clone()
 }
@@ -324,7 +375,7 @@ func (sm *stateManager) requestStateTransactionIfNeeded() {
 func (sm *stateManager) requestStateTransaction(pb *pendingBlock) {
 	txid := pb.block.StateTransactionID()
 	sm.log.Debugf("query transaction from the node. txid = %s", txid.String())
-	_ = nodeconn.RequestConfirmedTransactionFromNode(&txid)
+	_ = sm.nodeConn.RequestConfirmedTransaction(&txid)
 	pb.stateTransactionRequestDeadline = time.Now().Add(chain.StateTransactionRequestTimeout)
 }
 
@@ -342,8 +393,11 @@ func (sm *stateManager) numPongsHasQuorum() bool {
 	return sm.numPongs() >= sm.chain.Quorum()-1
 }
 
+// pingPongReceived records the pong. Pings from access peers are answered, but not counted
 func (sm *stateManager) pingPongReceived(senderIndex uint16) {
-	sm.pingPong[senderIndex] = true
+	if int(senderIndex) < len(sm.pingPong) {
+		sm.pingPong[senderIndex] = true
+	}
 }
 
 func (sm *stateManager) respondPongToPeer(targetPeerIndex uint16) {
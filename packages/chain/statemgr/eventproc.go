@@ -42,7 +42,7 @@ func (sm *stateManager) eventGetBlockMsg(msg *chain.GetBlockMsg) {
 		"sender index", msg.SenderIndex,
 		"block index", msg.BlockIndex,
 	)
-	block, err := state.LoadBlock(sm.chain.ID(), msg.BlockIndex)
+	block, err := state.LoadBlockFromDB(sm.db, msg.BlockIndex)
 	if err != nil || block == nil {
 		// can't load block, can't respond
 		return
@@ -220,7 +220,7 @@ func (sm *stateManager) eventPendingBlockMsg(msg chain.PendingBlockMsg) {
 		"ts", msg.Block.Timestamp(),
 	)
 
-	sm.addPendingBlock(msg.Block)
+	sm.addPendingBlock(msg.Block, msg.Results)
 	sm.takeAction()
 }
 
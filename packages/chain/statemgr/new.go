@@ -9,16 +9,22 @@ import (
 	"time"
 
 	valuetransaction "github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
+	"github.com/iotaledger/hive.go/kvstore"
 	"github.com/iotaledger/hive.go/logger"
 	"github.com/iotaledger/wasp/packages/chain"
+	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/hashing"
 	"github.com/iotaledger/wasp/packages/sctransaction"
 	"github.com/iotaledger/wasp/packages/state"
 	"github.com/iotaledger/wasp/packages/util"
+	"github.com/iotaledger/wasp/packages/vm"
 )
 
 type stateManager struct {
-	chain chain.Chain
+	chain    chain.Chain
+	nodeConn chain.NodeConnection
+	// partition of the chain in the database
+	db kvstore.KVStore
 
 	// becomes true after initially loaded state is validated.
 	// after that it is always true
@@ -91,14 +97,18 @@ type pendingBlock struct {
 	nextState state.VirtualState
 	// state transaction request deadline. For committed batches only
 	stateTransactionRequestDeadline time.Time
+	// results of the requests of the block by request ID. Nil if the node did not run the block on the VM
+	results map[coretypes.RequestID]*vm.RequestResult
 }
 
-func New(c chain.Chain, log *logger.Logger) chain.StateManager {
+func New(c chain.Chain, nodeConn chain.NodeConnection, db kvstore.KVStore, log *logger.Logger) chain.StateManager {
 	ret := &stateManager{
 		chain:                        c,
+		nodeConn:                     nodeConn,
+		db:                           db,
 		pingPong:                     make([]bool, c.Size()),
 		pendingBlocks:                make(map[hashing.HashValue]*pendingBlock),
-		permutation:                  util.NewPermutation16(c.NumPeers(), nil),
+		permutation:                  util.NewPermutation16(c.Size(), nil),
 		log:                          log.Named("s"),
 		evidenceStateIndexCh:         make(chan uint32),
 		eventStateIndexPingPongMsgCh: make(chan *chain.StateIndexPingPongMsg),
@@ -125,7 +135,7 @@ func (sm *stateManager) initLoadState() {
 	var batch state.Block
 	var stateExists bool
 
-	sm.solidState, batch, stateExists, err = state.LoadSolidState(sm.chain.ID())
+	sm.solidState, batch, stateExists, err = state.LoadSolidStateFromDB(sm.db, sm.chain.ID())
 	if err != nil {
 		sm.log.Errorf("initLoadState: %v", err)
 		sm.chain.Dismiss()
@@ -134,7 +144,7 @@ func (sm *stateManager) initLoadState() {
 
 	if stateExists {
 		// state loaded, will be waiting for it to be confirmed from the tangle
-		sm.addPendingBlock(batch)
+		sm.addPendingBlock(batch, nil)
 		sm.largestEvidencedStateIndex = sm.solidState.BlockIndex()
 
 		h := sm.solidState.Hash()
@@ -147,7 +157,7 @@ func (sm *stateManager) initLoadState() {
 	} else {
 		// pre-origin state. Origin block is empty block.
 		// Will be waiting for the origin transaction to arrive
-		sm.addPendingBlock(state.MustNewOriginBlock(sm.chain.Color()))
+		sm.addPendingBlock(state.MustNewOriginBlock(sm.chain.Color()), nil)
 
 		sm.log.Info("solid state does not exist: WAITING FOR THE ORIGIN TRANSACTION")
 	}
@@ -14,6 +14,7 @@ func callView(chain chain.Chain, hname coretypes.Hname, fname string, params dic
 	if err != nil {
 		return nil, fmt.Errorf(fmt.Sprintf("Failed to create context: %v", err))
 	}
+	defer vctx.Release()
 
 	ret, err := vctx.CallView(hname, coretypes.Hn(fname), params)
 	if err != nil {
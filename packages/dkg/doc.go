@@ -8,6 +8,8 @@
 //
 // Implementation is based on <https://github.com/dedis/kyber/blob/master/share/dkg/rabin/dkg.go>
 // which is based on <https://link.springer.com/article/10.1007/s00145-006-0347-3>.
+//
+// Only the peers trusted by the node (see peering.TrustedNetworkManager) can
+// initiate and participate in the DKG. A node rejects the initialization,
+// if the initiator or any of the participants is not trusted by it.
 package dkg
-
-// TODO: Only authenticated nodes can initiate (and participate in?) the DKG.
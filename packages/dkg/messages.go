@@ -19,9 +19,11 @@ import (
 	"github.com/iotaledger/wasp/packages/peering"
 	"github.com/iotaledger/wasp/packages/util"
 	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
 	"go.dedis.ch/kyber/v3/share"
 	rabin_dkg "go.dedis.ch/kyber/v3/share/dkg/rabin"
 	rabin_vss "go.dedis.ch/kyber/v3/share/vss/rabin"
+	"go.dedis.ch/kyber/v3/sign/bls"
 )
 
 const (
@@ -184,6 +186,7 @@ type initiatorInitMsg struct {
 	threshold    uint16
 	timeout      time.Duration
 	roundRetry   time.Duration
+	signature    []byte      // Signature of the initiator, see sign().
 	suite        kyber.Group // Transient, for un-marshaling only.
 }
 
@@ -201,6 +204,16 @@ func (m *initiatorInitMsg) Write(w io.Writer) error {
 	if err = util.WriteByte(w, m.step); err != nil {
 		return err
 	}
+	if err = m.writePayload(w); err != nil {
+		return err
+	}
+	if err = util.WriteBytes16(w, m.signature); err != nil {
+		return err
+	}
+	return nil
+}
+func (m *initiatorInitMsg) writePayload(w io.Writer) error {
+	var err error
 	if err = util.WriteString16(w, m.dkgRef); err != nil {
 		return err
 	}
@@ -268,8 +281,34 @@ func (m *initiatorInitMsg) Read(r io.Reader) error {
 		return err
 	}
 	m.roundRetry = time.Duration(roundRetryMS) * time.Millisecond
+	if m.signature, err = util.ReadBytes16(r); err != nil {
+		return err
+	}
 	return nil
 }
+
+// signedData binds the message to the DKG instance, i.e. the peering ID it is sent with.
+func (m *initiatorInitMsg) signedData(dkgID *coretypes.ChainID) []byte {
+	var buf bytes.Buffer
+	buf.Write(dkgID[:])
+	if err := m.writePayload(&buf); err != nil {
+		panic(err)
+	}
+	return buf.Bytes()
+}
+
+// sign signs the message with the key of the initiator. The peers don't rely
+// on the peering network to authenticate the initiator, they verify the signature.
+func (m *initiatorInitMsg) sign(dkgID *coretypes.ChainID, secKey kyber.Scalar, suite pairing.Suite) error {
+	var err error
+	m.signature, err = bls.Sign(suite, secKey, m.signedData(dkgID))
+	return err
+}
+
+// verify checks the signature of the initiator.
+func (m *initiatorInitMsg) verify(dkgID *coretypes.ChainID, suite pairing.Suite) error {
+	return bls.Verify(suite, m.initiatorPub, m.signedData(dkgID), m.signature)
+}
 func (m *initiatorInitMsg) fromBytes(buf []byte, group kyber.Group) error {
 	r := bytes.NewReader(buf)
 	m.suite = group
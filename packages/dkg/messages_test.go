@@ -0,0 +1,60 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package dkg
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/util/key"
+)
+
+// TestInitiatorInitMsgSignature checks, that the peers accept the DKG initialization
+// only if it is signed by the initiator for this particular DKG instance.
+func TestInitiatorInitMsgSignature(t *testing.T) {
+	suite := pairing.NewSuiteBn256()
+	initiator := key.NewKeyPair(suite)
+	dkgID := coretypes.NewRandomChainID()
+	newMsg := func() *initiatorInitMsg {
+		return &initiatorInitMsg{
+			dkgRef:       dkgID.String(),
+			peerNetIDs:   []string{"a", "b"},
+			peerPubs:     []kyber.Point{key.NewKeyPair(suite).Public, key.NewKeyPair(suite).Public},
+			initiatorPub: initiator.Public,
+			threshold:    2,
+			timeout:      time.Minute,
+			roundRetry:   time.Second,
+		}
+	}
+	decode := func(msg *initiatorInitMsg) *initiatorInitMsg {
+		ret := &initiatorInitMsg{}
+		require.NoError(t, ret.fromBytes(makePeerMessage(&dkgID, rabinStep0Initialize, msg).MsgData, suite))
+		return ret
+	}
+
+	msg := newMsg()
+	require.NoError(t, msg.sign(&dkgID, initiator.Private, suite))
+	require.NoError(t, decode(msg).verify(&dkgID, suite))
+
+	// the signed message sent for another DKG instance
+	otherID := coretypes.NewRandomChainID()
+	require.Error(t, decode(msg).verify(&otherID, suite))
+
+	// the message modified after it was signed
+	modified := decode(msg)
+	modified.threshold = 1
+	require.Error(t, modified.verify(&dkgID, suite))
+
+	// the message impersonating the initiator, signed by another key
+	forged := newMsg()
+	require.NoError(t, forged.sign(&dkgID, key.NewKeyPair(suite).Private, suite))
+	require.Error(t, decode(forged).verify(&dkgID, suite))
+
+	// the message without a signature
+	require.Error(t, decode(newMsg()).verify(&dkgID, suite))
+}
@@ -23,14 +23,15 @@ import (
 type Node struct {
 	secKey      kyber.Scalar
 	pubKey      kyber.Point
-	suite       Suite                    // Cryptography to use.
-	netProvider peering.NetworkProvider  // Network to communicate through.
-	registry    tcrypto.RegistryProvider // Where to store the generated keys.
-	processes   map[string]*proc         // Only for introspection.
-	procLock    *sync.RWMutex            // To guard access to the process pool.
-	recvQueue   chan *peering.RecvEvent  // Incoming events processed async.
-	recvStopCh  chan bool                // To coordinate shutdown.
-	attachID    interface{}              // Peering attach ID
+	suite       Suite                         // Cryptography to use.
+	netProvider peering.NetworkProvider       // Network to communicate through.
+	trusted     peering.TrustedNetworkManager // Only trusted peers can initiate and participate.
+	registry    tcrypto.RegistryProvider      // Where to store the generated keys.
+	processes   map[string]*proc              // Only for introspection.
+	procLock    *sync.RWMutex                 // To guard access to the process pool.
+	recvQueue   chan *peering.RecvEvent       // Incoming events processed async.
+	recvStopCh  chan bool                     // To coordinate shutdown.
+	attachID    interface{}                   // Peering attach ID
 	log         *logger.Logger
 }
 
@@ -41,6 +42,7 @@ func NewNode(
 	pubKey kyber.Point,
 	suite Suite,
 	netProvider peering.NetworkProvider,
+	trusted peering.TrustedNetworkManager,
 	registry tcrypto.RegistryProvider,
 	log *logger.Logger,
 ) *Node {
@@ -49,6 +51,7 @@ func NewNode(
 		pubKey:      pubKey,
 		suite:       suite,
 		netProvider: netProvider,
+		trusted:     trusted,
 		registry:    registry,
 		processes:   make(map[string]*proc),
 		procLock:    &sync.RWMutex{},
@@ -114,20 +117,29 @@ func (n *Node) GenerateDistributedKey(
 			peerPubs[i] = n.PubKey()
 		}
 	}
+	for i := range peerNetIDs {
+		if err = n.isTrustedPeer(peerPubs[i], peerNetIDs[i]); err != nil {
+			return nil, err
+		}
+	}
 	//
 	// Initialize the peers.
+	initMsg := &initiatorInitMsg{
+		dkgRef:       dkgID.String(), // It could be some other identifier.
+		peerNetIDs:   peerNetIDs,
+		peerPubs:     peerPubs,
+		initiatorPub: n.pubKey,
+		threshold:    threshold,
+		timeout:      timeout,
+		roundRetry:   roundRetry,
+	}
+	if err = initMsg.sign(&dkgID, n.secKey, n.suite); err != nil {
+		return nil, err
+	}
 	if err = n.exchangeInitiatorAcks(netGroup, netGroup.AllNodes(), recvCh, rTimeout, gTimeout, rabinStep0Initialize,
 		func(peerIdx uint16, peer peering.PeerSender) {
 			n.log.Debugf("Initiator sends step=%v command to %v", rabinStep0Initialize, peer.NetID())
-			peer.SendMsg(makePeerMessage(&dkgID, rabinStep0Initialize, &initiatorInitMsg{
-				dkgRef:       dkgID.String(), // It could be some other identifier.
-				peerNetIDs:   peerNetIDs,
-				peerPubs:     peerPubs,
-				initiatorPub: n.pubKey,
-				threshold:    threshold,
-				timeout:      timeout,
-				roundRetry:   roundRetry,
-			}))
+			peer.SendMsg(makePeerMessage(&dkgID, rabinStep0Initialize, initMsg))
 		},
 	); err != nil {
 		return nil, err
@@ -256,6 +268,14 @@ func (n *Node) onInitMsg(recv *peering.RecvEvent) {
 	req := initiatorInitMsg{}
 	if err = req.fromBytes(recv.Msg.MsgData, n.suite); err != nil {
 		n.log.Warnf("Dropping unknown message: %v", recv)
+		return
+	}
+	if err = n.checkTrustedInit(recv, &req); err != nil {
+		n.log.Warnf("Rejecting DKG %v initiated by %v, reason=%v", req.dkgRef, recv.From.NetID(), err)
+		recv.From.SendMsg(makePeerMessage(&recv.Msg.ChainID, req.step, &initiatorStatusMsg{
+			error: err,
+		}))
+		return
 	}
 	n.procLock.RLock()
 	if _, ok := n.processes[req.dkgRef]; ok {
@@ -282,6 +302,37 @@ func (n *Node) onInitMsg(recv *peering.RecvEvent) {
 	}()
 }
 
+// checkTrustedInit ensures, that the initiator and all the participants of the DKG are trusted.
+// The request must be signed by the initiator, the peering network doesn't authenticate the sender.
+func (n *Node) checkTrustedInit(recv *peering.RecvEvent, req *initiatorInitMsg) error {
+	if err := req.verify(&recv.Msg.ChainID, n.suite); err != nil {
+		return fmt.Errorf("invalid signature of the initiator %v: %v", recv.From.NetID(), err)
+	}
+	if !req.initiatorPub.Equal(recv.From.PubKey()) {
+		return fmt.Errorf("initiator pubKey %v does not match the key of the peer %v", req.initiatorPub, recv.From.NetID())
+	}
+	if err := n.isTrustedPeer(req.initiatorPub, recv.From.NetID()); err != nil {
+		return err
+	}
+	if len(req.peerPubs) != len(req.peerNetIDs) {
+		return fmt.Errorf("inconsistent peerNetIDs and peerPubs")
+	}
+	for i := range req.peerNetIDs {
+		if err := n.isTrustedPeer(req.peerPubs[i], req.peerNetIDs[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isTrustedPeer consults the trusted network manager. The node always trusts itself.
+func (n *Node) isTrustedPeer(pubKey kyber.Point, netID string) error {
+	if pubKey.Equal(n.pubKey) {
+		return nil
+	}
+	return n.trusted.IsTrustedPeer(pubKey, netID)
+}
+
 // Called by the DKG process on termination.
 func (n *Node) dropProcess(p *proc) bool {
 	n.procLock.Lock()
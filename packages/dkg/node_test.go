@@ -49,11 +49,12 @@ func TestBasic(t *testing.T) {
 	var networkProviders []peering.NetworkProvider = peeringNetwork.NetworkProviders()
 	//
 	// Initialize the DKG subsystem in each node.
+	var trusted = testutil.NewTrustedNetworkManager(peerNetIDs, peerPubs)
 	var dkgNodes []*dkg.Node = make([]*dkg.Node, len(peerNetIDs))
 	for i := range peerNetIDs {
 		registry := testutil.NewDkgRegistryProvider(suite)
 		dkgNodes[i] = dkg.NewNode(
-			peerSecs[i], peerPubs[i], suite, networkProviders[i], registry,
+			peerSecs[i], peerPubs[i], suite, networkProviders[i], trusted, registry,
 			testutil.WithLevel(log.With("NetID", peerNetIDs[i]), logger.LevelDebug, false),
 		)
 	}
@@ -100,11 +101,12 @@ func TestNoPubs(t *testing.T) {
 	var networkProviders []peering.NetworkProvider = peeringNetwork.NetworkProviders()
 	//
 	// Initialize the DKG subsystem in each node.
+	var trusted = testutil.NewTrustedNetworkManager(peerNetIDs, peerPubs)
 	var dkgNodes []*dkg.Node = make([]*dkg.Node, len(peerNetIDs))
 	for i := range peerNetIDs {
 		registry := testutil.NewDkgRegistryProvider(suite)
 		dkgNodes[i] = dkg.NewNode(
-			peerSecs[i], peerPubs[i], suite, networkProviders[i], registry,
+			peerSecs[i], peerPubs[i], suite, networkProviders[i], trusted, registry,
 			testutil.WithLevel(log.With("NetID", peerNetIDs[i]), logger.LevelDebug, false),
 		)
 	}
@@ -159,11 +161,12 @@ func TestUnreliableNet(t *testing.T) {
 	var networkProviders []peering.NetworkProvider = peeringNetwork.NetworkProviders()
 	//
 	// Initialize the DKG subsystem in each node.
+	var trusted = testutil.NewTrustedNetworkManager(peerNetIDs, peerPubs)
 	var dkgNodes []*dkg.Node = make([]*dkg.Node, len(peerNetIDs))
 	for i := range peerNetIDs {
 		registry := testutil.NewDkgRegistryProvider(suite)
 		dkgNodes[i] = dkg.NewNode(
-			peerSecs[i], peerPubs[i], suite, networkProviders[i], registry,
+			peerSecs[i], peerPubs[i], suite, networkProviders[i], trusted, registry,
 			testutil.WithLevel(log.With("NetID", peerNetIDs[i]), logger.LevelDebug, false),
 		)
 	}
@@ -210,11 +213,12 @@ func TestLowN(t *testing.T) {
 		var networkProviders []peering.NetworkProvider = peeringNetwork.NetworkProviders()
 		//
 		// Initialize the DKG subsystem in each node.
+		var trusted = testutil.NewTrustedNetworkManager(peerNetIDs, peerPubs)
 		var dkgNodes []*dkg.Node = make([]*dkg.Node, len(peerNetIDs))
 		for i := range peerNetIDs {
 			registry := testutil.NewDkgRegistryProvider(suite)
 			dkgNodes[i] = dkg.NewNode(
-				peerSecs[i], peerPubs[i], suite, networkProviders[i], registry,
+				peerSecs[i], peerPubs[i], suite, networkProviders[i], trusted, registry,
 				testutil.WithLevel(log.With("NetID", peerNetIDs[i]), logger.LevelDebug, false),
 			)
 		}
@@ -233,3 +237,84 @@ func TestLowN(t *testing.T) {
 		require.NotNil(t, dkShare.SharedPublic)
 	}
 }
+
+// TestUntrusted checks, if the DKG is rejected, when the initiator or a participant is not trusted.
+func TestUntrusted(t *testing.T) {
+	log := testutil.NewLogger(t)
+	defer log.Sync()
+	//
+	// Create a fake network and keys for the tests.
+	var timeout = 3 * time.Second
+	var threshold uint16 = 3
+	var peerCount uint16 = 4
+	var peerNetIDs []string = make([]string, peerCount)
+	var peerPubs []kyber.Point = make([]kyber.Point, len(peerNetIDs))
+	var peerSecs []kyber.Scalar = make([]kyber.Scalar, len(peerNetIDs))
+	var suite = pairing.NewSuiteBn256() // NOTE: That's from the Pairing Adapter.
+	for i := range peerNetIDs {
+		peerPair := key.NewKeyPair(suite)
+		peerNetIDs[i] = fmt.Sprintf("P%02d", i)
+		peerSecs[i] = peerPair.Private
+		peerPubs[i] = peerPair.Public
+	}
+	var peeringNetwork *testutil.PeeringNetwork = testutil.NewPeeringNetwork(
+		peerNetIDs, peerPubs, peerSecs, 10000,
+		testutil.NewPeeringNetReliable(),
+		testutil.WithLevel(log, logger.LevelWarn, false),
+	)
+	var networkProviders []peering.NetworkProvider = peeringNetwork.NetworkProviders()
+	//
+	// Initialize the DKG subsystem in each node, each node trusts all the peers.
+	var trusted []*testutil.TrustedNetworkManager = make([]*testutil.TrustedNetworkManager, len(peerNetIDs))
+	var dkgNodes []*dkg.Node = make([]*dkg.Node, len(peerNetIDs))
+	for i := range peerNetIDs {
+		registry := testutil.NewDkgRegistryProvider(suite)
+		trusted[i] = testutil.NewTrustedNetworkManager(peerNetIDs, peerPubs)
+		dkgNodes[i] = dkg.NewNode(
+			peerSecs[i], peerPubs[i], suite, networkProviders[i], trusted[i], registry,
+			testutil.WithLevel(log.With("NetID", peerNetIDs[i]), logger.LevelDebug, false),
+		)
+	}
+	generate := func(initiator int) error {
+		_, err := dkgNodes[initiator].GenerateDistributedKey(
+			peerNetIDs,
+			peerPubs,
+			threshold,
+			100*time.Millisecond,
+			500*time.Millisecond,
+			timeout,
+		)
+		return err
+	}
+	//
+	// The initiator does not trust one of the participants.
+	_, err := trusted[0].DistrustPeer(peerPubs[3])
+	require.Nil(t, err)
+	require.NotNil(t, generate(0))
+	_, err = trusted[0].TrustPeer(peerPubs[3], peerNetIDs[3])
+	require.Nil(t, err)
+	//
+	// A participant does not trust the initiator.
+	_, err = trusted[2].DistrustPeer(peerPubs[0])
+	require.Nil(t, err)
+	require.NotNil(t, generate(0))
+	_, err = trusted[2].TrustPeer(peerPubs[0], peerNetIDs[0])
+	require.Nil(t, err)
+	//
+	// A participant does not trust another participant.
+	_, err = trusted[2].DistrustPeer(peerPubs[3])
+	require.Nil(t, err)
+	require.NotNil(t, generate(0))
+	_, err = trusted[2].TrustPeer(peerPubs[3], peerNetIDs[3])
+	require.Nil(t, err)
+	//
+	// A participant trusts another participant under a different NetID.
+	_, err = trusted[3].TrustPeer(peerPubs[1], "other:4000")
+	require.Nil(t, err)
+	require.NotNil(t, generate(0))
+	_, err = trusted[3].TrustPeer(peerPubs[1], peerNetIDs[1])
+	require.Nil(t, err)
+	//
+	// All the peers are trusted again.
+	require.Nil(t, generate(0))
+}
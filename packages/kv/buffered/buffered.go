@@ -20,6 +20,10 @@ type BufferedKVStore interface {
 	ClearMutations()
 	Clone() BufferedKVStore
 
+	// SetReadCache sets the cache of DB reads. The cache is shared by the clones
+	SetReadCache(cache *ReadCache)
+	ReadCache() *ReadCache
+
 	// only for testing!
 	DangerouslyDumpToDict() dict.Dict
 	// only for testing!
@@ -46,6 +50,7 @@ func asDBError(e error) error {
 type bufferedKVStore struct {
 	db        kvstore.KVStore
 	mutations MutationSequence
+	cache     *ReadCache
 }
 
 func NewBufferedKVStore(db kvstore.KVStore) BufferedKVStore {
@@ -59,9 +64,18 @@ func (b *bufferedKVStore) Clone() BufferedKVStore {
 	return &bufferedKVStore{
 		db:        b.db,
 		mutations: b.mutations.Clone(),
+		cache:     b.cache,
 	}
 }
 
+func (b *bufferedKVStore) SetReadCache(cache *ReadCache) {
+	b.cache = cache
+}
+
+func (b *bufferedKVStore) ReadCache() *ReadCache {
+	return b.cache
+}
+
 func (b *bufferedKVStore) Mutations() MutationSequence {
 	return b.mutations
 }
@@ -122,16 +136,37 @@ func (b *bufferedKVStore) Del(key kv.Key) {
 	b.mutations.Add(NewMutationDel(key))
 }
 
+// DelPrefix records deletion of all keys with the prefix as a single mutation
+func (b *bufferedKVStore) DelPrefix(prefix kv.Key) {
+	b.mutations.Add(NewMutationDelPrefix(prefix))
+}
+
 func (b *bufferedKVStore) Get(key kv.Key) ([]byte, error) {
 	mut := b.mutations.Latest(key)
 	if mut != nil {
 		return mut.Value(), nil
 	}
+	return b.getFromDb(key)
+}
+
+// getFromDb reads the value through the read cache, if any
+func (b *bufferedKVStore) getFromDb(key kv.Key) ([]byte, error) {
+	if b.cache != nil {
+		if v, ok := b.cache.get(key); ok {
+			return v, nil
+		}
+	}
 	v, err := b.db.Get(kvstore.Key(key))
 	if err == kvstore.ErrKeyNotFound {
-		return nil, nil
+		v, err = nil, nil
 	}
-	return v, asDBError(err)
+	if err != nil {
+		return nil, asDBError(err)
+	}
+	if b.cache != nil {
+		b.cache.put(key, v)
+	}
+	return v, nil
 }
 
 func (b *bufferedKVStore) MustGet(key kv.Key) []byte {
@@ -143,6 +178,11 @@ func (b *bufferedKVStore) Has(key kv.Key) (bool, error) {
 	if mut != nil {
 		return mut.Value() != nil, nil
 	}
+	if b.cache != nil {
+		if v, ok := b.cache.get(key); ok {
+			return v != nil, nil
+		}
+	}
 	v, err := b.db.Has(kvstore.Key(key))
 	return v, asDBError(err)
 }
@@ -152,16 +192,20 @@ func (b *bufferedKVStore) MustHas(key kv.Key) bool {
 }
 
 func (b *bufferedKVStore) Iterate(prefix kv.Key, f func(key kv.Key, value []byte) bool) error {
-	seen, done := b.mutations.IterateValues(prefix, f)
+	done := b.mutations.IterateValues(prefix, f)
 	if done {
 		return nil
 	}
 	return b.db.Iterate([]byte(prefix), func(key kvstore.Key, value kvstore.Value) bool {
 		k := kv.Key(key)
-		_, ok := seen[k]
-		if ok {
+		if b.mutations.Latest(k) != nil {
+			// already seen or deleted
 			return true
 		}
+		if b.cache != nil {
+			// values of iterated collections are usually read again
+			b.cache.put(k, value)
+		}
 		return f(k, value)
 	})
 }
@@ -171,7 +215,7 @@ func (b *bufferedKVStore) MustIterate(prefix kv.Key, f func(key kv.Key, value []
 }
 
 func (b *bufferedKVStore) IterateKeys(prefix kv.Key, f func(key kv.Key) bool) error {
-	seen, done := b.mutations.IterateValues(prefix, func(key kv.Key, value []byte) bool {
+	done := b.mutations.IterateValues(prefix, func(key kv.Key, value []byte) bool {
 		return f(key)
 	})
 	if done {
@@ -179,8 +223,8 @@ func (b *bufferedKVStore) IterateKeys(prefix kv.Key, f func(key kv.Key) bool) er
 	}
 	return b.db.IterateKeys([]byte(prefix), func(key kvstore.Key) bool {
 		k := kv.Key(key)
-		_, ok := seen[k]
-		if ok {
+		if b.mutations.Latest(k) != nil {
+			// already seen or deleted
 			return true
 		}
 		return f(k)
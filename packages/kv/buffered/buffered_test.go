@@ -79,3 +79,42 @@ func TestBufferedKVStore(t *testing.T) {
 		m,
 	)
 }
+
+func TestBufferedKVStoreDelPrefix(t *testing.T) {
+	db := mapdb.NewMapDB()
+	_ = db.Set([]byte("ab1"), []byte("v1"))
+	_ = db.Set([]byte("ab2"), []byte("v2"))
+	_ = db.Set([]byte("cd"), []byte("v3"))
+
+	b := NewBufferedKVStore(db)
+	b.Set(kv.Key("ab3"), []byte("v4"))
+	b.DelPrefix(kv.Key("ab"))
+	b.Set(kv.Key("ab4"), []byte("v5"))
+
+	assert.False(t, b.MustHas(kv.Key("ab1")))
+	assert.False(t, b.MustHas(kv.Key("ab3")))
+	assert.Nil(t, b.MustGet(kv.Key("ab2")))
+	assert.Equal(t, []byte("v5"), b.MustGet(kv.Key("ab4")))
+	assert.Equal(t, []byte("v3"), b.MustGet(kv.Key("cd")))
+
+	keys := make([]kv.Key, 0)
+	b.MustIterateKeys(kv.EmptyPrefix, func(key kv.Key) bool {
+		keys = append(keys, key)
+		return true
+	})
+	assert.ElementsMatch(t, []kv.Key{"ab4", "cd"}, keys)
+
+	assert.EqualValues(
+		t,
+		map[kv.Key][]byte{
+			kv.Key("ab4"): []byte("v5"),
+			kv.Key("cd"):  []byte("v3"),
+		},
+		b.DangerouslyDumpToDict(),
+	)
+
+	// not committed to DB
+	v, err := db.Get([]byte("ab1"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("v1"), v)
+}
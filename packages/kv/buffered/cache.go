@@ -0,0 +1,80 @@
+package buffered
+
+import (
+	"sync"
+
+	"github.com/iotaledger/wasp/packages/kv"
+)
+
+// DefaultReadCacheSize is the default limit of the read cache in bytes
+const DefaultReadCacheSize = 16 * 1024 * 1024
+
+// ReadCache keeps values read from the DB by the BufferedKVStore, including absent keys.
+// It is only valid as long as the DB does not change, i.e. for the lifetime of one VM task
+// which holds the solid state of the chain (see state.AcquireSolidState).
+// When the cache is full, new values are not cached anymore
+type ReadCache struct {
+	mutex   sync.Mutex
+	values  map[kv.Key][]byte
+	size    int
+	maxSize int
+	hits    uint64
+	misses  uint64
+}
+
+// ReadCacheStats are counters of the read cache
+type ReadCacheStats struct {
+	Hits    uint64
+	Misses  uint64
+	Entries int
+	Size    int
+}
+
+// NewReadCache creates a cache which keeps at most maxSize bytes of keys and values
+func NewReadCache(maxSize int) *ReadCache {
+	return &ReadCache{
+		values:  make(map[kv.Key][]byte),
+		maxSize: maxSize,
+	}
+}
+
+// get returns the cached value and whether the key was found in the cache. nil value means the key is absent in the DB
+func (c *ReadCache) get(key kv.Key) ([]byte, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	v, ok := c.values[key]
+	if ok {
+		c.hits++
+	} else {
+		c.misses++
+	}
+	return v, ok
+}
+
+func (c *ReadCache) put(key kv.Key, value []byte) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if _, ok := c.values[key]; ok {
+		return
+	}
+	size := len(key) + len(value)
+	if c.size+size > c.maxSize {
+		return
+	}
+	c.values[key] = value
+	c.size += size
+}
+
+func (c *ReadCache) Stats() ReadCacheStats {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return ReadCacheStats{
+		Hits:    c.hits,
+		Misses:  c.misses,
+		Entries: len(c.values),
+		Size:    c.size,
+	}
+}
@@ -0,0 +1,72 @@
+package buffered
+
+import (
+	"testing"
+
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadCache(t *testing.T) {
+	db := mapdb.NewMapDB()
+	_ = db.Set([]byte("a"), []byte("v1"))
+	_ = db.Set([]byte("b"), []byte("v2"))
+
+	b := NewBufferedKVStore(db)
+	cache := NewReadCache(DefaultReadCacheSize)
+	b.SetReadCache(cache)
+
+	require.EqualValues(t, []byte("v1"), b.MustGet("a"))
+	require.Nil(t, b.MustGet("c"))
+	require.EqualValues(t, ReadCacheStats{Hits: 0, Misses: 2, Entries: 2, Size: 1 + 2 + 1}, cache.Stats())
+
+	// served from the cache, even if the DB has changed
+	_ = db.Set([]byte("a"), []byte("v3"))
+	_ = db.Set([]byte("c"), []byte("v4"))
+	require.EqualValues(t, []byte("v1"), b.MustGet("a"))
+	require.False(t, b.MustHas("c"))
+	require.EqualValues(t, 2, cache.Stats().Hits)
+
+	// mutations take precedence over the cache
+	b.Set("a", []byte("v5"))
+	require.EqualValues(t, []byte("v5"), b.MustGet("a"))
+
+	// the cache is shared by clones
+	require.Same(t, cache, b.Clone().ReadCache())
+}
+
+func TestReadCacheLimit(t *testing.T) {
+	db := mapdb.NewMapDB()
+	_ = db.Set([]byte("a"), []byte("v1"))
+	_ = db.Set([]byte("b"), []byte("v2"))
+
+	b := NewBufferedKVStore(db)
+	cache := NewReadCache(4)
+	b.SetReadCache(cache)
+
+	b.MustGet("a")
+	b.MustGet("b")
+	stats := cache.Stats()
+	require.EqualValues(t, 1, stats.Entries)
+	require.EqualValues(t, 3, stats.Size)
+
+	b.MustGet("b")
+	require.EqualValues(t, 0, cache.Stats().Hits)
+}
+
+func TestReadCacheIterate(t *testing.T) {
+	db := mapdb.NewMapDB()
+	_ = db.Set([]byte("a1"), []byte("v1"))
+	_ = db.Set([]byte("a2"), []byte("v2"))
+
+	b := NewBufferedKVStore(db)
+	cache := NewReadCache(DefaultReadCacheSize)
+	b.SetReadCache(cache)
+
+	b.MustIterate("a", func(key kv.Key, value []byte) bool {
+		return true
+	})
+	require.EqualValues(t, []byte("v2"), b.MustGet("a2"))
+	require.EqualValues(t, 1, cache.Stats().Hits)
+}
@@ -8,7 +8,7 @@ import (
 	"github.com/iotaledger/wasp/packages/util"
 )
 
-// Mutation represents a single "set" or "del" operation over a KVStore
+// Mutation represents a single "set", "del" or "del prefix" operation over a KVStore
 type Mutation interface {
 	Read(io.Reader) error
 	Write(io.Writer) error
@@ -17,7 +17,7 @@ type Mutation interface {
 
 	ApplyTo(w kv.KVStoreWriter)
 
-	// Key returns the key that is mutated
+	// Key returns the key that is mutated (the prefix for "del prefix")
 	Key() kv.Key
 	// Value returns the value after the mutation (nil if deleted)
 	Value() []byte
@@ -34,14 +34,21 @@ type MutationSequence interface {
 	Clone() MutationSequence
 
 	Len() int
+	// Size returns the total size of keys and values of all mutations
+	Size() int
 
 	// Iterate over all mutations in order, even ones affecting the same key repeatedly
 	Iterate(func(mut Mutation) bool)
-	// Iterate over the latest mutation recorded for each key
+	// Iterate over the latest mutation recorded for each key.
+	// Keys deleted by DelPrefix and not mutated after that are not included, see DelPrefixes
 	IterateLatest(func(key kv.Key, mut Mutation) bool)
-	// Iterate over the latest value recorded for each non-deleted key
-	IterateValues(prefix kv.Key, f func(key kv.Key, value []byte) bool) (map[kv.Key]bool, bool)
+	// Iterate over the latest value recorded for each non-deleted key. Returns true if interrupted by f
+	IterateValues(prefix kv.Key, f func(key kv.Key, value []byte) bool) bool
+	// DelPrefixes returns prefixes deleted by the sequence, in order
+	DelPrefixes() []kv.Key
 
+	// Latest returns the latest mutation of the key, including the "del prefix" which deleted it.
+	// Returns nil if the key is not mutated
 	Latest(key kv.Key) Mutation
 
 	Add(mut Mutation)
@@ -52,11 +59,14 @@ type MutationSequence interface {
 const (
 	mutationMagicSet = iota
 	mutationMagicDel
+	mutationMagicDelPrefix
 )
 
 type mutationSequence struct {
 	muts        []Mutation
 	latestByKey map[kv.Key]*Mutation
+	// "del prefix" mutations, in order. Keys mutated before the deletion are removed from latestByKey
+	delPrefixes []Mutation
 }
 
 func NewMutationSequence() MutationSequence {
@@ -131,27 +141,50 @@ func (ms *mutationSequence) IterateLatest(f func(kv.Key, Mutation) bool) {
 	}
 }
 
-func (ms *mutationSequence) IterateValues(prefix kv.Key, f func(key kv.Key, value []byte) bool) (map[kv.Key]bool, bool) {
-	seen := make(map[kv.Key]bool)
+func (ms *mutationSequence) IterateValues(prefix kv.Key, f func(key kv.Key, value []byte) bool) bool {
 	for key, mut := range ms.latestByKey {
 		if !key.HasPrefix(prefix) {
 			continue
 		}
-		seen[key] = true
 		v := (*mut).Value()
 		if v != nil && !f(key, v) {
-			return seen, true
+			return true
 		}
 	}
-	return seen, false
+	return false
+}
+
+func (ms *mutationSequence) DelPrefixes() []kv.Key {
+	ret := make([]kv.Key, len(ms.delPrefixes))
+	for i, mut := range ms.delPrefixes {
+		ret[i] = mut.Key()
+	}
+	return ret
 }
 
 func (ms *mutationSequence) Len() int {
 	return len(ms.muts)
 }
 
+func (ms *mutationSequence) Size() int {
+	ret := 0
+	for _, mut := range ms.muts {
+		ret += len(mut.Key()) + len(mut.Value())
+	}
+	return ret
+}
+
 func (ms *mutationSequence) Add(mut Mutation) {
 	ms.muts = append(ms.muts, mut)
+	if _, ok := mut.(*mutationDelPrefix); ok {
+		for key := range ms.latestByKey {
+			if key.HasPrefix(mut.Key()) {
+				delete(ms.latestByKey, key)
+			}
+		}
+		ms.delPrefixes = append(ms.delPrefixes, mut)
+		return
+	}
 	ms.latestByKey[mut.Key()] = &mut
 }
 
@@ -163,10 +196,15 @@ func (ms *mutationSequence) ApplyTo(w kv.KVStoreWriter) {
 
 func (ms *mutationSequence) Latest(key kv.Key) Mutation {
 	mut, ok := ms.latestByKey[key]
-	if !ok {
-		return nil
+	if ok {
+		return *mut
+	}
+	for i := len(ms.delPrefixes) - 1; i >= 0; i-- {
+		if key.HasPrefix(ms.delPrefixes[i].Key()) {
+			return ms.delPrefixes[i]
+		}
 	}
-	return *mut
+	return nil
 }
 
 func (ms *mutationSequence) Clone() MutationSequence {
@@ -174,7 +212,9 @@ func (ms *mutationSequence) Clone() MutationSequence {
 	for k, v := range ms.latestByKey {
 		mapClone[k] = v
 	}
-	return &mutationSequence{muts: ms.muts[:], latestByKey: mapClone}
+	delPrefixes := make([]Mutation, len(ms.delPrefixes))
+	copy(delPrefixes, ms.delPrefixes)
+	return &mutationSequence{muts: ms.muts[:], latestByKey: mapClone, delPrefixes: delPrefixes}
 }
 
 type mutationSet struct {
@@ -186,12 +226,18 @@ type mutationDel struct {
 	k kv.Key
 }
 
+type mutationDelPrefix struct {
+	prefix kv.Key
+}
+
 func newFromMagic(magic int) (Mutation, error) {
 	switch magic {
 	case mutationMagicSet:
 		return &mutationSet{}, nil
 	case mutationMagicDel:
 		return &mutationDel{}, nil
+	case mutationMagicDelPrefix:
+		return &mutationDelPrefix{}, nil
 	}
 	return nil, fmt.Errorf("Unknown mutation magic %d", magic)
 }
@@ -284,3 +330,40 @@ func (m *mutationDel) Value() []byte {
 func (m *mutationDel) ApplyTo(w kv.KVStoreWriter) {
 	w.Del(m.k)
 }
+
+func (m *mutationDelPrefix) getMagic() int {
+	return mutationMagicDelPrefix
+}
+
+func NewMutationDelPrefix(prefix kv.Key) *mutationDelPrefix {
+	return &mutationDelPrefix{prefix: prefix}
+}
+
+func (m *mutationDelPrefix) Write(w io.Writer) error {
+	return util.WriteBytes16(w, []byte(m.prefix))
+}
+
+func (m *mutationDelPrefix) Read(r io.Reader) error {
+	prefix, err := util.ReadBytes16(r)
+	if err != nil {
+		return err
+	}
+	m.prefix = kv.Key(prefix)
+	return nil
+}
+
+func (m *mutationDelPrefix) String() string {
+	return fmt.Sprintf("DEL PREFIX %s", m.prefix)
+}
+
+func (m *mutationDelPrefix) Key() kv.Key {
+	return m.prefix
+}
+
+func (m *mutationDelPrefix) Value() []byte {
+	return nil
+}
+
+func (m *mutationDelPrefix) ApplyTo(w kv.KVStoreWriter) {
+	w.DelPrefix(m.prefix)
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"testing"
 
+	"github.com/iotaledger/wasp/packages/kv"
 	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/iotaledger/wasp/packages/util"
 	"github.com/stretchr/testify/assert"
@@ -51,3 +52,64 @@ func TestMutationSequenceMarshaling(t *testing.T) {
 
 	assert.EqualValues(t, util.GetHashValue(ms), util.GetHashValue(ms2))
 }
+
+func TestApplyMutationDelPrefix(t *testing.T) {
+	vars := dict.New()
+	vars.Set("k1", []byte("v1"))
+	vars.Set("k2", []byte("v2"))
+	vars.Set("x", []byte("v3"))
+
+	mset := NewMutationDelPrefix("k")
+	mset.ApplyTo(vars)
+
+	assert.EqualValues(t, 1, len(vars))
+	v, _ := vars.Get("x")
+	assert.Equal(t, []byte("v3"), v)
+}
+
+func TestMutationSequenceDelPrefix(t *testing.T) {
+	ms := NewMutationSequence()
+	ms.Add(NewMutationSet("k1", []byte("v1")))
+	ms.Add(NewMutationSet("x", []byte("v2")))
+	ms.Add(NewMutationDelPrefix("k"))
+	ms.Add(NewMutationSet("k2", []byte("v3")))
+
+	assert.Nil(t, ms.Latest("k1").Value())
+	assert.Nil(t, ms.Latest("k3").Value())
+	assert.Equal(t, []byte("v3"), ms.Latest("k2").Value())
+	assert.Equal(t, []byte("v2"), ms.Latest("x").Value())
+	assert.Nil(t, ms.Latest("y"))
+	assert.EqualValues(t, []kv.Key{"k"}, ms.DelPrefixes())
+
+	n := 0
+	ms.IterateLatest(func(key kv.Key, mut Mutation) bool {
+		assert.NotEqual(t, kv.Key("k1"), key)
+		n++
+		return true
+	})
+	assert.Equal(t, 2, n)
+
+	var buf bytes.Buffer
+	err := ms.Write(&buf)
+	assert.NoError(t, err)
+
+	ms2 := NewMutationSequence()
+	err = ms2.Read(bytes.NewBuffer(buf.Bytes()))
+	assert.NoError(t, err)
+	assert.EqualValues(t, util.GetHashValue(ms), util.GetHashValue(ms2))
+	assert.Nil(t, ms2.Latest("k1").Value())
+
+	vars := dict.New()
+	ms.ApplyTo(vars)
+	assert.EqualValues(t, dict.Dict{"x": []byte("v2"), "k2": []byte("v3")}, vars)
+}
+
+func TestMutationSequenceSize(t *testing.T) {
+	ms := NewMutationSequence()
+	assert.Zero(t, ms.Size())
+
+	ms.Add(NewMutationSet("ab", []byte{1, 2, 3}))
+	ms.Add(NewMutationDel("c"))
+	ms.Add(NewMutationDelPrefix("de"))
+	assert.EqualValues(t, 2+3+1+2, ms.Size())
+}
@@ -58,6 +58,10 @@ func (a *ImmutableArray) getElemKey(idx uint16) kv.Key {
 	return ArrayElemKey(a.name, idx)
 }
 
+func (a *ImmutableArray) getElemPrefix() kv.Key {
+	return kv.Key(a.name) + kv.Key([]byte{arrayElemKeyCode})
+}
+
 func ArrayElemKey(name string, idx uint16) kv.Key {
 	var buf bytes.Buffer
 	buf.Write([]byte(name))
@@ -155,15 +159,9 @@ func (a *Array) MustExtend(other *ImmutableArray) {
 	}
 }
 
-// TODO implement with DelPrefix
+// Erase deletes all elements of the array with a single DelPrefix
 func (a *Array) Erase() error {
-	n, err := a.Len()
-	if err != nil {
-		return err
-	}
-	for i := uint16(0); i < n; i++ {
-		a.kvw.Del(a.getElemKey(i))
-	}
+	a.kvw.DelPrefix(a.getElemPrefix())
 	a.setSize(0)
 	return nil
 }
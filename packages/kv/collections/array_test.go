@@ -41,6 +41,15 @@ func TestBasicArray(t *testing.T) {
 
 	arr2.MustPush(d4)
 	assert.EqualValues(t, arr.MustLen()+1, arr2.MustLen())
+
+	arr.MustErase()
+	assert.EqualValues(t, 0, arr.MustLen())
+	assert.EqualValues(t, 5, arr2.MustLen())
+	arr.MustPush(d1)
+	assert.EqualValues(t, 1, arr.MustLen())
+	assert.EqualValues(t, d1, arr.MustGetAt(0))
+	// size key and elements of both arrays
+	assert.EqualValues(t, 2+6, len(vars))
 }
 
 func TestConcurrentAccess(t *testing.T) {
@@ -170,9 +170,10 @@ func (m *ImmutableMap) Len() (uint32, error) {
 	return util.MustUint32From4Bytes(v), nil
 }
 
+// Erase deletes all elements of the map with a single DelPrefix
 func (m *Map) Erase() {
-	// TODO needs DelPrefix method in KVStore
-	panic("implement me")
+	m.kvw.DelPrefix(m.getElemKey(nil))
+	m.kvw.Del(m.getSizeKey())
 }
 
 // Iterate non-deterministic
@@ -76,6 +76,12 @@ func TestBasicMap(t *testing.T) {
 
 	v = m.MustGetAt(k3)
 	assert.EqualValues(t, v3, v)
+
+	m.Erase()
+	assert.Zero(t, m.MustLen())
+	assert.False(t, m.MustHasAt(k1))
+	assert.False(t, m.MustHasAt(k3))
+	assert.Zero(t, len(vars))
 }
 
 func TestIterate(t *testing.T) {
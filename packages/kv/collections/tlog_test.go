@@ -54,6 +54,13 @@ func TestTlogBasic(t *testing.T) {
 
 	tl.MustAppend(nowisNext2, nil)
 	assert.EqualValues(t, 7, tl.MustLen())
+
+	tl.Erase()
+	assert.Zero(t, tl.MustLen())
+	assert.Zero(t, len(vars))
+	tl.MustAppend(nowis, d1)
+	assert.EqualValues(t, 1, tl.MustLen())
+	assert.EqualValues(t, nowis, tl.MustLatest())
 }
 
 const (
@@ -141,6 +141,15 @@ func (d Dict) Set(key kv.Key, value []byte) {
 	d[key] = value
 }
 
+// DelPrefix removes all key/value pairs with the prefix
+func (d Dict) DelPrefix(prefix kv.Key) {
+	for k := range d {
+		if k.HasPrefix(prefix) {
+			delete(d, k)
+		}
+	}
+}
+
 // Del removes key/value pair
 func (d Dict) Del(key kv.Key) {
 	delete(d, key)
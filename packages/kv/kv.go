@@ -38,11 +38,9 @@ type KVStoreReader interface {
 type KVStoreWriter interface {
 	Set(key Key, value []byte)
 	Del(key Key)
-
-	// TODO add DelPrefix(prefix []byte)
-	// deletes all keys with the prefix. Currently we don't have a possibility to iterate over keys
-	// and maybe we do not need one in the sandbox. However we need a possibility to efficiently clear arrays,
+	// DelPrefix deletes all keys with the prefix. Used to efficiently clear arrays,
 	// dictionaries and timestamped logs
+	DelPrefix(prefix Key)
 }
 
 func MustGet(kvs KVStore, key Key) []byte {
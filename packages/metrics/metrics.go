@@ -0,0 +1,128 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+// Package metrics contains Prometheus collectors of the chain, consensus and VM internals.
+// The collectors are updated by the node components and exposed by the Metrics plugin
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "wasp"
+
+var (
+	stateIndex = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "chain",
+		Name:      "state_index",
+		Help:      "Index of the last solid state of the chain",
+	}, []string{"chain"})
+
+	stateTimestamp = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "chain",
+		Name:      "state_timestamp_seconds",
+		Help:      "Unix time when the node committed the last solid state of the chain",
+	}, []string{"chain"})
+
+	backlogLength = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "consensus",
+		Name:      "backlog_length",
+		Help:      "Number of requests in the backlog of the consensus operator",
+	}, []string{"chain"})
+
+	consensusStageDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "consensus",
+		Name:      "stage_duration_seconds",
+		Help:      "Time the consensus operator spent in the consensus stage",
+		Buckets:   []float64{0.01, 0.05, 0.1, 0.5, 1, 5, 10, 30, 60, 120},
+	}, []string{"chain", "stage"})
+	// names of the consensus stages observed so far, to be able to delete them
+	consensusStages      = make(map[string]struct{})
+	consensusStagesMutex = &sync.Mutex{}
+
+	requestRunDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "vm",
+		Name:      "request_duration_seconds",
+		Help:      "Time spent running a request on the VM",
+		Buckets:   prometheus.ExponentialBuckets(0.0005, 4, 10),
+	}, []string{"chain"})
+
+	requestsProcessed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "vm",
+		Name:      "requests_total",
+		Help:      "Number of requests run on the VM",
+	}, []string{"chain"})
+
+	requestsFailed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "vm",
+		Name:      "requests_failed_total",
+		Help:      "Number of requests which ended with an error",
+	}, []string{"chain"})
+)
+
+// Collectors returns all collectors of the package to be registered in the Prometheus registry
+func Collectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		stateIndex,
+		stateTimestamp,
+		backlogLength,
+		consensusStageDuration,
+		requestRunDuration,
+		requestsProcessed,
+		requestsFailed,
+	}
+}
+
+// SetStateIndex records the state transition of the chain
+func SetStateIndex(chainID *coretypes.ChainID, blockIndex uint32) {
+	stateIndex.WithLabelValues(chainID.String()).Set(float64(blockIndex))
+	stateTimestamp.WithLabelValues(chainID.String()).SetToCurrentTime()
+}
+
+func SetBacklogLength(chainID *coretypes.ChainID, length int) {
+	backlogLength.WithLabelValues(chainID.String()).Set(float64(length))
+}
+
+func ObserveConsensusStage(chainID *coretypes.ChainID, stage string, duration time.Duration) {
+	consensusStagesMutex.Lock()
+	consensusStages[stage] = struct{}{}
+	consensusStagesMutex.Unlock()
+	consensusStageDuration.WithLabelValues(chainID.String(), stage).Observe(duration.Seconds())
+}
+
+// ObserveRequest records the run of the request on the VM. err is the error returned by the call, if any
+func ObserveRequest(chainID *coretypes.ChainID, duration time.Duration, err error) {
+	label := chainID.String()
+	requestRunDuration.WithLabelValues(label).Observe(duration.Seconds())
+	requestsProcessed.WithLabelValues(label).Inc()
+	if err != nil {
+		requestsFailed.WithLabelValues(label).Inc()
+	}
+}
+
+// ForgetChain removes all metrics of the chain, for example when the chain is deactivated
+func ForgetChain(chainID *coretypes.ChainID) {
+	labels := prometheus.Labels{"chain": chainID.String()}
+	stateIndex.Delete(labels)
+	stateTimestamp.Delete(labels)
+	backlogLength.Delete(labels)
+	requestRunDuration.Delete(labels)
+	requestsProcessed.Delete(labels)
+	requestsFailed.Delete(labels)
+	consensusStagesMutex.Lock()
+	defer consensusStagesMutex.Unlock()
+	for s := range consensusStages {
+		consensusStageDuration.Delete(prometheus.Labels{"chain": chainID.String(), "stage": s})
+	}
+}
@@ -0,0 +1,32 @@
+package metrics
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChainMetrics(t *testing.T) {
+	chainID := coretypes.ChainID{1, 2, 3}
+	label := chainID.String()
+
+	SetStateIndex(&chainID, 5)
+	require.EqualValues(t, 5, testutil.ToFloat64(stateIndex.WithLabelValues(label)))
+
+	ObserveRequest(&chainID, time.Millisecond, nil)
+	ObserveRequest(&chainID, time.Millisecond, errors.New("failed"))
+	require.EqualValues(t, 2, testutil.ToFloat64(requestsProcessed.WithLabelValues(label)))
+	require.EqualValues(t, 1, testutil.ToFloat64(requestsFailed.WithLabelValues(label)))
+
+	ObserveConsensusStage(&chainID, "NoSync", time.Second)
+	require.EqualValues(t, 1, testutil.CollectAndCount(consensusStageDuration))
+
+	ForgetChain(&chainID)
+	require.EqualValues(t, 0, testutil.CollectAndCount(stateIndex))
+	require.EqualValues(t, 0, testutil.CollectAndCount(requestsFailed))
+	require.EqualValues(t, 0, testutil.CollectAndCount(consensusStageDuration))
+}
@@ -16,6 +16,8 @@ const (
 	DatabaseDir      = "database.directory"
 	DatabaseInMemory = "database.inMemory"
 
+	StateHistoryRetention = "state.historyRetention"
+
 	WebAPIBindAddress    = "webapi.bindAddress"
 	WebAPIAdminWhitelist = "webapi.adminWhitelist"
 	WebAPIAuth           = "webapi.auth"
@@ -30,6 +32,8 @@ const (
 	PeeringPort    = "peering.port"
 
 	NanomsgPublisherPort = "nanomsg.port"
+
+	MetricsBindAddress = "metrics.bindAddress"
 )
 
 func InitFlags() {
@@ -43,6 +47,8 @@ func InitFlags() {
 	flag.String(DatabaseDir, "waspdb", "path to the database folder")
 	flag.Bool(DatabaseInMemory, false, "whether the database is only kept in memory and not persisted")
 
+	flag.Int(StateHistoryRetention, 1000, "number of recent blocks for which past states of chains are kept (0 = no history)")
+
 	flag.String(WebAPIBindAddress, "127.0.0.1:8080", "the bind address for the web API")
 	flag.StringSlice(WebAPIAdminWhitelist, []string{}, "IP whitelist for /adm wndpoints")
 	flag.StringToString(WebAPIAuth, nil, "authentication scheme for web API")
@@ -57,6 +63,8 @@ func InitFlags() {
 	flag.String(PeeringMyNetId, "127.0.0.1:4000", "node host address as it is recognized by other peers")
 
 	flag.Int(NanomsgPublisherPort, 5550, "the port for nanomsg even publisher")
+
+	flag.String(MetricsBindAddress, "127.0.0.1:2112", "the bind address for the Prometheus metrics endpoint")
 }
 
 func GetBool(name string) bool {
@@ -7,5 +7,6 @@ const (
 	PriorityNodeConnection
 	PriorityDispatcher
 	PriorityWebAPI
+	PriorityMetrics
 	PriorityBadgerGarbageCollection
 )
@@ -11,6 +11,7 @@ import (
 	"github.com/iotaledger/wasp/packages/peering"
 	"github.com/iotaledger/wasp/packages/util"
 	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/sign/bls"
 )
 
 // structure of the encoded PeerMessage:
@@ -103,29 +104,74 @@ func decodeMessage(data []byte) (*peering.PeerMessage, error) {
 	}
 }
 
+// handshakeMsg is exchanged in 3 steps, each signed with the key of the sender:
+//   - the dialing node sends its nonce
+//   - the accepting node responds with its nonce and the nonce of the dialing node as a challenge
+//   - the dialing node responds with the nonce of the accepting node as a challenge
+//
+// A node is authenticated by its signature over the challenge, which is fresh for every connection,
+// so a handshake recorded on another connection can't be replayed.
 type handshakeMsg struct {
 	peeringID string      // Pair of peer NetIDs
 	srcNetID  string      // Their NetID
 	pubKey    kyber.Point // Our PubKey.
+	nonce     []byte      // Our nonce, to be signed by the remote node.
+	challenge []byte      // The nonce of the remote node, empty in the first message.
 }
 
-func (m *handshakeMsg) bytes() ([]byte, error) {
+func (m *handshakeMsg) bytes(secKey kyber.Scalar, suite Suite) ([]byte, error) {
 	var err error
-	var buf bytes.Buffer
-	if err = util.WriteString16(&buf, m.peeringID); err != nil {
+	//
+	// Payload.
+	var payloadBuf bytes.Buffer
+	if err = util.WriteString16(&payloadBuf, m.peeringID); err != nil {
+		return nil, err
+	}
+	if err = util.WriteString16(&payloadBuf, m.srcNetID); err != nil {
+		return nil, err
+	}
+	if err = util.WriteMarshaled(&payloadBuf, m.pubKey); err != nil {
+		return nil, err
+	}
+	if err = util.WriteBytes16(&payloadBuf, m.nonce); err != nil {
+		return nil, err
+	}
+	if err = util.WriteBytes16(&payloadBuf, m.challenge); err != nil {
+		return nil, err
+	}
+	var payload = payloadBuf.Bytes()
+	var signature []byte
+	if signature, err = bls.Sign(suite, secKey, payload); err != nil {
 		return nil, err
 	}
-	if err = util.WriteString16(&buf, m.srcNetID); err != nil {
+	//
+	// Signed frame.
+	var signedBuf bytes.Buffer
+	if err = util.WriteBytes16(&signedBuf, signature); err != nil {
 		return nil, err
 	}
-	if err = util.WriteMarshaled(&buf, m.pubKey); err != nil {
+	if err = util.WriteBytes16(&signedBuf, payload); err != nil {
 		return nil, err
 	}
-	return buf.Bytes(), nil
+	return signedBuf.Bytes(), nil
 }
-func handshakeMsgFromBytes(buf []byte, suite kyber.Group) (*handshakeMsg, error) {
+
+func handshakeMsgFromBytes(buf []byte, suite Suite) (*handshakeMsg, error) {
 	var err error
-	r := bytes.NewReader(buf)
+	//
+	// Signed frame.
+	rSigned := bytes.NewReader(buf)
+	var payload []byte
+	var signature []byte
+	if signature, err = util.ReadBytes16(rSigned); err != nil {
+		return nil, err
+	}
+	if payload, err = util.ReadBytes16(rSigned); err != nil {
+		return nil, err
+	}
+	//
+	// Payload.
+	r := bytes.NewReader(payload)
 	m := handshakeMsg{}
 	if m.peeringID, err = util.ReadString16(r); err != nil {
 		return nil, err
@@ -137,5 +183,16 @@ func handshakeMsgFromBytes(buf []byte, suite kyber.Group) (*handshakeMsg, error)
 	if err = util.ReadMarshaled(r, m.pubKey); err != nil {
 		return nil, err
 	}
+	if m.nonce, err = util.ReadBytes16(r); err != nil {
+		return nil, err
+	}
+	if m.challenge, err = util.ReadBytes16(r); err != nil {
+		return nil, err
+	}
+	//
+	// Verify the signature.
+	if err = bls.Verify(suite, m.pubKey, payload, signature); err != nil {
+		return nil, err
+	}
 	return &m, nil
 }
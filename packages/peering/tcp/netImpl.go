@@ -26,13 +26,22 @@ type NetImpl struct {
 	events     *events.Event
 
 	nodeKeyPair *key.Pair
-	suite       kyber.Group
+	trusted     peering.TrustedNetworkManager // Only trusted peers are connected, if set.
+	suite       Suite
 	log         *logger.Logger
 }
 
 // NewNetworkProvider is a constructor for the TCP based
-// peering network implementation.
-func NewNetworkProvider(myNetID string, port int, nodeKeyPair *key.Pair, suite kyber.Group, log *logger.Logger) (*NetImpl, error) {
+// peering network implementation. If trusted is nil, handshakes
+// from all the peers are accepted.
+func NewNetworkProvider(
+	myNetID string,
+	port int,
+	nodeKeyPair *key.Pair,
+	trusted peering.TrustedNetworkManager,
+	suite Suite,
+	log *logger.Logger,
+) (*NetImpl, error) {
 	if err := peering.CheckMyNetID(myNetID, port); err != nil {
 		// can't continue because NetID parameter is not correct
 		log.Panicf("checkMyNetworkID: '%v'. || Check the 'netid' parameter in config.json", err)
@@ -44,6 +53,7 @@ func NewNetworkProvider(myNetID string, port int, nodeKeyPair *key.Pair, suite k
 		peers:       make(map[string]*peer),
 		peersMutex:  &sync.RWMutex{},
 		nodeKeyPair: nodeKeyPair,
+		trusted:     trusted,
 		suite:       suite,
 		log:         log,
 	}
@@ -181,6 +191,14 @@ func (n *NetImpl) peeringID(remoteNetID string) string {
 	return n.myNetID + "<" + remoteNetID
 }
 
+// checkTrusted returns an error, if the peer sending the handshake is not trusted.
+func (n *NetImpl) checkTrusted(hMsg *handshakeMsg) error {
+	if n.trusted == nil {
+		return nil
+	}
+	return n.trusted.IsTrustedPeer(hMsg.pubKey, hMsg.srcNetID)
+}
+
 // usePeer adds new connection to the peer pool
 // if it already exists, returns existing.
 // Return nil for for own netID
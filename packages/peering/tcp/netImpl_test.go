@@ -26,9 +26,9 @@ func TestBasic(t *testing.T) {
 	chain2 := coretypes.NewRandomChainID()
 	netIDs := []string{"localhost:9017", "localhost:9018", "localhost:9019"}
 	nodes := make([]peering.NetworkProvider, len(netIDs))
-	nodes[0], err0 = tcp.NewNetworkProvider(netIDs[0], 9017, key.NewKeyPair(suite), suite, log.Named("node0"))
-	nodes[1], err1 = tcp.NewNetworkProvider(netIDs[1], 9018, key.NewKeyPair(suite), suite, log.Named("node1"))
-	nodes[2], err2 = tcp.NewNetworkProvider(netIDs[2], 9019, key.NewKeyPair(suite), suite, log.Named("node2"))
+	nodes[0], err0 = tcp.NewNetworkProvider(netIDs[0], 9017, key.NewKeyPair(suite), nil, suite, log.Named("node0"))
+	nodes[1], err1 = tcp.NewNetworkProvider(netIDs[1], 9018, key.NewKeyPair(suite), nil, suite, log.Named("node1"))
+	nodes[2], err2 = tcp.NewNetworkProvider(netIDs[2], 9019, key.NewKeyPair(suite), nil, suite, log.Named("node2"))
 	require.Nil(t, err0)
 	require.Nil(t, err1)
 	require.Nil(t, err2)
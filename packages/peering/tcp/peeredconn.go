@@ -4,7 +4,10 @@
 package tcp
 
 import (
+	"bytes"
+	"crypto/rand"
 	"net"
+	"time"
 
 	"github.com/iotaledger/goshimmer/dapps/waspconn/packages/chopper"
 	"github.com/iotaledger/goshimmer/packages/tangle"
@@ -23,6 +26,8 @@ type peeredConnection struct {
 	net         *NetImpl
 	msgChopper  *chopper.Chopper
 	handshakeOk bool
+	nonce       []byte        // the challenge for the remote node in the handshake
+	pending     *handshakeMsg // the first handshake from the inbound peer, not authenticated yet
 }
 
 // creates new peered connection and attach event handlers for received data and closing
@@ -32,6 +37,7 @@ func newPeeredConnection(conn net.Conn, net *NetImpl, peer *peer) *peeredConnect
 		peer:               peer, // may be nil
 		net:                net,
 		msgChopper:         chopper.NewChopper(),
+		nonce:              newNonce(),
 	}
 	c.Events.ReceiveMessage.Attach(events.NewClosure(func(data []byte) {
 		c.receiveData(data)
@@ -97,52 +103,120 @@ func (c *peeredConnection) receiveData(data []byte) {
 	}
 }
 
+// sends the handshake message, signed by the node. It contains the nonce of the connection
+// and the challenge (the nonce of the remote node) if it is already known
+func (c *peeredConnection) sendHandshake(peeringID string, challenge []byte) error {
+	var err error
+	msg := handshakeMsg{
+		peeringID: peeringID,
+		srcNetID:  c.net.Self().NetID(),
+		pubKey:    c.net.nodeKeyPair.Public,
+		nonce:     c.nonce,
+		challenge: challenge,
+	}
+	var msgData []byte
+	if msgData, err = msg.bytes(c.net.nodeKeyPair.Private, c.net.suite); err != nil {
+		return err
+	}
+	data := encodeMessage(&peering.PeerMessage{
+		MsgType: msgTypeHandshake,
+		MsgData: msgData,
+	}, time.Now().UnixNano())
+	_, err = c.Write(data)
+	c.net.log.Debugf("sendHandshake '%s' --> '%s', id = %s", c.net.myNetID, c.RemoteAddr().String(), peeringID)
+	return err
+}
+
 // receives handshake response from the outbound peer
 // assumes the connection is already peered (i can be only for outbound peers)
+// The response must be signed by the peer over the nonce of the connection
 func (c *peeredConnection) processHandShakeOutbound(msg *peering.PeerMessage) {
 	var err error
 	var hMsg *handshakeMsg
 	if hMsg, err = handshakeMsgFromBytes(msg.MsgData, c.net.suite); err != nil {
-		c.net.log.Errorf(
+		c.net.log.Warnf(
 			"closeConn the peer connection: wrong handshake message from outbound peer %v, error: %v",
 			c.peer.peeringID(), err,
 		)
-		panic(err)
+		c.peer.closeConn()
+		return
 	}
 	c.net.log.Debugf("received handshake from outbound %s", hMsg.peeringID)
+	if !bytes.Equal(hMsg.challenge, c.nonce) {
+		c.net.log.Warnf("closeConn the peer connection: outbound peer %s failed the handshake challenge", hMsg.peeringID)
+		c.peer.closeConn()
+		return
+	}
+	if err = c.net.checkTrusted(hMsg); err != nil {
+		c.net.log.Warnf("closeConn the peer connection: untrusted outbound peer %s: %v", hMsg.peeringID, err)
+		c.peer.closeConn()
+		return
+	}
 	if hMsg.peeringID != c.peer.peeringID() {
 		c.net.log.Errorf(
 			"closeConn the peer connection: wrong handshake message from outbound peer: expected %s got '%s'",
 			c.peer.peeringID(), hMsg.peeringID,
 		)
-		if c.peer != nil {
-			// may ne be peered yet
-			c.peer.closeConn()
-		}
-	} else {
-		c.net.log.Infof("CONNECTED WITH PEER %s (outbound)", hMsg.peeringID)
-		c.peer.remotePubKey = hMsg.pubKey
-		c.peer.handshakeOk = true
-		c.peer.waitReady.Done()
+		c.peer.closeConn()
+		return
 	}
+	// the peer is authenticated, prove the own identity to finish the handshake
+	if err = c.sendHandshake(hMsg.peeringID, hMsg.nonce); err != nil {
+		c.net.log.Errorf("error while responding to handshake: %v. Closing connection", err)
+		c.peer.closeConn()
+		return
+	}
+	c.net.log.Infof("CONNECTED WITH PEER %s (outbound)", hMsg.peeringID)
+	c.peer.remotePubKey = hMsg.pubKey
+	c.peer.handshakeOk = true
+	c.peer.waitReady.Done()
 }
 
-// receives handshake from the inbound peer
-// links connection with the peer
-// sends response back to finish the handshake
+// receives handshakes from the inbound peer
+// the first one is responded with the challenge to the peer. The second one must be signed
+// by the same peer over the nonce of the connection. Only then the connection is linked with the peer
 func (c *peeredConnection) processHandShakeInbound(msg *peering.PeerMessage) {
 	var err error
 	var hMsg *handshakeMsg
 	if hMsg, err = handshakeMsgFromBytes(msg.MsgData, c.net.suite); err != nil {
-		c.net.log.Errorf(
-			"closeConn the peer connection: wrong handshake message from outbound peer %v, error: %v",
-			c.peer.peeringID(), err,
-		)
-		panic(err)
+		c.net.log.Warnf("closeConn the peer connection: wrong handshake message from inbound peer, error: %v", err)
+		_ = c.Close()
+		return
 	}
 
 	c.net.log.Infof("received handshake from inbound id = %s, peers=%+v", hMsg.peeringID, c.net.peers)
 
+	if err = c.net.checkTrusted(hMsg); err != nil {
+		c.net.log.Warnf("inbound connection from untrusted peer id %s: %v. Closing..", hMsg.peeringID, err)
+		_ = c.Close()
+		return
+	}
+
+	if c.pending == nil {
+		c.net.peersMutex.RLock()
+		peer, ok := c.net.peers[hMsg.peeringID]
+		c.net.peersMutex.RUnlock()
+
+		if !ok || !peer.IsInbound() {
+			c.net.log.Warnf("inbound connection from unexpected peer id %s. Closing..", hMsg.peeringID)
+			_ = c.Close()
+			return
+		}
+		c.pending = hMsg
+		if err := c.sendHandshake(hMsg.peeringID, hMsg.nonce); err != nil {
+			c.net.log.Errorf("error while responding to handshake: %v. Closing connection", err)
+			_ = c.Close()
+		}
+		return
+	}
+
+	if hMsg.peeringID != c.pending.peeringID || hMsg.srcNetID != c.pending.srcNetID ||
+		!hMsg.pubKey.Equal(c.pending.pubKey) || !bytes.Equal(hMsg.challenge, c.nonce) {
+		c.net.log.Warnf("inbound peer id %s failed the handshake challenge. Closing..", hMsg.peeringID)
+		_ = c.Close()
+		return
+	}
+
 	c.net.peersMutex.RLock()
 	peer, ok := c.net.peers[hMsg.peeringID]
 	c.net.peersMutex.RUnlock()
@@ -162,9 +236,13 @@ func (c *peeredConnection) processHandShakeInbound(msg *peering.PeerMessage) {
 	peer.Unlock()
 
 	c.net.log.Infof("CONNECTED WITH PEER %s (inbound)", hMsg.peeringID)
+}
 
-	if err := peer.sendHandshake(); err != nil {
-		c.net.log.Errorf("error while responding to handshake: %v. Closing connection", err)
-		_ = c.Close()
+// newNonce returns the random nonce of the connection, which the remote node signs in the handshake
+func newNonce() []byte {
+	ret := make([]byte, handshakeNonceSize)
+	if _, err := rand.Read(ret); err != nil {
+		panic(err)
 	}
+	return ret
 }
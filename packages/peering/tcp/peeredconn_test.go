@@ -0,0 +1,178 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package tcp
+
+import (
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/iotaledger/goshimmer/packages/tangle"
+	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/hive.go/netutil/buffconn"
+	"github.com/iotaledger/wasp/packages/peering"
+	"github.com/iotaledger/wasp/packages/testutil"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/util/key"
+)
+
+func TestHandshakeCodec(t *testing.T) {
+	suite := pairing.NewSuiteBn256()
+	pair := key.NewKeyPair(suite)
+	a := handshakeMsg{
+		peeringID: "a<b",
+		srcNetID:  "a",
+		pubKey:    pair.Public,
+		nonce:     newNonce(),
+		challenge: newNonce(),
+	}
+	buf, err := a.bytes(pair.Private, suite)
+	require.NoError(t, err)
+
+	b, err := handshakeMsgFromBytes(buf, suite)
+	require.NoError(t, err)
+	require.Equal(t, a.peeringID, b.peeringID)
+	require.Equal(t, a.srcNetID, b.srcNetID)
+	require.True(t, a.pubKey.Equal(b.pubKey))
+	require.Equal(t, a.nonce, b.nonce)
+	require.Equal(t, a.challenge, b.challenge)
+
+	// signed with a key other than the one in the message
+	buf, err = a.bytes(key.NewKeyPair(suite).Private, suite)
+	require.NoError(t, err)
+	_, err = handshakeMsgFromBytes(buf, suite)
+	require.Error(t, err)
+}
+
+// TestHandshakeImpersonation checks, that the node doesn't pair with a connection,
+// which presents the public key of the peer without holding its private key.
+func TestHandshakeImpersonation(t *testing.T) {
+	suite := pairing.NewSuiteBn256()
+	victim := key.NewKeyPair(suite)
+	peer := startHandshakeTestNode(t, suite, 9029)
+
+	conn := dialHandshakeTestNode(t, 9029)
+	conn.send(t, suite, key.NewKeyPair(suite), &handshakeMsg{
+		peeringID: peer.peeringID(),
+		srcNetID:  peer.remoteNetID,
+		pubKey:    victim.Public,
+		nonce:     newNonce(),
+	})
+	conn.requireClosed(t)
+	require.False(t, peer.IsAlive())
+}
+
+// TestHandshakeReplay checks, that the handshakes of the peer recorded on
+// another connection are not accepted, because the challenge is fresh.
+func TestHandshakeReplay(t *testing.T) {
+	suite := pairing.NewSuiteBn256()
+	victim := key.NewKeyPair(suite)
+	peer := startHandshakeTestNode(t, suite, 9030)
+
+	// the handshakes of the victim recorded on a previous connection
+	first := &handshakeMsg{
+		peeringID: peer.peeringID(),
+		srcNetID:  peer.remoteNetID,
+		pubKey:    victim.Public,
+		nonce:     newNonce(),
+	}
+	second := &handshakeMsg{
+		peeringID: peer.peeringID(),
+		srcNetID:  peer.remoteNetID,
+		pubKey:    victim.Public,
+		nonce:     first.nonce,
+		challenge: newNonce(),
+	}
+
+	conn := dialHandshakeTestNode(t, 9030)
+	conn.send(t, suite, victim, first)
+	resp := conn.recvHandshake(t, suite)
+	require.Equal(t, first.nonce, resp.challenge)
+	require.NotEqual(t, second.challenge, resp.nonce)
+
+	conn.send(t, suite, victim, second)
+	conn.requireClosed(t)
+	require.False(t, peer.IsAlive())
+}
+
+// startHandshakeTestNode runs the node, which expects an inbound connection from the peer
+func startHandshakeTestNode(t *testing.T, suite Suite, port int) *peer {
+	log := testutil.NewLogger(t)
+	node, err := NewNetworkProvider(fmt.Sprintf("localhost:%d", port), port, key.NewKeyPair(suite), nil, suite, log)
+	require.NoError(t, err)
+	stopCh := make(chan struct{})
+	t.Cleanup(func() { close(stopCh) })
+	go node.Run(stopCh)
+
+	// the NetID of the peer is less than the NetID of the node, so the peer is inbound
+	p, err := node.PeerByNetID("localhost:1000")
+	require.NoError(t, err)
+	require.True(t, p.(*peer).IsInbound())
+	<-time.After(100 * time.Millisecond)
+	return p.(*peer)
+}
+
+type handshakeTestConn struct {
+	*buffconn.BufferedConnection
+	recvCh   chan []byte
+	closedCh chan struct{}
+}
+
+func dialHandshakeTestNode(t *testing.T, port int) *handshakeTestConn {
+	rawConn, err := net.DialTimeout("tcp", fmt.Sprintf("localhost:%d", port), time.Second)
+	require.NoError(t, err)
+	conn := &handshakeTestConn{
+		BufferedConnection: buffconn.NewBufferedConnection(rawConn, tangle.MaxMessageSize),
+		recvCh:             make(chan []byte, 10),
+		closedCh:           make(chan struct{}),
+	}
+	conn.Events.ReceiveMessage.Attach(events.NewClosure(func(data []byte) {
+		conn.recvCh <- append([]byte(nil), data...)
+	}))
+	conn.Events.Close.Attach(events.NewClosure(func() {
+		close(conn.closedCh)
+	}))
+	go func() {
+		// Read returns, when the node closes the connection
+		_ = conn.Read()
+		_ = conn.Close()
+	}()
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func (c *handshakeTestConn) send(t *testing.T, suite Suite, pair *key.Pair, msg *handshakeMsg) {
+	msgData, err := msg.bytes(pair.Private, suite)
+	require.NoError(t, err)
+	_, err = c.Write(encodeMessage(&peering.PeerMessage{
+		MsgType: msgTypeHandshake,
+		MsgData: msgData,
+	}, time.Now().UnixNano()))
+	require.NoError(t, err)
+}
+
+func (c *handshakeTestConn) recvHandshake(t *testing.T, suite Suite) *handshakeMsg {
+	select {
+	case data := <-c.recvCh:
+		msg, err := decodeMessage(data)
+		require.NoError(t, err)
+		require.EqualValues(t, msgTypeHandshake, msg.MsgType)
+		ret, err := handshakeMsgFromBytes(msg.MsgData, suite)
+		require.NoError(t, err)
+		return ret
+	case <-time.After(5 * time.Second):
+		t.Fatalf("no handshake response")
+		return nil
+	}
+}
+
+func (c *handshakeTestConn) requireClosed(t *testing.T) {
+	select {
+	case <-c.closedCh:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("the connection was not closed")
+	}
+}
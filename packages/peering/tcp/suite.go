@@ -0,0 +1,15 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package tcp
+
+import (
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// Suite is a collection of kyber interfaces used to sign the handshakes.
+type Suite interface {
+	pairing.Suite
+	kyber.Group
+}
@@ -0,0 +1,40 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package peering
+
+import (
+	"fmt"
+
+	"go.dedis.ch/kyber/v3"
+)
+
+// TrustedPeer is a peer, explicitly trusted by the operator of this node.
+// The peer is identified by its public key, the NetID is the address at which it is known.
+type TrustedPeer struct {
+	PubKey kyber.Point
+	NetID  string
+}
+
+// TrustedNetworkManager maintains the list of peers, trusted by this node.
+// Only the trusted peers are paired by the peering network and can take part in the DKG.
+type TrustedNetworkManager interface {
+	// IsTrustedPeer returns nil, if the peer with the specified public key is trusted
+	// and is known by the specified NetID. Otherwise the reason is returned as an error.
+	IsTrustedPeer(pubKey kyber.Point, netID string) error
+	TrustPeer(pubKey kyber.Point, netID string) (*TrustedPeer, error)
+	DistrustPeer(pubKey kyber.Point) (*TrustedPeer, error)
+	TrustedPeers() ([]*TrustedPeer, error)
+}
+
+// CheckTrustedPeer is a helper for the IsTrustedPeer implementations,
+// it checks the trusted peer record found by the public key against the NetID.
+func CheckTrustedPeer(tp *TrustedPeer, pubKey kyber.Point, netID string) error {
+	if tp == nil {
+		return fmt.Errorf("peer %v with pubKey %v is not trusted", netID, pubKey)
+	}
+	if tp.NetID != netID {
+		return fmt.Errorf("peer with pubKey %v is trusted as %v, not as %v", pubKey, tp.NetID, netID)
+	}
+	return nil
+}
@@ -5,16 +5,21 @@ package udp
 
 import (
 	"bytes"
+	"fmt"
+	"time"
 
 	"github.com/iotaledger/wasp/packages/util"
 	"go.dedis.ch/kyber/v3"
 	"go.dedis.ch/kyber/v3/sign/bls"
 )
 
+// handshakeMsg is signed by the sender. The timestamp makes a recorded handshake
+// useless for a replay: it is accepted only if fresh and newer than the last one from the peer.
 type handshakeMsg struct {
-	netID   string      // Their NetID
-	pubKey  kyber.Point // Our PubKey.
-	respond bool        // Do the message asks for a response?
+	netID     string      // Their NetID
+	pubKey    kyber.Point // Our PubKey.
+	respond   bool        // Do the message asks for a response?
+	timestamp int64       // Unix nanoseconds, when the message was created.
 }
 
 func (m *handshakeMsg) bytes(secKey kyber.Scalar, suite Suite) ([]byte, error) {
@@ -31,6 +36,9 @@ func (m *handshakeMsg) bytes(secKey kyber.Scalar, suite Suite) ([]byte, error) {
 	if err = util.WriteBoolByte(&payloadBuf, m.respond); err != nil {
 		return nil, err
 	}
+	if err = util.WriteInt64(&payloadBuf, m.timestamp); err != nil {
+		return nil, err
+	}
 	var payload = payloadBuf.Bytes()
 	var signature []byte
 	if signature, err = bls.Sign(suite, secKey, payload); err != nil {
@@ -75,6 +83,9 @@ func handshakeMsgFromBytes(buf []byte, suite Suite) (*handshakeMsg, error) {
 	if err = util.ReadBoolByte(rPayload, &m.respond); err != nil {
 		return nil, err
 	}
+	if err = util.ReadInt64(rPayload, &m.timestamp); err != nil {
+		return nil, err
+	}
 	//
 	// Verify the signature.
 	if err = bls.Verify(suite, m.pubKey, payload, signature); err != nil {
@@ -82,3 +93,12 @@ func handshakeMsgFromBytes(buf []byte, suite Suite) (*handshakeMsg, error) {
 	}
 	return &m, nil
 }
+
+// checkFresh returns an error, if the handshake was created too long ago or in the future.
+func (m *handshakeMsg) checkFresh(now time.Time) error {
+	age := now.Sub(time.Unix(0, m.timestamp))
+	if age > handshakeMaxAge || age < -handshakeMaxAge {
+		return fmt.Errorf("handshake from %v is not fresh, age=%v", m.netID, age)
+	}
+	return nil
+}
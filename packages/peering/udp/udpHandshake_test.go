@@ -6,6 +6,10 @@ package udp
 import (
 	"net"
 	"testing"
+	"time"
+
+	"github.com/iotaledger/wasp/packages/peering"
+	"github.com/iotaledger/wasp/packages/testutil"
 
 	"github.com/stretchr/testify/require"
 	"go.dedis.ch/kyber/v3/pairing"
@@ -17,9 +21,10 @@ func TestHandshakeCodec(t *testing.T) {
 	suite := pairing.NewSuiteBn256()
 	pair := key.NewKeyPair(suite)
 	a := handshakeMsg{
-		netID:   "some",
-		pubKey:  pair.Public,
-		respond: true,
+		netID:     "some",
+		pubKey:    pair.Public,
+		respond:   true,
+		timestamp: time.Now().UnixNano(),
 	}
 	var buf []byte
 	buf, err = a.bytes(pair.Private, suite)
@@ -34,6 +39,7 @@ func TestHandshakeCodec(t *testing.T) {
 	require.Equal(t, a.netID, b.netID)
 	require.True(t, a.pubKey.Equal(b.pubKey))
 	require.Equal(t, a.respond, b.respond)
+	require.Equal(t, a.timestamp, b.timestamp)
 	//
 	// Damaged message.
 	buf[2] = buf[2] + 1
@@ -43,6 +49,68 @@ func TestHandshakeCodec(t *testing.T) {
 	require.Nil(t, c)
 }
 
+// TestHandshakeReplay checks, that a recorded handshake of the peer,
+// replayed from another UDPAddr, does not rebind the peer to that address.
+func TestHandshakeReplay(t *testing.T) {
+	suite := pairing.NewSuiteBn256()
+	victim := key.NewKeyPair(suite)
+	n, err := NewNetworkProvider("localhost:9031", 9031, key.NewKeyPair(suite), nil, suite, testutil.NewLogger(t))
+	require.NoError(t, err)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	go n.Run(stopCh)
+
+	connA, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err)
+	defer connA.Close()
+	connB, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err)
+	defer connB.Close()
+	nodeAddr, err := net.ResolveUDPAddr("udp", "localhost:9031")
+	require.NoError(t, err)
+
+	makeHandshake := func(ts time.Time) []byte {
+		h := handshakeMsg{netID: "localhost:9032", pubKey: victim.Public, timestamp: ts.UnixNano()}
+		msgData, err := h.bytes(victim.Private, suite)
+		require.NoError(t, err)
+		buf, err := (&peering.PeerMessage{MsgType: peering.MsgTypeHandshake, MsgData: msgData}).Bytes()
+		require.NoError(t, err)
+		return buf
+	}
+	peerAddr := func() string {
+		<-time.After(200 * time.Millisecond)
+		n.peersLock.RLock()
+		defer n.peersLock.RUnlock()
+		p, ok := n.peers["localhost:9032"]
+		if !ok {
+			return ""
+		}
+		p.accessLock.RLock()
+		defer p.accessLock.RUnlock()
+		return p.remoteUDPAddr.String()
+	}
+
+	recorded := makeHandshake(time.Now())
+	_, err = connA.WriteToUDP(recorded, nodeAddr)
+	require.NoError(t, err)
+	require.Equal(t, connA.LocalAddr().String(), peerAddr())
+
+	// the replayed handshake
+	_, err = connB.WriteToUDP(recorded, nodeAddr)
+	require.NoError(t, err)
+	require.Equal(t, connA.LocalAddr().String(), peerAddr())
+
+	// the handshake, which is too old
+	_, err = connB.WriteToUDP(makeHandshake(time.Now().Add(-2*handshakeMaxAge)), nodeAddr)
+	require.NoError(t, err)
+	require.Equal(t, connA.LocalAddr().String(), peerAddr())
+
+	// the fresh handshake of the peer moves it to the new address
+	_, err = connB.WriteToUDP(makeHandshake(time.Now()), nodeAddr)
+	require.NoError(t, err)
+	require.Equal(t, connB.LocalAddr().String(), peerAddr())
+}
+
 func TestUDPAddrString(t *testing.T) {
 	var err error
 	var addr *net.UDPAddr
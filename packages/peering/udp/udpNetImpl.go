@@ -38,13 +38,22 @@ type NetImpl struct {
 	recvEvents  *events.Event
 	recvQueue   chan *peering.RecvEvent // A queue for received messages.
 	nodeKeyPair *key.Pair
+	trusted     peering.TrustedNetworkManager // Only trusted peers are paired, if set.
 	suite       Suite
 	log         *logger.Logger
 }
 
 // NewNetworkProvider is a constructor for the TCP based
-// peering network implementation.
-func NewNetworkProvider(myNetID string, port int, nodeKeyPair *key.Pair, suite Suite, log *logger.Logger) (*NetImpl, error) {
+// peering network implementation. If trusted is nil, handshakes
+// from all the peers are accepted.
+func NewNetworkProvider(
+	myNetID string,
+	port int,
+	nodeKeyPair *key.Pair,
+	trusted peering.TrustedNetworkManager,
+	suite Suite,
+	log *logger.Logger,
+) (*NetImpl, error) {
 	var err error
 	if err = peering.CheckMyNetID(myNetID, port); err != nil {
 		// can't continue because NetID parameter is not correct
@@ -70,6 +79,7 @@ func NewNetworkProvider(myNetID string, port int, nodeKeyPair *key.Pair, suite S
 		recvEvents:  nil, // Initialized bellow.
 		recvQueue:   make(chan *peering.RecvEvent, recvQueueSize),
 		nodeKeyPair: nodeKeyPair,
+		trusted:     trusted,
 		suite:       suite,
 		log:         log,
 	}
@@ -259,10 +269,24 @@ func (n *NetImpl) receiveLoop(stopCh chan bool) {
 			var h *handshakeMsg
 			if h, err = handshakeMsgFromBytes(peerMsg.MsgData, n.suite); err != nil {
 				n.log.Warnf("Error while decoding a UDP handshake, reason=%v", err)
+				break
+			}
+			if n.trusted != nil {
+				if err = n.trusted.IsTrustedPeer(h.pubKey, h.netID); err != nil {
+					n.log.Warnf("Dropping handshake from %v, reason=%v", peerUDPAddr, err)
+					break
+				}
+			}
+			if err = h.checkFresh(time.Now()); err != nil {
+				n.log.Warnf("Dropping handshake from %v, reason=%v", peerUDPAddr, err)
+				break
 			}
 			n.peersLock.Lock()
 			if p, ok := n.peers[h.netID]; ok {
-				if oldUDPAddrStr, newUDPAddrStr := p.handleHandshake(h, peerUDPAddr); oldUDPAddrStr != newUDPAddrStr {
+				var oldUDPAddrStr, newUDPAddrStr string
+				if oldUDPAddrStr, newUDPAddrStr, err = p.handleHandshake(h, peerUDPAddr); err != nil {
+					n.log.Warnf("Dropping handshake from %v, reason=%v", peerUDPAddr, err)
+				} else if oldUDPAddrStr != newUDPAddrStr {
 					// Update the index to find the peer later on.
 					n.peersByAddr[newUDPAddrStr] = p
 					delete(n.peersByAddr, oldUDPAddrStr)
@@ -270,9 +294,10 @@ func (n *NetImpl) receiveLoop(stopCh chan bool) {
 			} else {
 				if p, err = newPeerFromHandshake(h, peerUDPAddr, n); err != nil {
 					n.log.Warnf("Error while creating a peer based on UDP handshake, reason=%v", err)
+				} else {
+					n.peers[p.NetID()] = p
+					n.peersByAddr[p.remoteUDPAddr.String()] = p
 				}
-				n.peers[p.NetID()] = p
-				n.peersByAddr[p.remoteUDPAddr.String()] = p
 			}
 			n.peersLock.Unlock()
 		case peering.MsgTypeMsgChunk:
@@ -306,6 +331,10 @@ func (n *NetImpl) receiveUserMsg(msg *peering.PeerMessage, peerUDPAddr *net.UDPA
 	n.peersLock.RLock()
 	if p, ok := n.peersByAddr[remoteUDPAddrStr]; ok {
 		n.peersLock.RUnlock()
+		if !p.isPaired() {
+			n.log.Warnf("Dropping received message from not paired peer=%v", remoteUDPAddrStr)
+			return
+		}
 		p.noteReceived()
 		n.recvQueue <- &peering.RecvEvent{
 			From: p,
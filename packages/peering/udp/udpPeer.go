@@ -5,6 +5,7 @@ package udp
 
 import (
 	"errors"
+	"fmt"
 	"net"
 	"sync"
 	"time"
@@ -20,6 +21,7 @@ const (
 	inactiveDeadline   = 1 * time.Minute
 	inactivePingTime   = 30 * time.Second
 	sendMsgSyncTimeout = 3 * time.Second
+	handshakeMaxAge    = inactiveDeadline // Tolerated age of the handshake, including the clock skew.
 
 	maxChunkSize = 508 // Safe payload size for UDP.
 )
@@ -32,6 +34,7 @@ type peer struct {
 	accessLock    *sync.RWMutex
 	lastMsgSent   time.Time
 	lastMsgRecv   time.Time
+	lastHandshake int64 // Timestamp of the last accepted handshake, older ones are replays.
 	numUsers      int
 	msgChopper    *chopper.Chopper
 	net           *NetImpl
@@ -58,7 +61,11 @@ func newPeerFromHandshake(handshake *handshakeMsg, remoteUDPAddr *net.UDPAddr, n
 	if p, err = newPeer(handshake.netID, remoteUDPAddr, n); err != nil {
 		return nil, err
 	}
-	if oldUDPAddrStr, newUDPAddrStr := p.handleHandshake(handshake, remoteUDPAddr); oldUDPAddrStr != newUDPAddrStr {
+	oldUDPAddrStr, newUDPAddrStr, err := p.handleHandshake(handshake, remoteUDPAddr)
+	if err != nil {
+		return nil, err
+	}
+	if oldUDPAddrStr != newUDPAddrStr {
 		return nil, errors.New("inconsistent_udp_addr_on_create")
 	}
 	return p, nil
@@ -90,10 +97,17 @@ func (p *peer) usePeer() {
 	p.numUsers++
 }
 
-func (p *peer) handleHandshake(handshake *handshakeMsg, remoteUDPAddr *net.UDPAddr) (string, string) {
+// handleHandshake accepts the handshake, if it is newer than the previous one.
+// Otherwise it is a replay, and the remote UDPAddr of the peer is kept.
+func (p *peer) handleHandshake(handshake *handshakeMsg, remoteUDPAddr *net.UDPAddr) (string, string, error) {
 	p.accessLock.Lock()
 	oldUDPAddrStr := p.remoteUDPAddr.String()
 	newUDPAddrStr := remoteUDPAddr.String()
+	if handshake.timestamp <= p.lastHandshake {
+		p.accessLock.Unlock()
+		return oldUDPAddrStr, oldUDPAddrStr, fmt.Errorf("replayed handshake from %v", newUDPAddrStr)
+	}
+	p.lastHandshake = handshake.timestamp
 	if oldUDPAddrStr != newUDPAddrStr {
 		p.log.Warnf("Remote UDPAddr has changed, old=%v, new=%v", oldUDPAddrStr, newUDPAddrStr)
 		p.remoteUDPAddr = remoteUDPAddr
@@ -118,15 +132,16 @@ func (p *peer) handleHandshake(handshake *handshakeMsg, remoteUDPAddr *net.UDPAd
 		// Respond to the handshake, if asked.
 		p.sendHandshake(false)
 	}
-	return oldUDPAddrStr, newUDPAddrStr
+	return oldUDPAddrStr, newUDPAddrStr, nil
 }
 
 func (p *peer) sendHandshake(respond bool) {
 	var err error
 	handshake := handshakeMsg{
-		netID:   p.net.NetID(),
-		pubKey:  p.net.PubKey(),
-		respond: respond,
+		netID:     p.net.NetID(),
+		pubKey:    p.net.PubKey(),
+		respond:   respond,
+		timestamp: time.Now().UnixNano(),
 	}
 	var msgDataBin []byte
 	if msgDataBin, err = handshake.bytes(p.net.nodeKeyPair.Private, p.net.suite); err != nil {
@@ -139,6 +154,13 @@ func (p *peer) sendHandshake(respond bool) {
 	})
 }
 
+// isPaired returns true, if the handshake was received from the peer.
+func (p *peer) isPaired() bool {
+	p.accessLock.RLock()
+	defer p.accessLock.RUnlock()
+	return p.remotePubKey != nil
+}
+
 func (p *peer) noteReceived() {
 	p.accessLock.Lock()
 	p.lastMsgRecv = time.Now()
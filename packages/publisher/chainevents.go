@@ -0,0 +1,56 @@
+package publisher
+
+import (
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	valuetransaction "github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
+	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+)
+
+// StateTransition is published when the node commits new solid state of the chain
+type StateTransition struct {
+	ChainID    coretypes.ChainID
+	BlockIndex uint32
+	BlockSize  uint16
+	StateTxID  valuetransaction.ID
+	StateHash  hashing.HashValue
+	Timestamp  int64
+}
+
+// RequestCompleted is published when the block with the request is committed, after the StateTransition of the block.
+// Result and Error are known only if the node has run the block on the VM, otherwise they are empty
+type RequestCompleted struct {
+	ChainID    coretypes.ChainID
+	RequestID  coretypes.RequestID
+	BlockIndex uint32
+	Result     dict.Dict
+	Error      error
+}
+
+// ContractEvent is emitted by the smart contract with Sandbox.Event. It is published when the block with the request
+// is committed, before the RequestCompleted of the request. Events of the calls which were rolled back are not published.
+// Events are known only if the node has run the block on the VM
+type ContractEvent struct {
+	ChainID coretypes.ChainID
+	Hname   coretypes.Hname
+	Message string
+}
+
+// ChainRecordChanged is published when the chain record is saved to the registry
+type ChainRecordChanged struct {
+	ChainID coretypes.ChainID
+	Color   balance.Color
+	Active  bool
+}
+
+// ChainEvent is triggered with one of *StateTransition, *RequestCompleted, *ContractEvent or *ChainRecordChanged.
+// Unlike Event, it carries structured data, not strings
+var ChainEvent = events.NewEvent(func(handler interface{}, params ...interface{}) {
+	handler.(func(ev interface{}))(params[0])
+})
+
+func PublishChainEvent(ev interface{}) {
+	ChainEvent.Trigger(ev)
+}
@@ -20,6 +20,7 @@ type ChainRecord struct {
 	ChainID        coretypes.ChainID
 	Color          balance.Color // origin tx hash
 	CommitteeNodes []string      // "host_addr:port"
+	AccessNodes    []string      // "host_addr:port", follow the chain without taking part in the consensus
 	Active         bool
 }
 
@@ -42,6 +43,11 @@ func SaveChainRecord(bd *ChainRecord) error {
 		return err
 	}
 	publisher.Publish("chainrec", bd.ChainID.String(), bd.Color.String())
+	publisher.PublishChainEvent(&publisher.ChainRecordChanged{
+		ChainID: bd.ChainID,
+		Color:   bd.Color,
+		Active:  bd.Active,
+	})
 	return nil
 }
 
@@ -126,6 +132,9 @@ func (bd *ChainRecord) Write(w io.Writer) error {
 	if err := util.WriteBoolByte(w, bd.Active); err != nil {
 		return err
 	}
+	if err := util.WriteStrings16(w, bd.AccessNodes); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -143,6 +152,10 @@ func (bd *ChainRecord) Read(r io.Reader) error {
 	if err = util.ReadBoolByte(r, &bd.Active); err != nil {
 		return err
 	}
+	// records saved before the access nodes were introduced end here, which reads as no access nodes
+	if bd.AccessNodes, err = util.ReadStrings16(r); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -150,5 +163,6 @@ func (bd *ChainRecord) String() string {
 	ret := "      Target: " + bd.ChainID.String() + "\n"
 	ret += "      Color: " + bd.Color.String() + "\n"
 	ret += fmt.Sprintf("      Committee nodes: %+v\n", bd.CommitteeNodes)
+	ret += fmt.Sprintf("      Access nodes: %+v\n", bd.AccessNodes)
 	return ret
 }
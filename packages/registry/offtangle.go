@@ -0,0 +1,95 @@
+package registry
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/sctransaction"
+	"github.com/iotaledger/wasp/packages/util"
+	"github.com/iotaledger/wasp/plugins/database"
+)
+
+// off-tangle args of requests are stored in the registry by the hash of the args.
+// The hash is committed in the request transaction, the args are delivered by the web API or by peers.
+// Each record keeps the time when the args were saved, so the args which are not referenced
+// by any request can be pruned after some time
+
+func dbkeyOffTangleArgs(h *hashing.HashValue) []byte {
+	return database.MakeKey(database.ObjectTypeOffTangleArgs, h[:])
+}
+
+// SaveOffTangleArgs stores args in the registry and returns the hash of args
+func SaveOffTangleArgs(args dict.Dict) (hashing.HashValue, error) {
+	h := sctransaction.HashOffTangleArgs(args)
+	var buf bytes.Buffer
+	_ = util.WriteInt64(&buf, time.Now().UnixNano())
+	buf.Write(util.MustBytes(args))
+	if err := database.GetRegistryPartition().Set(dbkeyOffTangleArgs(&h), buf.Bytes()); err != nil {
+		return hashing.HashValue{}, err
+	}
+	return h, nil
+}
+
+// GetOffTangleArgs returns args with the hash or nil if args are not in the registry
+func GetOffTangleArgs(h *hashing.HashValue) (dict.Dict, error) {
+	data, err := database.GetRegistryPartition().Get(dbkeyOffTangleArgs(h))
+	if err == kvstore.ErrKeyNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 8 {
+		return nil, fmt.Errorf("GetOffTangleArgs: wrong record")
+	}
+	ret := dict.New()
+	if err := ret.Read(bytes.NewReader(data[8:])); err != nil {
+		return nil, err
+	}
+	if sctransaction.HashOffTangleArgs(ret) != *h {
+		return nil, fmt.Errorf("GetOffTangleArgs: hash of stored args mismatch")
+	}
+	return ret, nil
+}
+
+// HasOffTangleArgs checks if the args with the hash are in the registry
+func HasOffTangleArgs(h *hashing.HashValue) (bool, error) {
+	return database.GetRegistryPartition().Has(dbkeyOffTangleArgs(h))
+}
+
+// PruneOffTangleArgs deletes args saved before the given time, except those for which 'keep' returns true.
+// Returns number of deleted records
+func PruneOffTangleArgs(savedBefore time.Time, keep func(h *hashing.HashValue) bool) (int, error) {
+	db := database.GetRegistryPartition()
+	toDelete := make([][]byte, 0)
+	err := db.Iterate(database.MakeKey(database.ObjectTypeOffTangleArgs), func(key kvstore.Key, value kvstore.Value) bool {
+		h, err := hashing.HashValueFromBytes(key[1:])
+		if err != nil {
+			return true
+		}
+		if len(value) >= 8 {
+			ts, _ := util.Int64From8Bytes(value[:8])
+			if !time.Unix(0, ts).Before(savedBefore) {
+				return true
+			}
+		}
+		if keep(&h) {
+			return true
+		}
+		toDelete = append(toDelete, append([]byte{}, key...))
+		return true
+	})
+	if err != nil {
+		return 0, err
+	}
+	for _, key := range toDelete {
+		if err := db.Delete(key); err != nil {
+			return 0, err
+		}
+	}
+	return len(toDelete), nil
+}
@@ -0,0 +1,127 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"bytes"
+
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/wasp/packages/peering"
+	"github.com/iotaledger/wasp/packages/util"
+	"github.com/iotaledger/wasp/plugins/database"
+	"go.dedis.ch/kyber/v3"
+)
+
+// IsTrustedPeer implements peering.TrustedNetworkManager.
+func (r *Impl) IsTrustedPeer(pubKey kyber.Point, netID string) error {
+	tp, err := r.loadTrustedPeer(pubKey)
+	if err != nil {
+		return err
+	}
+	return peering.CheckTrustedPeer(tp, pubKey, netID)
+}
+
+// TrustPeer implements peering.TrustedNetworkManager.
+// Trusting an already trusted peer updates its NetID.
+func (r *Impl) TrustPeer(pubKey kyber.Point, netID string) (*peering.TrustedPeer, error) {
+	var err error
+	var dbKey []byte
+	if dbKey, err = dbKeyForTrustedPeer(pubKey); err != nil {
+		return nil, err
+	}
+	tp := &peering.TrustedPeer{PubKey: pubKey, NetID: netID}
+	var data []byte
+	if data, err = trustedPeerToBytes(tp); err != nil {
+		return nil, err
+	}
+	if err = database.GetRegistryPartition().Set(dbKey, data); err != nil {
+		return nil, err
+	}
+	r.log.Infof("Trusting peer %v with pubKey %v", netID, pubKey)
+	return tp, nil
+}
+
+// DistrustPeer implements peering.TrustedNetworkManager.
+// Returns the removed peer or nil, if the peer was not trusted.
+func (r *Impl) DistrustPeer(pubKey kyber.Point) (*peering.TrustedPeer, error) {
+	var err error
+	var tp *peering.TrustedPeer
+	if tp, err = r.loadTrustedPeer(pubKey); err != nil || tp == nil {
+		return nil, err
+	}
+	var dbKey []byte
+	if dbKey, err = dbKeyForTrustedPeer(pubKey); err != nil {
+		return nil, err
+	}
+	if err = database.GetRegistryPartition().Delete(dbKey); err != nil {
+		return nil, err
+	}
+	r.log.Infof("Distrusting peer %v with pubKey %v", tp.NetID, pubKey)
+	return tp, nil
+}
+
+// TrustedPeers implements peering.TrustedNetworkManager.
+func (r *Impl) TrustedPeers() ([]*peering.TrustedPeer, error) {
+	ret := make([]*peering.TrustedPeer, 0)
+	err := database.GetRegistryPartition().Iterate([]byte{database.ObjectTypeTrustedPeer}, func(key kvstore.Key, value kvstore.Value) bool {
+		if tp, err := trustedPeerFromBytes(value, r.suite); err == nil {
+			ret = append(ret, tp)
+		} else {
+			r.log.Warnf("corrupted trusted peer record with key %x", key)
+		}
+		return true
+	})
+	return ret, err
+}
+
+// loadTrustedPeer returns nil, if the peer is not trusted.
+func (r *Impl) loadTrustedPeer(pubKey kyber.Point) (*peering.TrustedPeer, error) {
+	var err error
+	var dbKey []byte
+	if dbKey, err = dbKeyForTrustedPeer(pubKey); err != nil {
+		return nil, err
+	}
+	var data []byte
+	data, err = database.GetRegistryPartition().Get(dbKey)
+	if err == kvstore.ErrKeyNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return trustedPeerFromBytes(data, r.suite)
+}
+
+func dbKeyForTrustedPeer(pubKey kyber.Point) ([]byte, error) {
+	pubKeyBytes, err := pubKey.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return database.MakeKey(database.ObjectTypeTrustedPeer, pubKeyBytes), nil
+}
+
+func trustedPeerToBytes(tp *peering.TrustedPeer) ([]byte, error) {
+	var err error
+	var w bytes.Buffer
+	if err = util.WriteString16(&w, tp.NetID); err != nil {
+		return nil, err
+	}
+	if err = util.WriteMarshaled(&w, tp.PubKey); err != nil {
+		return nil, err
+	}
+	return w.Bytes(), nil
+}
+
+func trustedPeerFromBytes(buf []byte, suite kyber.Group) (*peering.TrustedPeer, error) {
+	var err error
+	r := bytes.NewReader(buf)
+	tp := peering.TrustedPeer{PubKey: suite.Point()}
+	if tp.NetID, err = util.ReadString16(r); err != nil {
+		return nil, err
+	}
+	if err = util.ReadMarshaled(r, tp.PubKey); err != nil {
+		return nil, err
+	}
+	return &tp, nil
+}
@@ -0,0 +1,43 @@
+package sctransaction
+
+import (
+	"fmt"
+
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/util"
+)
+
+// ParamArgsHash is the only argument of the request with off-tangle args
+const ParamArgsHash = "$$argshash$$"
+
+// HashOffTangleArgs is the hash of the args the request with off-tangle args commits to.
+// It is taken over the exact serialized bytes of the args
+func HashOffTangleArgs(args dict.Dict) hashing.HashValue {
+	return hashing.HashData(util.MustBytes(args))
+}
+
+// WithOffTangleArgs replaces args of the request with the hash of the args.
+// The args themselves are delivered to the committee nodes off-tangle
+func (req *RequestSection) WithOffTangleArgs(args dict.Dict) *RequestSection {
+	if args == nil {
+		args = dict.New()
+	}
+	h := HashOffTangleArgs(args)
+	req.args = dict.New()
+	req.args.Set(ParamArgsHash, h[:])
+	return req
+}
+
+// IsOffTangle checks if only the hash of the args is committed in the request
+func (req *RequestSection) IsOffTangle() bool {
+	return len(req.args) == 1 && req.args.MustHas(ParamArgsHash)
+}
+
+// OffTangleArgsHash returns the hash of the off-tangle args of the request
+func (req *RequestSection) OffTangleArgsHash() (hashing.HashValue, error) {
+	if !req.IsOffTangle() {
+		return hashing.HashValue{}, fmt.Errorf("args of the request are not off-tangle")
+	}
+	return hashing.HashValueFromBytes(req.args.MustGet(ParamArgsHash))
+}
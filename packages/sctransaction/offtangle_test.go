@@ -0,0 +1,47 @@
+package sctransaction
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/vm/core/root"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOffTangleArgs(t *testing.T) {
+	args := dict.New()
+	args.Set("data", codec.EncodeString("some large payload"))
+	cid := coretypes.NewContractID(coretypes.ChainID{}, root.Interface.Hname())
+	rsec := NewRequestSectionByWallet(cid, coretypes.Hn("store")).WithOffTangleArgs(args)
+	require.True(t, rsec.IsOffTangle())
+	require.False(t, rsec.Args().MustHas("data"))
+
+	var buf bytes.Buffer
+	require.NoError(t, rsec.Write(&buf))
+	rsecBack := &RequestSection{}
+	require.NoError(t, rsecBack.Read(bytes.NewReader(buf.Bytes())))
+	require.True(t, rsecBack.IsOffTangle())
+
+	h, err := rsecBack.OffTangleArgsHash()
+	require.NoError(t, err)
+	require.EqualValues(t, HashOffTangleArgs(args), h)
+}
+
+func TestOffTangleArgsHashUnambiguous(t *testing.T) {
+	// both dicts concatenate to the same bytes, so Dict.Hash() can't tell them apart
+	args1 := dict.Dict{"ab": []byte("c")}
+	args2 := dict.Dict{"a": []byte("bc")}
+	require.EqualValues(t, args1.Hash(), args2.Hash())
+	require.NotEqual(t, HashOffTangleArgs(args1), HashOffTangleArgs(args2))
+}
+
+func TestNotOffTangle(t *testing.T) {
+	cid := coretypes.NewContractID(coretypes.ChainID{}, root.Interface.Hname())
+	rsec := NewRequestSectionByWallet(cid, coretypes.EntryPointInit).WithArgs(dict.Dict{"a": []byte{1}})
+	require.False(t, rsec.IsOffTangle())
+	_, err := rsec.OffTangleArgsHash()
+	require.Error(t, err)
+}
@@ -20,3 +20,31 @@ func TestWriteRead(t *testing.T) {
 	require.NoError(t, err)
 	require.EqualValues(t, buf1.Bytes(), buf.Bytes())
 }
+
+func TestWriteReadGasLimit(t *testing.T) {
+	cid := coretypes.NewContractID(coretypes.ChainID{}, root.Interface.Hname())
+	rsec := NewRequestSectionByWallet(cid, coretypes.EntryPointInit).WithGasLimit(12345)
+	var buf bytes.Buffer
+	err := rsec.Write(&buf)
+	require.NoError(t, err)
+	rsecBack := &RequestSection{}
+	err = rsecBack.Read(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	require.EqualValues(t, 12345, rsecBack.GasLimit())
+	require.EqualValues(t, 12345, rsecBack.Clone().GasLimit())
+}
+
+func TestWriteReadCallback(t *testing.T) {
+	cid := coretypes.NewContractID(coretypes.ChainID{}, root.Interface.Hname())
+	rsec := NewRequestSection(coretypes.Hn("sender"), cid, coretypes.EntryPointInit).
+		WithCallback(coretypes.Hn("callback"), 42)
+	var buf bytes.Buffer
+	err := rsec.Write(&buf)
+	require.NoError(t, err)
+	rsecBack := &RequestSection{}
+	err = rsecBack.Read(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	require.EqualValues(t, coretypes.Hn("callback"), rsecBack.Callback())
+	require.EqualValues(t, 42, rsecBack.CallbackNonce())
+	require.EqualValues(t, 42, rsecBack.Clone().CallbackNonce())
+}
@@ -33,6 +33,12 @@ type RequestSection struct {
 	// settles the request is greater or equal to the request timelock.
 	// 0 timelock naturally means it has no effect
 	timelock uint32
+	// maximum gas the request may burn. 0 means vmtypes.MaxGasPerRequest
+	gasLimit uint32
+	// entry point of the sender contract which receives the receipt of the request. 0 means no receipt
+	callback coretypes.Hname
+	// identifies the request among the outstanding requests of the sender chain. Returned with the receipt
+	callbackNonce uint32
 	// input arguments in the form of variable/value pairs
 	args dict.Dict
 	// all tokens transferred with the request EXCEPT the 1 minted request token
@@ -71,6 +77,8 @@ func (req *RequestSection) Clone() *RequestSection {
 	}
 	ret := NewRequestSection(req.senderContractHname, req.targetContractID, req.entryPoint).
 		WithTimelock(req.timelock).
+		WithGasLimit(req.gasLimit).
+		WithCallback(req.callback, req.callbackNonce).
 		WithTransfer(req.transfer)
 	ret.args = req.args.Clone()
 	return ret
@@ -103,6 +111,18 @@ func (req *RequestSection) Timelock() uint32 {
 	return req.timelock
 }
 
+func (req *RequestSection) GasLimit() uint32 {
+	return req.gasLimit
+}
+
+func (req *RequestSection) Callback() coretypes.Hname {
+	return req.callback
+}
+
+func (req *RequestSection) CallbackNonce() uint32 {
+	return req.callbackNonce
+}
+
 func (req *RequestSection) Transfer() coretypes.ColoredBalances {
 	return req.transfer
 }
@@ -112,6 +132,18 @@ func (req *RequestSection) WithTimelock(tl uint32) *RequestSection {
 	return req
 }
 
+func (req *RequestSection) WithGasLimit(gasLimit uint32) *RequestSection {
+	req.gasLimit = gasLimit
+	return req
+}
+
+// WithCallback requests the receipt to be posted back to the callback entry point of the sender contract
+func (req *RequestSection) WithCallback(callback coretypes.Hname, nonce uint32) *RequestSection {
+	req.callback = callback
+	req.callbackNonce = nonce
+	return req
+}
+
 func (req *RequestSection) WithTransfer(transfer coretypes.ColoredBalances) *RequestSection {
 	if transfer == nil {
 		transfer = cbalances.NewFromMap(nil)
@@ -136,6 +168,15 @@ func (req *RequestSection) Write(w io.Writer) error {
 	if err := util.WriteUint32(w, req.timelock); err != nil {
 		return err
 	}
+	if err := util.WriteUint32(w, req.gasLimit); err != nil {
+		return err
+	}
+	if err := req.callback.Write(w); err != nil {
+		return err
+	}
+	if err := util.WriteUint32(w, req.callbackNonce); err != nil {
+		return err
+	}
 	if err := req.entryPoint.Write(w); err != nil {
 		return err
 	}
@@ -158,6 +199,15 @@ func (req *RequestSection) Read(r io.Reader) error {
 	if err := util.ReadUint32(r, &req.timelock); err != nil {
 		return err
 	}
+	if err := util.ReadUint32(r, &req.gasLimit); err != nil {
+		return err
+	}
+	if err := req.callback.Read(r); err != nil {
+		return err
+	}
+	if err := util.ReadUint32(r, &req.callbackNonce); err != nil {
+		return err
+	}
 	if err := req.entryPoint.Read(r); err != nil {
 		return err
 	}
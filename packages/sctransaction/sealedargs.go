@@ -0,0 +1,197 @@
+package sctransaction
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"io"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/util"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+// ParamSealedArgs is the only argument of the request with sealed args
+const ParamSealedArgs = "$$sealed$$"
+
+// SealedArgs is the 'stealth mode' of request arguments: the arguments are encrypted with the
+// symmetric key, derived from the ElGamal encryption to the shared public key of the committee.
+// No single node can decrypt the arguments: the committee recovers the key from the decryption
+// shares of a quorum of nodes in the consensus, right before running the request in the VM.
+// Hence either all nodes unseal the args or none
+type SealedArgs struct {
+	// ephemeral public key R = r·G of the sender. The key is derived from r·P = x·R,
+	// where P = x·G is the shared public key of the committee
+	EphemeralKey []byte
+	// AES-GCM nonce and encrypted arguments
+	Nonce      []byte
+	Ciphertext []byte
+}
+
+// SealArgs encrypts the args to the committee, given by the shared public key of the chain address
+func SealArgs(args dict.Dict, suite kyber.Group, sharedPublic kyber.Point) (*SealedArgs, error) {
+	if sharedPublic == nil {
+		return nil, fmt.Errorf("SealArgs: shared public key of the committee is not provided")
+	}
+	r := suite.Scalar().Pick(random.New())
+	ephemeralKey := suite.Point().Mul(r, nil)
+	ephemeralBytes, err := ephemeralKey.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	key, err := sealingKey(suite.Point().Mul(r, sharedPublic), ephemeralBytes)
+	if err != nil {
+		return nil, err
+	}
+	aesgcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	ret := &SealedArgs{
+		EphemeralKey: ephemeralBytes,
+		Nonce:        make([]byte, aesgcm.NonceSize()),
+	}
+	if _, err := rand.Read(ret.Nonce); err != nil {
+		return nil, err
+	}
+	if args == nil {
+		args = dict.New()
+	}
+	ret.Ciphertext = aesgcm.Seal(nil, ret.Nonce, util.MustBytes(args), ret.EphemeralKey)
+	return ret, nil
+}
+
+// Key derives the symmetric key of the args from the secret x·R, recovered by the committee
+// from the decryption shares of the ephemeral key
+func (s *SealedArgs) Key(secret kyber.Point) ([]byte, error) {
+	return sealingKey(secret, s.EphemeralKey)
+}
+
+// Unseal decrypts the args with the symmetric key. AES-GCM authenticates the ciphertext
+// together with the ephemeral key, so a wrong key or tampered args fail to decrypt
+func (s *SealedArgs) Unseal(key []byte) (dict.Dict, error) {
+	aesgcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(s.Nonce) != aesgcm.NonceSize() {
+		return nil, fmt.Errorf("Unseal: wrong nonce size")
+	}
+	plaintext, err := aesgcm.Open(nil, s.Nonce, s.Ciphertext, s.EphemeralKey)
+	if err != nil {
+		return nil, fmt.Errorf("Unseal: can't decrypt args: %v", err)
+	}
+	ret := dict.New()
+	if err := ret.Read(bytes.NewReader(plaintext)); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// VerifySealingKey checks the shared public key of the committee before sealing args to it:
+// it must be the key of the chain address
+func VerifySealingKey(chainAddr *address.Address, sharedPublic kyber.Point) error {
+	pubBytes, err := sharedPublic.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	if address.FromBLSPubKey(pubBytes) != *chainAddr {
+		return fmt.Errorf("VerifySealingKey: shared public key is not the key of the address %s", chainAddr.String())
+	}
+	return nil
+}
+
+func sealingKey(secret kyber.Point, ephemeralKey []byte) ([]byte, error) {
+	secretBytes, err := secret.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	h := hashing.HashData(secretBytes, ephemeralKey)
+	return h[:], nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Dict returns sealed args in the form of request args
+func (s *SealedArgs) Dict() dict.Dict {
+	ret := dict.New()
+	ret.Set(ParamSealedArgs, util.MustBytes(s))
+	return ret
+}
+
+// IsSealedArgs checks if args are encrypted to the committee
+func IsSealedArgs(args dict.Dict) bool {
+	return len(args) == 1 && args.MustHas(ParamSealedArgs)
+}
+
+// SealedArgsFromDict decodes sealed args from the form of request args
+func SealedArgsFromDict(args dict.Dict) (*SealedArgs, error) {
+	if !IsSealedArgs(args) {
+		return nil, fmt.Errorf("args are not sealed")
+	}
+	ret := new(SealedArgs)
+	if err := ret.Read(bytes.NewReader(args.MustGet(ParamSealedArgs))); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// WithSealedArgs replaces args of the request with the args encrypted to the committee
+func (req *RequestSection) WithSealedArgs(args dict.Dict, suite kyber.Group, sharedPublic kyber.Point) (*RequestSection, error) {
+	sealed, err := SealArgs(args, suite, sharedPublic)
+	if err != nil {
+		return nil, err
+	}
+	req.args = sealed.Dict()
+	return req, nil
+}
+
+// IsSealed checks if args of the request are encrypted to the committee
+func (req *RequestSection) IsSealed() bool {
+	return IsSealedArgs(req.args)
+}
+
+// SealedArgs returns encrypted args of the request
+func (req *RequestSection) SealedArgs() (*SealedArgs, error) {
+	return SealedArgsFromDict(req.args)
+}
+
+// encoding
+
+func (s *SealedArgs) Write(w io.Writer) error {
+	if err := util.WriteBytes16(w, s.EphemeralKey); err != nil {
+		return err
+	}
+	if err := util.WriteBytes16(w, s.Nonce); err != nil {
+		return err
+	}
+	if err := util.WriteBytes32(w, s.Ciphertext); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *SealedArgs) Read(r io.Reader) error {
+	var err error
+	if s.EphemeralKey, err = util.ReadBytes16(r); err != nil {
+		return err
+	}
+	if s.Nonce, err = util.ReadBytes16(r); err != nil {
+		return err
+	}
+	if s.Ciphertext, err = util.ReadBytes32(r); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package sctransaction
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/tcrypto"
+	"github.com/iotaledger/wasp/packages/util"
+	"github.com/iotaledger/wasp/packages/vm/core/root"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/share"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+// makeDKShares creates key shares of the committee with the threshold t, as if produced by the DKG
+func makeDKShares(t *testing.T, suite *pairing.SuiteBn256, n, threshold uint16) []*tcrypto.DKShare {
+	priPoly := share.NewPriPoly(suite, int(threshold), nil, random.New())
+	pubPoly := priPoly.Commit(nil)
+	_, commits := pubPoly.Info()
+	for len(commits) < int(n)-1 {
+		commits = append(commits, suite.Point().Null())
+	}
+	priShares := priPoly.Shares(int(n))
+	pubShares := make([]kyber.Point, n)
+	for i := range priShares {
+		pubShares[i] = suite.Point().Mul(priShares[i].V, nil)
+	}
+	ret := make([]*tcrypto.DKShare, n)
+	for i := range ret {
+		dks, err := tcrypto.NewDKShare(uint16(i), n, threshold, pubPoly.Commit(), commits, pubShares, priShares[i].V)
+		require.NoError(t, err)
+		data, err := dks.Bytes()
+		require.NoError(t, err)
+		ret[i], err = tcrypto.DKShareFromBytes(data, suite)
+		require.NoError(t, err)
+	}
+	return ret
+}
+
+// decryptionShares returns decryption shares of the sealed args by the nodes
+func decryptionShares(t *testing.T, sealed *SealedArgs, dkshares []*tcrypto.DKShare) [][]byte {
+	var err error
+	ret := make([][]byte, len(dkshares))
+	for i, dks := range dkshares {
+		ret[i], err = dks.DecryptionShare(sealed.EphemeralKey)
+		require.NoError(t, err)
+	}
+	return ret
+}
+
+func unsealWithShares(sealed *SealedArgs, dks *tcrypto.DKShare, decShares [][]byte) (dict.Dict, error) {
+	secret, err := dks.RecoverDecryptionSecret(sealed.EphemeralKey, decShares)
+	if err != nil {
+		return nil, err
+	}
+	key, err := sealed.Key(secret)
+	if err != nil {
+		return nil, err
+	}
+	return sealed.Unseal(key)
+}
+
+func TestSealedArgs(t *testing.T) {
+	suite := pairing.NewSuiteBn256()
+	const n, threshold = 4, 3
+	dkshares := makeDKShares(t, suite, n, threshold)
+
+	args := dict.New()
+	args.Set("bid", codec.EncodeInt64(1000))
+	cid := coretypes.NewContractID(coretypes.ChainID{}, root.Interface.Hname())
+	rsec, err := NewRequestSectionByWallet(cid, coretypes.Hn("placeBid")).
+		WithSealedArgs(args, suite, dkshares[0].SharedPublic)
+	require.NoError(t, err)
+	require.True(t, rsec.IsSealed())
+	require.False(t, rsec.Args().MustHas("bid"))
+
+	var buf bytes.Buffer
+	require.NoError(t, rsec.Write(&buf))
+	rsecBack := &RequestSection{}
+	require.NoError(t, rsecBack.Read(bytes.NewReader(buf.Bytes())))
+	require.True(t, rsecBack.IsSealed())
+
+	sealed, err := rsecBack.SealedArgs()
+	require.NoError(t, err)
+	decShares := decryptionShares(t, sealed, dkshares)
+
+	// any quorum of shares unseals the same args on any node
+	for _, dks := range dkshares {
+		for skip := range decShares {
+			quorum := append(append([][]byte{}, decShares[:skip]...), decShares[skip+1:]...)
+			unsealed, err := unsealWithShares(sealed, dks, quorum)
+			require.NoError(t, err)
+			require.EqualValues(t, args.Hash(), unsealed.Hash())
+		}
+	}
+
+	// less than quorum of shares
+	_, err = unsealWithShares(sealed, dkshares[0], decShares[:threshold-1])
+	require.Error(t, err)
+
+	// the same share twice doesn't make the quorum
+	_, err = unsealWithShares(sealed, dkshares[0], [][]byte{decShares[0], decShares[1], decShares[1]})
+	require.Error(t, err)
+
+	// key shares of other committee can't unseal
+	other := makeDKShares(t, suite, n, threshold)
+	otherShares := decryptionShares(t, sealed, other)
+	_, err = unsealWithShares(sealed, other[0], otherShares)
+	require.Error(t, err)
+	// and their shares are not accepted as the shares of the committee
+	_, err = unsealWithShares(sealed, dkshares[0], otherShares)
+	require.Error(t, err)
+
+	// tampered args fail to decrypt
+	tampered := *sealed
+	tampered.Ciphertext = append([]byte{}, sealed.Ciphertext...)
+	tampered.Ciphertext[0] ^= 1
+	_, err = unsealWithShares(&tampered, dkshares[0], decShares)
+	require.Error(t, err)
+}
+
+func TestForgedDecryptionShare(t *testing.T) {
+	suite := pairing.NewSuiteBn256()
+	dkshares := makeDKShares(t, suite, 4, 3)
+	ephemeralKey, err := suite.Point().Pick(random.New()).MarshalBinary()
+	require.NoError(t, err)
+
+	decShare, err := dkshares[1].DecryptionShare(ephemeralKey)
+	require.NoError(t, err)
+	sh, err := dkshares[0].VerifyDecryptionShare(ephemeralKey, decShare)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, sh.I)
+
+	// share of another ephemeral key
+	otherKey, err := suite.Point().Pick(random.New()).MarshalBinary()
+	require.NoError(t, err)
+	_, err = dkshares[0].VerifyDecryptionShare(otherKey, decShare)
+	require.Error(t, err)
+
+	// share claimed by another node
+	forged := append([]byte{}, decShare...)
+	forged[0], forged[1] = 2, 0
+	_, err = dkshares[0].VerifyDecryptionShare(ephemeralKey, forged)
+	require.Error(t, err)
+
+	// random point instead of the share
+	var buf bytes.Buffer
+	require.NoError(t, util.WriteUint16(&buf, 1))
+	require.NoError(t, util.WriteMarshaled(&buf, suite.Point().Pick(random.New())))
+	forged = append(buf.Bytes(), decShare[buf.Len():]...)
+	_, err = dkshares[0].VerifyDecryptionShare(ephemeralKey, forged)
+	require.Error(t, err)
+}
+
+func TestVerifySealingKey(t *testing.T) {
+	suite := pairing.NewSuiteBn256()
+	dkshares := makeDKShares(t, suite, 4, 3)
+	chainAddr := *dkshares[0].Address
+
+	require.NoError(t, VerifySealingKey(&chainAddr, dkshares[0].SharedPublic))
+
+	// key of another address
+	otherAddr := address.Random()
+	require.Error(t, VerifySealingKey(&otherAddr, dkshares[0].SharedPublic))
+
+	// the public key share of the node is not the key of the chain
+	require.Error(t, VerifySealingKey(&chainAddr, dkshares[0].PublicShares[0]))
+}
+
+func TestNotSealed(t *testing.T) {
+	cid := coretypes.NewContractID(coretypes.ChainID{}, root.Interface.Hname())
+	rsec := NewRequestSectionByWallet(cid, coretypes.EntryPointInit).WithArgs(dict.Dict{"a": []byte{1}})
+	require.False(t, rsec.IsSealed())
+	_, err := rsec.SealedArgs()
+	require.Error(t, err)
+}
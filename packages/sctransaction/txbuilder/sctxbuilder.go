@@ -10,6 +10,7 @@ import (
 	"github.com/iotaledger/wasp/packages/hashing"
 	"github.com/iotaledger/wasp/packages/sctransaction"
 	"github.com/iotaledger/wasp/packages/txutil/vtxbuilder"
+	"go.dedis.ch/kyber/v3"
 )
 
 type Builder struct {
@@ -76,7 +77,13 @@ func (txb *Builder) SetStateParams(stateIndex uint32, stateHash *hashing.HashVal
 // AddRequestSectionWithTransfer adds request block with the request
 // token and adds respective outputs for the colored transfers
 func (txb *Builder) AddRequestSection(req *sctransaction.RequestSection) error {
-	targetAddr := (address.Address)(req.Target().ChainID())
+	return txb.AddRequestSectionToAddress(req, (address.Address)(req.Target().ChainID()))
+}
+
+// AddRequestSectionToAddress adds request block and sends the request token and the transfer to the
+// specified address of the target chain. It is needed when the committee of the target chain was rotated
+// and the chain is controlled by the address different from the chain ID
+func (txb *Builder) AddRequestSectionToAddress(req *sctransaction.RequestSection, targetAddr address.Address) error {
 	if err := txb.MintColor(targetAddr, balance.ColorIOTA, 1); err != nil {
 		return err
 	}
@@ -94,6 +101,15 @@ func (txb *Builder) AddRequestSection(req *sctransaction.RequestSection) error {
 	return nil
 }
 
+// AddSealedRequestSection adds request block with the args encrypted to the committee of the target chain.
+// The sharedPublic is the shared public key of the DKShare, which controls the address of the target chain
+func (txb *Builder) AddSealedRequestSection(req *sctransaction.RequestSection, suite kyber.Group, sharedPublic kyber.Point) error {
+	if _, err := req.WithSealedArgs(req.Args(), suite, sharedPublic); err != nil {
+		return err
+	}
+	return txb.AddRequestSection(req)
+}
+
 func (txb *Builder) Build(useAllInputs bool) (*sctransaction.Transaction, error) {
 	return sctransaction.NewTransaction(
 		txb.Builder.Build(useAllInputs),
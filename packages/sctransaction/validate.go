@@ -6,6 +6,7 @@ import (
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
 	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/coretypes/cbalances"
 	"github.com/iotaledger/wasp/packages/txutil"
 )
 
@@ -81,3 +82,35 @@ func (tx *Transaction) validateRequests(isOrigin bool) error {
 	}
 	return errors.New("invalid tokens")
 }
+
+// ValidateRequestsToAddress checks the outputs to the address against the transfers of the requests to the chain.
+// After the committee of the chain was rotated, the requests are sent to the current address of the chain,
+// which differs from the chain ID, so the outputs to it are not checked when the transaction is parsed.
+// The requests sent to the address of the chain ID are valid, because such outputs are checked when the
+// transaction is parsed. Their tokens are forwarded to the current address of the chain later
+func (tx *Transaction) ValidateRequestsToAddress(chainID *coretypes.ChainID, addr address.Address) error {
+	if _, ok := tx.OutputBalancesByAddress(address.Address(*chainID)); ok {
+		return nil
+	}
+	expected := make(map[balance.Color]int64)
+	for _, req := range tx.Requests() {
+		if req.Target().ChainID() != *chainID {
+			continue
+		}
+		req.Transfer().AddToMap(expected)
+		// add one request token
+		expected[balance.ColorNew]++
+	}
+	if len(expected) == 0 {
+		return nil
+	}
+	bals, _ := tx.OutputBalancesByAddress(addr)
+	diff := cbalances.NewFromBalances(bals).Diff(cbalances.NewFromMap(expected))
+	if diff.Balance(balance.ColorNew) != 0 {
+		return fmt.Errorf("wrong number of minted tokens in the output to the address %s", addr.String())
+	}
+	if !diff.NonNegative() {
+		return fmt.Errorf("mismatch between request metadata and outputs for address %s", addr.String())
+	}
+	return nil
+}
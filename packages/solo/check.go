@@ -11,6 +11,7 @@ import (
 	"github.com/iotaledger/wasp/packages/vm/core/accounts"
 	"github.com/iotaledger/wasp/packages/vm/core/blob"
 	"github.com/iotaledger/wasp/packages/vm/core/eventlog"
+	"github.com/iotaledger/wasp/packages/vm/core/governance"
 	"github.com/iotaledger/wasp/packages/vm/core/root"
 	"github.com/stretchr/testify/require"
 )
@@ -52,6 +53,13 @@ func (ch *Chain) CheckChain() {
 	require.EqualValues(ch.Env.T, eventlog.Interface.ProgramHash, chainlogRec.ProgramHash)
 	require.EqualValues(ch.Env.T, ch.OriginatorAgentID, chainlogRec.Creator)
 
+	governanceRec, err := ch.FindContract(governance.Interface.Name)
+	require.NoError(ch.Env.T, err)
+	require.EqualValues(ch.Env.T, governance.Interface.Name, governanceRec.Name)
+	require.EqualValues(ch.Env.T, governance.Interface.Description, governanceRec.Description)
+	require.EqualValues(ch.Env.T, governance.Interface.ProgramHash, governanceRec.ProgramHash)
+	require.EqualValues(ch.Env.T, ch.OriginatorAgentID, governanceRec.Creator)
+
 	ch.CheckAccountLedger()
 }
 
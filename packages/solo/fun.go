@@ -17,10 +17,12 @@ import (
 	"github.com/iotaledger/wasp/packages/kv/codec"
 	"github.com/iotaledger/wasp/packages/kv/collections"
 	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/vm/contract"
 	"github.com/iotaledger/wasp/packages/vm/core/accounts"
 	"github.com/iotaledger/wasp/packages/vm/core/blob"
 	"github.com/iotaledger/wasp/packages/vm/core/eventlog"
 	"github.com/iotaledger/wasp/packages/vm/core/root"
+	"github.com/iotaledger/wasp/packages/vm/examples"
 	"github.com/iotaledger/wasp/plugins/wasmtimevm"
 	"github.com/stretchr/testify/require"
 	"io/ioutil"
@@ -179,12 +181,76 @@ func (ch *Chain) GetWasmBinary(progHash hashing.HashValue) ([]byte, error) {
 //     smart contact must be made available with the call examples.AddProcessor
 func (ch *Chain) DeployContract(sigScheme signaturescheme.SignatureScheme, name string, programHash hashing.HashValue, params ...interface{}) error {
 	par := []interface{}{root.ParamProgramHash, programHash, root.ParamName, name}
+	par = append(par, exampleABI(programHash)...)
 	par = append(par, params...)
 	req := NewCall(root.Interface.Name, root.FuncDeployContract, par...)
 	_, err := ch.PostRequest(req, sigScheme)
 	return err
 }
 
+// UpgradeContract replaces the program of the deployed smart contract with the program of the programHash.
+// The 'migrate' entry point of the new program, if present, is called with the params
+func (ch *Chain) UpgradeContract(sigScheme signaturescheme.SignatureScheme, name string, programHash hashing.HashValue, params ...interface{}) error {
+	par := []interface{}{root.ParamProgramHash, programHash, root.ParamHname, coretypes.Hn(name)}
+	par = append(par, exampleABI(programHash)...)
+	par = append(par, params...)
+	req := NewCall(root.Interface.Name, root.FuncUpgradeContract, par...)
+	_, err := ch.PostRequest(req, sigScheme)
+	return err
+}
+
+// exampleABI returns the ABI parameter for deployment of the builtin example program.
+// Programs in blobs carry their ABI in the blob
+func exampleABI(programHash hashing.HashValue) []interface{} {
+	proc, ok := examples.GetExampleProcessor(programHash)
+	if !ok {
+		return nil
+	}
+	itf, ok := proc.(*contract.ContractInterface)
+	if !ok {
+		return nil
+	}
+	return []interface{}{root.ParamABI, itf.ABI().Bytes()}
+}
+
+// GetContractABI returns the ABI of the deployed contract. Returns nil if the ABI of the contract is not known
+func (ch *Chain) GetContractABI(name string) (*contract.ABI, error) {
+	res, err := ch.CallView(root.Interface.Name, root.FuncGetContractABI, root.ParamHname, coretypes.Hn(name))
+	if err != nil {
+		return nil, err
+	}
+	data := res.MustGet(root.ParamData)
+	if data == nil {
+		return nil, nil
+	}
+	return contract.ABIFromBytes(data)
+}
+
+// RotateCommittee hands the chain over to the new committee. In Solo the committee is represented by the
+// signature scheme of the chain address. The request to the root contract is signed by the sigScheme,
+// which must belong to the chain owner. After the call the chain token and all funds of the chain
+// are on the new address and the chain continues with the newSigScheme
+func (ch *Chain) RotateCommittee(sigScheme signaturescheme.SignatureScheme, newSigScheme signaturescheme.SignatureScheme) error {
+	req := NewCall(root.Interface.Name, root.FuncRotateCommittee, root.ParamChainAddress, newSigScheme.Address())
+	// one iota per chain in the directory of chain addresses pays for the announcement of the new address
+	res, err := ch.CallView(root.Interface.Name, root.FuncGetChainAddresses)
+	if err != nil {
+		return err
+	}
+	if n := collections.NewMapReadOnly(res, root.VarChainAddresses).MustLen(); n > 0 {
+		req.WithTransfer(balance.ColorIOTA, int64(n))
+	}
+	if _, err := ch.PostRequest(req, sigScheme); err != nil {
+		return err
+	}
+	ch.runVMMutex.Lock()
+	defer ch.runVMMutex.Unlock()
+
+	ch.ChainSigScheme = newSigScheme
+	ch.ChainAddress = newSigScheme.Address()
+	return nil
+}
+
 // DeployWasmContract is syntactic sugar for uploading Wasm binary from file and
 // deploying the smart contract in one call
 func (ch *Chain) DeployWasmContract(sigScheme signaturescheme.SignatureScheme, name string, fname string, params ...interface{}) error {
@@ -364,6 +430,37 @@ func (ch *Chain) GetEventLogRecordsString(name string) (string, error) {
 	return ret, nil
 }
 
+// GetEvents retrieves all structured events of the contract in the order they were emitted,
+// following the cursor of the 'getEvents' view of the 'eventlog'.
+// Optional filter is given as pairs of params of the view, e.g. eventlog.ParamEventName, "transfer"
+func (ch *Chain) GetEvents(name string, filter ...interface{}) ([]*eventlog.Event, error) {
+	params := append([]interface{}{eventlog.ParamContractHname, coretypes.Hn(name)}, filter...)
+	ret := make([]*eventlog.Event, 0)
+	args := params
+	for {
+		res, err := ch.CallView(eventlog.Interface.Name, eventlog.FuncGetEvents, args...)
+		if err != nil {
+			return nil, err
+		}
+		recs := collections.NewArrayReadOnly(res, eventlog.ParamRecords)
+		for i := uint16(0); i < recs.MustLen(); i++ {
+			e, err := eventlog.DecodeEvent(recs.MustGetAt(i))
+			if err != nil {
+				return nil, err
+			}
+			ret = append(ret, e)
+		}
+		cursor, ok, err := codec.DecodeInt64(res.MustGet(eventlog.ParamCursor))
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return ret, nil
+		}
+		args = append(params[:len(params):len(params)], eventlog.ParamCursor, cursor)
+	}
+}
+
 // GetEventLogNumRecords returns total number of eventlog records for the given contact.
 func (ch *Chain) GetEventLogNumRecords(name string) int {
 	res, err := ch.CallView(eventlog.Interface.Name, eventlog.FuncGetNumRecords,
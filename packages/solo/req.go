@@ -25,6 +25,7 @@ type CallParams struct {
 	epName     string
 	entryPoint coretypes.Hname
 	transfer   coretypes.ColoredBalances
+	gasLimit   uint32
 	params     dict.Dict
 }
 
@@ -60,6 +61,12 @@ func (r *CallParams) WithTransfers(transfer map[balance.Color]int64) *CallParams
 	return r
 }
 
+// WithGasLimit limits the gas the request may burn. By default the request gets the maximum gas allowed
+func (r *CallParams) WithGasLimit(gasLimit uint32) *CallParams {
+	r.gasLimit = gasLimit
+	return r
+}
+
 func toMap(params ...interface{}) map[string]interface{} {
 	par := make(map[string]interface{})
 	if len(params) == 0 {
@@ -107,8 +114,9 @@ func (ch *Chain) PostRequest(req *CallParams, sigScheme signaturescheme.Signatur
 
 	reqSect := sctransaction.NewRequestSectionByWallet(coretypes.NewContractID(ch.ChainID, req.target), req.entryPoint).
 		WithTransfer(req.transfer).
+		WithGasLimit(req.gasLimit).
 		WithArgs(req.params)
-	err = txb.AddRequestSection(reqSect)
+	err = txb.AddRequestSectionToAddress(reqSect, ch.ChainAddress)
 	require.NoError(ch.Env.T, err)
 
 	tx, err := txb.Build(false)
@@ -27,6 +27,7 @@ func (ch *Chain) runBatch(batch []vm.RequestRefWithFreeTokens, trace string) (di
 		Processors:         ch.proc,
 		ChainID:            ch.ChainID,
 		Color:              ch.ChainColor,
+		Address:            ch.ChainAddress,
 		Entropy:            *hashing.RandomHash(nil),
 		ValidatorFeeTarget: ch.ValidatorFeeTarget,
 		Balances:           waspconn.OutputsToBalances(ch.Env.utxoDB.GetAddressOutputs(ch.ChainAddress)),
@@ -54,6 +55,7 @@ func (ch *Chain) runBatch(batch []vm.RequestRefWithFreeTokens, trace string) (di
 	task.ResultTransaction.Sign(ch.ChainSigScheme)
 
 	ch.settleStateTransition(task.VirtualState, task.ResultBlock, task.ResultTransaction)
+	ch.requeueUnprocessed(batch, task.ResultBlock)
 	return callRes, callErr
 }
 
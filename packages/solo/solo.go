@@ -11,13 +11,17 @@ import (
 	"github.com/iotaledger/hive.go/crypto/ed25519"
 	"github.com/iotaledger/hive.go/kvstore/mapdb"
 	"github.com/iotaledger/hive.go/logger"
+	"github.com/iotaledger/wasp/packages/chain/mempool"
 	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/subrealm"
 	"github.com/iotaledger/wasp/packages/sctransaction"
 	"github.com/iotaledger/wasp/packages/sctransaction/origin"
 	"github.com/iotaledger/wasp/packages/sctransaction/txbuilder"
 	"github.com/iotaledger/wasp/packages/state"
 	"github.com/iotaledger/wasp/packages/testutil"
 	"github.com/iotaledger/wasp/packages/vm"
+	"github.com/iotaledger/wasp/packages/vm/core/root"
 	"github.com/iotaledger/wasp/packages/vm/processors"
 	_ "github.com/iotaledger/wasp/packages/vm/sandbox"
 	"github.com/iotaledger/wasp/packages/vm/vmtypes"
@@ -63,7 +67,7 @@ type Chain struct {
 	// It is a default signature scheme in many of 'solo' calls which require private key.
 	OriginatorSigScheme signaturescheme.SignatureScheme
 
-	// ChainID is the ID of the chain (in this version alias of the ChainAddress)
+	// ChainID is the ID of the chain (in this version alias of the original ChainAddress)
 	ChainID coretypes.ChainID
 
 	// ChainAddress is the alias of ChainSigScheme.Address()
@@ -98,7 +102,7 @@ type Chain struct {
 	runVMMutex   *sync.Mutex
 	chPosted     sync.WaitGroup
 	chInRequest  chan sctransaction.RequestRef
-	backlog      []sctransaction.RequestRef
+	backlog      []*backlogRequest
 	backlogMutex *sync.Mutex
 	batch        []*sctransaction.RequestRef
 	batchMutex   *sync.Mutex
@@ -180,7 +184,7 @@ func (env *Solo) NewChain(chainOriginator signaturescheme.SignatureScheme, name
 		//
 		runVMMutex:   &sync.Mutex{},
 		chInRequest:  make(chan sctransaction.RequestRef),
-		backlog:      make([]sctransaction.RequestRef, 0),
+		backlog:      make([]*backlogRequest, 0),
 		backlogMutex: &sync.Mutex{},
 		batch:        nil,
 		batchMutex:   &sync.Mutex{},
@@ -235,38 +239,88 @@ func (env *Solo) NewChain(chainOriginator signaturescheme.SignatureScheme, name
 	return ret
 }
 
+// backlogRequest is the request in the backlog with the time of arrival
+type backlogRequest struct {
+	ref     sctransaction.RequestRef
+	arrival time.Time
+}
+
 func (ch *Chain) readRequestsLoop() {
 	for r := range ch.chInRequest {
 		ch.backlogMutex.Lock()
-		ch.backlog = append(ch.backlog, r)
+		ch.backlog = append(ch.backlog, &backlogRequest{ref: r, arrival: time.Now()})
 		ch.backlogMutex.Unlock()
 		ch.chPosted.Done()
 	}
 }
 
-// collateBatch selects requests which are not time locked
-// returns batch and and 'remains unprocessed' flag
+// collateBatch selects requests which are not time locked.
+// Like the leader of the committee, it takes them in the order of priority within the block limits of the chain
 func (ch *Chain) collateBatch() []vm.RequestRefWithFreeTokens {
+	ch.runVMMutex.Lock()
+	defer ch.runVMMutex.Unlock()
 	ch.backlogMutex.Lock()
 	defer ch.backlogMutex.Unlock()
 
-	ret := make([]vm.RequestRefWithFreeTokens, 0)
-	remain := ch.backlog[:0]
-	for _, ref := range ch.backlog {
+	ready := make([]*backlogRequest, 0)
+	remain := make([]*backlogRequest, 0)
+	for _, r := range ch.backlog {
 		// using logical clock
-		if int64(ref.RequestSection().Timelock()) <= ch.Env.LogicalTime().Unix() {
-			if ref.RequestSection().Timelock() != 0 {
-				ch.Log.Infof("unlocked time-locked request %s", ref.RequestID().String())
+		if int64(r.ref.RequestSection().Timelock()) <= ch.Env.LogicalTime().Unix() {
+			if r.ref.RequestSection().Timelock() != 0 {
+				ch.Log.Infof("unlocked time-locked request %s", r.ref.RequestID().String())
 			}
-			ret = append(ret, vm.RequestRefWithFreeTokens{RequestRef: ref})
+			ready = append(ready, r)
 		} else {
-			remain = append(remain, ref)
+			remain = append(remain, r)
+		}
+	}
+	if len(ready) == 0 {
+		return nil
+	}
+	rootState := subrealm.New(ch.State.Variables(), kv.Key(root.Interface.Hname().Bytes()))
+	feeColor, _, _, err := root.GetDefaultFeeInfo(rootState)
+	require.NoError(ch.Env.T, err)
+
+	byID := make(map[coretypes.RequestID]*backlogRequest, len(ready))
+	entries := make([]*mempool.Entry, len(ready))
+	for i, r := range ready {
+		entries[i] = mempool.NewEntry(&r.ref, feeColor, r.arrival)
+		byID[entries[i].RequestID] = r
+	}
+	mempool.SortByPriority(entries)
+	selected := make(map[coretypes.RequestID]bool)
+	ret := make([]vm.RequestRefWithFreeTokens, 0, len(entries))
+	for _, e := range mempool.Select(entries, root.GetBlockLimits(rootState), nil) {
+		selected[e.RequestID] = true
+		ret = append(ret, vm.RequestRefWithFreeTokens{RequestRef: byID[e.RequestID].ref})
+	}
+	for _, r := range ready {
+		if !selected[*r.ref.RequestID()] {
+			remain = append(remain, r)
 		}
 	}
 	ch.backlog = remain
 	return ret
 }
 
+// requeueUnprocessed puts requests of the batch which didn't fit into the block back to the backlog.
+// They take precedence over requests with the same fee which arrived later
+func (ch *Chain) requeueUnprocessed(batch []vm.RequestRefWithFreeTokens, block state.Block) {
+	processed := make(map[coretypes.RequestID]bool)
+	for _, rid := range block.RequestIDs() {
+		processed[*rid] = true
+	}
+	ch.backlogMutex.Lock()
+	defer ch.backlogMutex.Unlock()
+
+	for _, r := range batch {
+		if !processed[*r.RequestID()] {
+			ch.backlog = append(ch.backlog, &backlogRequest{ref: r.RequestRef})
+		}
+	}
+}
+
 // batchLoop mimics leaders's behavior in the Wasp committee
 func (ch *Chain) batchLoop() {
 	for {
@@ -182,7 +182,16 @@ func dbkeyBatch(stateIndex uint32) []byte {
 }
 
 func LoadBlock(chainID *coretypes.ChainID, stateIndex uint32) (Block, error) {
-	data, err := database.GetPartition(chainID).Get(dbkeyBatch(stateIndex))
+	return loadBlock(database.GetPartition(chainID), stateIndex)
+}
+
+// LoadBlockFromDB loads the block from the given partition of the chain instead of the node's database
+func LoadBlockFromDB(db kvstore.KVStore, stateIndex uint32) (Block, error) {
+	return loadBlock(db, stateIndex)
+}
+
+func loadBlock(db kvstore.KVStore, stateIndex uint32) (Block, error) {
+	data, err := db.Get(dbkeyBatch(stateIndex))
 	if err == kvstore.ErrKeyNotFound {
 		return nil, nil
 	}
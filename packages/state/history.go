@@ -0,0 +1,189 @@
+package state
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/buffered"
+	"github.com/iotaledger/wasp/packages/util"
+	"github.com/iotaledger/wasp/plugins/database"
+)
+
+// DefaultHistoryRetention is the default number of recent blocks for which the past states are kept
+const DefaultHistoryRetention = 1000
+
+var historyRetention uint32 = DefaultHistoryRetention
+
+// SetHistoryRetention sets the number of recent blocks for which the past states of the chain can be loaded.
+// 0 means no history is kept, only the solid state
+func SetHistoryRetention(blocks int) {
+	if blocks < 0 {
+		blocks = 0
+	}
+	historyRetention = uint32(blocks)
+}
+
+// undoRecord is stored together with each committed block. It contains mutations which revert
+// the state variables to the values before the block and the header of the previous solid state
+type undoRecord struct {
+	prevState []byte
+	mutations buffered.MutationSequence
+}
+
+func dbkeyUndo(blockIndex uint32) []byte {
+	return database.MakeKey(database.ObjectTypeStateUndo, util.Uint32To4Bytes(blockIndex))
+}
+
+// makeUndoRecord collects previous values of all variables mutated by the virtual state.
+// Returns nil if the state is committed for the first time
+func (vs *virtualState) makeUndoRecord() (*undoRecord, error) {
+	prevState, err := vs.db.Get(database.MakeKey(database.ObjectTypeSolidState))
+	if err == kvstore.ErrKeyNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	ret := &undoRecord{
+		prevState: prevState,
+		mutations: buffered.NewMutationSequence(),
+	}
+	mutated := make(map[kv.Key]bool)
+	vs.variables.Mutations().IterateLatest(func(k kv.Key, _ buffered.Mutation) bool {
+		mutated[k] = true
+		var v []byte
+		v, err = vs.db.Get(dbkeyStateVariable(k))
+		switch err {
+		case nil:
+			ret.mutations.Add(buffered.NewMutationSet(k, v))
+		case kvstore.ErrKeyNotFound:
+			err = nil
+			ret.mutations.Add(buffered.NewMutationDel(k))
+		default:
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	// variables deleted by prefix
+	err = vs.iterateDeletedByPrefix(mutated, func(k kv.Key, v []byte) {
+		ret.mutations.Add(buffered.NewMutationSet(k, v))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (u *undoRecord) Write(w io.Writer) error {
+	if err := util.WriteBytes32(w, u.prevState); err != nil {
+		return err
+	}
+	return u.mutations.Write(w)
+}
+
+func (u *undoRecord) Read(r io.Reader) error {
+	var err error
+	if u.prevState, err = util.ReadBytes32(r); err != nil {
+		return err
+	}
+	u.mutations = buffered.NewMutationSequence()
+	return u.mutations.Read(r)
+}
+
+// undoKeysToPrune returns the db keys of the undo records which fall out of the retention window
+// after the block with the given index is committed
+func undoKeysToPrune(db kvstore.KVStore, blockIndex uint32) ([][]byte, error) {
+	ret := make([][]byte, 0)
+	err := db.IterateKeys(database.MakeKey(database.ObjectTypeStateUndo), func(key kvstore.Key) bool {
+		i, err := util.Uint32From4Bytes(key[1:])
+		if err == nil && uint64(i)+uint64(historyRetention) <= uint64(blockIndex) {
+			ret = append(ret, append([]byte{}, key...))
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func loadUndoRecord(db kvstore.KVStore, blockIndex uint32) (*undoRecord, bool, error) {
+	data, err := db.Get(dbkeyUndo(blockIndex))
+	if err == kvstore.ErrKeyNotFound {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	ret := new(undoRecord)
+	if err := ret.Read(bytes.NewReader(data)); err != nil {
+		return nil, false, err
+	}
+	return ret, true, nil
+}
+
+// LoadStateAt loads the past state of the chain as it was right after the block with the given index.
+// The past state is reconstructed from the solid state by reverting the blocks committed after it,
+// so it is only available within the history retention window.
+// The Merkle tree is reverted together with the variables, so the state hash and the proofs of the
+// past state can be verified against its anchor transaction like those of the solid state.
+// The returned state is read only: it must not be committed.
+// Returns false if the chain has no solid state
+func LoadStateAt(chainID *coretypes.ChainID, blockIndex uint32) (VirtualState, Block, bool, error) {
+	return loadStateAt(getSCPartition(chainID), chainID, blockIndex)
+}
+
+func loadStateAt(db kvstore.KVStore, chainID *coretypes.ChainID, blockIndex uint32) (VirtualState, Block, bool, error) {
+	solidState, solidBlock, ok, err := loadSolidState(db, chainID)
+	if err != nil || !ok {
+		return nil, nil, ok, err
+	}
+	solidIndex := solidState.BlockIndex()
+	if blockIndex == solidIndex {
+		return solidState, solidBlock, true, nil
+	}
+	if blockIndex > solidIndex {
+		return nil, nil, false, fmt.Errorf("block #%d is not solid yet. Solid state index is #%d", blockIndex, solidIndex)
+	}
+	vs := NewVirtualState(db, chainID)
+	var undo *undoRecord
+	for i := solidIndex; i > blockIndex; i-- {
+		var exists bool
+		undo, exists, err = loadUndoRecord(db, i)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		if !exists {
+			return nil, nil, false, fmt.Errorf("state #%d is out of the history retention window", blockIndex)
+		}
+		// older mutations override newer ones
+		undo.mutations.ApplyTo(vs.variables)
+	}
+	if err = vs.Read(bytes.NewReader(undo.prevState)); err != nil {
+		return nil, nil, false, fmt.Errorf("loading variable state #%d: %v", blockIndex, err)
+	}
+	// revert the uncommitted Merkle tree of the solid state and check it against the past state hash
+	vs.variables.Mutations().IterateLatest(func(k kv.Key, mut buffered.Mutation) bool {
+		vs.merkle.update(k, mut.Value())
+		return true
+	})
+	if h := StateHashFromMerkleRoot(&vs.updatesHash, vs.blockIndex, vs.MerkleRoot()); h != vs.stateHash {
+		return nil, nil, false, fmt.Errorf("state #%d: inconsistent state hash %s, calculated %s",
+			blockIndex, vs.stateHash.String(), h.String())
+	}
+	block, err := loadBlock(db, blockIndex)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	if block == nil {
+		return nil, nil, false, fmt.Errorf("block #%d not found", blockIndex)
+	}
+	return vs, block, true, nil
+}
@@ -0,0 +1,201 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/buffered"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadStateAt(t *testing.T) {
+	chainID := coretypes.ChainID{1, 3, 3, 7}
+	db := mapdb.NewMapDB()
+	vs := NewVirtualState(db, &chainID)
+
+	SetHistoryRetention(2)
+	defer SetHistoryRetention(DefaultHistoryRetention)
+
+	hashes := make([]hashing.HashValue, 0)
+	for i, key := range []string{"a", "b", "c", "d"} {
+		txid := (transaction.ID)(hashing.HashStrings(key))
+		reqid := coretypes.NewRequestID(txid, 0)
+		su := NewStateUpdate(&reqid).WithTimestamp(int64(i + 1))
+		su.Mutations().Add(buffered.NewMutationSet("a", []byte{byte(i)}))
+		su.Mutations().Add(buffered.NewMutationSet(kv.Key(key), []byte{byte(i)}))
+		if key == "d" {
+			su.Mutations().Add(buffered.NewMutationDel("b"))
+		}
+		block, err := NewBlock([]StateUpdate{su})
+		require.NoError(t, err)
+		block.WithBlockIndex(uint32(i)).WithStateTransaction(txid)
+		require.NoError(t, vs.ApplyBlock(block))
+		require.NoError(t, vs.CommitToDb(block))
+		hashes = append(hashes, *vs.Hash())
+	}
+
+	vs3, block3, ok, err := loadStateAt(db, &chainID, 3)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.EqualValues(t, 3, vs3.BlockIndex())
+	require.EqualValues(t, 3, block3.StateIndex())
+	require.False(t, vs3.Variables().MustHas("b"))
+
+	vs2, block2, ok, err := loadStateAt(db, &chainID, 2)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.EqualValues(t, 2, vs2.BlockIndex())
+	require.EqualValues(t, 3, vs2.Timestamp())
+	require.EqualValues(t, hashes[2], *vs2.Hash())
+	require.EqualValues(t, (transaction.ID)(hashing.HashStrings("c")), block2.StateTransactionID())
+	require.EqualValues(t, []byte{2}, vs2.Variables().MustGet("a"))
+	require.EqualValues(t, []byte{1}, vs2.Variables().MustGet("b"))
+	require.False(t, vs2.Variables().MustHas("d"))
+
+	// the past state is provable against its state hash
+	require.EqualValues(t, hashes[2], StateHashFromMerkleRoot(vs2.UpdatesHash(), 2, vs2.MerkleRoot()))
+	proof, err := vs2.GetProof("b")
+	require.NoError(t, err)
+	require.True(t, VerifyProof(vs2.MerkleRoot(), "b", []byte{1}, proof))
+	proof, err = vs2.GetProof("d")
+	require.NoError(t, err)
+	require.True(t, VerifyProof(vs2.MerkleRoot(), "d", nil, proof))
+
+	vs1, _, ok, err := loadStateAt(db, &chainID, 1)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.EqualValues(t, hashes[1], *vs1.Hash())
+	require.EqualValues(t, []byte{1}, vs1.Variables().MustGet("a"))
+	require.False(t, vs1.Variables().MustHas("c"))
+	keys := 0
+	vs1.Variables().MustIterateKeys("", func(kv.Key) bool {
+		keys++
+		return true
+	})
+	require.EqualValues(t, 2, keys)
+
+	// out of the retention window
+	_, _, _, err = loadStateAt(db, &chainID, 0)
+	require.Error(t, err)
+
+	// not solid yet
+	_, _, _, err = loadStateAt(db, &chainID, 4)
+	require.Error(t, err)
+
+	// solid state itself is not reverted
+	vs4, _, _, err := loadSolidState(db, &chainID)
+	require.NoError(t, err)
+	require.EqualValues(t, []byte{3}, vs4.Variables().MustGet("a"))
+	require.EqualValues(t, *vs.MerkleRoot(), *vs4.MerkleRoot())
+}
+
+func TestPruneHistory(t *testing.T) {
+	chainID := coretypes.ChainID{1, 3, 3, 7}
+	db := mapdb.NewMapDB()
+	vs := NewVirtualState(db, &chainID)
+
+	SetHistoryRetention(4)
+	defer SetHistoryRetention(DefaultHistoryRetention)
+
+	commit := func(i int) {
+		txid := (transaction.ID)(hashing.HashStrings(string(rune('a' + i))))
+		reqid := coretypes.NewRequestID(txid, 0)
+		su := NewStateUpdate(&reqid).WithTimestamp(int64(i + 1))
+		su.Mutations().Add(buffered.NewMutationSet("a", []byte{byte(i)}))
+		block, err := NewBlock([]StateUpdate{su})
+		require.NoError(t, err)
+		block.WithBlockIndex(uint32(i)).WithStateTransaction(txid)
+		require.NoError(t, vs.ApplyBlock(block))
+		require.NoError(t, vs.CommitToDb(block))
+	}
+	undoRecords := func() []uint32 {
+		ret := make([]uint32, 0)
+		for i := uint32(0); i < 10; i++ {
+			if _, exists, err := loadUndoRecord(db, i); err == nil && exists {
+				ret = append(ret, i)
+			}
+		}
+		return ret
+	}
+	for i := 0; i < 6; i++ {
+		commit(i)
+	}
+	require.EqualValues(t, []uint32{2, 3, 4, 5}, undoRecords())
+
+	// all records below the shrunk window are removed at once
+	SetHistoryRetention(1)
+	commit(6)
+	require.EqualValues(t, []uint32{6}, undoRecords())
+
+	SetHistoryRetention(0)
+	commit(7)
+	require.EqualValues(t, []uint32{}, undoRecords())
+}
+
+func TestDelPrefix(t *testing.T) {
+	chainID := coretypes.ChainID{1, 3, 3, 7}
+	db := mapdb.NewMapDB()
+	vs := NewVirtualState(db, &chainID)
+	vsExpected := NewVirtualState(mapdb.NewMapDB(), &chainID)
+
+	SetHistoryRetention(2)
+	defer SetHistoryRetention(DefaultHistoryRetention)
+
+	commit := func(i int, muts ...buffered.Mutation) {
+		txid := (transaction.ID)(hashing.HashStrings(string(rune('a' + i))))
+		reqid := coretypes.NewRequestID(txid, 0)
+		su := NewStateUpdate(&reqid).WithTimestamp(int64(i + 1))
+		for _, mut := range muts {
+			su.Mutations().Add(mut)
+		}
+		block, err := NewBlock([]StateUpdate{su})
+		require.NoError(t, err)
+		block.WithBlockIndex(uint32(i)).WithStateTransaction(txid)
+		require.NoError(t, vs.ApplyBlock(block))
+		require.NoError(t, vs.CommitToDb(block))
+	}
+	commit(0,
+		buffered.NewMutationSet("a1", []byte{1}),
+		buffered.NewMutationSet("a2", []byte{2}),
+		buffered.NewMutationSet("b", []byte{3}),
+	)
+	commit(1,
+		buffered.NewMutationSet("a3", []byte{4}),
+		buffered.NewMutationDelPrefix("a"),
+		buffered.NewMutationSet("a2", []byte{5}),
+	)
+
+	require.False(t, vs.Variables().MustHas("a1"))
+	require.False(t, vs.Variables().MustHas("a3"))
+	require.EqualValues(t, []byte{5}, vs.Variables().MustGet("a2"))
+	v, err := db.Get(dbkeyStateVariable("a1"))
+	require.Equal(t, kvstore.ErrKeyNotFound, err)
+	require.Nil(t, v)
+
+	// the Merkle tree is the same as the one of the state with individual keys
+	su := NewStateUpdate(nil)
+	su.Mutations().Add(buffered.NewMutationSet("a2", []byte{5}))
+	su.Mutations().Add(buffered.NewMutationSet("b", []byte{3}))
+	vsExpected.ApplyStateUpdate(su)
+	require.EqualValues(t, *vsExpected.MerkleRoot(), *vs.MerkleRoot())
+
+	// reloaded from DB
+	vs1, _, ok, err := loadSolidState(db, &chainID)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.False(t, vs1.Variables().MustHas("a1"))
+	require.EqualValues(t, []byte{5}, vs1.Variables().MustGet("a2"))
+
+	// past state is restored
+	vs0, _, ok, err := loadStateAt(db, &chainID, 0)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.EqualValues(t, []byte{1}, vs0.Variables().MustGet("a1"))
+	require.EqualValues(t, []byte{2}, vs0.Variables().MustGet("a2"))
+	require.False(t, vs0.Variables().MustHas("a3"))
+}
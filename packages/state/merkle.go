@@ -0,0 +1,257 @@
+package state
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/util"
+	"github.com/iotaledger/wasp/plugins/database"
+)
+
+// merkleDepth is the depth of the sparse Merkle tree: one level for each bit of the key hash
+const merkleDepth = hashing.HashSize * 8
+
+// defaultHashes[d] is the hash of the empty subtree with the root at depth d
+var defaultHashes [merkleDepth + 1]hashing.HashValue
+
+func init() {
+	for d := merkleDepth - 1; d >= 0; d-- {
+		defaultHashes[d] = hashing.HashData(defaultHashes[d+1][:], defaultHashes[d+1][:])
+	}
+}
+
+// merkleTree is a sparse Merkle tree over the state variables. The path of a variable is the hash of its key.
+// Only nodes which differ from the empty subtree are stored in the db.
+// Updates are cached in memory until committed to the db together with the state
+type merkleTree struct {
+	db kvstore.KVStore
+	// uncommitted nodes. nil means the node is empty and must be deleted from the db
+	dirty map[string]*hashing.HashValue
+}
+
+// MerkleProof is a proof of inclusion (or of absence) of the key/value pair in the state
+type MerkleProof struct {
+	// bit i is set when the sibling on depth i+1 is not an empty subtree
+	Bitmap [merkleDepth / 8]byte
+	// non-empty siblings, from the leaf towards the root
+	Siblings []hashing.HashValue
+}
+
+func newMerkleTree(db kvstore.KVStore) *merkleTree {
+	return &merkleTree{
+		db:    db,
+		dirty: make(map[string]*hashing.HashValue),
+	}
+}
+
+func (t *merkleTree) clone() *merkleTree {
+	ret := newMerkleTree(t.db)
+	for k, v := range t.dirty {
+		ret.dirty[k] = v
+	}
+	return ret
+}
+
+func (t *merkleTree) root() *hashing.HashValue {
+	ret := t.mustGetNode(0, hashing.NilHash)
+	return &ret
+}
+
+// update recalculates the path of the key. nil value means the key is deleted
+func (t *merkleTree) update(key kv.Key, value []byte) {
+	path := merklePath(key)
+	h := merkleLeafHash(&path, value)
+	for d := merkleDepth; d > 0; d-- {
+		t.setNode(d, &path, &h)
+		sib := siblingPath(d, &path)
+		sibHash := t.mustGetNode(d, &sib)
+		if merkleBit(&path, d-1) {
+			h = hashing.HashData(sibHash[:], h[:])
+		} else {
+			h = hashing.HashData(h[:], sibHash[:])
+		}
+	}
+	t.setNode(0, &path, &h)
+}
+
+func (t *merkleTree) proof(key kv.Key) (*MerkleProof, error) {
+	path := merklePath(key)
+	ret := &MerkleProof{Siblings: make([]hashing.HashValue, 0)}
+	for d := merkleDepth; d > 0; d-- {
+		sib := siblingPath(d, &path)
+		sibHash, err := t.getNode(d, &sib)
+		if err != nil {
+			return nil, err
+		}
+		if sibHash == defaultHashes[d] {
+			continue
+		}
+		ret.Bitmap[(d-1)/8] |= 1 << ((d - 1) % 8)
+		ret.Siblings = append(ret.Siblings, sibHash)
+	}
+	return ret, nil
+}
+
+// iterateDirty iterates uncommitted nodes. Value is nil if the node must be deleted
+func (t *merkleTree) iterateDirty(f func(key []byte, value []byte)) {
+	for k, h := range t.dirty {
+		if h == nil {
+			f([]byte(k), nil)
+		} else {
+			f([]byte(k), h[:])
+		}
+	}
+}
+
+func (t *merkleTree) clearDirty() {
+	t.dirty = make(map[string]*hashing.HashValue)
+}
+
+func (t *merkleTree) setNode(depth int, path *hashing.HashValue, h *hashing.HashValue) {
+	k := string(merkleNodeKey(depth, path))
+	if *h == defaultHashes[depth] {
+		t.dirty[k] = nil
+		return
+	}
+	hcopy := *h
+	t.dirty[k] = &hcopy
+}
+
+func (t *merkleTree) getNode(depth int, path *hashing.HashValue) (hashing.HashValue, error) {
+	k := merkleNodeKey(depth, path)
+	if h, ok := t.dirty[string(k)]; ok {
+		if h == nil {
+			return defaultHashes[depth], nil
+		}
+		return *h, nil
+	}
+	if t.db == nil {
+		return defaultHashes[depth], nil
+	}
+	data, err := t.db.Get(k)
+	if err == kvstore.ErrKeyNotFound {
+		return defaultHashes[depth], nil
+	}
+	if err != nil {
+		return hashing.HashValue{}, err
+	}
+	return hashing.HashValueFromBytes(data)
+}
+
+func (t *merkleTree) mustGetNode(depth int, path *hashing.HashValue) hashing.HashValue {
+	ret, err := t.getNode(depth, path)
+	if err != nil {
+		panic(err)
+	}
+	return ret
+}
+
+// VerifyProof checks if the proof confirms the key/value pair against the Merkle root.
+// nil value checks if the key is absent in the state
+func VerifyProof(root *hashing.HashValue, key kv.Key, value []byte, proof *MerkleProof) bool {
+	path := merklePath(key)
+	h := merkleLeafHash(&path, value)
+	next := 0
+	for d := merkleDepth; d > 0; d-- {
+		sibHash := defaultHashes[d]
+		if proof.Bitmap[(d-1)/8]&(1<<((d-1)%8)) != 0 {
+			if next >= len(proof.Siblings) {
+				return false
+			}
+			sibHash = proof.Siblings[next]
+			next++
+		}
+		if merkleBit(&path, d-1) {
+			h = hashing.HashData(sibHash[:], h[:])
+		} else {
+			h = hashing.HashData(h[:], sibHash[:])
+		}
+	}
+	return next == len(proof.Siblings) && h == *root
+}
+
+func NewMerkleProofFromBytes(data []byte) (*MerkleProof, error) {
+	ret := new(MerkleProof)
+	if err := ret.Read(bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (p *MerkleProof) Bytes() []byte {
+	var buf bytes.Buffer
+	_ = p.Write(&buf)
+	return buf.Bytes()
+}
+
+func (p *MerkleProof) Write(w io.Writer) error {
+	if _, err := w.Write(p.Bitmap[:]); err != nil {
+		return err
+	}
+	if err := util.WriteUint16(w, uint16(len(p.Siblings))); err != nil {
+		return err
+	}
+	for i := range p.Siblings {
+		if _, err := w.Write(p.Siblings[i][:]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *MerkleProof) Read(r io.Reader) error {
+	if _, err := io.ReadFull(r, p.Bitmap[:]); err != nil {
+		return err
+	}
+	var n uint16
+	if err := util.ReadUint16(r, &n); err != nil {
+		return err
+	}
+	if int(n) > merkleDepth {
+		return fmt.Errorf("wrong number of siblings in the Merkle proof: %d", n)
+	}
+	p.Siblings = make([]hashing.HashValue, n)
+	for i := range p.Siblings {
+		if err := util.ReadHashValue(r, &p.Siblings[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func merklePath(key kv.Key) hashing.HashValue {
+	return hashing.HashData([]byte(key))
+}
+
+func merkleLeafHash(path *hashing.HashValue, value []byte) hashing.HashValue {
+	if value == nil {
+		return defaultHashes[merkleDepth]
+	}
+	return hashing.HashData(path[:], value)
+}
+
+// merkleBit returns bit i of the path, counting from the most significant bit
+func merkleBit(path *hashing.HashValue, i int) bool {
+	return path[i/8]&(0x80>>(i%8)) != 0
+}
+
+// siblingPath returns path to the sibling of the node on the depth
+func siblingPath(depth int, path *hashing.HashValue) hashing.HashValue {
+	ret := *path
+	ret[(depth-1)/8] ^= 0x80 >> ((depth - 1) % 8)
+	return ret
+}
+
+// merkleNodeKey is the db key of the node: depth and the first depth bits of the path
+func merkleNodeKey(depth int, path *hashing.HashValue) []byte {
+	prefix := make([]byte, (depth+7)/8)
+	copy(prefix, path[:len(prefix)])
+	if depth%8 != 0 {
+		prefix[len(prefix)-1] &= byte(0xFF << (8 - depth%8))
+	}
+	return database.MakeKey(database.ObjectTypeMerkleNode, util.Uint16To2Bytes(uint16(depth)), prefix)
+}
@@ -0,0 +1,139 @@
+package state
+
+import (
+	"bytes"
+	"testing"
+	"testing/iotest"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
+	"github.com/iotaledger/goshimmer/packages/database"
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/buffered"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMerkleEmpty(t *testing.T) {
+	chainID := coretypes.ChainID{1, 3, 3, 7}
+	vs := NewVirtualState(mapdb.NewMapDB(), &chainID)
+	assert.EqualValues(t, defaultHashes[0], *vs.MerkleRoot())
+
+	proof, err := vs.GetProof("a")
+	assert.NoError(t, err)
+	assert.Len(t, proof.Siblings, 0)
+	assert.True(t, VerifyProof(vs.MerkleRoot(), "a", nil, proof))
+	assert.False(t, VerifyProof(vs.MerkleRoot(), "a", []byte{1}, proof))
+}
+
+func TestMerkleProof(t *testing.T) {
+	txid := (transaction.ID)(hashing.HashStrings("test string 1"))
+	reqid := coretypes.NewRequestID(txid, 0)
+	su := NewStateUpdate(&reqid)
+	su.Mutations().Add(buffered.NewMutationSet("a", []byte{1}))
+	su.Mutations().Add(buffered.NewMutationSet("b", []byte{2}))
+	su.Mutations().Add(buffered.NewMutationSet("c", []byte{3}))
+
+	chainID := coretypes.ChainID{1, 3, 3, 7}
+	vs := NewVirtualState(mapdb.NewMapDB(), &chainID)
+	vs.ApplyStateUpdate(su)
+	root := vs.MerkleRoot()
+
+	for _, k := range []kv.Key{"a", "b", "c", "d"} {
+		proof, err := vs.GetProof(k)
+		assert.NoError(t, err)
+		value := vs.Variables().MustGet(k)
+		assert.True(t, VerifyProof(root, k, value, proof))
+		assert.False(t, VerifyProof(root, k, []byte{9}, proof))
+
+		proofBack, err := NewMerkleProofFromBytes(proof.Bytes())
+		assert.NoError(t, err)
+		assert.EqualValues(t, proof, proofBack)
+	}
+	proof, err := vs.GetProof("a")
+	assert.NoError(t, err)
+	assert.False(t, VerifyProof(root, "b", []byte{1}, proof))
+}
+
+func TestMerkleProofTruncated(t *testing.T) {
+	txid := (transaction.ID)(hashing.HashStrings("test string 1"))
+	reqid := coretypes.NewRequestID(txid, 0)
+	su := NewStateUpdate(&reqid)
+	su.Mutations().Add(buffered.NewMutationSet("a", []byte{1}))
+	su.Mutations().Add(buffered.NewMutationSet("b", []byte{2}))
+
+	chainID := coretypes.ChainID{1, 3, 3, 7}
+	vs := NewVirtualState(mapdb.NewMapDB(), &chainID)
+	vs.ApplyStateUpdate(su)
+	proof, err := vs.GetProof("a")
+	assert.NoError(t, err)
+	data := proof.Bytes()
+
+	for i := 0; i < len(data); i++ {
+		_, err := NewMerkleProofFromBytes(data[:i])
+		assert.Error(t, err)
+	}
+	// short reads of a complete input are not errors
+	proofBack := &MerkleProof{}
+	assert.NoError(t, proofBack.Read(iotest.OneByteReader(bytes.NewReader(data))))
+	assert.EqualValues(t, proof, proofBack)
+}
+
+func TestMerkleOrderIndependent(t *testing.T) {
+	chainID := coretypes.ChainID{1, 3, 3, 7}
+	txid := (transaction.ID)(hashing.HashStrings("test string 1"))
+	reqid1 := coretypes.NewRequestID(txid, 0)
+	reqid2 := coretypes.NewRequestID(txid, 1)
+
+	su1 := NewStateUpdate(&reqid1)
+	su1.Mutations().Add(buffered.NewMutationSet("a", []byte{1}))
+	su1.Mutations().Add(buffered.NewMutationSet("b", []byte{2}))
+	su2 := NewStateUpdate(&reqid2)
+	su2.Mutations().Add(buffered.NewMutationDel("a"))
+
+	vs1 := NewVirtualState(mapdb.NewMapDB(), &chainID)
+	vs1.ApplyStateUpdate(su1)
+	vs1.ApplyStateUpdate(su2)
+
+	su3 := NewStateUpdate(&reqid1)
+	su3.Mutations().Add(buffered.NewMutationSet("b", []byte{2}))
+	vs2 := NewVirtualState(mapdb.NewMapDB(), &chainID)
+	vs2.ApplyStateUpdate(su3)
+
+	assert.EqualValues(t, vs1.MerkleRoot(), vs2.MerkleRoot())
+}
+
+func TestMerkleCommit(t *testing.T) {
+	tmpdb, _ := database.NewMemDB()
+	partition := tmpdb.NewStore().WithRealm([]byte("2"))
+
+	txid := (transaction.ID)(hashing.HashStrings("test string 1"))
+	reqid := coretypes.NewRequestID(txid, 5)
+	su := NewStateUpdate(&reqid)
+	su.Mutations().Add(buffered.NewMutationSet("x", []byte{1}))
+	block, err := NewBlock([]StateUpdate{su})
+	assert.NoError(t, err)
+
+	chainID := coretypes.ChainID{1, 3, 3, 7}
+	vs1 := NewVirtualState(partition, &chainID)
+	vsClone := vs1.Clone()
+	err = vs1.ApplyBlock(block)
+	assert.NoError(t, err)
+	assert.EqualValues(t, defaultHashes[0], *vsClone.MerkleRoot())
+
+	expected := StateHashFromMerkleRoot(vs1.UpdatesHash(), vs1.BlockIndex(), vs1.MerkleRoot())
+	assert.EqualValues(t, expected, *vs1.Hash())
+
+	err = vs1.CommitToDb(block)
+	assert.NoError(t, err)
+
+	vs2, _, _, err := loadSolidState(partition, &chainID)
+	assert.NoError(t, err)
+	assert.EqualValues(t, vs1.MerkleRoot(), vs2.MerkleRoot())
+	assert.EqualValues(t, vs1.UpdatesHash(), vs2.UpdatesHash())
+
+	proof, err := vs2.GetProof("x")
+	assert.NoError(t, err)
+	assert.True(t, VerifyProof(vs2.MerkleRoot(), "x", []byte{1}, proof))
+}
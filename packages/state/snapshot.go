@@ -0,0 +1,220 @@
+package state
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
+	valuetransaction "github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/sctransaction"
+	"github.com/iotaledger/wasp/packages/util"
+	"github.com/iotaledger/wasp/packages/vm/core/root"
+)
+
+// Snapshot is the solid state of the chain: all state variables together with the last block.
+// A node can bootstrap the chain from the snapshot instead of replaying all blocks from the origin.
+// The snapshot is verified by recalculating the state hash, which must be equal to the state hash
+// committed to the anchor transaction StateTxID
+type Snapshot struct {
+	ChainID     coretypes.ChainID
+	BlockIndex  uint32
+	Timestamp   int64
+	StateHash   hashing.HashValue
+	UpdatesHash hashing.HashValue
+	StateTxID   valuetransaction.ID
+	Variables   dict.Dict
+	Block       Block
+}
+
+// TakeSnapshot returns the snapshot of the solid state of the chain
+func TakeSnapshot(chainID *coretypes.ChainID) (*Snapshot, error) {
+	return takeSnapshot(getSCPartition(chainID), chainID)
+}
+
+func takeSnapshot(db kvstore.KVStore, chainID *coretypes.ChainID) (*Snapshot, error) {
+	vs, block, ok, err := loadSolidState(db, chainID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("solid state of the chain %s not found", chainID.String())
+	}
+	return &Snapshot{
+		ChainID:     *chainID,
+		BlockIndex:  vs.BlockIndex(),
+		Timestamp:   vs.Timestamp(),
+		StateHash:   *vs.Hash(),
+		UpdatesHash: *vs.UpdatesHash(),
+		StateTxID:   block.StateTransactionID(),
+		Variables:   vs.Variables().DangerouslyDumpToDict(),
+		Block:       block,
+	}, nil
+}
+
+// Verify checks if the state variables and the block are consistent with the state hash
+func (s *Snapshot) Verify() error {
+	_, err := s.toVirtualState(mapdb.NewMapDB())
+	return err
+}
+
+// VerifyAnchor checks if the snapshot is the state committed to the anchor transaction confirmed on L1.
+// Without it the snapshot is only consistent with itself
+func (s *Snapshot) VerifyAnchor(anchor *sctransaction.Transaction) error {
+	if anchor == nil || anchor.ID() != s.StateTxID {
+		return fmt.Errorf("snapshot: anchor transaction %s expected", s.StateTxID.String())
+	}
+	stateSection, ok := anchor.State()
+	if !ok {
+		return fmt.Errorf("snapshot: anchor transaction %s has no state section", s.StateTxID.String())
+	}
+	chainAddress, err := s.ChainAddress()
+	if err != nil {
+		return err
+	}
+	if _, ok := anchor.OutputBalancesByAddress(chainAddress); !ok {
+		return fmt.Errorf("snapshot: anchor transaction %s is not a state transaction of the chain %s at address %s",
+			s.StateTxID.String(), s.ChainID.String(), chainAddress.String())
+	}
+	if stateSection.BlockIndex() != s.BlockIndex {
+		return fmt.Errorf("snapshot: block index mismatch. Anchor transaction has #%d, snapshot has #%d",
+			stateSection.BlockIndex(), s.BlockIndex)
+	}
+	if stateSection.StateHash() != s.StateHash {
+		return fmt.Errorf("snapshot: state hash mismatch. Anchor transaction has %s, snapshot has %s",
+			stateSection.StateHash().String(), s.StateHash.String())
+	}
+	return nil
+}
+
+// ChainAddress returns the address of the chain in the snapshot, i.e. the address of the committee which
+// controlled the chain at the snapshot. It differs from the chain ID after the committee was rotated
+func (s *Snapshot) ChainAddress() (address.Address, error) {
+	ret, ok, err := root.GetChainAddress(s.Variables)
+	if err != nil {
+		return address.Address{}, fmt.Errorf("snapshot: wrong chain address in the state: %v", err)
+	}
+	if !ok {
+		// the chain is not initialized yet, the origin state belongs to the chain ID
+		return address.Address(s.ChainID), nil
+	}
+	return ret, nil
+}
+
+// ImportSnapshot stores the snapshot as the solid state of the chain. The chain must have no solid state yet.
+// The snapshot must be committed to the anchor transaction, confirmed on L1
+func ImportSnapshot(s *Snapshot, anchor *sctransaction.Transaction) error {
+	return importSnapshot(getSCPartition(&s.ChainID), s, anchor)
+}
+
+func importSnapshot(db kvstore.KVStore, s *Snapshot, anchor *sctransaction.Transaction) error {
+	if err := s.VerifyAnchor(anchor); err != nil {
+		return err
+	}
+	_, _, exists, err := loadSolidState(db, &s.ChainID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("solid state of the chain %s already exists", s.ChainID.String())
+	}
+	vs, err := s.toVirtualState(db)
+	if err != nil {
+		return err
+	}
+	return vs.CommitToDb(s.Block)
+}
+
+// toVirtualState builds uncommitted virtual state from the snapshot and checks the state hash
+func (s *Snapshot) toVirtualState(db kvstore.KVStore) (*virtualState, error) {
+	if s.Block == nil || s.Block.StateIndex() != s.BlockIndex {
+		return nil, fmt.Errorf("snapshot: block index of the block must be #%d", s.BlockIndex)
+	}
+	if s.Block.StateTransactionID() != s.StateTxID {
+		return nil, fmt.Errorf("snapshot: state transaction ID of the block must be %s", s.StateTxID.String())
+	}
+	vs := NewVirtualState(db, &s.ChainID)
+	for k, v := range s.Variables {
+		vs.variables.Set(k, v)
+		vs.merkle.update(k, v)
+	}
+	vs.blockIndex = s.BlockIndex
+	vs.timestamp = s.Timestamp
+	vs.updatesHash = s.UpdatesHash
+	vs.stateHash = StateHashFromMerkleRoot(&s.UpdatesHash, s.BlockIndex, vs.MerkleRoot())
+	vs.empty = false
+	if vs.stateHash != s.StateHash {
+		return nil, fmt.Errorf("snapshot: state hash mismatch. Expected %s, calculated %s",
+			s.StateHash.String(), vs.stateHash.String())
+	}
+	return vs, nil
+}
+
+func NewSnapshotFromBytes(data []byte) (*Snapshot, error) {
+	ret := new(Snapshot)
+	if err := ret.Read(bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (s *Snapshot) Bytes() []byte {
+	return util.MustBytes(s)
+}
+
+func (s *Snapshot) Write(w io.Writer) error {
+	if err := s.ChainID.Write(w); err != nil {
+		return err
+	}
+	if err := util.WriteUint32(w, s.BlockIndex); err != nil {
+		return err
+	}
+	if err := util.WriteInt64(w, s.Timestamp); err != nil {
+		return err
+	}
+	if _, err := w.Write(s.StateHash[:]); err != nil {
+		return err
+	}
+	if _, err := w.Write(s.UpdatesHash[:]); err != nil {
+		return err
+	}
+	if _, err := w.Write(s.StateTxID[:]); err != nil {
+		return err
+	}
+	if err := s.Variables.Write(w); err != nil {
+		return err
+	}
+	return s.Block.Write(w)
+}
+
+func (s *Snapshot) Read(r io.Reader) error {
+	if err := s.ChainID.Read(r); err != nil {
+		return err
+	}
+	if err := util.ReadUint32(r, &s.BlockIndex); err != nil {
+		return err
+	}
+	if err := util.ReadInt64(r, &s.Timestamp); err != nil {
+		return err
+	}
+	if err := util.ReadHashValue(r, &s.StateHash); err != nil {
+		return err
+	}
+	if err := util.ReadHashValue(r, &s.UpdatesHash); err != nil {
+		return err
+	}
+	if err := util.ReadTransactionId(r, &s.StateTxID); err != nil {
+		return err
+	}
+	s.Variables = dict.New()
+	if err := s.Variables.Read(r); err != nil {
+		return err
+	}
+	s.Block = new(block)
+	return s.Block.Read(r)
+}
@@ -0,0 +1,152 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/buffered"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/sctransaction"
+	"github.com/iotaledger/wasp/packages/vm/core/root"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshot(t *testing.T) {
+	chainID := coretypes.ChainID{1, 3, 3, 7}
+	db := mapdb.NewMapDB()
+	vs := NewVirtualState(db, &chainID)
+
+	_, err := takeSnapshot(db, &chainID)
+	require.Error(t, err)
+
+	for i, key := range []string{"a", "b"} {
+		txid := (transaction.ID)(hashing.HashStrings(key))
+		reqid := coretypes.NewRequestID(txid, 0)
+		su := NewStateUpdate(&reqid).WithTimestamp(int64(i + 1))
+		su.Mutations().Add(buffered.NewMutationSet("a", []byte{byte(i)}))
+		su.Mutations().Add(buffered.NewMutationSet(kv.Key(key), []byte{byte(i)}))
+		block, err := NewBlock([]StateUpdate{su})
+		require.NoError(t, err)
+		block.WithBlockIndex(uint32(i)).WithStateTransaction(txid)
+		require.NoError(t, vs.ApplyBlock(block))
+		require.NoError(t, vs.CommitToDb(block))
+	}
+
+	snapshot, err := takeSnapshot(db, &chainID)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, snapshot.BlockIndex)
+	require.EqualValues(t, 2, snapshot.Timestamp)
+	require.EqualValues(t, *vs.Hash(), snapshot.StateHash)
+	require.EqualValues(t, (transaction.ID)(hashing.HashStrings("b")), snapshot.StateTxID)
+	require.EqualValues(t, 2, len(snapshot.Variables))
+	require.NoError(t, snapshot.Verify())
+
+	snapshotBack, err := NewSnapshotFromBytes(snapshot.Bytes())
+	require.NoError(t, err)
+	require.NoError(t, snapshotBack.Verify())
+
+	db2 := mapdb.NewMapDB()
+	// the snapshot is not committed to the anchor transaction
+	require.Error(t, importSnapshot(db2, snapshotBack, nil))
+	require.Error(t, importSnapshot(db2, snapshotBack, newAnchorTx(t, snapshotBack, snapshotBack.BlockIndex+1, snapshotBack.StateHash)))
+	require.Error(t, importSnapshot(db2, snapshotBack, newAnchorTx(t, snapshotBack, snapshotBack.BlockIndex, hashing.HashStrings("wrong"))))
+	_, _, ok, err := loadSolidState(db2, &chainID)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	anchor := newAnchorTx(t, snapshotBack, snapshotBack.BlockIndex, snapshotBack.StateHash)
+	require.NoError(t, importSnapshot(db2, snapshotBack, anchor))
+	vs2, block2, ok, err := loadSolidState(db2, &chainID)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.EqualValues(t, *vs.Hash(), *vs2.Hash())
+	require.EqualValues(t, *vs.MerkleRoot(), *vs2.MerkleRoot())
+	require.EqualValues(t, vs.BlockIndex(), vs2.BlockIndex())
+	require.EqualValues(t, anchor.ID(), block2.StateTransactionID())
+	require.EqualValues(t, []byte{1}, vs2.Variables().MustGet("a"))
+
+	// can't import over the existing state
+	require.Error(t, importSnapshot(db2, snapshotBack, anchor))
+}
+
+func TestSnapshotTampered(t *testing.T) {
+	chainID := coretypes.ChainID{1, 3, 3, 7}
+	db := mapdb.NewMapDB()
+	vs := NewVirtualState(db, &chainID)
+	txid := (transaction.ID)(hashing.HashStrings("a"))
+	reqid := coretypes.NewRequestID(txid, 0)
+	su := NewStateUpdate(&reqid)
+	su.Mutations().Add(buffered.NewMutationSet("a", []byte{1}))
+	block, err := NewBlock([]StateUpdate{su})
+	require.NoError(t, err)
+	block.WithStateTransaction(txid)
+	require.NoError(t, vs.ApplyBlock(block))
+	require.NoError(t, vs.CommitToDb(block))
+
+	snapshot, err := takeSnapshot(db, &chainID)
+	require.NoError(t, err)
+	snapshot.Variables.Set("a", []byte{2})
+	require.Error(t, snapshot.Verify())
+	require.Error(t, importSnapshot(mapdb.NewMapDB(), snapshot, newAnchorTx(t, snapshot, snapshot.BlockIndex, snapshot.StateHash)))
+}
+
+func TestSnapshotRotatedChain(t *testing.T) {
+	chainID := coretypes.ChainID{1, 3, 3, 7}
+	newAddress := address.Address{2, 4, 4, 8}
+	db := mapdb.NewMapDB()
+	vs := NewVirtualState(db, &chainID)
+	txid := (transaction.ID)(hashing.HashStrings("a"))
+	reqid := coretypes.NewRequestID(txid, 0)
+	su := NewStateUpdate(&reqid)
+	// the committee was rotated: the chain is controlled from the new address
+	chainAddressKey := kv.Key(root.Interface.Hname().Bytes()) + root.VarChainAddress
+	su.Mutations().Add(buffered.NewMutationSet(chainAddressKey, codec.EncodeAddress(newAddress)))
+	block, err := NewBlock([]StateUpdate{su})
+	require.NoError(t, err)
+	block.WithStateTransaction(txid)
+	require.NoError(t, vs.ApplyBlock(block))
+	require.NoError(t, vs.CommitToDb(block))
+
+	snapshot, err := takeSnapshot(db, &chainID)
+	require.NoError(t, err)
+	chainAddress, err := snapshot.ChainAddress()
+	require.NoError(t, err)
+	require.EqualValues(t, newAddress, chainAddress)
+
+	// the state transaction must go to the address of the rotated committee, not to the chain ID
+	require.Error(t, importSnapshot(mapdb.NewMapDB(), snapshot, newAnchorTxToAddress(t, snapshot, address.Address(chainID))))
+	require.NoError(t, importSnapshot(mapdb.NewMapDB(), snapshot, newAnchorTxToAddress(t, snapshot, newAddress)))
+}
+
+// newAnchorTx creates the state transaction of the chain with the state section and makes
+// it the anchor transaction of the snapshot
+func newAnchorTx(t *testing.T, s *Snapshot, blockIndex uint32, stateHash hashing.HashValue) *sctransaction.Transaction {
+	return newAnchorTxWithParams(t, s, address.Address(s.ChainID), blockIndex, stateHash)
+}
+
+// newAnchorTxToAddress creates the anchor transaction of the snapshot which sends the chain token to the address
+func newAnchorTxToAddress(t *testing.T, s *Snapshot, addr address.Address) *sctransaction.Transaction {
+	return newAnchorTxWithParams(t, s, addr, s.BlockIndex, s.StateHash)
+}
+
+func newAnchorTxWithParams(t *testing.T, s *Snapshot, addr address.Address, blockIndex uint32, stateHash hashing.HashValue) *sctransaction.Transaction {
+	color := balance.Color(hashing.HashStrings("color"))
+	vtx := transaction.New(transaction.NewInputs(), transaction.NewOutputs(map[address.Address][]*balance.Balance{
+		addr: {balance.New(color, 1)},
+	}))
+	tx, err := sctransaction.NewTransaction(vtx, sctransaction.NewStateSection(sctransaction.NewStateSectionParams{
+		Color:      color,
+		BlockIndex: blockIndex,
+		StateHash:  stateHash,
+	}), nil)
+	require.NoError(t, err)
+	s.StateTxID = tx.ID()
+	s.Block.WithStateTransaction(tx.ID())
+	return tx
+}
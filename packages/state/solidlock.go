@@ -0,0 +1,56 @@
+package state
+
+import (
+	"sync"
+
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/wasp/packages/coretypes"
+)
+
+// The solid state of the chain in each DB is guarded by the RW lock. Readers of the solid state in the DB
+// (VM tasks, view calls) hold the read lock while they use it, committing the new solid state takes the write lock.
+// It guarantees that the state loaded from the DB does not change until it is released.
+// The lock belongs to the DB instance, so nodes with separate DBs in the same process don't block each other
+
+// rwLocker is implemented by DBs which carry their own lock, like the partitions of the node's database
+type rwLocker interface {
+	sync.Locker
+	RLock()
+	RUnlock()
+}
+
+var solidStateLocks = struct {
+	sync.Mutex
+	locks map[kvstore.KVStore]*sync.RWMutex
+}{locks: make(map[kvstore.KVStore]*sync.RWMutex)}
+
+func solidStateLock(db kvstore.KVStore) rwLocker {
+	if l, ok := db.(rwLocker); ok {
+		return l
+	}
+	solidStateLocks.Lock()
+	defer solidStateLocks.Unlock()
+
+	ret, ok := solidStateLocks.locks[db]
+	if !ok {
+		ret = &sync.RWMutex{}
+		solidStateLocks.locks[db] = ret
+	}
+	return ret
+}
+
+func acquireSolidState(db kvstore.KVStore) (release func()) {
+	lock := solidStateLock(db)
+	lock.RLock()
+	var once sync.Once
+	return func() {
+		once.Do(lock.RUnlock)
+	}
+}
+
+// AcquireSolidState guarantees that the solid state of the chain is not changed in the node's DB until the returned
+// function is called. The same goroutine must not commit the state of the chain before releasing it.
+// Calling the returned function more than once has no effect
+func AcquireSolidState(chainID *coretypes.ChainID) (release func()) {
+	return acquireSolidState(getSCPartition(chainID))
+}
@@ -0,0 +1,83 @@
+package state
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv/buffered"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcquireSolidState(t *testing.T) {
+	chainID := coretypes.ChainID{4, 2}
+	vs := NewVirtualState(mapdb.NewMapDB(), &chainID)
+	vs.Variables().SetReadCache(buffered.NewReadCache(buffered.DefaultReadCacheSize))
+
+	txid := (transaction.ID)(hashing.HashStrings("a"))
+	reqid := coretypes.NewRequestID(txid, 0)
+	su := NewStateUpdate(&reqid).WithTimestamp(1)
+	su.Mutations().Add(buffered.NewMutationSet("k", []byte{1}))
+	block, err := NewBlock([]StateUpdate{su})
+	require.NoError(t, err)
+	block.WithBlockIndex(0).WithStateTransaction(txid)
+	require.NoError(t, vs.ApplyBlock(block))
+
+	release1 := vs.AcquireSolid()
+	release2 := vs.AcquireSolid()
+
+	committed := make(chan error)
+	go func() {
+		committed <- vs.CommitToDb(block)
+	}()
+
+	select {
+	case <-committed:
+		t.Fatal("the state was committed while acquired")
+	case <-time.After(50 * time.Millisecond):
+	}
+	release1()
+	release1() // releasing twice has no effect
+	select {
+	case <-committed:
+		t.Fatal("the state was committed while acquired")
+	case <-time.After(50 * time.Millisecond):
+	}
+	release2()
+	require.NoError(t, <-committed)
+
+	// the read cache is dropped with the commit
+	require.Nil(t, vs.Variables().ReadCache())
+	require.EqualValues(t, []byte{1}, vs.Variables().MustGet("k"))
+}
+
+func TestAcquireSolidStatePerDB(t *testing.T) {
+	chainID := coretypes.ChainID{4, 2}
+	vs1 := NewVirtualState(mapdb.NewMapDB(), &chainID)
+	vs2 := NewVirtualState(mapdb.NewMapDB(), &chainID)
+
+	txid := (transaction.ID)(hashing.HashStrings("a"))
+	reqid := coretypes.NewRequestID(txid, 0)
+	su := NewStateUpdate(&reqid).WithTimestamp(1)
+	block, err := NewBlock([]StateUpdate{su})
+	require.NoError(t, err)
+	block.WithBlockIndex(0).WithStateTransaction(txid)
+	require.NoError(t, vs2.ApplyBlock(block))
+
+	// the state of the same chain in another DB is not blocked
+	release := vs1.AcquireSolid()
+	defer release()
+	committed := make(chan error)
+	go func() {
+		committed <- vs2.CommitToDb(block)
+	}()
+	select {
+	case err := <-committed:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("the state in another DB was blocked")
+	}
+}
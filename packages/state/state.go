@@ -24,7 +24,10 @@ type virtualState struct {
 	timestamp  int64
 	empty      bool
 	stateHash  hashing.HashValue
-	variables  buffered.BufferedKVStore
+	// hash of the chain of state updates before the block index and the Merkle root were committed to it
+	updatesHash hashing.HashValue
+	variables   buffered.BufferedKVStore
+	merkle      *merkleTree
 }
 
 func NewVirtualState(db kvstore.KVStore, chainID *coretypes.ChainID) *virtualState {
@@ -32,6 +35,7 @@ func NewVirtualState(db kvstore.KVStore, chainID *coretypes.ChainID) *virtualSta
 		chainID:   *chainID,
 		db:        db,
 		variables: buffered.NewBufferedKVStore(subRealm(db, []byte{database.ObjectTypeStateVariable})),
+		merkle:    newMerkleTree(db),
 		empty:     true,
 	}
 }
@@ -53,16 +57,22 @@ func subRealm(db kvstore.KVStore, realm []byte) kvstore.KVStore {
 
 func (vs *virtualState) Clone() VirtualState {
 	return &virtualState{
-		chainID:    vs.chainID,
-		db:         vs.db,
-		blockIndex: vs.blockIndex,
-		timestamp:  vs.timestamp,
-		empty:      vs.empty,
-		stateHash:  vs.stateHash,
-		variables:  vs.variables.Clone(),
+		chainID:     vs.chainID,
+		db:          vs.db,
+		blockIndex:  vs.blockIndex,
+		timestamp:   vs.timestamp,
+		empty:       vs.empty,
+		stateHash:   vs.stateHash,
+		updatesHash: vs.updatesHash,
+		variables:   vs.variables.Clone(),
+		merkle:      vs.merkle.clone(),
 	}
 }
 
+func (vs *virtualState) AcquireSolid() func() {
+	return acquireSolidState(vs.db)
+}
+
 func (vs *virtualState) InitiatedBy(ownerAddr *address.Address) bool {
 	addr, ok, err := codec.DecodeAddress(vs.Variables().MustGet(vmconst.VarNameOwnerAddress))
 	if !ok || err != nil {
@@ -89,8 +99,8 @@ func (vs *virtualState) BlockIndex() uint32 {
 }
 
 func (vs *virtualState) ApplyBlockIndex(blockIndex uint32) {
-	vh := vs.Hash()
-	vs.stateHash = hashing.HashData(vh[:], util.Uint32To4Bytes(blockIndex))
+	vs.updatesHash = *vs.Hash()
+	vs.stateHash = StateHashFromMerkleRoot(&vs.updatesHash, blockIndex, vs.MerkleRoot())
 	vs.empty = false
 	vs.blockIndex = blockIndex
 }
@@ -121,7 +131,23 @@ func (vs *virtualState) ApplyBlock(batch Block) error {
 
 // applies one state update. Doesn't change state index
 func (vs *virtualState) ApplyStateUpdate(stateUpd StateUpdate) {
+	// keys deleted by prefix are collected before the update to remove them from the Merkle tree.
+	// Keys mutated after the deletion are updated again below
+	deleted := make([]kv.Key, 0)
+	for _, prefix := range stateUpd.Mutations().DelPrefixes() {
+		vs.Variables().MustIterateKeys(prefix, func(key kv.Key) bool {
+			deleted = append(deleted, key)
+			return true
+		})
+	}
 	stateUpd.Mutations().ApplyTo(vs.Variables())
+	for _, key := range deleted {
+		vs.merkle.update(key, nil)
+	}
+	stateUpd.Mutations().IterateLatest(func(key kv.Key, mut buffered.Mutation) bool {
+		vs.merkle.update(key, mut.Value())
+		return true
+	})
 	vs.timestamp = stateUpd.Timestamp()
 	vh := vs.Hash()
 	sh := util.GetHashValue(stateUpd)
@@ -133,6 +159,24 @@ func (vs *virtualState) Hash() *hashing.HashValue {
 	return &vs.stateHash
 }
 
+func (vs *virtualState) UpdatesHash() *hashing.HashValue {
+	return &vs.updatesHash
+}
+
+func (vs *virtualState) MerkleRoot() *hashing.HashValue {
+	return vs.merkle.root()
+}
+
+func (vs *virtualState) GetProof(key kv.Key) (*MerkleProof, error) {
+	return vs.merkle.proof(key)
+}
+
+// StateHashFromMerkleRoot calculates the state hash, committed to the anchor transaction,
+// from the hash of state updates, the block index and the Merkle root of the state variables
+func StateHashFromMerkleRoot(updatesHash *hashing.HashValue, blockIndex uint32, root *hashing.HashValue) hashing.HashValue {
+	return hashing.HashData(updatesHash[:], util.Uint32To4Bytes(blockIndex), root[:])
+}
+
 func (vs *virtualState) Write(w io.Writer) error {
 	if _, err := w.Write(util.Uint32To4Bytes(vs.blockIndex)); err != nil {
 		return err
@@ -143,6 +187,9 @@ func (vs *virtualState) Write(w io.Writer) error {
 	if _, err := w.Write(vs.stateHash[:]); err != nil {
 		return err
 	}
+	if _, err := w.Write(vs.updatesHash[:]); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -155,7 +202,10 @@ func (vs *virtualState) Read(r io.Reader) error {
 		return err
 	}
 	vs.timestamp = int64(ts)
-	if _, err := r.Read(vs.stateHash[:]); err != nil {
+	if _, err := io.ReadFull(r, vs.stateHash[:]); err != nil {
+		return err
+	}
+	if _, err := io.ReadFull(r, vs.updatesHash[:]); err != nil {
 		return err
 	}
 	// after reading something, the state is not empty
@@ -190,20 +240,65 @@ func (vs *virtualState) CommitToDb(b Block) error {
 		values = append(values, []byte{0})
 	}
 
+	// store the undo record of the block to be able to load past states
+	// and remove all records which fall out of the retention window.
+	// The window may have been shrunk since the records were stored
+	if historyRetention > 0 {
+		undo, err := vs.makeUndoRecord()
+		if err != nil {
+			return err
+		}
+		if undo != nil {
+			keys = append(keys, dbkeyUndo(b.StateIndex()))
+			values = append(values, util.MustBytes(undo))
+		}
+	}
+	pruned, err := undoKeysToPrune(vs.db, b.StateIndex())
+	if err != nil {
+		return err
+	}
+	for _, k := range pruned {
+		keys = append(keys, k)
+		values = append(values, nil)
+	}
+
 	// store uncommitted mutations
+	mutated := make(map[kv.Key]bool)
 	vs.variables.Mutations().IterateLatest(func(k kv.Key, mut buffered.Mutation) bool {
 		keys = append(keys, dbkeyStateVariable(k))
+		mutated[k] = true
 
 		// if mutation is MutationDel, mut.Value() = nil and the key is deleted
 		values = append(values, mut.Value())
 		return true
 	})
+	// delete keys deleted by prefix and not mutated after that
+	err = vs.iterateDeletedByPrefix(mutated, func(k kv.Key, _ []byte) {
+		keys = append(keys, dbkeyStateVariable(k))
+		values = append(values, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	// store uncommitted nodes of the Merkle tree
+	vs.merkle.iterateDirty(func(key []byte, value []byte) {
+		keys = append(keys, key)
+		values = append(values, value)
+	})
+
+	lock := solidStateLock(vs.db)
+	lock.Lock()
+	defer lock.Unlock()
 
 	err = util.DbSetMulti(vs.db, keys, values)
 	if err != nil {
 		return err
 	}
+	// cached reads are not valid after the DB has changed
+	vs.variables.SetReadCache(nil)
 	vs.variables.ClearMutations()
+	vs.merkle.clearDirty()
 	return nil
 }
 
@@ -211,6 +306,11 @@ func LoadSolidState(chainID *coretypes.ChainID) (VirtualState, Block, bool, erro
 	return loadSolidState(getSCPartition(chainID), chainID)
 }
 
+// LoadSolidStateFromDB loads the solid state from the given partition of the chain instead of the node's database
+func LoadSolidStateFromDB(db kvstore.KVStore, chainID *coretypes.ChainID) (VirtualState, Block, bool, error) {
+	return loadSolidState(db, chainID)
+}
+
 func loadSolidState(db kvstore.KVStore, chainID *coretypes.ChainID) (VirtualState, Block, bool, error) {
 	stateIndexBin, err := db.Get(database.MakeKey(database.ObjectTypeSolidStateIndex))
 	if err == kvstore.ErrKeyNotFound {
@@ -242,6 +342,26 @@ func loadSolidState(db kvstore.KVStore, chainID *coretypes.ChainID) (VirtualStat
 	return vs, batch, true, nil
 }
 
+// iterateDeletedByPrefix iterates committed variables which are deleted by uncommitted "del prefix" mutations,
+// except the ones in 'mutated'
+func (vs *virtualState) iterateDeletedByPrefix(mutated map[kv.Key]bool, f func(k kv.Key, v []byte)) error {
+	seen := make(map[kv.Key]bool)
+	for _, prefix := range vs.variables.Mutations().DelPrefixes() {
+		err := vs.db.Iterate(dbkeyStateVariable(prefix), func(key kvstore.Key, value kvstore.Value) bool {
+			k := kv.Key(key[1:])
+			if !mutated[k] && !seen[k] {
+				seen[k] = true
+				f(k, value)
+			}
+			return true
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func dbkeyStateVariable(key kv.Key) []byte {
 	return database.MakeKey(database.ObjectTypeStateVariable, []byte(key))
 }
@@ -251,5 +371,10 @@ func dbkeyRequest(reqid *coretypes.RequestID) []byte {
 }
 
 func IsRequestCompleted(addr *coretypes.ChainID, reqid *coretypes.RequestID) (bool, error) {
-	return getSCPartition(addr).Has(dbkeyRequest(reqid))
+	return IsRequestCompletedInDB(getSCPartition(addr), reqid)
+}
+
+// IsRequestCompletedInDB checks the record of the processed request in the given partition of the chain
+func IsRequestCompletedInDB(db kvstore.KVStore, reqid *coretypes.RequestID) (bool, error) {
+	return db.Has(dbkeyRequest(reqid))
 }
@@ -1,6 +1,7 @@
 package state
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
@@ -43,6 +44,23 @@ func TestVariableStateBasic(t *testing.T) {
 	assert.EqualValues(t, vs3.Hash(), vs4.Hash())
 }
 
+func TestVirtualStateReadTruncated(t *testing.T) {
+	chainID := coretypes.ChainID{1, 3, 3, 7}
+	vs := NewVirtualState(mapdb.NewMapDB(), &chainID)
+	txid := (transaction.ID)(hashing.HashStrings("test string 1"))
+	reqid := coretypes.NewRequestID(txid, 0)
+	vs.ApplyStateUpdate(NewStateUpdate(&reqid).WithTimestamp(1))
+	data := util.MustBytes(vs)
+
+	for i := 0; i < len(data); i++ {
+		vsBack := NewVirtualState(mapdb.NewMapDB(), &chainID)
+		assert.Error(t, vsBack.Read(bytes.NewReader(data[:i])))
+	}
+	vsBack := NewVirtualState(mapdb.NewMapDB(), &chainID)
+	assert.NoError(t, vsBack.Read(bytes.NewReader(data)))
+	assert.EqualValues(t, vs.Hash(), vsBack.Hash())
+}
+
 func TestApply(t *testing.T) {
 	txid1 := (transaction.ID)(hashing.HashStrings("test string 1"))
 	reqid1 := coretypes.NewRequestID(txid1, 5)
@@ -7,6 +7,7 @@ import (
 	valuetransaction "github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
 	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv"
 	"github.com/iotaledger/wasp/packages/kv/buffered"
 )
 
@@ -27,10 +28,19 @@ type VirtualState interface {
 	// commit means saving virtual state to sc db, making it persistent (solid)
 	CommitToDb(batch Block) error
 	// return hash of the variable state. It is a root of the Merkle chain of all
-	// state updates starting from the origin
+	// state updates starting from the origin, committed together with the Merkle root of the variables
 	Hash() *hashing.HashValue
+	// hash of the chain of state updates before the last block index was applied
+	UpdatesHash() *hashing.HashValue
+	// root of the sparse Merkle tree of all state variables
+	MerkleRoot() *hashing.HashValue
+	// proof of inclusion (or absence) of the variable in the solid state. Uncommitted updates are included
+	GetProof(key kv.Key) (*MerkleProof, error)
 	// the storage of variable/value pairs
 	Variables() buffered.BufferedKVStore
+	// guarantees that the solid state is not changed in the DB the state is backed by, until the returned
+	// function is called (see AcquireSolidState)
+	AcquireSolid() (release func())
 	Clone() VirtualState
 	DangerouslyConvertToString() string
 }
@@ -5,6 +5,8 @@ package tcrypto
 
 import (
 	"bytes"
+	"encoding"
+	"fmt"
 	"io"
 
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
@@ -12,6 +14,7 @@ import (
 	"github.com/iotaledger/wasp/packages/tcrypto/tbdn"
 	"github.com/iotaledger/wasp/packages/util"
 	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/proof/dleq"
 	"go.dedis.ch/kyber/v3/share"
 	"go.dedis.ch/kyber/v3/sign/bdn"
 )
@@ -172,6 +175,98 @@ func (s *DKShare) SignShare(data []byte) (tbdn.SigShare, error) {
 	return tbdn.Sign(s.suite, &priShare, data)
 }
 
+// DecryptionShare computes the share of the node in the threshold decryption of the ElGamal
+// ciphertext with the (marshaled) ephemeral key R: x_i·R, where x_i is the private share of the node.
+// The share is returned together with the proof that it is computed with the private share
+// behind the public share of the node: index (2 bytes) | x_i·R | DLEQ proof
+func (s *DKShare) DecryptionShare(ephemeralKey []byte) ([]byte, error) {
+	R, err := s.ephemeralPoint(ephemeralKey)
+	if err != nil {
+		return nil, err
+	}
+	proof, _, xR, err := dleq.NewDLEQProof(s.suite, s.suite.Point().Base(), R, s.PrivateShare)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := util.WriteUint16(&buf, *s.Index); err != nil {
+		return nil, err
+	}
+	for _, m := range []encoding.BinaryMarshaler{xR, proof.C, proof.R, proof.VG, proof.VH} {
+		if err := util.WriteMarshaled(&buf, m); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// VerifyDecryptionShare checks the decryption share against the public share of the node which produced it.
+// Returns the share as the point with the index of the node
+func (s *DKShare) VerifyDecryptionShare(ephemeralKey []byte, decShare []byte) (*share.PubShare, error) {
+	R, err := s.ephemeralPoint(ephemeralKey)
+	if err != nil {
+		return nil, err
+	}
+	r := bytes.NewReader(decShare)
+	var index uint16
+	if err := util.ReadUint16(r, &index); err != nil {
+		return nil, err
+	}
+	if index >= s.N {
+		return nil, fmt.Errorf("wrong index of the decryption share: %d", index)
+	}
+	xR := s.suite.Point()
+	proof := &dleq.Proof{
+		C:  s.suite.Scalar(),
+		R:  s.suite.Scalar(),
+		VG: s.suite.Point(),
+		VH: s.suite.Point(),
+	}
+	for _, m := range []encoding.BinaryUnmarshaler{xR, proof.C, proof.R, proof.VG, proof.VH} {
+		if err := util.ReadMarshaled(r, m); err != nil {
+			return nil, err
+		}
+	}
+	if r.Len() != 0 {
+		return nil, fmt.Errorf("unexpected bytes after the decryption share")
+	}
+	if err := proof.Verify(s.suite, s.suite.Point().Base(), R, s.PublicShares[index], xR); err != nil {
+		return nil, fmt.Errorf("decryption share #%d: %v", index, err)
+	}
+	return &share.PubShare{I: int(index), V: xR}, nil
+}
+
+// RecoverDecryptionSecret recovers the secret x·R of the ElGamal ciphertext with the ephemeral key R,
+// encrypted to the shared public key. All decryption shares are verified and at least T of them from
+// different nodes must be provided. Any T valid shares recover the same secret
+func (s *DKShare) RecoverDecryptionSecret(ephemeralKey []byte, decShares [][]byte) (kyber.Point, error) {
+	pubShares := make([]*share.PubShare, 0, len(decShares))
+	seen := make(map[int]bool)
+	for _, data := range decShares {
+		sh, err := s.VerifyDecryptionShare(ephemeralKey, data)
+		if err != nil {
+			return nil, err
+		}
+		if seen[sh.I] {
+			return nil, fmt.Errorf("duplicate decryption share #%d", sh.I)
+		}
+		seen[sh.I] = true
+		pubShares = append(pubShares, sh)
+	}
+	if len(pubShares) < int(s.T) {
+		return nil, fmt.Errorf("not enough decryption shares: %d, need %d", len(pubShares), s.T)
+	}
+	return share.RecoverCommit(s.suite, pubShares, int(s.T), int(s.N))
+}
+
+func (s *DKShare) ephemeralPoint(data []byte) (kyber.Point, error) {
+	ret := s.suite.Point()
+	if err := ret.UnmarshalBinary(data); err != nil {
+		return nil, fmt.Errorf("wrong ephemeral key: %v", err)
+	}
+	return ret, nil
+}
+
 // VerifySigShare verifies the signature of a particular share.
 func (s *DKShare) VerifySigShare(data []byte, sigshare tbdn.SigShare) error {
 	idx, err := sigshare.Index()
@@ -0,0 +1,395 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+// Package chainsim runs a committee of full chain instances (chainimpl, consensus, statemgr)
+// in one process. The nodes talk over the mocked peering network of testutil and share a simulated
+// Level 1 ledger, each node keeps the state of the chain in its own in-memory database.
+// The simulator injects faults: crashes and restarts of nodes, partitions of the network
+// and byzantine nodes which tamper with the messages they send.
+//
+// Keys, addresses and the random choices of the faults are derived from Config.Seed, so a scenario
+// is replayed with the same seed. The scheduling of the goroutines of the nodes is not controlled.
+// Off-tangle args are not supported: they are kept in the registry of the process.
+package chainsim
+
+import (
+	"crypto/cipher"
+	"fmt"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address/signaturescheme"
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	valuetransaction "github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
+	"github.com/iotaledger/hive.go/crypto/ed25519"
+	"github.com/iotaledger/hive.go/logger"
+	"github.com/iotaledger/wasp/client/level1"
+	"github.com/iotaledger/wasp/packages/apilib"
+	_ "github.com/iotaledger/wasp/packages/chain/chainimpl" // activate init
+	"github.com/iotaledger/wasp/packages/chain/consensus"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/registry"
+	"github.com/iotaledger/wasp/packages/sctransaction"
+	"github.com/iotaledger/wasp/packages/sctransaction/origin"
+	"github.com/iotaledger/wasp/packages/tcrypto"
+	"github.com/iotaledger/wasp/packages/testutil"
+	"github.com/iotaledger/wasp/packages/vm/core/root"
+	_ "github.com/iotaledger/wasp/packages/vm/sandbox" // activate init
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/group/edwards25519"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/share"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+const (
+	// DeployTimeout is the time for the committee to process the init request of the chain
+	DeployTimeout = 60 * time.Second
+	// peering messages buffered for each node
+	netBufSize = 1000
+	// period of checking conditions in WaitUntil
+	waitPeriod = 50 * time.Millisecond
+)
+
+type Config struct {
+	// N is the size of the committee, T is the quorum. T must be less than N
+	N uint16
+	T uint16
+	// Seed of the keys and of the random choices of the simulator
+	Seed int64
+	// NetBehavior is the behavior of the network under the injected faults. Reliable if nil
+	NetBehavior testutil.PeeringNetBehavior
+	// LogLevel of the nodes. Info by default
+	LogLevel logger.Level
+	// PeerTimeout is the time the nodes wait for the peers in the consensus before the leader is rotated.
+	// consensus.DefaultPeerTimeout if 0
+	PeerTimeout time.Duration
+}
+
+type Simulator struct {
+	Nodes        []*Node
+	ChainID      coretypes.ChainID
+	ChainColor   balance.Color
+	ChainAddress address.Address
+	// SealingKey is the shared public key of the committee. Request args are sealed to it
+	SealingKey kyber.Point
+	// OwnerSigScheme is the owner of the chain. It has the funds to post requests
+	OwnerSigScheme signaturescheme.SignatureScheme
+
+	t           *testing.T
+	log         *logger.Logger
+	netIDs      []string
+	netBehavior testutil.PeeringNetBehavior
+	faults      *faults
+	ledger      *ledger
+	chainRecord *registry.ChainRecord
+	quorum      uint16
+	keyStream   cipher.Stream
+	stopOnce    sync.Once
+}
+
+// New starts the committee, deploys the chain and waits until the init request of the chain is
+// processed by all nodes. The simulator is stopped when the test finishes
+func New(t *testing.T, cfg *Config) *Simulator {
+	require.True(t, cfg.T > 0 && cfg.T < cfg.N, "wrong quorum T = %d of N = %d", cfg.T, cfg.N)
+
+	sim := &Simulator{
+		t:   t,
+		log: testutil.WithLevel(testutil.NewLogger(t, "04:05.000"), cfg.LogLevel, false),
+	}
+	t.Cleanup(sim.Stop)
+	consensus.SetPeerTimeout(cfg.PeerTimeout)
+
+	rnd := rand.New(rand.NewSource(cfg.Seed))
+	keyStream := random.New(rnd)
+	sim.quorum = cfg.T
+	sim.keyStream = keyStream
+
+	sim.netIDs = make([]string, cfg.N)
+	peerPubs := make([]kyber.Point, cfg.N)
+	peerSecs := make([]kyber.Scalar, cfg.N)
+	peerSuite := edwards25519.NewBlakeSHA256Ed25519()
+	for i := range sim.netIDs {
+		sim.netIDs[i] = fmt.Sprintf("localhost:%d", 4000+i)
+		peerSecs[i] = peerSuite.Scalar().Pick(keyStream)
+		peerPubs[i] = peerSuite.Point().Mul(peerSecs[i], nil)
+	}
+	sim.faults = newFaults(sim.netIDs)
+	sim.netBehavior = cfg.NetBehavior
+	if sim.netBehavior == nil {
+		sim.netBehavior = testutil.NewPeeringNetReliable()
+	}
+	network := testutil.NewPeeringNetwork(
+		sim.netIDs, peerPubs, peerSecs, netBufSize,
+		testutil.NewPeeringNetIntercepted(sim.netBehavior, sim.faults),
+		testutil.WithLevel(sim.log.Named("net"), logger.LevelWarn, false),
+	)
+	netProviders := network.NetworkProviders()
+
+	dksProviders, chainAddr, sharedPublic := sim.dealKeys(cfg.N, cfg.T, keyStream, nil)
+	sim.ChainAddress = chainAddr
+	sim.SealingKey = sharedPublic
+	sim.ChainID = coretypes.ChainID(chainAddr)
+
+	seed := make([]byte, ed25519.SeedSize)
+	_, _ = rnd.Read(seed)
+	ownerKey := ed25519.PrivateKeyFromSeed(seed)
+	sim.OwnerSigScheme = signaturescheme.ED25519(ed25519.KeyPair{PrivateKey: ownerKey, PublicKey: ownerKey.Public()})
+
+	sim.ledger = newLedger(sim)
+	sim.Nodes = make([]*Node, cfg.N)
+	for i := range sim.Nodes {
+		sim.Nodes[i] = newNode(sim, i, sim.netIDs[i], netProviders[i], dksProviders[i])
+	}
+	sim.deployChain()
+	return sim
+}
+
+// dealKeys generates the shares of the key of the chain address for each node, like the DKG does.
+// The shares are saved to the registries of the nodes, new registries are created if dksProviders is nil.
+// Returns the shared public key too
+func (sim *Simulator) dealKeys(n, t uint16, keyStream cipher.Stream, dksProviders []tcrypto.RegistryProvider) ([]tcrypto.RegistryProvider, address.Address, kyber.Point) {
+	suite := pairing.NewSuiteBn256()
+	priPoly := share.NewPriPoly(suite, int(t), suite.Scalar().Pick(keyStream), keyStream)
+	pubPoly := priPoly.Commit(nil)
+	_, commits := pubPoly.Info()
+	// the serialized DKShare holds N-1 commits. The missing coefficients of the polynomial are zero
+	for len(commits) < int(n)-1 {
+		commits = append(commits, suite.Point().Null())
+	}
+	priShares := priPoly.Shares(int(n))
+	pubShares := make([]kyber.Point, n)
+	for i := range priShares {
+		pubShares[i] = suite.Point().Mul(priShares[i].V, nil)
+	}
+	ret := dksProviders
+	if ret == nil {
+		ret = make([]tcrypto.RegistryProvider, n)
+		for i := range ret {
+			ret[i] = testutil.NewDkgRegistryProvider(suite)
+		}
+	}
+	var chainAddr address.Address
+	for i := range ret {
+		dkshare, err := tcrypto.NewDKShare(uint16(i), n, t, pubPoly.Commit(), commits, pubShares, priShares[i].V)
+		require.NoError(sim.t, err)
+		require.NoError(sim.t, ret[i].SaveDKShare(dkshare))
+		chainAddr = *dkshare.Address
+	}
+	return ret, chainAddr, pubPoly.Commit()
+}
+
+// deployChain posts the origin transaction, starts the nodes and posts the init request
+func (sim *Simulator) deployChain() {
+	ownerAddr := sim.OwnerSigScheme.Address()
+	require.NoError(sim.t, sim.ledger.RequestFunds(&ownerAddr))
+
+	originTx, err := origin.NewOriginTransaction(origin.NewOriginTransactionParams{
+		OriginAddress:             sim.ChainAddress,
+		OriginatorSignatureScheme: sim.OwnerSigScheme,
+		AllInputs:                 sim.ledger.getAddressOutputs(ownerAddr),
+	})
+	require.NoError(sim.t, err)
+	require.NoError(sim.t, sim.ledger.PostTransaction(originTx.Transaction))
+	sim.ChainColor = balance.Color(originTx.ID())
+
+	sim.chainRecord = &registry.ChainRecord{
+		ChainID:        sim.ChainID,
+		Color:          sim.ChainColor,
+		CommitteeNodes: sim.netIDs,
+		Active:         true,
+	}
+	for _, node := range sim.Nodes {
+		node.start()
+	}
+
+	reqTx, err := origin.NewRootInitRequestTransaction(origin.NewRootInitRequestTransactionParams{
+		ChainID:              sim.ChainID,
+		ChainColor:           sim.ChainColor,
+		ChainAddress:         sim.ChainAddress,
+		OwnerSignatureScheme: sim.OwnerSigScheme,
+		AllInputs:            sim.ledger.getAddressOutputs(ownerAddr),
+		Description:          "simulated chain",
+	})
+	require.NoError(sim.t, err)
+	require.NoError(sim.t, sim.ledger.PostTransaction(reqTx.Transaction))
+	require.True(sim.t, sim.WaitForRequestsProcessed(reqTx, DeployTimeout), "the chain was not deployed in %v", DeployTimeout)
+	sim.log.Infof("deployed chain %s on %d nodes", sim.ChainID.String(), len(sim.Nodes))
+}
+
+// RotateCommittee hands the chain over to the committee with new keys, like apilib.RotateCommittee does.
+// The new keys are dealt to the same nodes, which keep the keys of the previous address and forward the tokens
+// sent to it. The chain objects are restarted with the new address when all running nodes processed the rotation
+func (sim *Simulator) RotateCommittee() {
+	dksProviders := make([]tcrypto.RegistryProvider, len(sim.Nodes))
+	for i, node := range sim.Nodes {
+		dksProviders[i] = node.dksProvider
+	}
+	_, newAddr, sharedPublic := sim.dealKeys(uint16(len(sim.Nodes)), sim.quorum, sim.keyStream, dksProviders)
+	tx := sim.PostRequest(apilib.RequestSectionParams{
+		TargetContractID: coretypes.NewContractID(sim.ChainID, root.Interface.Hname()),
+		EntryPointCode:   coretypes.Hn(root.FuncRotateCommittee),
+		Vars: codec.MakeDict(map[string]interface{}{
+			root.ParamChainAddress: newAddr,
+		}),
+	})
+	require.True(sim.t, sim.WaitForRequestsProcessed(tx, DeployTimeout), "the committee was not rotated in %v", DeployTimeout)
+	dismissed := sim.WaitUntil(func() bool {
+		for _, node := range sim.Nodes {
+			if c := node.Chain(); c != nil && !c.IsDismissed() {
+				return false
+			}
+		}
+		return true
+	}, DeployTimeout)
+	require.True(sim.t, dismissed, "the previous committee was not dismissed in %v", DeployTimeout)
+
+	sim.ChainAddress = newAddr
+	sim.SealingKey = sharedPublic
+	for _, node := range sim.Nodes {
+		if node.IsRunning() {
+			node.stop()
+			node.start()
+		}
+	}
+	sim.log.Infof("rotated the committee of the chain %s to the address %s", sim.ChainID.String(), newAddr.String())
+}
+
+// Stop dismisses the chains of all nodes and closes the network
+func (sim *Simulator) Stop() {
+	sim.stopOnce.Do(func() {
+		for _, node := range sim.Nodes {
+			if node != nil {
+				node.stop()
+			}
+		}
+		if sim.netBehavior != nil {
+			sim.netBehavior.Close()
+		}
+	})
+}
+
+// Level1 returns the simulated ledger, to be used to build the transactions to the chain
+func (sim *Simulator) Level1() level1.Level1Client {
+	return sim.ledger
+}
+
+// PostRequest posts the transaction with the requests of the owner of the chain
+func (sim *Simulator) PostRequest(sections ...apilib.RequestSectionParams) *sctransaction.Transaction {
+	tx, err := apilib.CreateRequestTransaction(apilib.CreateRequestTransactionParams{
+		Level1Client:         sim.ledger,
+		SenderSigScheme:      sim.OwnerSigScheme,
+		RequestSectionParams: sections,
+		Post:                 true,
+	})
+	require.NoError(sim.t, err)
+	return tx
+}
+
+// WaitUntil checks the condition periodically until it is true or the timeout expires
+func (sim *Simulator) WaitUntil(cond func() bool, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(waitPeriod)
+	}
+	return true
+}
+
+// WaitForRequestsProcessed waits until the requests in the transaction are processed by all running nodes
+func (sim *Simulator) WaitForRequestsProcessed(tx *sctransaction.Transaction, timeout time.Duration) bool {
+	return sim.WaitUntil(func() bool {
+		for _, node := range sim.Nodes {
+			if !node.IsRunning() {
+				continue
+			}
+			for i := range tx.Requests() {
+				reqid := coretypes.NewRequestID(tx.ID(), uint16(i))
+				if !node.IsRequestProcessed(&reqid) {
+					return false
+				}
+			}
+		}
+		return true
+	}, timeout)
+}
+
+// CheckConsistency returns an error if the solid states of two nodes with the same block index differ
+func (sim *Simulator) CheckConsistency() error {
+	type stateAt struct {
+		node int
+		hash string
+	}
+	byIndex := make(map[uint32]stateAt)
+	for _, node := range sim.Nodes {
+		vs, ok, err := node.SolidState()
+		if err != nil {
+			return fmt.Errorf("node #%d: %v", node.Index, err)
+		}
+		if !ok {
+			continue
+		}
+		h := vs.Hash().String()
+		if prev, ok := byIndex[vs.BlockIndex()]; ok && prev.hash != h {
+			return fmt.Errorf("state #%d differs between nodes #%d and #%d: %s != %s",
+				vs.BlockIndex(), prev.node, node.Index, prev.hash, h)
+		}
+		byIndex[vs.BlockIndex()] = stateAt{node: node.Index, hash: h}
+	}
+	return nil
+}
+
+// Crash dismisses the chain of the node. The node does not send or receive anything until restarted
+func (sim *Simulator) Crash(index int) {
+	sim.log.Infof("crash node #%d", index)
+	sim.faults.setCrashed(sim.netIDs[index], true)
+	sim.Nodes[index].stop()
+}
+
+// Restart starts the crashed node again. The chain is loaded from the database of the node
+func (sim *Simulator) Restart(index int) {
+	sim.log.Infof("restart node #%d", index)
+	sim.faults.setCrashed(sim.netIDs[index], false)
+	sim.Nodes[index].start()
+}
+
+// Partition splits the network into the groups of nodes. The nodes not listed form one more group
+func (sim *Simulator) Partition(groups ...[]int) {
+	sim.log.Infof("partition the network: %+v", groups)
+	sim.faults.setPartition(sim.netIDs, groups)
+}
+
+// Heal removes the partition of the network
+func (sim *Simulator) Heal() {
+	sim.log.Infof("heal the network")
+	sim.faults.heal()
+}
+
+// SetByzantine makes the messages sent by the node pass the function. nil makes the node honest again
+func (sim *Simulator) SetByzantine(index int, mutate ByzantineFunc) {
+	sim.log.Infof("node #%d is byzantine: %v", index, mutate != nil)
+	sim.faults.setByzantine(sim.netIDs[index], mutate)
+}
+
+func (sim *Simulator) transactionConfirmed(tx *valuetransaction.Transaction) {
+	for _, node := range sim.Nodes {
+		if node != nil {
+			node.transactionConfirmed(tx)
+		}
+	}
+}
+
+func (sim *Simulator) transactionRejected(tx *valuetransaction.Transaction) {
+	for _, node := range sim.Nodes {
+		if node != nil {
+			node.transactionRejected(tx)
+		}
+	}
+}
@@ -0,0 +1,262 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package chainsim
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/hive.go/logger"
+	"github.com/iotaledger/wasp/packages/apilib"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/kv/subrealm"
+	"github.com/iotaledger/wasp/packages/publisher"
+	"github.com/iotaledger/wasp/packages/sctransaction"
+	"github.com/iotaledger/wasp/packages/vm/core/accounts"
+	"github.com/iotaledger/wasp/packages/vm/core/blob"
+	"github.com/stretchr/testify/require"
+)
+
+const processTimeout = 30 * time.Second
+
+// peerTimeout is the peer timeout of the consensus in the tests where the leader may be faulty,
+// so the leader is rotated several times within the processTimeout
+const peerTimeout = 2 * time.Second
+
+func postDeposit(sim *Simulator) *sctransaction.Transaction {
+	return sim.PostRequest(apilib.RequestSectionParams{
+		TargetContractID: coretypes.NewContractID(sim.ChainID, accounts.Interface.Hname()),
+		EntryPointCode:   coretypes.Hn(accounts.FuncDeposit),
+	})
+}
+
+func requireProcessed(t *testing.T, sim *Simulator, tx *sctransaction.Transaction) {
+	require.True(t, sim.WaitForRequestsProcessed(tx, processTimeout), "request %s was not processed", tx.ID().String())
+	require.NoError(t, sim.CheckConsistency())
+}
+
+func requireSameState(t *testing.T, sim *Simulator) {
+	ok := sim.WaitUntil(func() bool {
+		vs0, _, err := sim.Nodes[0].SolidState()
+		require.NoError(t, err)
+		for _, node := range sim.Nodes[1:] {
+			vs, _, err := node.SolidState()
+			require.NoError(t, err)
+			if vs == nil || vs.BlockIndex() != vs0.BlockIndex() {
+				return false
+			}
+		}
+		return true
+	}, processTimeout)
+	require.True(t, ok, "the nodes did not reach the same state")
+	require.NoError(t, sim.CheckConsistency())
+}
+
+func TestDeploy(t *testing.T) {
+	sim := New(t, &Config{N: 4, T: 3, Seed: 1})
+
+	tx := postDeposit(sim)
+	requireProcessed(t, sim, tx)
+	requireSameState(t, sim)
+}
+
+func TestCrashRestart(t *testing.T) {
+	sim := New(t, &Config{N: 4, T: 3, Seed: 2, PeerTimeout: peerTimeout})
+
+	sim.Crash(3)
+	tx := postDeposit(sim)
+	requireProcessed(t, sim, tx)
+	require.False(t, sim.Nodes[3].IsRunning())
+
+	sim.Restart(3)
+	requireSameState(t, sim)
+	reqid := coretypes.NewRequestID(tx.ID(), 0)
+	require.True(t, sim.Nodes[3].IsRequestProcessed(&reqid))
+
+	// the restarted node takes part in the consensus again. The crashed node may be the leader
+	sim.Crash(0)
+	tx = postDeposit(sim)
+	requireProcessed(t, sim, tx)
+}
+
+func TestPartition(t *testing.T) {
+	sim := New(t, &Config{N: 4, T: 3, Seed: 3})
+
+	sim.Partition([]int{0, 1, 2}, []int{3})
+	tx := postDeposit(sim)
+	require.True(t, sim.WaitUntil(func() bool {
+		reqid := coretypes.NewRequestID(tx.ID(), 0)
+		return sim.Nodes[0].IsRequestProcessed(&reqid)
+	}, processTimeout))
+	require.NoError(t, sim.CheckConsistency())
+
+	sim.Heal()
+	requireProcessed(t, sim, tx)
+	requireSameState(t, sim)
+}
+
+func TestNoQuorum(t *testing.T) {
+	sim := New(t, &Config{N: 4, T: 3, Seed: 4})
+
+	sim.Partition([]int{0, 1}, []int{2, 3})
+	tx := postDeposit(sim)
+	time.Sleep(3 * time.Second)
+	reqid := coretypes.NewRequestID(tx.ID(), 0)
+	for _, node := range sim.Nodes {
+		require.False(t, node.IsRequestProcessed(&reqid))
+	}
+
+	sim.Heal()
+	requireProcessed(t, sim, tx)
+}
+
+func TestByzantine(t *testing.T) {
+	// the leader is rotated soon when the byzantine node is the leader
+	sim := New(t, &Config{N: 4, T: 3, Seed: 5, PeerTimeout: peerTimeout})
+
+	// the honest nodes process the requests and never commit inconsistent states
+	// while the byzantine node corrupts its messages
+	sim.SetByzantine(1, CorruptData(5))
+	for i := 0; i < 3; i++ {
+		tx := postDeposit(sim)
+		reqid := coretypes.NewRequestID(tx.ID(), 0)
+		require.True(t, sim.WaitUntil(func() bool {
+			for _, node := range sim.Nodes {
+				if node.Index != 1 && !node.IsRequestProcessed(&reqid) {
+					return false
+				}
+			}
+			return true
+		}, processTimeout), "request %s was not processed by the honest nodes", tx.ID().String())
+		require.NoError(t, sim.CheckConsistency())
+	}
+
+	// the node catches up when it becomes honest again
+	sim.SetByzantine(1, nil)
+	requireSameState(t, sim)
+}
+
+func TestContractEventsOnCommit(t *testing.T) {
+	sim := New(t, &Config{N: 4, T: 3, Seed: 7})
+
+	var mutex sync.Mutex
+	var published []interface{}
+	closure := events.NewClosure(func(ev interface{}) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		switch ev := ev.(type) {
+		case *publisher.StateTransition:
+			if ev.ChainID == sim.ChainID {
+				published = append(published, ev)
+			}
+		case *publisher.ContractEvent:
+			if ev.ChainID == sim.ChainID {
+				published = append(published, ev)
+			}
+		case *publisher.RequestCompleted:
+			if ev.ChainID == sim.ChainID {
+				published = append(published, ev)
+			}
+		}
+	})
+	publisher.ChainEvent.Attach(closure)
+	defer publisher.ChainEvent.Detach(closure)
+
+	tx := sim.PostRequest(apilib.RequestSectionParams{
+		TargetContractID: coretypes.NewContractID(sim.ChainID, blob.Interface.Hname()),
+		EntryPointCode:   coretypes.Hn(blob.FuncStoreBlob),
+		Vars:             dict.Dict{"field": []byte("value")},
+	})
+	requireProcessed(t, sim, tx)
+	requireSameState(t, sim)
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	reqid := coretypes.NewRequestID(tx.ID(), 0)
+	var blockIndex uint32
+	found := false
+	for _, ev := range published {
+		if ev, ok := ev.(*publisher.RequestCompleted); ok && ev.RequestID == reqid {
+			blockIndex = ev.BlockIndex
+			found = true
+		}
+	}
+	require.True(t, found)
+
+	// each node publishes the event once, after it has committed the block with the request
+	var committed, emitted int
+	for _, ev := range published {
+		switch ev := ev.(type) {
+		case *publisher.StateTransition:
+			if ev.BlockIndex == blockIndex {
+				committed++
+			}
+		case *publisher.ContractEvent:
+			emitted++
+			require.Greater(t, committed, 0, "the contract event was published before the block was committed")
+		}
+	}
+	require.Greater(t, emitted, 0)
+	require.LessOrEqual(t, emitted, len(sim.Nodes))
+}
+
+func TestSealedArgs(t *testing.T) {
+	sim := New(t, &Config{N: 4, T: 3, Seed: 8})
+
+	fields := dict.Dict{"field": []byte("sealed value")}
+	tx := sim.PostRequest(apilib.RequestSectionParams{
+		TargetContractID: coretypes.NewContractID(sim.ChainID, blob.Interface.Hname()),
+		EntryPointCode:   coretypes.Hn(blob.FuncStoreBlob),
+		Vars:             fields,
+		SealingKey:       sim.SealingKey,
+	})
+	require.True(t, tx.Requests()[0].IsSealed())
+	requireProcessed(t, sim, tx)
+	requireSameState(t, sim)
+
+	// the committee unsealed the args: the blob is stored
+	blobHash := blob.MustGetBlobHash(fields)
+	for _, node := range sim.Nodes {
+		vs, _, err := node.SolidState()
+		require.NoError(t, err)
+		blobState := subrealm.New(vs.Variables(), kv.Key(blob.Interface.Hname().Bytes()))
+		require.True(t, blob.GetDirectoryR(blobState).MustHasAt(blobHash[:]))
+	}
+}
+
+func TestLargeCommittee(t *testing.T) {
+	if testing.Short() {
+		t.Skip("large committee")
+	}
+	sim := New(t, &Config{N: 100, T: 67, Seed: 6, LogLevel: logger.LevelWarn})
+
+	tx := postDeposit(sim)
+	requireProcessed(t, sim, tx)
+}
+
+func TestRequestsAfterRotation(t *testing.T) {
+	sim := New(t, &Config{N: 4, T: 3, Seed: 9})
+	prevAddress := sim.ChainAddress
+
+	sim.RotateCommittee()
+	require.NotEqual(t, prevAddress, sim.ChainAddress)
+
+	// the request to the current address of the chain
+	tx := sim.PostRequest(apilib.RequestSectionParams{
+		TargetContractID: coretypes.NewContractID(sim.ChainID, accounts.Interface.Hname()),
+		EntryPointCode:   coretypes.Hn(accounts.FuncDeposit),
+		TargetAddress:    &sim.ChainAddress,
+	})
+	requireProcessed(t, sim, tx)
+
+	// the request to the address of the chain ID: its tokens are forwarded by the previous committee
+	tx = postDeposit(sim)
+	requireProcessed(t, sim, tx)
+	requireSameState(t, sim)
+	require.Empty(t, sim.Level1().(*ledger).getAddressOutputs(prevAddress))
+}
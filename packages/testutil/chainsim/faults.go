@@ -0,0 +1,126 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package chainsim
+
+import (
+	"math/rand"
+	"sync"
+
+	"github.com/iotaledger/wasp/packages/peering"
+	"github.com/iotaledger/wasp/packages/testutil"
+)
+
+// ByzantineFunc replaces the messages sent by the byzantine node to the node with index `to`.
+// It returns the message to deliver or nil to drop it. The message must not be modified in place
+type ByzantineFunc func(to int, msg *peering.PeerMessage) *peering.PeerMessage
+
+// faults decides on the delivery of each peer message according to the injected faults
+type faults struct {
+	mutex     sync.RWMutex
+	indices   map[string]int
+	crashed   map[string]bool
+	partition map[string]int // nil if the network is not partitioned
+	byzantine map[string]ByzantineFunc
+}
+
+var _ testutil.PeeringMsgInterceptor = &faults{}
+
+func newFaults(netIDs []string) *faults {
+	ret := &faults{
+		indices:   make(map[string]int),
+		crashed:   make(map[string]bool),
+		byzantine: make(map[string]ByzantineFunc),
+	}
+	for i, netID := range netIDs {
+		ret.indices[netID] = i
+	}
+	return ret
+}
+
+// Intercept implements testutil.PeeringMsgInterceptor
+func (f *faults) Intercept(from, to string, msg *peering.PeerMessage) *peering.PeerMessage {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	if !f.isReachable(from, to) || f.crashed[from] {
+		return nil
+	}
+	if mutate, ok := f.byzantine[from]; ok {
+		return mutate(f.indices[to], msg)
+	}
+	return msg
+}
+
+// IsReachable implements testutil.PeeringMsgInterceptor
+func (f *faults) IsReachable(from, to string) bool {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+	return f.isReachable(from, to)
+}
+
+func (f *faults) isReachable(from, to string) bool {
+	if f.crashed[to] {
+		return false
+	}
+	return f.partition == nil || f.partition[from] == f.partition[to]
+}
+
+func (f *faults) setCrashed(netID string, crashed bool) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.crashed[netID] = crashed
+}
+
+// setPartition splits the network into the groups of node indices. The nodes not listed form one more group
+func (f *faults) setPartition(netIDs []string, groups [][]int) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.partition = make(map[string]int)
+	for _, netID := range netIDs {
+		f.partition[netID] = 0
+	}
+	for i, group := range groups {
+		for _, idx := range group {
+			f.partition[netIDs[idx]] = i + 1
+		}
+	}
+}
+
+func (f *faults) heal() {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.partition = nil
+}
+
+func (f *faults) setByzantine(netID string, mutate ByzantineFunc) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if mutate == nil {
+		delete(f.byzantine, netID)
+		return
+	}
+	f.byzantine[netID] = mutate
+}
+
+// CorruptData returns the ByzantineFunc which flips a random bit in the data of every message.
+// The same seed corrupts the same bits of the same sequence of messages
+func CorruptData(seed int64) ByzantineFunc {
+	rnd := rand.New(rand.NewSource(seed))
+	var mutex sync.Mutex
+	return func(_ int, msg *peering.PeerMessage) *peering.PeerMessage {
+		if len(msg.MsgData) == 0 {
+			return msg
+		}
+		mutex.Lock()
+		pos := rnd.Intn(len(msg.MsgData))
+		bit := byte(1) << uint(rnd.Intn(8))
+		mutex.Unlock()
+
+		ret := *msg
+		ret.MsgData = append([]byte{}, msg.MsgData...)
+		ret.MsgData[pos] ^= bit
+		return &ret
+	}
+}
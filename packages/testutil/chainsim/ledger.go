@@ -0,0 +1,109 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package chainsim
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	valuetransaction "github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
+	"github.com/iotaledger/goshimmer/dapps/waspconn/packages/utxodb"
+	"github.com/iotaledger/wasp/client/level1"
+)
+
+// ledger is the simulated Level 1 ledger. Posted transactions are confirmed or rejected at once,
+// in the goroutine of the poster, and the updates are dispatched to the running nodes in the order of their indices
+type ledger struct {
+	utxodb   *utxodb.UtxoDB
+	rejected map[valuetransaction.ID]bool
+	mutex    sync.Mutex
+	sim      *Simulator
+}
+
+// ledger implements level1.Level1Client, so that the transactions to the chain can be built with apilib
+var _ level1.Level1Client = &ledger{}
+
+func newLedger(sim *Simulator) *ledger {
+	return &ledger{
+		utxodb:   utxodb.New(),
+		rejected: make(map[valuetransaction.ID]bool),
+		sim:      sim,
+	}
+}
+
+func (l *ledger) RequestFunds(targetAddress *address.Address) error {
+	l.mutex.Lock()
+	tx, err := l.utxodb.RequestFunds(*targetAddress)
+	l.mutex.Unlock()
+	if err != nil {
+		return err
+	}
+	l.sim.transactionConfirmed(tx)
+	return nil
+}
+
+func (l *ledger) GetConfirmedAccountOutputs(addr *address.Address) (map[valuetransaction.OutputID][]*balance.Balance, error) {
+	return l.getAddressOutputs(*addr), nil
+}
+
+// PostTransaction confirms the transaction or rejects it if it conflicts with the ledger.
+// Posting a confirmed transaction again is not a conflict
+func (l *ledger) PostTransaction(tx *valuetransaction.Transaction) error {
+	txid := tx.ID()
+	l.mutex.Lock()
+	if l.utxodb.IsConfirmed(&txid) {
+		l.mutex.Unlock()
+		return nil
+	}
+	err := l.utxodb.AddTransaction(tx)
+	if err != nil {
+		l.rejected[txid] = true
+	}
+	l.mutex.Unlock()
+
+	if err != nil {
+		l.sim.transactionRejected(tx)
+		return fmt.Errorf("rejected tx %s: %v", txid.String(), err)
+	}
+	l.sim.transactionConfirmed(tx)
+	return nil
+}
+
+func (l *ledger) PostAndWaitForConfirmation(tx *valuetransaction.Transaction) error {
+	return l.PostTransaction(tx)
+}
+
+func (l *ledger) WaitForConfirmation(txid valuetransaction.ID) error {
+	if l.isConfirmed(&txid) {
+		return nil
+	}
+	return fmt.Errorf("transaction %s is not confirmed", txid.String())
+}
+
+func (l *ledger) isConfirmed(txid *valuetransaction.ID) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.utxodb.IsConfirmed(txid)
+}
+
+func (l *ledger) isRejected(txid *valuetransaction.ID) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.rejected[*txid]
+}
+
+func (l *ledger) getConfirmedTransaction(txid *valuetransaction.ID) *valuetransaction.Transaction {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	tx, _ := l.utxodb.GetTransaction(*txid)
+	return tx
+}
+
+func (l *ledger) getAddressOutputs(addr address.Address) map[valuetransaction.OutputID][]*balance.Balance {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.utxodb.GetAddressOutputs(addr)
+}
@@ -0,0 +1,396 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package chainsim
+
+import (
+	"sync"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	valuetransaction "github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
+	"github.com/iotaledger/goshimmer/dapps/waspconn/packages/waspconn"
+	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+	"github.com/iotaledger/hive.go/logger"
+	"github.com/iotaledger/wasp/packages/chain"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/peering"
+	"github.com/iotaledger/wasp/packages/sctransaction"
+	"github.com/iotaledger/wasp/packages/state"
+	"github.com/iotaledger/wasp/packages/tcrypto"
+)
+
+// Node is one simulated Wasp node running the chain. The database of the node survives the crash
+type Node struct {
+	Index       int
+	NetID       string
+	sim         *Simulator
+	netProvider peering.NetworkProvider
+	dksProvider tcrypto.RegistryProvider
+	db          kvstore.KVStore
+	log         *logger.Logger
+
+	mutex         sync.RWMutex
+	chain         chain.Chain
+	conn          *nodeConn
+	subscriptions map[address.Address]balance.Color
+	// forwarder of the previous address of the chain, after the committee was rotated
+	forwarder chain.Forwarder
+}
+
+func newNode(sim *Simulator, index int, netID string, netProvider peering.NetworkProvider, dksProvider tcrypto.RegistryProvider) *Node {
+	return &Node{
+		Index:       index,
+		NetID:       netID,
+		sim:         sim,
+		netProvider: netProvider,
+		dksProvider: dksProvider,
+		db:          mapdb.NewMapDB(),
+		log:         sim.log.Named(netID),
+	}
+}
+
+// Chain returns the running chain object of the node or nil if the node is crashed
+func (n *Node) Chain() chain.Chain {
+	n.mutex.RLock()
+	defer n.mutex.RUnlock()
+	return n.chain
+}
+
+// IsRunning returns false if the node is crashed
+func (n *Node) IsRunning() bool {
+	return n.Chain() != nil
+}
+
+// SolidState loads the solid state of the chain from the database of the node
+func (n *Node) SolidState() (state.VirtualState, bool, error) {
+	vs, _, ok, err := state.LoadSolidStateFromDB(n.db, &n.sim.ChainID)
+	return vs, ok, err
+}
+
+// IsRequestProcessed checks the database of the node for the record of the processed request
+func (n *Node) IsRequestProcessed(reqid *coretypes.RequestID) bool {
+	ret, err := state.IsRequestCompletedInDB(n.db, reqid)
+	return err == nil && ret
+}
+
+// start creates the chain object. The node connection of each start is new,
+// so that the calls from the chain object of the previous start are ignored
+func (n *Node) start() {
+	conn := &nodeConn{node: n}
+	n.mutex.Lock()
+	if n.conn != nil {
+		n.mutex.Unlock()
+		return
+	}
+	n.conn = conn
+	n.subscriptions = make(map[address.Address]balance.Color)
+	n.mutex.Unlock()
+
+	// the same way the chains plugin activates the chain
+	n.startForwarder(conn, address.Address(n.sim.ChainID))
+	c := chain.New(n.sim.chainRecord, n.log, n.netProvider, n.dksProvider, conn, n.db, func() {
+		n.subscribe(conn)
+	})
+	if c == nil {
+		n.sim.t.Fatalf("failed to create the chain object on the node %s", n.NetID)
+	}
+	c.EventRotated().Attach(events.NewClosure(func(from address.Address) {
+		n.startForwarder(conn, from)
+	}))
+	n.mutex.Lock()
+	n.chain = c
+	n.mutex.Unlock()
+}
+
+// stop dismisses the chain object and closes the forwarder as if the node crashed
+func (n *Node) stop() {
+	n.mutex.Lock()
+	c := n.chain
+	fwd := n.forwarder
+	n.chain = nil
+	n.forwarder = nil
+	n.conn = nil
+	n.mutex.Unlock()
+
+	if c != nil {
+		c.Dismiss()
+	}
+	if fwd != nil {
+		fwd.Close()
+	}
+}
+
+// startForwarder starts forwarding the tokens from the previous address of the chain to the current one,
+// if the node controls the previous address. The tokens already in the address are forwarded at once
+func (n *Node) startForwarder(conn *nodeConn, from address.Address) {
+	fwd := chain.NewForwarder(n.sim.chainRecord, from, n.log, n.netProvider, n.dksProvider, conn, n.db)
+	if fwd == nil {
+		return
+	}
+	n.mutex.Lock()
+	if n.conn != conn {
+		n.mutex.Unlock()
+		fwd.Close()
+		return
+	}
+	prev := n.forwarder
+	n.forwarder = fwd
+	n.subscriptions[from] = n.sim.ChainColor
+	n.mutex.Unlock()
+
+	if prev != nil {
+		prev.Close()
+	}
+	fwd.ReceiveMessage(chain.BalancesMsg{
+		Balances: waspconn.OutputsToBalances(n.sim.ledger.getAddressOutputs(from)),
+	})
+}
+
+func (n *Node) currentForwarder() chain.Forwarder {
+	n.mutex.RLock()
+	defer n.mutex.RUnlock()
+	return n.forwarder
+}
+
+// current returns the chain object if it was created with the connection
+func (n *Node) current(conn *nodeConn) chain.Chain {
+	n.mutex.RLock()
+	defer n.mutex.RUnlock()
+	if conn != nil && n.conn != conn {
+		return nil
+	}
+	return n.chain
+}
+
+// subscribe starts the updates of the address of the chain to the node and pushes the backlog.
+// After the committee was rotated, the address of the chain ID is subscribed too, because requests
+// may still be sent to it
+func (n *Node) subscribe(conn *nodeConn) {
+	c := n.current(conn)
+	if c == nil {
+		return
+	}
+	addr := c.Address()
+	chainColor := *c.Color()
+	chainIDAddress := address.Address(*c.ID())
+	n.mutex.Lock()
+	n.subscriptions[addr] = chainColor
+	n.subscriptions[chainIDAddress] = chainColor
+	n.mutex.Unlock()
+
+	if chainIDAddress != addr {
+		n.pushBacklog(c, chainIDAddress, chainColor)
+	}
+	n.pushBacklog(c, addr, chainColor)
+	n.dispatchStateAnchor(c, addr, n.sim.ledger.getAddressOutputs(addr), chainColor)
+}
+
+// pushBacklog dispatches the transactions which colored the tokens in the outputs of the address
+func (n *Node) pushBacklog(c chain.Chain, addr address.Address, chainColor balance.Color) {
+	outs := n.sim.ledger.getAddressOutputs(addr)
+	balancesByColor, _ := waspconn.OutputBalancesByColor(outs)
+	for col, b := range balancesByColor {
+		if col == balance.ColorIOTA || col == balance.ColorNew {
+			continue
+		}
+		if col == chainColor && b == 1 {
+			// the chain token belongs to the backlog only if there are more than 1
+			continue
+		}
+		txid := (valuetransaction.ID)(col)
+		if tx := n.sim.ledger.getConfirmedTransaction(&txid); tx != nil {
+			n.dispatchAddressUpdate(c, addr, tx)
+		}
+	}
+}
+
+// dispatchStateAnchor pushes the transaction which holds the chain token in the address, i.e. the current state
+// of the chain, so the node doesn't have to wait for the periodic balance requests to sync
+func (n *Node) dispatchStateAnchor(c chain.Chain, addr address.Address, outs map[valuetransaction.OutputID][]*balance.Balance, chainColor balance.Color) {
+	for oid, bals := range outs {
+		for _, b := range bals {
+			if b.Color != chainColor {
+				continue
+			}
+			txid := oid.TransactionID()
+			vtx := n.sim.ledger.getConfirmedTransaction(&txid)
+			if vtx == nil {
+				return
+			}
+			tx, err := sctransaction.ParseValueTransaction(vtx)
+			if err != nil {
+				return
+			}
+			if txProp := tx.MustProperties(); !txProp.IsState() || txProp.ChainAddress() != addr {
+				return
+			}
+			c.ReceiveMessage(chain.BalancesMsg{
+				Balances: waspconn.OutputsToBalances(outs),
+			})
+			c.ReceiveMessage(&chain.StateTransactionMsg{
+				Transaction: tx,
+			})
+			return
+		}
+	}
+}
+
+// subscribedAddresses returns the subscribed addresses among the outputs of the transaction
+func (n *Node) subscribedAddresses(tx *valuetransaction.Transaction) []address.Address {
+	n.mutex.RLock()
+	defer n.mutex.RUnlock()
+
+	ret := make([]address.Address, 0)
+	tx.Outputs().ForEach(func(addr address.Address, _ []*balance.Balance) bool {
+		if _, ok := n.subscriptions[addr]; ok {
+			ret = append(ret, addr)
+		}
+		return true
+	})
+	return ret
+}
+
+func (n *Node) transactionConfirmed(tx *valuetransaction.Transaction) {
+	c := n.current(nil)
+	fwd := n.currentForwarder()
+	if c == nil && fwd == nil {
+		return
+	}
+	for _, addr := range n.subscribedAddresses(tx) {
+		if fwd != nil && fwd.From() == addr {
+			fwd.ReceiveMessage(chain.BalancesMsg{
+				Balances: waspconn.OutputsToBalances(n.sim.ledger.getAddressOutputs(addr)),
+			})
+		}
+		if c != nil {
+			n.dispatchAddressUpdate(c, addr, tx)
+		}
+	}
+}
+
+func (n *Node) transactionRejected(tx *valuetransaction.Transaction) {
+	c := n.current(nil)
+	if c == nil {
+		return
+	}
+	if len(n.subscribedAddresses(tx)) == 0 {
+		return
+	}
+	txid := tx.ID()
+	c.ReceiveMessage(&chain.TransactionInclusionLevelMsg{
+		TxId:  &txid,
+		Level: waspconn.TransactionInclusionLevelRejected,
+	})
+}
+
+// dispatchAddressUpdate passes the balances of the address, the state and the requests in the
+// transaction to the chain, the same way the dispatcher of the Wasp node does
+func (n *Node) dispatchAddressUpdate(c chain.Chain, addr address.Address, vtx *valuetransaction.Transaction) {
+	balancesMsg := chain.BalancesMsg{
+		Balances: waspconn.OutputsToBalances(n.sim.ledger.getAddressOutputs(addr)),
+	}
+	tx, err := sctransaction.ParseValueTransaction(vtx)
+	if err != nil {
+		// not a SC transaction. Only the balances of the address are updated
+		if c.Address() == addr {
+			c.ReceiveMessage(balancesMsg)
+		}
+		return
+	}
+	if c.Address() != addr {
+		// the requests sent to the address of the chain ID after the committee was rotated
+		dispatchRequests(c, tx, nil)
+		return
+	}
+	c.ReceiveMessage(balancesMsg)
+	txProp := tx.MustProperties()
+	if txProp.IsState() && txProp.ChainAddress() == addr {
+		c.ReceiveMessage(&chain.StateTransactionMsg{
+			Transaction: tx,
+		})
+	}
+	if addr != address.Address(*c.ID()) {
+		if err := tx.ValidateRequestsToAddress(c.ID(), addr); err != nil {
+			n.log.Warnf("requests of tx %s ignored: %v", tx.ID().String(), err)
+			return
+		}
+	}
+	freeTokens := txProp.FreeTokensForAddress(addr)
+	if freeTokens != nil && freeTokens.Len() == 0 {
+		freeTokens = nil
+	}
+	dispatchRequests(c, tx, freeTokens)
+}
+
+// dispatchRequests sends the requests of the transaction to the chain
+func dispatchRequests(c chain.Chain, tx *sctransaction.Transaction, freeTokens coretypes.ColoredBalances) {
+	for i, reqBlk := range tx.Requests() {
+		if reqBlk.Target().ChainID() == *c.ID() {
+			c.ReceiveMessage(&chain.RequestMsg{
+				Transaction: tx,
+				Index:       (uint16)(i),
+				FreeTokens:  freeTokens,
+			})
+			freeTokens = nil
+		}
+	}
+}
+
+// nodeConn is the connection of one chain object of the node to the simulated ledger
+type nodeConn struct {
+	node *Node
+}
+
+func (conn *nodeConn) PostTransaction(tx *valuetransaction.Transaction, _ *address.Address, _ uint16) error {
+	if conn.node.current(conn) == nil {
+		return nil
+	}
+	return conn.node.sim.ledger.PostTransaction(tx)
+}
+
+func (conn *nodeConn) RequestConfirmedTransaction(txid *valuetransaction.ID) error {
+	c := conn.node.current(conn)
+	if c == nil {
+		return nil
+	}
+	vtx := conn.node.sim.ledger.getConfirmedTransaction(txid)
+	if vtx == nil {
+		return nil
+	}
+	tx, err := sctransaction.ParseValueTransaction(vtx)
+	if err != nil {
+		return nil
+	}
+	txProp := tx.MustProperties()
+	if !txProp.IsState() || *txProp.MustStateColor() != *c.Color() {
+		return nil
+	}
+	c.ReceiveMessage(&chain.StateTransactionMsg{
+		Transaction: tx,
+	})
+	return nil
+}
+
+func (conn *nodeConn) RequestInclusionLevel(txid *valuetransaction.ID, _ *address.Address) error {
+	c := conn.node.current(conn)
+	if c == nil {
+		return nil
+	}
+	var level byte
+	switch {
+	case conn.node.sim.ledger.isConfirmed(txid):
+		level = waspconn.TransactionInclusionLevelConfirmed
+	case conn.node.sim.ledger.isRejected(txid):
+		level = waspconn.TransactionInclusionLevelRejected
+	default:
+		return nil
+	}
+	c.ReceiveMessage(&chain.TransactionInclusionLevelMsg{
+		TxId:  txid,
+		Level: level,
+	})
+	return nil
+}
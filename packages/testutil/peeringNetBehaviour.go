@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/iotaledger/hive.go/logger"
+	"github.com/iotaledger/wasp/packages/peering"
 )
 
 // An interface for all the network behaviors.
@@ -138,3 +139,79 @@ func (n *peeringNetUnreliable) sendDelayed(recv *peeringMsg, outCh chan *peering
 	)
 	outCh <- recv
 }
+
+// PeeringMsgInterceptor controls the delivery of messages in the network with the
+// intercepted behavior. It is used to inject faults: crashed nodes, partitions, byzantine peers.
+type PeeringMsgInterceptor interface {
+	// Intercept returns the message to be delivered from the node `from` to the node `to`:
+	// the message itself, a modified copy of it or nil to drop the message.
+	// The message is shared by all its recipients, so it must not be modified in place.
+	Intercept(from, to string, msg *peering.PeerMessage) *peering.PeerMessage
+	// IsReachable tells whether the node `to` is seen alive by the node `from`.
+	IsReachable(from, to string) bool
+}
+
+// peeringNetReachability is implemented by the behaviors which decide on the liveness of the peers.
+type peeringNetReachability interface {
+	isReachable(from, to string) bool
+}
+
+// peeringNetIntercepted passes each message through the interceptor before the underlying behavior.
+type peeringNetIntercepted struct {
+	inner       PeeringNetBehavior
+	interceptor PeeringMsgInterceptor
+	closeChs    []chan bool
+}
+
+// NewPeeringNetIntercepted constructs the PeeringNetBehavior, which delivers the messages
+// accepted by the interceptor with the inner behavior.
+func NewPeeringNetIntercepted(inner PeeringNetBehavior, interceptor PeeringMsgInterceptor) PeeringNetBehavior {
+	return &peeringNetIntercepted{
+		inner:       inner,
+		interceptor: interceptor,
+		closeChs:    make([]chan bool, 0),
+	}
+}
+
+// AddLink implements PeeringNetBehavior.
+func (n *peeringNetIntercepted) AddLink(inCh, outCh chan *peeringMsg, dstNetID string) {
+	closeCh := make(chan bool)
+	n.closeChs = append(n.closeChs, closeCh)
+	midCh := make(chan *peeringMsg, cap(inCh))
+	n.inner.AddLink(midCh, outCh, dstNetID)
+	go n.recvLoop(inCh, midCh, closeCh, dstNetID)
+}
+
+// Close implements PeeringNetBehavior.
+func (n *peeringNetIntercepted) Close() {
+	for i := range n.closeChs {
+		close(n.closeChs[i])
+	}
+	n.inner.Close()
+}
+
+func (n *peeringNetIntercepted) isReachable(from, to string) bool {
+	return n.interceptor.IsReachable(from, to)
+}
+
+func (n *peeringNetIntercepted) recvLoop(inCh, outCh chan *peeringMsg, closeCh chan bool, dstNetID string) {
+	for {
+		select {
+		case <-closeCh:
+			return
+		case recv, ok := <-inCh:
+			if !ok {
+				return
+			}
+			msg := n.interceptor.Intercept(recv.from.netID, dstNetID, &recv.msg)
+			if msg == nil {
+				continue // Drop the message.
+			}
+			select {
+			case <-closeCh:
+				return
+			case outCh <- &peeringMsg{from: recv.from, msg: *msg}:
+			}
+		}
+	}
+}
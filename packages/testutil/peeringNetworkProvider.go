@@ -5,6 +5,7 @@ package testutil
 
 import (
 	"errors"
+	"sync"
 
 	"github.com/iotaledger/hive.go/logger"
 	"github.com/iotaledger/wasp/packages/coretypes"
@@ -74,29 +75,21 @@ func (p *PeeringNetwork) NetworkProviders() []peering.NetworkProvider {
 	return copy
 }
 
-func (p *PeeringNetwork) nodeByNetID(nodeNetID string) *peeringNode {
-	for i := range p.nodes {
-		if p.nodes[i].netID == nodeNetID {
-			return p.nodes[i]
-		}
-	}
-	return nil
-}
-
 //
 // peeringNode stands for a mock of a node in a fake network.
 // It does NOT implement the peering.PeerSender, because the source
 // node should be known for the sender.
 //
 type peeringNode struct {
-	netID   string
-	pubKey  kyber.Point
-	secKey  kyber.Scalar
-	sendCh  chan *peeringMsg
-	recvCh  chan *peeringMsg
-	recvCbs []*peeringCb
-	network *PeeringNetwork
-	log     *logger.Logger
+	netID    string
+	pubKey   kyber.Point
+	secKey   kyber.Scalar
+	sendCh   chan *peeringMsg
+	recvCh   chan *peeringMsg
+	recvCbs  []*peeringCb
+	recvLock sync.RWMutex
+	network  *PeeringNetwork
+	log      *logger.Logger
 }
 type peeringMsg struct {
 	from *peeringNode
@@ -112,7 +105,7 @@ func newPeeringNode(netID string, pubKey kyber.Point, secKey kyber.Scalar, netwo
 	sendCh := make(chan *peeringMsg, network.bufSize)
 	recvCh := make(chan *peeringMsg, network.bufSize)
 	recvCbs := make([]*peeringCb, 0)
-	node := peeringNode{
+	node := &peeringNode{
 		netID:   netID,
 		pubKey:  pubKey,
 		secKey:  secKey,
@@ -131,7 +124,10 @@ func newPeeringNode(netID string, pubKey kyber.Point, secKey kyber.Scalar, netwo
 				pm.msg.MsgType, pm.from.netID, pm.msg.ChainID,
 			)
 			msgChainID := pm.msg.ChainID.String()
-			for _, cb := range node.recvCbs {
+			node.recvLock.RLock()
+			recvCbs := node.recvCbs
+			node.recvLock.RUnlock()
+			for _, cb := range recvCbs {
 				if cb.chainID == nil || cb.chainID.String() == msgChainID {
 					cb.callback(&peering.RecvEvent{
 						From: cb.destNP.senderByNetID(pm.from.netID),
@@ -141,7 +137,7 @@ func newPeeringNode(netID string, pubKey kyber.Point, secKey kyber.Scalar, netwo
 			}
 		}
 	}()
-	return &node
+	return node
 }
 
 func (n *peeringNode) sendMsg(from *peeringNode, msg *peering.PeerMessage) {
@@ -188,11 +184,11 @@ func (p *peeringNetworkProvider) Self() peering.PeerSender {
 func (p *peeringNetworkProvider) Group(peerAddrs []string) (peering.GroupProvider, error) {
 	peers := make([]peering.PeerSender, len(peerAddrs))
 	for i := range peerAddrs {
-		n := p.network.nodeByNetID(peerAddrs[i])
-		if n == nil {
+		s := p.senderByNetID(peerAddrs[i])
+		if s == nil {
 			return nil, errors.New("unknown_node_location")
 		}
-		peers[i] = p.senders[i]
+		peers[i] = s
 	}
 	return group.NewPeeringGroupProvider(p, peers, p.network.log), nil
 }
@@ -202,17 +198,29 @@ func (p *peeringNetworkProvider) Attach(
 	chainID *coretypes.ChainID,
 	callback func(recv *peering.RecvEvent),
 ) interface{} {
-	p.self.recvCbs = append(p.self.recvCbs, &peeringCb{
+	cb := &peeringCb{
 		callback: callback,
 		destNP:   p,
 		chainID:  chainID,
-	})
-	return nil // We don't care on the attachIDs for now.
+	}
+	p.self.recvLock.Lock()
+	defer p.self.recvLock.Unlock()
+	// the slice is copied, so that the receive loop can iterate the previous one without the lock
+	p.self.recvCbs = append(append([]*peeringCb{}, p.self.recvCbs...), cb)
+	return cb
 }
 
 // Detach implements peering.NetworkProvider.
 func (p *peeringNetworkProvider) Detach(attachID interface{}) {
-	// Detach is not important in tests.
+	p.self.recvLock.Lock()
+	defer p.self.recvLock.Unlock()
+	recvCbs := make([]*peeringCb, 0, len(p.self.recvCbs))
+	for _, cb := range p.self.recvCbs {
+		if cb != attachID {
+			recvCbs = append(recvCbs, cb)
+		}
+	}
+	p.self.recvCbs = recvCbs
 }
 
 // PeerByNetID implements peering.NetworkProvider.
@@ -283,8 +291,12 @@ func (p *peeringSender) SendMsg(msg *peering.PeerMessage) {
 }
 
 // IsAlive implements peering.PeerSender.
+// The peer is always alive unless the network behavior tells otherwise.
 func (p *peeringSender) IsAlive() bool {
-	return true // Not needed in tests.
+	if r, ok := p.netProvider.network.behavior.(peeringNetReachability); ok {
+		return r.isReachable(p.netProvider.self.netID, p.node.netID)
+	}
+	return true
 }
 
 // IsInbound implements peering.PeerStatusProvider.
@@ -0,0 +1,65 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package testutil
+
+import (
+	"sync"
+
+	"github.com/iotaledger/wasp/packages/peering"
+	"go.dedis.ch/kyber/v3"
+)
+
+// TrustedNetworkManager stands for a mock for peering.TrustedNetworkManager.
+type TrustedNetworkManager struct {
+	peers map[string]*peering.TrustedPeer // By PubKey.String()
+	lock  *sync.RWMutex
+}
+
+// NewTrustedNetworkManager creates new mocked trusted network manager, trusting the specified peers.
+func NewTrustedNetworkManager(peerNetIDs []string, peerPubs []kyber.Point) *TrustedNetworkManager {
+	tnm := &TrustedNetworkManager{
+		peers: make(map[string]*peering.TrustedPeer),
+		lock:  &sync.RWMutex{},
+	}
+	for i := range peerNetIDs {
+		_, _ = tnm.TrustPeer(peerPubs[i], peerNetIDs[i])
+	}
+	return tnm
+}
+
+// IsTrustedPeer implements peering.TrustedNetworkManager.
+func (tnm *TrustedNetworkManager) IsTrustedPeer(pubKey kyber.Point, netID string) error {
+	tnm.lock.RLock()
+	defer tnm.lock.RUnlock()
+	return peering.CheckTrustedPeer(tnm.peers[pubKey.String()], pubKey, netID)
+}
+
+// TrustPeer implements peering.TrustedNetworkManager.
+func (tnm *TrustedNetworkManager) TrustPeer(pubKey kyber.Point, netID string) (*peering.TrustedPeer, error) {
+	tnm.lock.Lock()
+	defer tnm.lock.Unlock()
+	tp := &peering.TrustedPeer{PubKey: pubKey, NetID: netID}
+	tnm.peers[pubKey.String()] = tp
+	return tp, nil
+}
+
+// DistrustPeer implements peering.TrustedNetworkManager.
+func (tnm *TrustedNetworkManager) DistrustPeer(pubKey kyber.Point) (*peering.TrustedPeer, error) {
+	tnm.lock.Lock()
+	defer tnm.lock.Unlock()
+	tp := tnm.peers[pubKey.String()]
+	delete(tnm.peers, pubKey.String())
+	return tp, nil
+}
+
+// TrustedPeers implements peering.TrustedNetworkManager.
+func (tnm *TrustedNetworkManager) TrustedPeers() ([]*peering.TrustedPeer, error) {
+	tnm.lock.RLock()
+	defer tnm.lock.RUnlock()
+	ret := make([]*peering.TrustedPeer, 0, len(tnm.peers))
+	for _, tp := range tnm.peers {
+		ret = append(ret, tp)
+	}
+	return ret, nil
+}
@@ -16,8 +16,10 @@ func AddAuthentication(e *echo.Echo, config map[string]string) {
 		return
 	}
 	switch scheme {
-	case "basic":
+	case SchemeBasic:
 		addBasicAuth(e, config["username"], config["password"])
+	case SchemeAPIKey, SchemeJWT:
+		// token schemes protect the admin endpoints only, see TokenAuth
 	default:
 		panic(fmt.Sprintf("Unknown auth scheme %s", scheme))
 	}
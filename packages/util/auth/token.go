@@ -0,0 +1,207 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// authentication schemes of the web API. SchemeBasic protects the whole API,
+// token schemes protect the admin endpoints only
+const (
+	SchemeBasic  = "basic"
+	SchemeAPIKey = "apikey"
+	SchemeJWT    = "jwt"
+)
+
+// MaxJWTLifetime is the longest lifetime of the JWT. Tokens without expiration or with longer lifetime are rejected
+const MaxJWTLifetime = 24 * time.Hour
+
+// roles of the callers of the admin API. The operator is allowed everything the read-only role is allowed
+const (
+	RoleReadOnly = "readonly"
+	RoleOperator = "operator"
+)
+
+// Identity is the authenticated caller
+type Identity struct {
+	Subject string
+	Role    string
+}
+
+// HasRole checks if the role of the caller is sufficient for the required role
+func (id *Identity) HasRole(required string) bool {
+	switch required {
+	case RoleReadOnly:
+		return id.Role == RoleReadOnly || id.Role == RoleOperator
+	case RoleOperator:
+		return id.Role == RoleOperator
+	}
+	return false
+}
+
+// TokenAuth authenticates the callers by the bearer token in the Authorization header.
+// The token is either one of the configured API keys or the JWT signed with the configured secret.
+//
+// Configuration (webapi.auth):
+// - scheme: "apikey". Keys "readonly" and "operator" are comma-separated lists of API keys of the role
+// - scheme: "jwt". Key "secret" is the HMAC secret. The token must be signed with HS256 and have the "role"
+//   and "exp" claims, expiring in at most MaxJWTLifetime; the "sub" claim is used in the audit log
+type TokenAuth struct {
+	apiKeys   map[string]string // API key -> role
+	jwtSecret []byte
+}
+
+// NewTokenAuth returns nil if the config does not define a token scheme
+func NewTokenAuth(config map[string]string) (*TokenAuth, error) {
+	switch config["scheme"] {
+	case SchemeAPIKey:
+		ret := &TokenAuth{apiKeys: make(map[string]string)}
+		for _, role := range []string{RoleReadOnly, RoleOperator} {
+			for _, key := range strings.Split(config[role], ",") {
+				if key = strings.TrimSpace(key); key != "" {
+					ret.apiKeys[key] = role
+				}
+			}
+		}
+		if len(ret.apiKeys) == 0 {
+			return nil, fmt.Errorf("auth scheme %s: no API keys configured", SchemeAPIKey)
+		}
+		return ret, nil
+	case SchemeJWT:
+		secret := config["secret"]
+		if secret == "" {
+			return nil, fmt.Errorf("auth scheme %s: no secret configured", SchemeJWT)
+		}
+		return &TokenAuth{jwtSecret: []byte(secret)}, nil
+	}
+	return nil, nil
+}
+
+// Authenticate returns the identity of the caller or error if the request has no valid token
+func (a *TokenAuth) Authenticate(r *http.Request) (*Identity, error) {
+	header := r.Header.Get("Authorization")
+	if !strings.HasPrefix(header, "Bearer ") {
+		return nil, fmt.Errorf("missing bearer token")
+	}
+	token := strings.TrimPrefix(header, "Bearer ")
+	if a.jwtSecret != nil {
+		return a.authenticateJWT(token)
+	}
+	return a.authenticateAPIKey(token)
+}
+
+func (a *TokenAuth) authenticateAPIKey(token string) (*Identity, error) {
+	for key, role := range a.apiKeys {
+		if subtle.ConstantTimeCompare([]byte(key), []byte(token)) == 1 {
+			// the key itself must not appear in the logs
+			h := sha256.Sum256([]byte(key))
+			return &Identity{Subject: "apikey-" + hex.EncodeToString(h[:4]), Role: role}, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid API key")
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ,omitempty"`
+}
+
+type jwtClaims struct {
+	Subject   string `json:"sub,omitempty"`
+	Role      string `json:"role"`
+	IssuedAt  int64  `json:"iat,omitempty"`
+	ExpiresAt int64  `json:"exp"`
+	NotBefore int64  `json:"nbf,omitempty"`
+}
+
+// authenticateJWT verifies the HS256 signature and the claims of the token
+func (a *TokenAuth) authenticateJWT(token string) (*Identity, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("malformed token")
+	}
+	header := &jwtHeader{}
+	if err := decodeJWTPart(parts[0], header); err != nil {
+		return nil, fmt.Errorf("malformed token header: %v", err)
+	}
+	// the algorithm is fixed, the header can't choose another one
+	if header.Alg != "HS256" {
+		return nil, fmt.Errorf("unexpected signing method %s", header.Alg)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("malformed token signature: %v", err)
+	}
+	if !hmac.Equal(signature, signHS256(a.jwtSecret, parts[0]+"."+parts[1])) {
+		return nil, fmt.Errorf("invalid token signature")
+	}
+	claims := &jwtClaims{}
+	if err := decodeJWTPart(parts[1], claims); err != nil {
+		return nil, fmt.Errorf("malformed token claims: %v", err)
+	}
+	now := time.Now().Unix()
+	switch {
+	case claims.ExpiresAt == 0:
+		return nil, fmt.Errorf("token has no exp claim")
+	case claims.ExpiresAt <= now:
+		return nil, fmt.Errorf("token is expired")
+	case claims.ExpiresAt-now > int64(MaxJWTLifetime/time.Second):
+		return nil, fmt.Errorf("token lifetime exceeds %v", MaxJWTLifetime)
+	case claims.NotBefore > now:
+		return nil, fmt.Errorf("token is not valid yet")
+	}
+	if claims.Role != RoleReadOnly && claims.Role != RoleOperator {
+		return nil, fmt.Errorf("invalid role claim: %s", claims.Role)
+	}
+	return &Identity{Subject: claims.Subject, Role: claims.Role}, nil
+}
+
+// NewJWT issues the token for the role, signed with the secret and valid for the lifetime.
+// The lifetime can't exceed MaxJWTLifetime. Used by tools and tests
+func NewJWT(secret string, subject string, role string, lifetime time.Duration) (string, error) {
+	if lifetime <= 0 || lifetime > MaxJWTLifetime {
+		return "", fmt.Errorf("token lifetime must be positive and at most %v", MaxJWTLifetime)
+	}
+	now := time.Now()
+	return signJWT([]byte(secret), &jwtClaims{
+		Subject:   subject,
+		Role:      role,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(lifetime).Unix(),
+	})
+}
+
+func signJWT(secret []byte, claims interface{}) (string, error) {
+	header, err := json.Marshal(&jwtHeader{Alg: "HS256", Typ: "JWT"})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signHS256(secret, signingInput)), nil
+}
+
+func signHS256(secret []byte, signingInput string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signingInput))
+	return mac.Sum(nil)
+}
+
+func decodeJWTPart(part string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,114 @@
+package auth
+
+import (
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func requestWithToken(token string) *http.Request {
+	req, _ := http.NewRequest(http.MethodGet, "/adm/chainrecords", nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return req
+}
+
+func TestNoTokenScheme(t *testing.T) {
+	a, err := NewTokenAuth(nil)
+	require.NoError(t, err)
+	require.Nil(t, a)
+
+	a, err = NewTokenAuth(map[string]string{"scheme": SchemeBasic})
+	require.NoError(t, err)
+	require.Nil(t, a)
+}
+
+func TestAPIKey(t *testing.T) {
+	_, err := NewTokenAuth(map[string]string{"scheme": SchemeAPIKey})
+	require.Error(t, err)
+
+	a, err := NewTokenAuth(map[string]string{
+		"scheme":     SchemeAPIKey,
+		RoleReadOnly: "key1, key2",
+		RoleOperator: "key3",
+	})
+	require.NoError(t, err)
+
+	id, err := a.Authenticate(requestWithToken("key2"))
+	require.NoError(t, err)
+	require.True(t, id.HasRole(RoleReadOnly))
+	require.False(t, id.HasRole(RoleOperator))
+	require.NotContains(t, id.Subject, "key2")
+
+	id, err = a.Authenticate(requestWithToken("key3"))
+	require.NoError(t, err)
+	require.True(t, id.HasRole(RoleReadOnly))
+	require.True(t, id.HasRole(RoleOperator))
+
+	_, err = a.Authenticate(requestWithToken("key4"))
+	require.Error(t, err)
+	_, err = a.Authenticate(requestWithToken(""))
+	require.Error(t, err)
+}
+
+func TestJWT(t *testing.T) {
+	a, err := NewTokenAuth(map[string]string{"scheme": SchemeJWT, "secret": "secret"})
+	require.NoError(t, err)
+
+	token, err := NewJWT("secret", "alice", RoleOperator, time.Hour)
+	require.NoError(t, err)
+	id, err := a.Authenticate(requestWithToken(token))
+	require.NoError(t, err)
+	require.EqualValues(t, "alice", id.Subject)
+	require.True(t, id.HasRole(RoleOperator))
+
+	token, err = NewJWT("other secret", "alice", RoleOperator, time.Hour)
+	require.NoError(t, err)
+	_, err = a.Authenticate(requestWithToken(token))
+	require.Error(t, err)
+
+	token, err = NewJWT("secret", "alice", "admin", time.Hour)
+	require.NoError(t, err)
+	_, err = a.Authenticate(requestWithToken(token))
+	require.Error(t, err)
+
+	_, err = NewJWT("secret", "alice", RoleOperator, 0)
+	require.Error(t, err)
+	_, err = NewJWT("secret", "alice", RoleOperator, MaxJWTLifetime+time.Second)
+	require.Error(t, err)
+}
+
+func TestJWTClaims(t *testing.T) {
+	a, err := NewTokenAuth(map[string]string{"scheme": SchemeJWT, "secret": "secret"})
+	require.NoError(t, err)
+	now := time.Now().Unix()
+
+	for _, claims := range []*jwtClaims{
+		// expired
+		{Role: RoleOperator, ExpiresAt: now - 3600},
+		// no expiration
+		{Role: RoleOperator},
+		// lifetime too long
+		{Role: RoleOperator, ExpiresAt: now + int64(2*MaxJWTLifetime/time.Second)},
+		// not valid yet
+		{Role: RoleOperator, ExpiresAt: now + 3600, NotBefore: now + 600},
+	} {
+		token, err := signJWT([]byte("secret"), claims)
+		require.NoError(t, err)
+		_, err = a.Authenticate(requestWithToken(token))
+		require.Error(t, err, "%+v", claims)
+	}
+
+	// unsigned token
+	token, err := NewJWT("secret", "alice", RoleOperator, time.Hour)
+	require.NoError(t, err)
+	parts := strings.Split(token, ".")
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`))
+	_, err = a.Authenticate(requestWithToken(header + "." + parts[1] + "."))
+	require.Error(t, err)
+}
@@ -20,7 +20,7 @@ func WriteByte(w io.Writer, val byte) error {
 
 func ReadByte(r io.Reader) (byte, error) {
 	var b [1]byte
-	_, err := r.Read(b[:])
+	_, err := io.ReadFull(r, b[:])
 	if err != nil {
 		return 0, err
 	}
@@ -97,7 +97,7 @@ func WriteUint16(w io.Writer, val uint16) error {
 
 func ReadUint16(r io.Reader, pval *uint16) error {
 	var tmp2 [2]byte
-	_, err := r.Read(tmp2[:])
+	_, err := io.ReadFull(r, tmp2[:])
 	if err != nil {
 		return err
 	}
@@ -112,7 +112,7 @@ func WriteUint32(w io.Writer, val uint32) error {
 
 func ReadUint32(r io.Reader, pval *uint32) error {
 	var tmp4 [4]byte
-	_, err := r.Read(tmp4[:])
+	_, err := io.ReadFull(r, tmp4[:])
 	if err != nil {
 		return err
 	}
@@ -127,7 +127,7 @@ func WriteUint64(w io.Writer, val uint64) error {
 
 func ReadUint64(r io.Reader, pval *uint64) error {
 	var tmp8 [8]byte
-	_, err := r.Read(tmp8[:])
+	_, err := io.ReadFull(r, tmp8[:])
 	if err != nil {
 		return err
 	}
@@ -142,7 +142,7 @@ func WriteInt64(w io.Writer, val int64) error {
 
 func ReadInt64(r io.Reader, pval *int64) error {
 	var tmp8 [8]byte
-	_, err := r.Read(tmp8[:])
+	_, err := io.ReadFull(r, tmp8[:])
 	if err != nil {
 		return err
 	}
@@ -175,7 +175,7 @@ func ReadBytes16(r io.Reader) ([]byte, error) {
 	}
 	if length != 0 {
 		ret := make([]byte, length)
-		_, err = r.Read(ret)
+		_, err = io.ReadFull(r, ret)
 		if err != nil {
 			return nil, err
 		}
@@ -200,7 +200,7 @@ func ReadBytes32(r io.Reader) ([]byte, error) {
 		return nil, err
 	}
 	ret := make([]byte, length)
-	_, err = r.Read(ret)
+	_, err = io.ReadFull(r, ret)
 	if err != nil {
 		return nil, err
 	}
@@ -218,7 +218,7 @@ func WriteBoolByte(w io.Writer, cond bool) error {
 
 func ReadBoolByte(r io.Reader, cond *bool) error {
 	var b [1]byte
-	_, err := r.Read(b[:])
+	_, err := io.ReadFull(r, b[:])
 	if err != nil {
 		return err
 	}
@@ -286,7 +286,7 @@ func ReadStrings16(r io.Reader) ([]string, error) {
 }
 
 func ReadTransactionId(r io.Reader, txid *transaction.ID) error {
-	n, err := r.Read(txid[:])
+	n, err := io.ReadFull(r, txid[:])
 	if err != nil {
 		return err
 	}
@@ -297,7 +297,7 @@ func ReadTransactionId(r io.Reader, txid *transaction.ID) error {
 }
 
 func ReadColor(r io.Reader, color *balance.Color) error {
-	n, err := r.Read(color[:])
+	n, err := io.ReadFull(r, color[:])
 	if err != nil {
 		return err
 	}
@@ -308,14 +308,8 @@ func ReadColor(r io.Reader, color *balance.Color) error {
 }
 
 func ReadHashValue(r io.Reader, h *hashing.HashValue) error {
-	n, err := r.Read(h[:])
-	if err != nil {
-		return err
-	}
-	if n != hashing.HashSize {
-		return errors.New("error while reading hash")
-	}
-	return nil
+	_, err := io.ReadFull(r, h[:])
+	return err
 }
 
 // WriteMarshaled supports kyber.Point, kyber.Scalar and similar.
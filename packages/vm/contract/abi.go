@@ -0,0 +1,239 @@
+package contract
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/util"
+	"github.com/mr-tron/base58"
+)
+
+// types of parameters and results in the ABI. Each type corresponds to the encoding in 'codec'
+const (
+	TypeBytes      = "bytes"
+	TypeString     = "string"
+	TypeInt64      = "int64"
+	TypeHname      = "hname"
+	TypeAddress    = "address"
+	TypeAgentID    = "agentid"
+	TypeChainID    = "chainid"
+	TypeContractID = "contractid"
+	TypeColor      = "color"
+	TypeHash       = "hash"
+	TypeRequestID  = "requestid"
+)
+
+// ABI describes the interface of the contract to the clients: functions, their parameters and results
+type ABI struct {
+	Functions []FunctionABI
+}
+
+// FunctionABI describes the entry point of the contract.
+// Params and Results lists only parameters and results known to the contract developer, other keys may be present too
+type FunctionABI struct {
+	Name    string
+	View    bool
+	Params  []FieldABI
+	Results []FieldABI
+}
+
+// FieldABI is the named and typed parameter or result of the function. Values of type 'bytes' are base58 in the human readable form
+type FieldABI struct {
+	Name string
+	Type string
+}
+
+func Field(name string, typ string) FieldABI {
+	return FieldABI{Name: name, Type: typ}
+}
+
+// WithParams annotates the function with its parameters
+func (f ContractFunctionInterface) WithParams(params ...FieldABI) ContractFunctionInterface {
+	f.Params = params
+	return f
+}
+
+// WithResults annotates the function with its results
+func (f ContractFunctionInterface) WithResults(results ...FieldABI) ContractFunctionInterface {
+	f.Results = results
+	return f
+}
+
+// ABI returns the ABI of the contract, functions sorted by name
+func (i *ContractInterface) ABI() *ABI {
+	ret := &ABI{Functions: make([]FunctionABI, 0, len(i.Functions))}
+	for _, f := range i.Functions {
+		ret.Functions = append(ret.Functions, FunctionABI{
+			Name:    f.Name,
+			View:    f.IsView(),
+			Params:  f.Params,
+			Results: f.Results,
+		})
+	}
+	sort.Slice(ret.Functions, func(i, j int) bool {
+		return ret.Functions[i].Name < ret.Functions[j].Name
+	})
+	return ret
+}
+
+// GetFunction returns the function by name
+func (a *ABI) GetFunction(name string) (*FunctionABI, bool) {
+	for i := range a.Functions {
+		if a.Functions[i].Name == name {
+			return &a.Functions[i], true
+		}
+	}
+	return nil, false
+}
+
+// GetParam returns the parameter of the function by name
+func (f *FunctionABI) GetParam(name string) (*FieldABI, bool) {
+	return findField(f.Params, name)
+}
+
+// GetResult returns the result of the function by name
+func (f *FunctionABI) GetResult(name string) (*FieldABI, bool) {
+	return findField(f.Results, name)
+}
+
+func findField(fields []FieldABI, name string) (*FieldABI, bool) {
+	for i := range fields {
+		if fields[i].Name == name {
+			return &fields[i], true
+		}
+	}
+	return nil, false
+}
+
+// Bytes encodes the ABI in JSON. The same form is stored in the contract record and in the 'abi' field of the blob
+func (a *ABI) Bytes() []byte {
+	ret, err := json.Marshal(a)
+	if err != nil {
+		panic(err)
+	}
+	return ret
+}
+
+func ABIFromBytes(data []byte) (*ABI, error) {
+	ret := &ABI{}
+	if err := json.Unmarshal(data, ret); err != nil {
+		return nil, fmt.Errorf("wrong ABI: %v", err)
+	}
+	return ret, nil
+}
+
+// EncodeFromString parses the human readable value and encodes it according to the type of the field
+func (f *FieldABI) EncodeFromString(s string) ([]byte, error) {
+	switch f.Type {
+	case TypeBytes:
+		return base58.Decode(s)
+	case TypeString:
+		return codec.EncodeString(s), nil
+	case TypeInt64:
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		return codec.EncodeInt64(n), nil
+	case TypeHname:
+		h, err := coretypes.HnameFromString(s)
+		if err != nil {
+			return nil, err
+		}
+		return codec.EncodeHname(h), nil
+	case TypeAddress:
+		addr, err := address.FromBase58(s)
+		if err != nil {
+			return nil, err
+		}
+		return codec.EncodeAddress(addr), nil
+	case TypeAgentID:
+		agentID, err := coretypes.NewAgentIDFromString(s)
+		if err != nil {
+			return nil, err
+		}
+		return codec.EncodeAgentID(agentID), nil
+	case TypeChainID:
+		chainID, err := coretypes.NewChainIDFromBase58(s)
+		if err != nil {
+			return nil, err
+		}
+		return codec.EncodeChainID(chainID), nil
+	case TypeContractID:
+		contractID, err := coretypes.NewContractIDFromString(s)
+		if err != nil {
+			return nil, err
+		}
+		return codec.EncodeContractID(contractID), nil
+	case TypeColor:
+		col, err := util.ColorFromString(s)
+		if err != nil {
+			return nil, err
+		}
+		return codec.EncodeColor(col), nil
+	case TypeHash:
+		h, err := hashing.HashValueFromBase58(s)
+		if err != nil {
+			return nil, err
+		}
+		return codec.EncodeHashValue(&h), nil
+	case TypeRequestID:
+		reqID, err := coretypes.NewRequestIDFromBase58(s)
+		if err != nil {
+			return nil, err
+		}
+		return codec.EncodeRequestID(reqID), nil
+	}
+	return nil, fmt.Errorf("unknown type '%s' of '%s'", f.Type, f.Name)
+}
+
+// DecodeToString decodes the value according to the type of the field and returns it in the human readable form
+func (f *FieldABI) DecodeToString(b []byte) (string, error) {
+	if b == nil {
+		return "", nil
+	}
+	var ret fmt.Stringer
+	var err error
+	switch f.Type {
+	case TypeBytes:
+		return base58.Encode(b), nil
+	case TypeString:
+		s, _, err := codec.DecodeString(b)
+		return s, err
+	case TypeInt64:
+		n, _, err := codec.DecodeInt64(b)
+		return strconv.FormatInt(n, 10), err
+	case TypeHname:
+		ret, _, err = codec.DecodeHname(b)
+	case TypeAddress:
+		ret, _, err = codec.DecodeAddress(b)
+	case TypeAgentID:
+		ret, _, err = codec.DecodeAgentID(b)
+	case TypeChainID:
+		ret, _, err = codec.DecodeChainID(b)
+	case TypeContractID:
+		ret, _, err = codec.DecodeContractID(b)
+	case TypeColor:
+		ret, _, err = codec.DecodeColor(b)
+	case TypeHash:
+		ret, _, err = codec.DecodeHashValue(b)
+	case TypeRequestID:
+		var reqID coretypes.RequestID
+		if reqID, _, err = codec.DecodeRequestID(b); err != nil {
+			return "", err
+		}
+		return reqID.Base58(), nil
+	default:
+		return "", fmt.Errorf("unknown type '%s' of '%s'", f.Type, f.Name)
+	}
+	if err != nil {
+		return "", err
+	}
+	return ret.String(), nil
+}
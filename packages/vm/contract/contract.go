@@ -20,6 +20,9 @@ type ContractFunctionInterface struct {
 	Name        string
 	Handler     Handler
 	ViewHandler ViewHandler
+	// optional description of params and results for the ABI
+	Params  []FieldABI
+	Results []FieldABI
 }
 
 func Funcs(init Handler, fns []ContractFunctionInterface) map[coretypes.Hname]ContractFunctionInterface {
@@ -302,3 +302,24 @@ func TestCreditDebit7(t *testing.T) {
 	total = checkLedger(t, state, "cp1")
 	require.True(t, transfer.Equal(total))
 }
+
+func TestAllowance(t *testing.T) {
+	curTest = "TestAllowance"
+	state := dict.New()
+
+	owner := coretypes.NewRandomAgentID()
+	spender := coretypes.NewRandomAgentID()
+	require.EqualValues(t, 0, GetAllowance(state, owner, spender, color))
+
+	setAllowance(state, owner, spender, color, 10)
+	require.EqualValues(t, 10, GetAllowance(state, owner, spender, color))
+	require.EqualValues(t, 0, GetAllowance(state, spender, owner, color))
+	require.EqualValues(t, 0, GetAllowance(state, owner, spender, balance.ColorIOTA))
+	// allowances are not part of the ledger
+	total := checkLedger(t, state, "cp1")
+	require.EqualValues(t, 0, total.Len())
+
+	setAllowance(state, owner, spender, color, 0)
+	require.EqualValues(t, 0, GetAllowance(state, owner, spender, color))
+	require.EqualValues(t, 0, len(state))
+}
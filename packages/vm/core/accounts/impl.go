@@ -2,12 +2,11 @@ package accounts
 
 import (
 	"fmt"
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
 	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/coretypes/cbalances"
 	"github.com/iotaledger/wasp/packages/kv/codec"
-	"github.com/iotaledger/wasp/packages/kv/collections"
 	"github.com/iotaledger/wasp/packages/kv/dict"
-	"github.com/iotaledger/wasp/packages/util"
 	"github.com/iotaledger/wasp/packages/vm/vmtypes"
 )
 
@@ -146,34 +145,138 @@ func withdrawToChain(ctx vmtypes.Sandbox) (dict.Dict, error) {
 	return nil, nil
 }
 
-// allow is similar to the ERC-20 allow function.
-// TODO not tested
-func allow(ctx vmtypes.Sandbox) (dict.Dict, error) {
+// approve is similar to the ERC-20 approve function: the caller allows the spender to take
+// up to the amount of tokens of the color from the caller's account with transferFrom.
+// The new amount replaces the previous allowance, 0 revokes it
+// Params:
+// - ParamSpender AgentID
+// - ParamColor balance.Color
+// - ParamAmount int64, non-negative
+func approve(ctx vmtypes.Sandbox) (dict.Dict, error) {
 	state := ctx.State()
-	MustCheckLedger(state, "accounts.allow.begin")
-	defer MustCheckLedger(state, "accounts.allow.exit")
+	MustCheckLedger(state, "accounts.approve.begin")
+	defer MustCheckLedger(state, "accounts.approve.exit")
 
-	agentID, ok, err := codec.DecodeAgentID(ctx.Params().MustGet(ParamAgentID))
+	spender, ok, err := codec.DecodeAgentID(ctx.Params().MustGet(ParamSpender))
 	if err != nil {
 		return nil, err
 	}
 	if !ok {
-		return nil, ErrParamWrongOrNotFound
+		return nil, fmt.Errorf("accounts.approve.fail: spender not specified")
 	}
-	amount, ok, err := codec.DecodeInt64(ctx.Params().MustGet(ParamAgentID))
+	col, ok, err := codec.DecodeColor(ctx.Params().MustGet(ParamColor))
 	if err != nil {
 		return nil, err
 	}
 	if !ok {
-		return nil, ErrParamWrongOrNotFound
+		return nil, fmt.Errorf("accounts.approve.fail: color not specified")
+	}
+	amount, ok, err := codec.DecodeInt64(ctx.Params().MustGet(ParamAmount))
+	if err != nil {
+		return nil, err
+	}
+	if !ok || amount < 0 {
+		return nil, fmt.Errorf("accounts.approve.fail: wrong amount")
+	}
+	owner := ctx.Caller()
+	setAllowance(state, owner, spender, col, amount)
+
+	ctx.Event(fmt.Sprintf("[approve] owner: %s, spender: %s, color: %s, amount: %d",
+		owner.String(), spender.String(), col.String(), amount))
+	ctx.Log().Debugf("accounts.approve.success: %s allows %s to spend %d of %s",
+		owner.String(), spender.String(), amount, col.String())
+	return nil, nil
+}
+
+// transferFrom is similar to the ERC-20 transferFrom function: moves tokens from the owner's account
+// to the target account. The caller must be approved by the owner, the allowance is decreased by the amount
+// Params:
+// - ParamOwner AgentID
+// - ParamAgentID AgentID of the target account. Default is ctx.Caller()
+// - ParamColor balance.Color
+// - ParamAmount int64, positive
+func transferFrom(ctx vmtypes.Sandbox) (dict.Dict, error) {
+	state := ctx.State()
+	MustCheckLedger(state, "accounts.transferFrom.begin")
+	defer MustCheckLedger(state, "accounts.transferFrom.exit")
+
+	spender := ctx.Caller()
+	owner, ok, err := codec.DecodeAgentID(ctx.Params().MustGet(ParamOwner))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("accounts.transferFrom.fail: owner not specified")
+	}
+	target, ok, err := codec.DecodeAgentID(ctx.Params().MustGet(ParamAgentID))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		target = spender
+	}
+	col, ok, err := codec.DecodeColor(ctx.Params().MustGet(ParamColor))
+	if err != nil {
+		return nil, err
 	}
-	allowances := collections.NewMap(state, VarStateAllowances)
-	if amount <= 0 {
-		allowances.MustDelAt(agentID[:])
-		ctx.Log().Debugf("accounts.allow.success. %s is not allowed to withdrawToAddress funds", agentID.String())
-	} else {
-		allowances.MustSetAt(agentID[:], util.Uint64To8Bytes(uint64(amount)))
-		ctx.Log().Debugf("accounts.allow.success. Allow %s to withdrawToAddress uo to %d", agentID.String(), amount)
+	if !ok {
+		return nil, fmt.Errorf("accounts.transferFrom.fail: color not specified")
+	}
+	amount, ok, err := codec.DecodeInt64(ctx.Params().MustGet(ParamAmount))
+	if err != nil {
+		return nil, err
+	}
+	if !ok || amount <= 0 {
+		return nil, fmt.Errorf("accounts.transferFrom.fail: wrong amount")
+	}
+	allowance := GetAllowance(state, owner, spender, col)
+	if allowance < amount {
+		return nil, fmt.Errorf("accounts.transferFrom.fail: allowance %d is less than %d", allowance, amount)
+	}
+	if GetBalance(state, owner, col) < amount {
+		return nil, fmt.Errorf("accounts.transferFrom.fail: not enough funds in the account of %s", owner.String())
 	}
+	if !MoveBetweenAccounts(state, owner, target, cbalances.NewFromMap(map[balance.Color]int64{col: amount})) {
+		return nil, fmt.Errorf("accounts.transferFrom.fail: not enough funds in the account of %s", owner.String())
+	}
+	setAllowance(state, owner, spender, col, allowance-amount)
+
+	ctx.Event(fmt.Sprintf("[transferFrom] owner: %s, spender: %s, target: %s, color: %s, amount: %d",
+		owner.String(), spender.String(), target.String(), col.String(), amount))
+	ctx.Log().Debugf("accounts.transferFrom.success: %s moved %d of %s from %s to %s",
+		spender.String(), amount, col.String(), owner.String(), target.String())
 	return nil, nil
 }
+
+// getAllowance returns the amount the spender is allowed to take from the owner's account
+// Params:
+// - ParamOwner AgentID
+// - ParamSpender AgentID
+// - ParamColor balance.Color
+// Returns ParamAmount int64
+func getAllowance(ctx vmtypes.SandboxView) (dict.Dict, error) {
+	owner, ok, err := codec.DecodeAgentID(ctx.Params().MustGet(ParamOwner))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("accounts.allowance: owner not specified")
+	}
+	spender, ok, err := codec.DecodeAgentID(ctx.Params().MustGet(ParamSpender))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("accounts.allowance: spender not specified")
+	}
+	col, ok, err := codec.DecodeColor(ctx.Params().MustGet(ParamColor))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("accounts.allowance: color not specified")
+	}
+	ret := dict.New()
+	ret.Set(ParamAmount, codec.EncodeInt64(GetAllowance(ctx.State(), owner, spender, col)))
+	return ret, nil
+}
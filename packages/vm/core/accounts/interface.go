@@ -22,13 +22,34 @@ var (
 
 func init() {
 	Interface.WithFunctions(initialize, []contract.ContractFunctionInterface{
-		contract.ViewFunc(FuncBalance, getBalance),
+		contract.ViewFunc(FuncBalance, getBalance).
+			WithParams(contract.Field(ParamAgentID, contract.TypeAgentID)),
 		contract.ViewFunc(FuncTotalAssets, getTotalAssets),
 		contract.ViewFunc(FuncAccounts, getAccounts),
-		contract.Func(FuncDeposit, deposit),
+		contract.Func(FuncDeposit, deposit).
+			WithParams(contract.Field(ParamAgentID, contract.TypeAgentID)),
 		contract.Func(FuncWithdrawToAddress, withdrawToAddress),
 		contract.Func(FuncWithdrawToChain, withdrawToChain),
-		contract.Func(FuncAllow, allow),
+		contract.Func(FuncApprove, approve).
+			WithParams(
+				contract.Field(ParamSpender, contract.TypeAgentID),
+				contract.Field(ParamColor, contract.TypeColor),
+				contract.Field(ParamAmount, contract.TypeInt64),
+			),
+		contract.Func(FuncTransferFrom, transferFrom).
+			WithParams(
+				contract.Field(ParamOwner, contract.TypeAgentID),
+				contract.Field(ParamAgentID, contract.TypeAgentID),
+				contract.Field(ParamColor, contract.TypeColor),
+				contract.Field(ParamAmount, contract.TypeInt64),
+			),
+		contract.ViewFunc(FuncAllowance, getAllowance).
+			WithParams(
+				contract.Field(ParamOwner, contract.TypeAgentID),
+				contract.Field(ParamSpender, contract.TypeAgentID),
+				contract.Field(ParamColor, contract.TypeColor),
+			).
+			WithResults(contract.Field(ParamAmount, contract.TypeInt64)),
 	})
 }
 
@@ -37,15 +58,20 @@ const (
 	FuncTotalAssets = "totalAssets"
 	FuncDeposit     = "deposit"
 
-	FuncAllow             = "allow"
 	FuncWithdrawToAddress = "withdrawToAddress"
 	FuncWithdrawToChain   = "withdrawToChain"
 	FuncAccounts          = "accounts"
 
+	FuncApprove      = "approve"
+	FuncTransferFrom = "transferFrom"
+	FuncAllowance    = "allowance"
+
 	ParamAgentID = "a"
 	ParamColor   = "c"
 	ParamAmount  = "t"
 	ParamChainID = "i"
+	ParamOwner   = "o"
+	ParamSpender = "s"
 )
 
 var (
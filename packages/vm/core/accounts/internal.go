@@ -134,6 +134,40 @@ func MoveBetweenAccounts(state kv.KVStore, fromAgentID, toAgentID coretypes.Agen
 	return true
 }
 
+func getAllowances(state kv.KVStore) *collections.Map {
+	return collections.NewMap(state, VarStateAllowances)
+}
+
+func getAllowancesR(state kv.KVStoreReader) *collections.ImmutableMap {
+	return collections.NewMapReadOnly(state, VarStateAllowances)
+}
+
+func allowanceKey(owner, spender coretypes.AgentID, color balance.Color) []byte {
+	ret := make([]byte, 0, 2*coretypes.AgentIDLength+balance.ColorLength)
+	ret = append(ret, owner[:]...)
+	ret = append(ret, spender[:]...)
+	return append(ret, color[:]...)
+}
+
+// GetAllowance returns the amount of tokens of the color the spender is allowed to take from the owner's account
+func GetAllowance(state kv.KVStoreReader, owner, spender coretypes.AgentID, color balance.Color) int64 {
+	v := getAllowancesR(state).MustGetAt(allowanceKey(owner, spender, color))
+	if v == nil {
+		return 0
+	}
+	return int64(util.MustUint64From8Bytes(v))
+}
+
+// setAllowance replaces the allowance. Allowances do not lock the tokens and do not affect the ledger
+func setAllowance(state kv.KVStore, owner, spender coretypes.AgentID, color balance.Color, amount int64) {
+	key := allowanceKey(owner, spender, color)
+	if amount <= 0 {
+		getAllowances(state).MustDelAt(key)
+		return
+	}
+	getAllowances(state).MustSetAt(key, util.Uint64To8Bytes(uint64(amount)))
+}
+
 func touchAccount(state kv.KVStore, account *collections.Map) {
 	if account.Name() == VarStateTotalAssets {
 		return
@@ -21,9 +21,16 @@ var (
 
 func init() {
 	Interface.WithFunctions(initialize, []contract.ContractFunctionInterface{
-		contract.Func(FuncStoreBlob, storeBlob),
-		contract.ViewFunc(FuncGetBlobInfo, getBlobInfo),
-		contract.ViewFunc(FuncGetBlobField, getBlobField),
+		contract.Func(FuncStoreBlob, storeBlob).
+			WithResults(contract.Field(ParamHash, contract.TypeHash)),
+		contract.ViewFunc(FuncGetBlobInfo, getBlobInfo).
+			WithParams(contract.Field(ParamHash, contract.TypeHash)),
+		contract.ViewFunc(FuncGetBlobField, getBlobField).
+			WithParams(
+				contract.Field(ParamHash, contract.TypeHash),
+				contract.Field(ParamField, contract.TypeString),
+			).
+			WithResults(contract.Field(ParamBytes, contract.TypeBytes)),
 		contract.ViewFunc(FuncListBlobs, listBlobs),
 	})
 }
@@ -40,6 +47,8 @@ const (
 	VarFieldVMType             = "v"
 	VarFieldProgramDescription = "d"
 	VarFieldProgramSource      = "s"
+	// optional ABI of the program, JSON encoded contract.ABI
+	VarFieldProgramABI = "a"
 
 	// function names
 	FuncGetBlobInfo  = "getBlobInfo"
@@ -0,0 +1,99 @@
+package eventlog
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/util"
+	"github.com/mr-tron/base58"
+)
+
+// MaxTopics is the maximum number of indexed topics of the structured event
+const MaxTopics = 4
+
+// Event is the structured event emitted by the contract with Sandbox.EmitEvent.
+// Events are indexed by the contract, by the name and by the value of each topic
+type Event struct {
+	Contract   coretypes.Hname
+	Name       string
+	Topics     dict.Dict
+	Payload    dict.Dict
+	BlockIndex uint32
+	Timestamp  int64
+	RequestID  coretypes.RequestID
+}
+
+func (e *Event) String() string {
+	topics := make([]string, 0, len(e.Topics))
+	for _, k := range e.Topics.KeysSorted() {
+		topics = append(topics, fmt.Sprintf("%s=%s", string(k), base58.Encode(e.Topics.MustGet(k))))
+	}
+	return fmt.Sprintf("%s(%s)", e.Name, strings.Join(topics, ", "))
+}
+
+func (e *Event) Write(w io.Writer) error {
+	if err := e.Contract.Write(w); err != nil {
+		return err
+	}
+	if err := util.WriteString16(w, e.Name); err != nil {
+		return err
+	}
+	if err := e.Topics.Write(w); err != nil {
+		return err
+	}
+	if err := e.Payload.Write(w); err != nil {
+		return err
+	}
+	if err := util.WriteUint32(w, e.BlockIndex); err != nil {
+		return err
+	}
+	if err := util.WriteInt64(w, e.Timestamp); err != nil {
+		return err
+	}
+	if err := e.RequestID.Write(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (e *Event) Read(r io.Reader) error {
+	var err error
+	if err = e.Contract.Read(r); err != nil {
+		return err
+	}
+	if e.Name, err = util.ReadString16(r); err != nil {
+		return err
+	}
+	e.Topics = dict.New()
+	if err = e.Topics.Read(r); err != nil {
+		return err
+	}
+	e.Payload = dict.New()
+	if err = e.Payload.Read(r); err != nil {
+		return err
+	}
+	if err = util.ReadUint32(r, &e.BlockIndex); err != nil {
+		return err
+	}
+	if err = util.ReadInt64(r, &e.Timestamp); err != nil {
+		return err
+	}
+	if err = e.RequestID.Read(r); err != nil {
+		return err
+	}
+	return nil
+}
+
+func EncodeEvent(e *Event) []byte {
+	return util.MustBytes(e)
+}
+
+func DecodeEvent(data []byte) (*Event, error) {
+	ret := new(Event)
+	err := ret.Read(bytes.NewReader(data))
+	return ret, err
+}
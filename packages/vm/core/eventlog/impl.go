@@ -2,6 +2,8 @@ package eventlog
 
 import (
 	"fmt"
+	"math"
+
 	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/kv"
 
@@ -92,3 +94,135 @@ func getHnameParameter(params dict.Dict) (coretypes.Hname, error) {
 	}
 	return contractName, nil
 }
+
+// getEvents returns structured events of the contract in the order they were emitted.
+// Parameters:
+//	- ParamContractHname Hname of the contract which emitted the events
+//	- ParamEventName Filter by the name of the event. Optional
+//	- ParamTopic, ParamTopicValue Filter by the value of the topic. Optional, requires ParamEventName
+//	- ParamFromBlock, ParamToBlock Interval of block indices. Defaults to all blocks
+//	- ParamMaxRecords Max amount of events to return. Defaults to 50, capped at MaxNumberOfEvents
+//	- ParamCursor Position to continue from, returned by the previous call. Optional
+// Returns events encoded with EncodeEvent in the array ParamRecords and ParamCursor if there are more events
+func getEvents(ctx vmtypes.SandboxView) (dict.Dict, error) {
+	params := ctx.Params()
+	contractHname, err := getHnameParameter(params)
+	if err != nil {
+		return nil, err
+	}
+	name, hasName, err := codec.DecodeString(params.MustGet(ParamEventName))
+	if err != nil {
+		return nil, err
+	}
+	topic, hasTopic, err := codec.DecodeString(params.MustGet(ParamTopic))
+	if err != nil {
+		return nil, err
+	}
+	var idx kv.Key
+	switch {
+	case hasTopic && !hasName:
+		return nil, fmt.Errorf("filter by topic requires parameter '%s'", ParamEventName)
+	case hasTopic:
+		idx = indexKey(contractHname, name, topic, string(params.MustGet(ParamTopicValue)))
+	case hasName:
+		idx = indexKey(contractHname, name)
+	default:
+		idx = indexKey(contractHname)
+	}
+	fromBlock, _, err := codec.DecodeInt64(params.MustGet(ParamFromBlock))
+	if err != nil {
+		return nil, err
+	}
+	toBlock, ok, err := codec.DecodeInt64(params.MustGet(ParamToBlock))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		toBlock = math.MaxUint32
+	}
+	maxRecords, ok, err := codec.DecodeInt64(params.MustGet(ParamMaxRecords))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		maxRecords = DefaultMaxNumberOfRecords
+	}
+	if maxRecords > MaxNumberOfEvents {
+		maxRecords = MaxNumberOfEvents
+	}
+	cursor, ok, err := codec.DecodeInt64(params.MustGet(ParamCursor))
+	if err != nil {
+		return nil, err
+	}
+	if fromBlock < 0 || toBlock < 0 || cursor < 0 {
+		return nil, fmt.Errorf("wrong parameters")
+	}
+
+	state := ctx.State()
+	_, next := indexBounds(state, idx)
+	pos := indexLowerBound(state, idx, uint32(fromBlock))
+	if ok && uint32(cursor) > pos {
+		pos = uint32(cursor)
+	}
+	ret := dict.New()
+	a := collections.NewArray(ret, ParamRecords)
+	for ; pos < next && int64(a.MustLen()) < maxRecords; pos++ {
+		blockIndex, seq := indexAt(state, idx, pos)
+		if int64(blockIndex) > toBlock {
+			pos = next
+			break
+		}
+		a.MustPush(state.MustGet(eventKey(seq)))
+	}
+	if pos < next {
+		if blockIndex, _ := indexAt(state, idx, pos); int64(blockIndex) <= toBlock {
+			ret.Set(ParamCursor, codec.EncodeInt64(int64(pos)))
+		}
+	}
+	return ret, nil
+}
+
+// setRetention sets the retention policy of the structured events. Can only be called by the chain owner.
+// Older events are pruned gradually, when new events are appended
+// Parameters:
+//	- ParamMaxEvents maximum number of stored events. 0 means unlimited. Optional, unchanged if absent
+//	- ParamRetentionBlocks events are kept for that many blocks. 0 means forever. Optional, unchanged if absent
+func setRetention(ctx vmtypes.Sandbox) (dict.Dict, error) {
+	if ctx.Caller() != ctx.ChainOwnerID() {
+		return nil, fmt.Errorf("eventlog.setRetention: not authorized")
+	}
+	params := ctx.Params()
+	for _, p := range []struct{ param, variable string }{
+		{ParamMaxEvents, VarMaxEvents},
+		{ParamRetentionBlocks, VarRetentionBlocks},
+	} {
+		v, ok, err := codec.DecodeInt64(params.MustGet(kv.Key(p.param)))
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		if v < 0 {
+			return nil, fmt.Errorf("eventlog.setRetention: wrong '%s'", p.param)
+		}
+		ctx.State().Set(kv.Key(p.variable), codec.EncodeInt64(v))
+	}
+	return nil, nil
+}
+
+// getRetention returns the retention policy of the structured events
+func getRetention(ctx vmtypes.SandboxView) (dict.Dict, error) {
+	maxEvents, _, err := codec.DecodeInt64(ctx.State().MustGet(VarMaxEvents))
+	if err != nil {
+		return nil, err
+	}
+	retention, _, err := codec.DecodeInt64(ctx.State().MustGet(VarRetentionBlocks))
+	if err != nil {
+		return nil, err
+	}
+	ret := dict.New()
+	ret.Set(ParamMaxEvents, codec.EncodeInt64(maxEvents))
+	ret.Set(ParamRetentionBlocks, codec.EncodeInt64(retention))
+	return ret, nil
+}
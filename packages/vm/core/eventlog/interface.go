@@ -21,8 +21,38 @@ var (
 
 func init() {
 	Interface.WithFunctions(initialize, []contract.ContractFunctionInterface{
-		contract.ViewFunc(FuncGetLogRecords, getLogRecords),
-		contract.ViewFunc(FuncGetNumRecords, getNumRecords),
+		contract.ViewFunc(FuncGetLogRecords, getLogRecords).
+			WithParams(
+				contract.Field(ParamContractHname, contract.TypeHname),
+				contract.Field(ParamFromTs, contract.TypeInt64),
+				contract.Field(ParamToTs, contract.TypeInt64),
+				contract.Field(ParamMaxLastRecords, contract.TypeInt64),
+			),
+		contract.ViewFunc(FuncGetNumRecords, getNumRecords).
+			WithParams(contract.Field(ParamContractHname, contract.TypeHname)).
+			WithResults(contract.Field(ParamNumRecords, contract.TypeInt64)),
+		contract.ViewFunc(FuncGetEvents, getEvents).
+			WithParams(
+				contract.Field(ParamContractHname, contract.TypeHname),
+				contract.Field(ParamEventName, contract.TypeString),
+				contract.Field(ParamTopic, contract.TypeString),
+				contract.Field(ParamTopicValue, contract.TypeBytes),
+				contract.Field(ParamFromBlock, contract.TypeInt64),
+				contract.Field(ParamToBlock, contract.TypeInt64),
+				contract.Field(ParamMaxRecords, contract.TypeInt64),
+				contract.Field(ParamCursor, contract.TypeInt64),
+			).
+			WithResults(contract.Field(ParamCursor, contract.TypeInt64)),
+		contract.Func(FuncSetRetention, setRetention).
+			WithParams(
+				contract.Field(ParamMaxEvents, contract.TypeInt64),
+				contract.Field(ParamRetentionBlocks, contract.TypeInt64),
+			),
+		contract.ViewFunc(FuncGetRetention, getRetention).
+			WithResults(
+				contract.Field(ParamMaxEvents, contract.TypeInt64),
+				contract.Field(ParamRetentionBlocks, contract.TypeInt64),
+			),
 	})
 }
 
@@ -35,9 +65,36 @@ const (
 	ParamNumRecords     = "numRecords"
 	ParamRecords        = "records"
 
+	// structured events
+	ParamEventName       = "eventName"
+	ParamTopic           = "topic"
+	ParamTopicValue      = "topicValue"
+	ParamFromBlock       = "fromBlock"
+	ParamToBlock         = "toBlock"
+	ParamMaxRecords      = "maxRecords"
+	ParamCursor          = "cursor"
+	ParamMaxEvents       = "maxEvents"
+	ParamRetentionBlocks = "retentionBlocks"
+
 	// function names
 	FuncGetLogRecords = "getLogRecords"
 	FuncGetNumRecords = "getNumRecords"
+	FuncGetEvents     = "getEvents"
+	FuncSetRetention  = "setRetention"
+	FuncGetRetention  = "getRetention"
 
 	DefaultMaxNumberOfRecords = 50
+	// MaxNumberOfEvents is the maximum number of structured events returned by one call to 'getEvents'
+	MaxNumberOfEvents = 1000
+)
+
+// state variables of the structured events. The 5th byte of each key is never 0 or 1,
+// so they do not collide with the timestamped logs, which are stored under the hname of the contract
+const (
+	VarEvents          = "#event"
+	VarIndex           = "#index"
+	VarFirstSeq        = "#firstSeq"
+	VarNextSeq         = "#nextSeq"
+	VarMaxEvents       = "#maxEvents"
+	VarRetentionBlocks = "#retentionBlocks"
 )
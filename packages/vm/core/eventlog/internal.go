@@ -1,11 +1,164 @@
 package eventlog
 
 import (
+	"bytes"
+	"fmt"
+
 	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/hashing"
 	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/codec"
 	"github.com/iotaledger/wasp/packages/kv/collections"
+	"github.com/iotaledger/wasp/packages/util"
 )
 
 func AppendToLog(state kv.KVStore, ts int64, contract coretypes.Hname, data []byte) {
 	collections.NewTimestampedLog(state, kv.Key(contract.Bytes())).MustAppend(ts, data)
 }
+
+// MaxPrunedPerEvent limits the number of events pruned by the retention policy when a new event is appended
+const MaxPrunedPerEvent = 16
+
+// Structured events are stored under the global sequence number in the order they were emitted.
+// Each index is the list of positions (block index, sequence number) of the events with the same
+// contract, the same contract and name, or the same contract, name and value of the topic.
+// Events are pruned in the order of sequence numbers, so the pruned event is always the first one
+// in each of its indices
+
+// AppendEvent stores the event, adds it to the indices and prunes old events according to the retention policy
+func AppendEvent(state kv.KVStore, e *Event) error {
+	if len(e.Topics) > MaxTopics {
+		return fmt.Errorf("too many topics in event '%s': max %d", e.Name, MaxTopics)
+	}
+	seq := getUint32(state, VarNextSeq)
+	state.Set(eventKey(seq), EncodeEvent(e))
+	setUint32(state, VarNextSeq, seq+1)
+	for _, idx := range eventIndices(e) {
+		indexPush(state, idx, e.BlockIndex, seq)
+	}
+	pruneEvents(state, e.BlockIndex)
+	return nil
+}
+
+// pruneEvents removes the oldest events which exceed the maximum number of events or the retention period
+func pruneEvents(state kv.KVStore, blockIndex uint32) {
+	maxEvents, _, _ := codec.DecodeInt64(state.MustGet(VarMaxEvents))
+	retention, _, _ := codec.DecodeInt64(state.MustGet(VarRetentionBlocks))
+	if maxEvents == 0 && retention == 0 {
+		return
+	}
+	first := getUint32(state, VarFirstSeq)
+	next := getUint32(state, VarNextSeq)
+	for i := 0; i < MaxPrunedPerEvent && first < next; i++ {
+		e, err := DecodeEvent(state.MustGet(eventKey(first)))
+		if err != nil {
+			panic(err)
+		}
+		expired := retention > 0 && int64(e.BlockIndex)+retention <= int64(blockIndex)
+		tooMany := maxEvents > 0 && int64(next-first) > maxEvents
+		if !expired && !tooMany {
+			break
+		}
+		for _, idx := range eventIndices(e) {
+			indexPopFront(state, idx, first)
+		}
+		state.Del(eventKey(first))
+		first++
+	}
+	setUint32(state, VarFirstSeq, first)
+}
+
+func eventKey(seq uint32) kv.Key {
+	return kv.Key(VarEvents) + kv.Key(util.Uint32To4Bytes(seq))
+}
+
+// eventIndices returns keys of all indices of the event
+func eventIndices(e *Event) []kv.Key {
+	ret := []kv.Key{
+		indexKey(e.Contract),
+		indexKey(e.Contract, e.Name),
+	}
+	for _, topic := range e.Topics.KeysSorted() {
+		ret = append(ret, indexKey(e.Contract, e.Name, string(topic), string(e.Topics.MustGet(topic))))
+	}
+	return ret
+}
+
+// indexKey is the prefix of the index of events of the contract filtered by the given name, topic and value of the topic
+func indexKey(contract coretypes.Hname, filter ...string) kv.Key {
+	var buf bytes.Buffer
+	buf.Write(contract.Bytes())
+	for _, s := range filter {
+		_ = util.WriteString16(&buf, s)
+	}
+	h := hashing.HashData(buf.Bytes())
+	return kv.Key(VarIndex) + kv.Key(h[:])
+}
+
+const (
+	indexFirstKeyCode = "f"
+	indexNextKeyCode  = "n"
+	indexElemKeyCode  = "e"
+)
+
+func indexElemKey(idx kv.Key, pos uint32) kv.Key {
+	return idx + indexElemKeyCode + kv.Key(util.Uint32To4Bytes(pos))
+}
+
+// indexBounds returns positions of the first and after the last element of the index
+func indexBounds(state kv.KVStoreReader, idx kv.Key) (uint32, uint32) {
+	return getUint32(state, idx+indexFirstKeyCode), getUint32(state, idx+indexNextKeyCode)
+}
+
+func indexPush(state kv.KVStore, idx kv.Key, blockIndex uint32, seq uint32) {
+	next := getUint32(state, idx+indexNextKeyCode)
+	state.Set(indexElemKey(idx, next), append(util.Uint32To4Bytes(blockIndex), util.Uint32To4Bytes(seq)...))
+	setUint32(state, idx+indexNextKeyCode, next+1)
+}
+
+func indexPopFront(state kv.KVStore, idx kv.Key, seq uint32) {
+	first, next := indexBounds(state, idx)
+	if first >= next {
+		return
+	}
+	if _, s := indexAt(state, idx, first); s != seq {
+		panic(fmt.Sprintf("eventlog: inconsistent index: expected event #%d, found #%d", seq, s))
+	}
+	state.Del(indexElemKey(idx, first))
+	setUint32(state, idx+indexFirstKeyCode, first+1)
+}
+
+// indexAt returns block index and sequence number of the event at the position in the index
+func indexAt(state kv.KVStoreReader, idx kv.Key, pos uint32) (uint32, uint32) {
+	data := state.MustGet(indexElemKey(idx, pos))
+	if len(data) != 8 {
+		panic(fmt.Sprintf("eventlog: wrong index element at %d", pos))
+	}
+	return util.MustUint32From4Bytes(data[:4]), util.MustUint32From4Bytes(data[4:])
+}
+
+// indexLowerBound returns the position of the first event in the index with the block index >= blockIndex
+func indexLowerBound(state kv.KVStoreReader, idx kv.Key, blockIndex uint32) uint32 {
+	lo, hi := indexBounds(state, idx)
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		if blk, _ := indexAt(state, idx, mid); blk < blockIndex {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	return lo
+}
+
+func getUint32(state kv.KVStoreReader, key kv.Key) uint32 {
+	data := state.MustGet(key)
+	if data == nil {
+		return 0
+	}
+	return util.MustUint32From4Bytes(data)
+}
+
+func setUint32(state kv.KVStore, key kv.Key, val uint32) {
+	state.Set(key, util.Uint32To4Bytes(val))
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/iotaledger/wasp/packages/vm/core/accounts"
 	"github.com/iotaledger/wasp/packages/vm/core/blob"
 	"github.com/iotaledger/wasp/packages/vm/core/eventlog"
+	"github.com/iotaledger/wasp/packages/vm/core/governance"
 	"github.com/iotaledger/wasp/packages/vm/core/root"
 	"github.com/iotaledger/wasp/packages/vm/vmtypes"
 )
@@ -29,6 +30,7 @@ func printReservedHnames() {
 	fmt.Printf("    %10s: '%s'\n", accounts.Interface.Hname().String(), accounts.Interface.Name)
 	fmt.Printf("    %10s: '%s'\n", blob.Interface.Hname().String(), blob.Interface.Name)
 	fmt.Printf("    %10s: '%s'\n", eventlog.Interface.Hname().String(), eventlog.Interface.Name)
+	fmt.Printf("    %10s: '%s'\n", governance.Interface.Hname().String(), governance.Interface.Name)
 	fmt.Printf("    %10s: '%s'\n", coretypes.EntryPointInit.String(), coretypes.FuncInit)
 	fmt.Printf("--------------- reserved hnames ------------------\n")
 }
@@ -46,6 +48,9 @@ func GetProcessor(programHash hashing.HashValue) (vmtypes.Processor, error) {
 
 	case eventlog.Interface.ProgramHash:
 		return eventlog.Interface, nil
+
+	case governance.Interface.ProgramHash:
+		return governance.Interface, nil
 	}
 	return nil, fmt.Errorf("can't find builtin processor with hash %s", programHash.String())
 }
@@ -0,0 +1,335 @@
+// 'governance' is a core contract on the chain. It makes possible to control the chain
+// by the group of agents instead of the single chain owner:
+// - maintaining the voting configuration: the member list or the color of voting tokens, the quorum and the voting period
+// - submitting proposals. The proposal is a call to the entry point of the 'root' contract (or of the 'governance' itself)
+// - voting for proposals, one vote per member or with the weight of the tokens on the on-chain account of the voter.
+//   The weights are taken when the proposal is created, so moving tokens after that gives no new votes
+// - executing the approved proposals
+// To take control over the chain, the chain owner delegates chain ownership to the 'governance' contract
+// and the first proposal calls 'root.claimChainOwnership'
+package governance
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/collections"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/vm/vmtypes"
+)
+
+// initialize is mandatory
+func initialize(ctx vmtypes.Sandbox) (dict.Dict, error) {
+	ctx.Log().Debugf("governance.initialize.success hname = %s", Interface.Hname().String())
+	return nil, nil
+}
+
+// setConfig sets the voting configuration. Only the chain owner can call it
+// Parameters (at least one is mandatory):
+// - ParamVotingMode int64 VotingModeMembers or VotingModeTokens
+// - ParamVotingColor balance.Color the color of voting tokens
+// - ParamQuorum int64 minimum total weight of votes
+// - ParamVotingPeriod int64 voting period in seconds
+func setConfig(ctx vmtypes.Sandbox) (dict.Dict, error) {
+	if ctx.Caller() != ctx.ChainOwnerID() {
+		return nil, fmt.Errorf("governance.setConfig: not authorized")
+	}
+	params := ctx.Params()
+	state := ctx.State()
+	mode, modeOk, err := codec.DecodeInt64(params.MustGet(ParamVotingMode))
+	if err != nil {
+		return nil, err
+	}
+	if modeOk && mode != VotingModeMembers && mode != VotingModeTokens {
+		return nil, fmt.Errorf("governance.setConfig: unknown voting mode %d", mode)
+	}
+	col, colOk, err := codec.DecodeColor(params.MustGet(ParamVotingColor))
+	if err != nil {
+		return nil, err
+	}
+	quorum, quorumOk, err := codec.DecodeInt64(params.MustGet(ParamQuorum))
+	if err != nil {
+		return nil, err
+	}
+	if quorumOk && quorum < 0 {
+		return nil, fmt.Errorf("parameter 'quorum' is invalid")
+	}
+	period, periodOk, err := codec.DecodeInt64(params.MustGet(ParamVotingPeriod))
+	if err != nil {
+		return nil, err
+	}
+	if periodOk && period <= 0 {
+		return nil, fmt.Errorf("parameter 'voting period' is invalid")
+	}
+	if !modeOk && !colOk && !quorumOk && !periodOk {
+		return nil, fmt.Errorf("governance.setConfig: wrong parameters")
+	}
+	if modeOk {
+		state.Set(VarVotingMode, codec.EncodeInt64(mode))
+	}
+	if colOk {
+		state.Set(VarVotingColor, codec.EncodeColor(col))
+	}
+	if quorumOk {
+		state.Set(VarQuorum, codec.EncodeInt64(quorum))
+	}
+	if periodOk {
+		state.Set(VarVotingPeriod, codec.EncodeInt64(period))
+	}
+	ctx.Log().Debugf("governance.setConfig.success")
+	return nil, nil
+}
+
+// addMember adds the agent to the member list. Only the chain owner can call it
+// Parameters:
+// - ParamMember AgentID
+func addMember(ctx vmtypes.Sandbox) (dict.Dict, error) {
+	agentID, err := checkMemberParams(ctx, "addMember")
+	if err != nil {
+		return nil, err
+	}
+	collections.NewMap(ctx.State(), VarMembers).MustSetAt(agentID[:], []byte{0xFF})
+	ctx.Event(fmt.Sprintf("[add member] %s", agentID.String()))
+	return nil, nil
+}
+
+// removeMember removes the agent from the member list. Only the chain owner can call it
+// Parameters:
+// - ParamMember AgentID
+func removeMember(ctx vmtypes.Sandbox) (dict.Dict, error) {
+	agentID, err := checkMemberParams(ctx, "removeMember")
+	if err != nil {
+		return nil, err
+	}
+	collections.NewMap(ctx.State(), VarMembers).MustDelAt(agentID[:])
+	ctx.Event(fmt.Sprintf("[remove member] %s", agentID.String()))
+	return nil, nil
+}
+
+func checkMemberParams(ctx vmtypes.Sandbox, funName string) (coretypes.AgentID, error) {
+	if ctx.Caller() != ctx.ChainOwnerID() {
+		return coretypes.AgentID{}, fmt.Errorf("governance.%s: not authorized", funName)
+	}
+	agentID, ok, err := codec.DecodeAgentID(ctx.Params().MustGet(ParamMember))
+	if err != nil {
+		return coretypes.AgentID{}, err
+	}
+	if !ok {
+		return coretypes.AgentID{}, fmt.Errorf("governance.%s: parameter 'member' not found", funName)
+	}
+	return agentID, nil
+}
+
+// propose submits the proposal and takes the snapshot of the voting weights. The caller must have a non-zero voting weight
+// Parameters:
+// - ParamEntryPoint string name of the entry point of the action
+// - ParamTarget Hname target contract of the action. Defaults to 'root'. Only 'root' and 'governance' are allowed
+// - ParamDescription string, optional
+// - ParamArgs the arguments of the action: the dict, serialized by Dict.Write. Optional
+// Returns ParamProposalID
+func propose(ctx vmtypes.Sandbox) (dict.Dict, error) {
+	params := ctx.Params()
+	cfg, err := GetConfig(ctx.State())
+	if err != nil {
+		return nil, err
+	}
+	proposals := collections.NewArray(ctx.State(), VarProposals)
+	id := proposals.MustLen()
+	total, err := snapshotWeights(ctx, cfg, id)
+	if err != nil {
+		return nil, err
+	}
+	caller := ctx.Caller()
+	weight, err := voteWeight(ctx.State(), id, caller)
+	if err != nil {
+		return nil, err
+	}
+	if weight <= 0 {
+		return nil, fmt.Errorf("governance.propose: not authorized")
+	}
+	epName, ok, err := codec.DecodeString(params.MustGet(ParamEntryPoint))
+	if err != nil {
+		return nil, err
+	}
+	if !ok || epName == "" {
+		return nil, fmt.Errorf("governance.propose: parameter 'entry point' not found")
+	}
+	target, ok, err := codec.DecodeHname(params.MustGet(ParamTarget))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		target = rootHname
+	}
+	if target != rootHname && target != Interface.Hname() {
+		return nil, fmt.Errorf("governance.propose: target %s is not allowed", target.String())
+	}
+	descr, _, err := codec.DecodeString(params.MustGet(ParamDescription))
+	if err != nil {
+		return nil, err
+	}
+	args := dict.New()
+	if argsBin := params.MustGet(ParamArgs); argsBin != nil {
+		if err := args.Read(bytes.NewReader(argsBin)); err != nil {
+			return nil, fmt.Errorf("governance.propose: parameter 'args' is invalid: %v", err)
+		}
+	}
+	p := &Proposal{
+		Proposer:    ctx.Caller(),
+		Description: descr,
+		Target:      target,
+		EntryPoint:  coretypes.Hn(epName),
+		Args:        args,
+		Deadline:    ctx.GetTimestamp() + cfg.VotingPeriod*int64(time.Second),
+		TotalWeight: total,
+		Status:      ProposalOpen,
+	}
+	proposals.MustPush(EncodeProposal(p))
+
+	ctx.Event(fmt.Sprintf("[proposal #%d] %s.%s by %s: %s", id, target.String(), epName, ctx.Caller().String(), descr))
+	ret := dict.New()
+	ret.Set(ParamProposalID, codec.EncodeInt64(int64(id)))
+	return ret, nil
+}
+
+// vote votes for or against the open proposal. Each agent can vote only once, before the deadline.
+// The weight of the vote is taken from the snapshot of the proposal: in VotingModeTokens it is the balance
+// of the voting color on the account of the caller when the proposal was created.
+// The proposal is executed immediately when 'yes' votes reach the majority of all possible votes and
+// the total weight of votes reaches the quorum
+// Parameters:
+// - ParamProposalID int64
+// - ParamVote int64 1 for 'yes', 0 for 'no'
+func vote(ctx vmtypes.Sandbox) (dict.Dict, error) {
+	id, p, err := getOpenProposal(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if ctx.GetTimestamp() > p.Deadline {
+		return nil, fmt.Errorf("governance.vote: voting for proposal #%d is closed", id)
+	}
+	yes, ok, err := codec.DecodeInt64(ctx.Params().MustGet(ParamVote))
+	if err != nil {
+		return nil, err
+	}
+	if !ok || (yes != 0 && yes != 1) {
+		return nil, fmt.Errorf("governance.vote: parameter 'vote' must be 1 or 0")
+	}
+	caller := ctx.Caller()
+	votes := getVotesMap(ctx.State(), id)
+	if votes.MustHasAt(caller[:]) {
+		return nil, fmt.Errorf("governance.vote: %s already voted for proposal #%d", caller.String(), id)
+	}
+	weight, err := voteWeight(ctx.State(), id, caller)
+	if err != nil {
+		return nil, err
+	}
+	if weight <= 0 {
+		return nil, fmt.Errorf("governance.vote: not authorized")
+	}
+	votes.MustSetAt(caller[:], codec.EncodeInt64(yes))
+	if yes == 1 {
+		p.Yes += weight
+	} else {
+		p.No += weight
+	}
+	saveProposal(ctx.State(), id, p)
+
+	cfg, err := GetConfig(ctx.State())
+	if err != nil {
+		return nil, err
+	}
+	if p.Yes*2 > p.TotalWeight && p.Yes+p.No >= cfg.Quorum {
+		executeAction(ctx, id, p)
+	}
+	return nil, nil
+}
+
+// executeProposal closes the proposal after the deadline. The action is executed if
+// the total weight of votes reaches the quorum and there are more 'yes' votes than 'no' votes.
+// Otherwise the proposal is rejected. Anyone can call it
+// Parameters:
+// - ParamProposalID int64
+func executeProposal(ctx vmtypes.Sandbox) (dict.Dict, error) {
+	id, p, err := getOpenProposal(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if ctx.GetTimestamp() <= p.Deadline {
+		return nil, fmt.Errorf("governance.executeProposal: voting for proposal #%d is not closed yet", id)
+	}
+	cfg, err := GetConfig(ctx.State())
+	if err != nil {
+		return nil, err
+	}
+	if p.Yes+p.No < cfg.Quorum || p.Yes <= p.No {
+		p.Status = ProposalRejected
+		saveProposal(ctx.State(), id, p)
+		ctx.Event(fmt.Sprintf("[proposal #%d rejected] yes: %d, no: %d", id, p.Yes, p.No))
+		return nil, nil
+	}
+	executeAction(ctx, id, p)
+	return nil, nil
+}
+
+func getOpenProposal(ctx vmtypes.Sandbox) (uint16, *Proposal, error) {
+	id, ok, err := codec.DecodeInt64(ctx.Params().MustGet(ParamProposalID))
+	if err != nil {
+		return 0, nil, err
+	}
+	if !ok || id < 0 || id > 0xFFFF {
+		return 0, nil, fmt.Errorf("parameter 'proposal ID' is invalid")
+	}
+	p, err := GetProposal(ctx.State(), uint16(id))
+	if err != nil {
+		return 0, nil, err
+	}
+	if p.Status != ProposalOpen {
+		return 0, nil, fmt.Errorf("proposal #%d is closed", id)
+	}
+	return uint16(id), p, nil
+}
+
+// getConfig returns the voting configuration, the member list (array ParamMember)
+// and the number of proposals
+func getConfig(ctx vmtypes.SandboxView) (dict.Dict, error) {
+	cfg, err := GetConfig(ctx.State())
+	if err != nil {
+		return nil, err
+	}
+	ret := dict.New()
+	ret.Set(ParamVotingMode, codec.EncodeInt64(cfg.VotingMode))
+	ret.Set(ParamVotingColor, codec.EncodeColor(cfg.VotingColor))
+	ret.Set(ParamQuorum, codec.EncodeInt64(cfg.Quorum))
+	ret.Set(ParamVotingPeriod, codec.EncodeInt64(cfg.VotingPeriod))
+	members := collections.NewArray(ret, ParamMember)
+	for _, m := range GetMembers(ctx.State()) {
+		members.MustPush(codec.EncodeAgentID(m))
+	}
+	n := collections.NewArrayReadOnly(ctx.State(), VarProposals).MustLen()
+	ret.Set(ParamNumProposals, codec.EncodeInt64(int64(n)))
+	return ret, nil
+}
+
+// getProposal returns the proposal, encoded by EncodeProposal, in ParamData
+// Parameters:
+// - ParamProposalID int64
+func getProposal(ctx vmtypes.SandboxView) (dict.Dict, error) {
+	id, ok, err := codec.DecodeInt64(ctx.Params().MustGet(ParamProposalID))
+	if err != nil {
+		return nil, err
+	}
+	if !ok || id < 0 || id > 0xFFFF {
+		return nil, fmt.Errorf("parameter 'proposal ID' is invalid")
+	}
+	p, err := GetProposal(ctx.State(), uint16(id))
+	if err != nil {
+		return nil, err
+	}
+	ret := dict.New()
+	ret.Set(ParamData, EncodeProposal(p))
+	return ret, nil
+}
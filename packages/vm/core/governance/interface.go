@@ -0,0 +1,157 @@
+package governance
+
+import (
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/vm/contract"
+)
+
+const (
+	Name        = "governance"
+	Version     = "0.1"
+	description = "Governance Contract"
+)
+
+var (
+	Interface = &contract.ContractInterface{
+		Name:        Name,
+		Description: description,
+		ProgramHash: hashing.HashStrings(Name),
+	}
+)
+
+func init() {
+	Interface.WithFunctions(initialize, []contract.ContractFunctionInterface{
+		contract.Func(FuncSetConfig, setConfig).
+			WithParams(
+				contract.Field(ParamVotingMode, contract.TypeInt64),
+				contract.Field(ParamVotingColor, contract.TypeColor),
+				contract.Field(ParamQuorum, contract.TypeInt64),
+				contract.Field(ParamVotingPeriod, contract.TypeInt64),
+			),
+		contract.Func(FuncAddMember, addMember).
+			WithParams(contract.Field(ParamMember, contract.TypeAgentID)),
+		contract.Func(FuncRemoveMember, removeMember).
+			WithParams(contract.Field(ParamMember, contract.TypeAgentID)),
+		contract.Func(FuncPropose, propose).
+			WithParams(
+				contract.Field(ParamDescription, contract.TypeString),
+				contract.Field(ParamTarget, contract.TypeHname),
+				contract.Field(ParamEntryPoint, contract.TypeString),
+				contract.Field(ParamArgs, contract.TypeBytes),
+			).
+			WithResults(contract.Field(ParamProposalID, contract.TypeInt64)),
+		contract.Func(FuncVote, vote).
+			WithParams(
+				contract.Field(ParamProposalID, contract.TypeInt64),
+				contract.Field(ParamVote, contract.TypeInt64),
+			),
+		contract.Func(FuncExecuteProposal, executeProposal).
+			WithParams(contract.Field(ParamProposalID, contract.TypeInt64)),
+		contract.ViewFunc(FuncGetConfig, getConfig).
+			WithResults(
+				contract.Field(ParamVotingMode, contract.TypeInt64),
+				contract.Field(ParamVotingColor, contract.TypeColor),
+				contract.Field(ParamQuorum, contract.TypeInt64),
+				contract.Field(ParamVotingPeriod, contract.TypeInt64),
+				contract.Field(ParamNumProposals, contract.TypeInt64),
+			),
+		contract.ViewFunc(FuncGetProposal, getProposal).
+			WithParams(contract.Field(ParamProposalID, contract.TypeInt64)).
+			WithResults(contract.Field(ParamData, contract.TypeBytes)),
+	})
+}
+
+// state variables
+const (
+	VarVotingMode   = "m"
+	VarVotingColor  = "c"
+	VarQuorum       = "q"
+	VarVotingPeriod = "p"
+	VarMembers      = "mb"
+	VarProposals    = "pr"
+	VarVotes        = "v"
+	VarWeights      = "w"
+)
+
+// param variables. The params of the proposed action are passed to 'propose' in ParamArgs
+const (
+	ParamVotingMode   = "$$gov.mode$$"
+	ParamVotingColor  = "$$gov.color$$"
+	ParamQuorum       = "$$gov.quorum$$"
+	ParamVotingPeriod = "$$gov.period$$"
+	ParamMember       = "$$gov.member$$"
+	ParamProposalID   = "$$gov.proposal$$"
+	ParamDescription  = "$$gov.description$$"
+	ParamTarget       = "$$gov.target$$"
+	ParamEntryPoint   = "$$gov.entrypoint$$"
+	ParamArgs         = "$$gov.args$$"
+	ParamVote         = "$$gov.vote$$"
+	ParamNumProposals = "$$gov.numproposals$$"
+	ParamData         = "$$gov.data$$"
+)
+
+// function names
+const (
+	FuncSetConfig       = "setConfig"
+	FuncAddMember       = "addMember"
+	FuncRemoveMember    = "removeMember"
+	FuncPropose         = "propose"
+	FuncVote            = "vote"
+	FuncExecuteProposal = "executeProposal"
+	FuncGetConfig       = "getConfig"
+	FuncGetProposal     = "getProposal"
+)
+
+// voting modes
+const (
+	// VotingModeMembers each member of the list has one vote
+	VotingModeMembers = int64(0)
+	// VotingModeTokens the weight of the vote is the balance of the voting color on the on-chain account of the voter
+	VotingModeTokens = int64(1)
+)
+
+// DefaultVotingPeriod is the voting period in seconds, when it is not set in the config
+const DefaultVotingPeriod = int64(24 * 60 * 60)
+
+// statuses of the proposal
+const (
+	ProposalOpen = byte(iota)
+	ProposalExecuted
+	ProposalRejected
+	ProposalFailed
+)
+
+// rootHname is hname of the 'root' contract, the default target of proposals.
+// The 'root' package can't be imported here: it deploys 'governance' during chain initialization
+var rootHname = coretypes.Hn("root")
+
+// Config is the voting configuration of the chain
+type Config struct {
+	VotingMode int64
+	// VotingColor is the color of tokens which give the voting weight in VotingModeTokens
+	VotingColor balance.Color
+	// Quorum is the minimum total weight of votes for the proposal to be executed after the deadline
+	Quorum int64
+	// VotingPeriod in seconds
+	VotingPeriod int64
+}
+
+// Proposal is the record of the proposed action and of the votes for it
+type Proposal struct {
+	Proposer    coretypes.AgentID
+	Description string
+	// the action: a call to the entry point of the target contract with the args
+	Target     coretypes.Hname
+	EntryPoint coretypes.Hname
+	Args       dict.Dict
+	// Deadline is the timestamp (nanoseconds) after which voting is closed
+	Deadline int64
+	// TotalWeight is the sum of the weights of all possible votes, taken when the proposal was created
+	TotalWeight int64
+	Yes         int64
+	No          int64
+	Status      byte
+}
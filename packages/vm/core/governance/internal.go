@@ -0,0 +1,237 @@
+package governance
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/collections"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/util"
+	"github.com/iotaledger/wasp/packages/vm/core/accounts"
+	"github.com/iotaledger/wasp/packages/vm/vmtypes"
+)
+
+// GetConfig reads the voting configuration from the state of the contract
+func GetConfig(state kv.KVStoreReader) (*Config, error) {
+	ret := &Config{
+		VotingMode:   VotingModeMembers,
+		VotingColor:  balance.ColorIOTA,
+		VotingPeriod: DefaultVotingPeriod,
+	}
+	var err error
+	var ok bool
+	if ret.VotingMode, ok, err = codec.DecodeInt64(state.MustGet(VarVotingMode)); err != nil {
+		return nil, err
+	} else if !ok {
+		ret.VotingMode = VotingModeMembers
+	}
+	var col balance.Color
+	if col, ok, err = codec.DecodeColor(state.MustGet(VarVotingColor)); err != nil {
+		return nil, err
+	} else if ok {
+		ret.VotingColor = col
+	}
+	if ret.Quorum, _, err = codec.DecodeInt64(state.MustGet(VarQuorum)); err != nil {
+		return nil, err
+	}
+	var period int64
+	if period, ok, err = codec.DecodeInt64(state.MustGet(VarVotingPeriod)); err != nil {
+		return nil, err
+	} else if ok {
+		ret.VotingPeriod = period
+	}
+	return ret, nil
+}
+
+// IsMember checks if the agent is in the member list
+func IsMember(state kv.KVStoreReader, agentID coretypes.AgentID) bool {
+	return collections.NewMapReadOnly(state, VarMembers).MustHasAt(agentID[:])
+}
+
+// GetMembers returns the member list
+func GetMembers(state kv.KVStoreReader) []coretypes.AgentID {
+	ret := make([]coretypes.AgentID, 0)
+	collections.NewMapReadOnly(state, VarMembers).MustIterateKeys(func(elemKey []byte) bool {
+		agentID, err := coretypes.NewAgentIDFromBytes(elemKey)
+		if err != nil {
+			panic(err)
+		}
+		ret = append(ret, agentID)
+		return true
+	})
+	return ret
+}
+
+// GetProposal returns the proposal by its ID, the index in the array of proposals
+func GetProposal(state kv.KVStoreReader, id uint16) (*Proposal, error) {
+	proposals := collections.NewArrayReadOnly(state, VarProposals)
+	if id >= proposals.MustLen() {
+		return nil, fmt.Errorf("proposal #%d not found", id)
+	}
+	return DecodeProposal(proposals.MustGetAt(id))
+}
+
+func saveProposal(state kv.KVStore, id uint16, p *Proposal) {
+	collections.NewArray(state, VarProposals).MustSetAt(id, EncodeProposal(p))
+}
+
+func getVotesMap(state kv.KVStore, id uint16) *collections.Map {
+	return collections.NewMap(state, VarVotes+string(util.Uint16To2Bytes(id)))
+}
+
+func getWeightsMap(state kv.KVStore, id uint16) *collections.Map {
+	return collections.NewMap(state, VarWeights+string(util.Uint16To2Bytes(id)))
+}
+
+// snapshotWeights stores the weights of all possible votes for the proposal according to the config.
+// Returns the sum of the weights
+func snapshotWeights(ctx vmtypes.Sandbox, cfg *Config, id uint16) (int64, error) {
+	weights := getWeightsMap(ctx.State(), id)
+	switch cfg.VotingMode {
+	case VotingModeMembers:
+		members := GetMembers(ctx.State())
+		for _, m := range members {
+			weights.MustSetAt(m[:], codec.EncodeInt64(1))
+		}
+		return int64(len(members)), nil
+	case VotingModeTokens:
+		accs, err := ctx.Call(accounts.Interface.Hname(), coretypes.Hn(accounts.FuncAccounts), nil, nil)
+		if err != nil {
+			return 0, err
+		}
+		var total int64
+		for k := range accs {
+			agentID, err := coretypes.NewAgentIDFromBytes([]byte(k))
+			if err != nil {
+				return 0, err
+			}
+			res, err := ctx.Call(accounts.Interface.Hname(), coretypes.Hn(accounts.FuncBalance), codec.MakeDict(map[string]interface{}{
+				accounts.ParamAgentID: agentID,
+			}), nil)
+			if err != nil {
+				return 0, err
+			}
+			w, err := balanceOfColor(res, cfg.VotingColor)
+			if err != nil {
+				return 0, err
+			}
+			if w > 0 {
+				weights.MustSetAt(agentID[:], codec.EncodeInt64(w))
+				total += w
+			}
+		}
+		return total, nil
+	}
+	return 0, fmt.Errorf("unknown voting mode %d", cfg.VotingMode)
+}
+
+// voteWeight returns the weight of the vote of the agent from the snapshot of the proposal
+func voteWeight(state kv.KVStore, id uint16, agentID coretypes.AgentID) (int64, error) {
+	ret, _, err := codec.DecodeInt64(getWeightsMap(state, id).MustGetAt(agentID[:]))
+	return ret, err
+}
+
+func balanceOfColor(balances dict.Dict, col balance.Color) (int64, error) {
+	ret, _, err := codec.DecodeInt64(balances.MustGet(kv.Key(col[:])))
+	return ret, err
+}
+
+// executeAction calls the proposed action and records the outcome in the proposal.
+// Failure of the call doesn't fail the request, it is recorded as the status of the proposal
+func executeAction(ctx vmtypes.Sandbox, id uint16, p *Proposal) {
+	_, err := ctx.Call(p.Target, p.EntryPoint, p.Args, nil)
+	if err != nil {
+		p.Status = ProposalFailed
+		ctx.Event(fmt.Sprintf("[proposal #%d failed] %v", id, err))
+	} else {
+		p.Status = ProposalExecuted
+		ctx.Event(fmt.Sprintf("[proposal #%d executed]", id))
+	}
+	saveProposal(ctx.State(), id, p)
+}
+
+// serde
+func (p *Proposal) Write(w io.Writer) error {
+	if _, err := w.Write(p.Proposer[:]); err != nil {
+		return err
+	}
+	if err := util.WriteString16(w, p.Description); err != nil {
+		return err
+	}
+	if err := p.Target.Write(w); err != nil {
+		return err
+	}
+	if err := p.EntryPoint.Write(w); err != nil {
+		return err
+	}
+	if err := p.Args.Write(w); err != nil {
+		return err
+	}
+	if err := util.WriteInt64(w, p.Deadline); err != nil {
+		return err
+	}
+	if err := util.WriteInt64(w, p.TotalWeight); err != nil {
+		return err
+	}
+	if err := util.WriteInt64(w, p.Yes); err != nil {
+		return err
+	}
+	if err := util.WriteInt64(w, p.No); err != nil {
+		return err
+	}
+	if err := util.WriteByte(w, p.Status); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (p *Proposal) Read(r io.Reader) error {
+	var err error
+	if err = coretypes.ReadAgentID(r, &p.Proposer); err != nil {
+		return err
+	}
+	if p.Description, err = util.ReadString16(r); err != nil {
+		return err
+	}
+	if err = p.Target.Read(r); err != nil {
+		return err
+	}
+	if err = p.EntryPoint.Read(r); err != nil {
+		return err
+	}
+	p.Args = dict.New()
+	if err = p.Args.Read(r); err != nil {
+		return err
+	}
+	if err = util.ReadInt64(r, &p.Deadline); err != nil {
+		return err
+	}
+	if err = util.ReadInt64(r, &p.TotalWeight); err != nil {
+		return err
+	}
+	if err = util.ReadInt64(r, &p.Yes); err != nil {
+		return err
+	}
+	if err = util.ReadInt64(r, &p.No); err != nil {
+		return err
+	}
+	if p.Status, err = util.ReadByte(r); err != nil {
+		return err
+	}
+	return nil
+}
+
+func EncodeProposal(p *Proposal) []byte {
+	return util.MustBytes(p)
+}
+
+func DecodeProposal(data []byte) (*Proposal, error) {
+	ret := new(Proposal)
+	err := ret.Read(bytes.NewReader(data))
+	return ret, err
+}
@@ -9,12 +9,14 @@ package root
 import (
 	"fmt"
 	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/kv"
 	"github.com/iotaledger/wasp/packages/kv/codec"
 	"github.com/iotaledger/wasp/packages/kv/collections"
 	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/iotaledger/wasp/packages/vm/core/accounts"
 	"github.com/iotaledger/wasp/packages/vm/core/blob"
 	"github.com/iotaledger/wasp/packages/vm/core/eventlog"
+	"github.com/iotaledger/wasp/packages/vm/core/governance"
 	"github.com/iotaledger/wasp/packages/vm/vmtypes"
 )
 
@@ -23,7 +25,7 @@ import (
 // - stores chain ID and chain description in the state
 // - sets state ownership to the caller
 // - creates record in the registry for the 'root' itself
-// - deploys other core contracts: 'accountsc', 'blob' by creating records in the registry and calling constructors
+// - deploys other core contracts: 'accountsc', 'blob', 'eventlog', 'governance' by creating records in the registry and calling constructors
 // Input:
 // - ParamChainID coretypes.ChainID. ID of the chain. Cannot be changed
 // - ParamDescription string defaults to "N/A"
@@ -92,6 +94,12 @@ func initialize(ctx vmtypes.Sandbox) (dict.Dict, error) {
 	if err != nil {
 		ctx.Log().Panicf("root.init.fail: %v", err)
 	}
+	// deploy governance
+	rec = NewContractRecord(governance.Interface, ctx.Caller())
+	err = storeAndInitContract(ctx, &rec, nil)
+	if err != nil {
+		ctx.Log().Panicf("root.init.fail: %v", err)
+	}
 	state.Set(VarStateInitialized, []byte{0xFF})
 	state.Set(VarChainID, codec.EncodeChainID(chainID))
 	state.Set(VarChainColor, codec.EncodeColor(chainColor))
@@ -113,6 +121,7 @@ func initialize(ctx vmtypes.Sandbox) (dict.Dict, error) {
 	ctx.Log().Debugf("root.initialize.deployed: '%s', hname = %s", blob.Interface.Name, blob.Interface.Hname().String())
 	ctx.Log().Debugf("root.initialize.deployed: '%s', hname = %s", accounts.Interface.Name, accounts.Interface.Hname().String())
 	ctx.Log().Debugf("root.initialize.deployed: '%s', hname = %s", eventlog.Interface.Name, eventlog.Interface.Hname().String())
+	ctx.Log().Debugf("root.initialize.deployed: '%s', hname = %s", governance.Interface.Name, governance.Interface.Hname().String())
 	ctx.Log().Debugf("root.initialize.success")
 	return nil, nil
 }
@@ -125,6 +134,7 @@ func initialize(ctx vmtypes.Sandbox) (dict.Dict, error) {
 // - ParamProgramHash HashValue is a hash of the blob which represents program binary in the 'blob' contract.
 //     In case of hardcoded examples its an arbitrary unique hash set in the global call examples.AddProcessor
 // - ParamDescription string is an arbitrary string. Defaults to "N/A"
+// - ParamABI []byte optional JSON encoded ABI of the program. Defaults to the ABI stored in the program blob, if any
 func deployContract(ctx vmtypes.Sandbox) (dict.Dict, error) {
 	ctx.Log().Debugf("root.deployContract.begin")
 	if !isAuthorizedToDeploy(ctx) {
@@ -155,10 +165,14 @@ func deployContract(ctx vmtypes.Sandbox) (dict.Dict, error) {
 	// pass to init function all params not consumed so far
 	initParams := dict.New()
 	for key, value := range params {
-		if key != ParamProgramHash && key != ParamName && key != ParamDescription {
+		if key != ParamProgramHash && key != ParamName && key != ParamDescription && key != ParamABI {
 			initParams.Set(key, value)
 		}
 	}
+	abi, err := getProgramABI(ctx, *proghash)
+	if err != nil {
+		return nil, fmt.Errorf("root.deployContract.fail: %v", err)
+	}
 	// calls to loads VM from binary to check if it loads successfully
 	err = ctx.DeployContract(*proghash, "", "", nil)
 	if err != nil {
@@ -174,12 +188,119 @@ func deployContract(ctx vmtypes.Sandbox) (dict.Dict, error) {
 	if err != nil {
 		return nil, fmt.Errorf("root.deployContract.fail: %v", err)
 	}
+	setContractABI(ctx.State(), coretypes.Hn(name), abi)
 
 	ctx.Event(fmt.Sprintf("[deploy] name: %s hname: %s, progHash: %s, dscr: '%s'",
 		name, coretypes.Hn(name), proghash.String(), description))
 	return nil, nil
 }
 
+// upgradeContract replaces the program of the deployed contract. The hname, the state, the balances and
+// the fees of the contract are kept. Then calls the 'migrate' entry point of the new program, if it exists.
+// If the migration fails, the upgrade is reverted.
+// Only the chain owner or the creator of the contract are allowed to upgrade it. Core contracts can't be upgraded
+// Inputs:
+// - ParamHname Hname of the contract
+// - ParamProgramHash HashValue of the new program
+// - ParamDescription string new description. Defaults to the current one
+// - ParamABI []byte optional JSON encoded ABI of the new program. Defaults to the ABI stored in the program blob, if any
+// All other parameters are passed to the 'migrate' entry point
+func upgradeContract(ctx vmtypes.Sandbox) (dict.Dict, error) {
+	ctx.Log().Debugf("root.upgradeContract.begin")
+	params := ctx.Params()
+	hname, ok, err := codec.DecodeHname(params.MustGet(ParamHname))
+	if err != nil {
+		return nil, fmt.Errorf("root.upgradeContract.wrong.param %s: %v", ParamHname, err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("root.upgradeContract.error: hname undefined")
+	}
+	if isCoreContract(hname) {
+		return nil, fmt.Errorf("root.upgradeContract.fail: core contract %s can't be upgraded", hname.String())
+	}
+	proghash, ok, err := codec.DecodeHashValue(params.MustGet(ParamProgramHash))
+	if err != nil {
+		return nil, fmt.Errorf("root.upgradeContract.wrong.param %s: %v", ParamProgramHash, err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("root.upgradeContract.error: ProgramHash undefined")
+	}
+	description, descriptionSet, err := codec.DecodeString(params.MustGet(ParamDescription))
+	if err != nil {
+		return nil, fmt.Errorf("root.upgradeContract.wrong.param %s: %v", ParamDescription, err)
+	}
+	rec, err := FindContract(ctx.State(), hname)
+	if err != nil {
+		return nil, fmt.Errorf("root.upgradeContract.fail: %v", err)
+	}
+	if !isAuthorizedToUpgrade(ctx, rec) {
+		return nil, fmt.Errorf("root.upgradeContract: not permitted")
+	}
+	if rec.ProgramHash == *proghash {
+		return nil, fmt.Errorf("root.upgradeContract.fail: contract already runs program %s", proghash.String())
+	}
+	// pass to migrate function all params not consumed so far
+	migrateParams := dict.New()
+	for key, value := range params {
+		if key != ParamHname && key != ParamProgramHash && key != ParamDescription && key != ParamABI {
+			migrateParams.Set(key, value)
+		}
+	}
+	abi, err := getProgramABI(ctx, *proghash)
+	if err != nil {
+		return nil, fmt.Errorf("root.upgradeContract.fail: %v", err)
+	}
+	// calls to loads VM from binary to check if it loads successfully
+	err = ctx.DeployContract(*proghash, "", "", nil)
+	if err != nil {
+		return nil, fmt.Errorf("root.upgradeContract.fail: %v", err)
+	}
+	oldProghash := rec.ProgramHash
+	contractRegistry := collections.NewMap(ctx.State(), VarContractRegistry)
+	oldRecBin := EncodeContractRecord(rec)
+	rec.ProgramHash = *proghash
+	if descriptionSet {
+		rec.Description = description
+	}
+	contractRegistry.MustSetAt(hname.Bytes(), EncodeContractRecord(rec))
+
+	_, err = ctx.Call(hname, coretypes.EntryPointMigrate, migrateParams, nil)
+	if err != nil && err != vmtypes.ErrEntryPointNotFound {
+		// migration failed: restore the record
+		contractRegistry.MustSetAt(hname.Bytes(), oldRecBin)
+		return nil, fmt.Errorf("root.upgradeContract.fail: contract '%s'/%s: calling 'migrate': %v", rec.Name, hname.String(), err)
+	}
+	setContractABI(ctx.State(), hname, abi)
+	getContractHistoryArray(ctx.State(), hname).MustPush(EncodeContractUpgrade(&ContractUpgrade{
+		From:       oldProghash,
+		To:         *proghash,
+		Timestamp:  ctx.GetTimestamp(),
+		UpgradedBy: ctx.Caller(),
+	}))
+	ctx.Event(fmt.Sprintf("[upgrade] name: %s hname: %s, progHash: %s -> %s",
+		rec.Name, hname.String(), oldProghash.String(), proghash.String()))
+	return nil, nil
+}
+
+// getContractHistory view returns the upgrades of the contract
+// Input:
+// - ParamHname
+// Output:
+// - VarContractHistory: an array of encoded ContractUpgrade, the oldest first
+func getContractHistory(ctx vmtypes.SandboxView) (dict.Dict, error) {
+	hname, ok, err := codec.DecodeHname(ctx.Params().MustGet(ParamHname))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("parameter 'hname' undefined")
+	}
+	ret := dict.New()
+	src := collections.NewArrayReadOnly(ctx.State(), VarContractHistory+string(hname.Bytes()))
+	collections.NewArray(ret, VarContractHistory).MustExtend(src)
+	return ret, nil
+}
+
 // findContract view finds and returns encoded record of the contract
 // Input:
 // - ParamHname
@@ -204,6 +325,31 @@ func findContract(ctx vmtypes.SandboxView) (dict.Dict, error) {
 	return ret, nil
 }
 
+// getContractABI view returns the ABI of the contract. Returns empty result if the ABI of the contract is not known
+// Input:
+// - ParamHname
+// Output:
+// - ParamData: JSON encoded contract.ABI
+func getContractABI(ctx vmtypes.SandboxView) (dict.Dict, error) {
+	hname, ok, err := codec.DecodeHname(ctx.Params().MustGet(ParamHname))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("parameter 'hname' undefined")
+	}
+	if _, err := FindContract(ctx.State(), hname); err != nil {
+		return nil, err
+	}
+	abi := GetContractABI(ctx.State(), hname)
+	if abi == nil {
+		return nil, nil
+	}
+	ret := dict.New()
+	ret.Set(ParamData, abi)
+	return ret, nil
+}
+
 // getChainInfo view returns general info about the chain: chain ID, chain owner ID,
 // description and the whole contract registry
 // Input: none
@@ -303,6 +449,7 @@ func getFeeInfo(ctx vmtypes.SandboxView) (dict.Dict, error) {
 	ret.Set(ParamFeeColor, codec.EncodeColor(feeColor))
 	ret.Set(ParamOwnerFee, codec.EncodeInt64(ownerFee))
 	ret.Set(ParamValidatorFee, codec.EncodeInt64(validatorFee))
+	ret.Set(ParamGasPerToken, codec.EncodeInt64(GetGasPerToken(ctx.State())))
 	return ret, nil
 }
 
@@ -310,6 +457,7 @@ func getFeeInfo(ctx vmtypes.SandboxView) (dict.Dict, error) {
 // Input:
 // - ParamOwnerFee int64 non-negative value of the owner fee. May be skipped, then it is not set
 // - ParamValidatorFee int64 non-negative value of the contract fee. May be skipped, then it is not set
+// - ParamGasPerToken int64 non-negative amount of gas one fee token buys. 0 means gas is free. May be skipped
 func setDefaultFee(ctx vmtypes.Sandbox) (dict.Dict, error) {
 	if !CheckAuthorizationByChainOwner(ctx.State(), ctx.Caller()) {
 		return nil, fmt.Errorf("root.setDefaultFee: not authorized")
@@ -328,7 +476,14 @@ func setDefaultFee(ctx vmtypes.Sandbox) (dict.Dict, error) {
 	if validatorFeeOk && validatorFee < 0 {
 		return nil, fmt.Errorf("parameter 'validator fee' is invalid")
 	}
-	if !ownerFeeOk && !validatorFeeOk {
+	gasPerToken, gasPerTokenOk, err := codec.DecodeInt64(ctx.Params().MustGet(ParamGasPerToken))
+	if err != nil {
+		return nil, err
+	}
+	if gasPerTokenOk && gasPerToken < 0 {
+		return nil, fmt.Errorf("parameter 'gas per token' is invalid")
+	}
+	if !ownerFeeOk && !validatorFeeOk && !gasPerTokenOk {
 		return nil, fmt.Errorf("missing parameters")
 	}
 	if ownerFeeOk {
@@ -345,9 +500,72 @@ func setDefaultFee(ctx vmtypes.Sandbox) (dict.Dict, error) {
 			ctx.State().Del(VarDefaultValidatorFee)
 		}
 	}
+	if gasPerTokenOk {
+		if gasPerToken > 0 {
+			ctx.State().Set(VarGasPerToken, codec.EncodeInt64(gasPerToken))
+		} else {
+			ctx.State().Del(VarGasPerToken)
+		}
+	}
 	return nil, nil
 }
 
+// setBlockLimits sets the limits of blocks of the chain. Only the chain owner can call it
+// Input:
+// - ParamMaxBlockRequests int64 maximum number of requests in the block. May be skipped, then it is not set
+// - ParamMaxBlockMutationSize int64 maximum total size of state mutations in the block. May be skipped, then it is not set
+// - ParamMaxRequestsPerSender int64 maximum number of requests of one sender in the block. May be skipped, then it is not set
+// 0 removes the limit
+func setBlockLimits(ctx vmtypes.Sandbox) (dict.Dict, error) {
+	if !CheckAuthorizationByChainOwner(ctx.State(), ctx.Caller()) {
+		return nil, fmt.Errorf("root.setBlockLimits: not authorized")
+	}
+	limits := []struct {
+		param string
+		key   kv.Key
+	}{
+		{ParamMaxBlockRequests, VarMaxBlockRequests},
+		{ParamMaxBlockMutationSize, VarMaxBlockMutationSize},
+		{ParamMaxRequestsPerSender, VarMaxRequestsPerSender},
+	}
+	values := make([]int64, len(limits))
+	set := make([]bool, len(limits))
+	anySet := false
+	for i, l := range limits {
+		var err error
+		if values[i], set[i], err = codec.DecodeInt64(ctx.Params().MustGet(kv.Key(l.param))); err != nil {
+			return nil, err
+		}
+		if set[i] && values[i] < 0 {
+			return nil, fmt.Errorf("parameter '%s' is invalid", l.param)
+		}
+		anySet = anySet || set[i]
+	}
+	if !anySet {
+		return nil, fmt.Errorf("missing parameters")
+	}
+	for i, l := range limits {
+		switch {
+		case !set[i]:
+		case values[i] > 0:
+			ctx.State().Set(l.key, codec.EncodeInt64(values[i]))
+		default:
+			ctx.State().Del(l.key)
+		}
+	}
+	return nil, nil
+}
+
+// getBlockLimits returns the limits of blocks. 0 means no limit
+func getBlockLimits(ctx vmtypes.SandboxView) (dict.Dict, error) {
+	limits := GetBlockLimits(ctx.State())
+	ret := dict.New()
+	ret.Set(ParamMaxBlockRequests, codec.EncodeInt64(limits.MaxRequests))
+	ret.Set(ParamMaxBlockMutationSize, codec.EncodeInt64(limits.MaxMutationSize))
+	ret.Set(ParamMaxRequestsPerSender, codec.EncodeInt64(limits.MaxRequestsPerSender))
+	return ret, nil
+}
+
 // setContractFee sets fee for the particular smart contract
 // Input:
 // - ParamHname coretypes.Hname smart contract ID
@@ -427,3 +645,119 @@ func revokeDeployPermission(ctx vmtypes.Sandbox) (dict.Dict, error) {
 	collections.NewMap(ctx.State(), VarDeployAuthorisations).MustDelAt(deployer.Bytes())
 	return nil, nil
 }
+
+// getCrossChainRequests view returns requests posted with the callback, for which the receipt has not arrived yet
+// Input:
+// - ParamHname - optional, hname of the sender contract. Default is all contracts
+// Output:
+// - VarCrossChainRequests: a map nonce -> encoded CrossChainRequest
+func getCrossChainRequests(ctx vmtypes.SandboxView) (dict.Dict, error) {
+	hname, filter, err := codec.DecodeHname(ctx.Params().MustGet(ParamHname))
+	if err != nil {
+		return nil, err
+	}
+	ret := dict.New()
+	src := collections.NewMapReadOnly(ctx.State(), VarCrossChainRequests)
+	dst := collections.NewMap(ret, VarCrossChainRequests)
+	src.MustIterate(func(elemKey []byte, value []byte) bool {
+		if filter {
+			rec, err := DecodeCrossChainRequest(value)
+			if err != nil || rec.Sender != hname {
+				return true
+			}
+		}
+		dst.MustSetAt(elemKey, value)
+		return true
+	})
+	return ret, nil
+}
+
+// rotateCommittee hands the chain over to the committee which controls the new chain address.
+// The anchor transaction of the block moves the chain token and all funds of the chain to the new address,
+// after which the new committee continues the chain. Only the chain owner can rotate the committee.
+// The new address is announced to the chains in the directory of chain addresses with 'setChainAddress'
+// requests, sent by the previous committee in the same anchor transaction. The request tokens are paid from
+// the account of the root contract, i.e. from the tokens transferred with the request
+// Input:
+// - ParamChainAddress address.Address address of the new committee, the one produced by the DKG
+func rotateCommittee(ctx vmtypes.Sandbox) (dict.Dict, error) {
+	if !CheckAuthorizationByChainOwner(ctx.State(), ctx.Caller()) {
+		return nil, fmt.Errorf("root.rotateCommittee: not authorized")
+	}
+	newAddress, ok, err := codec.DecodeAddress(ctx.Params().MustGet(ParamChainAddress))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("parameter 'chain address' undefined")
+	}
+	prevAddress, _, _ := codec.DecodeAddress(ctx.State().MustGet(VarChainAddress))
+	if newAddress == prevAddress {
+		return nil, fmt.Errorf("root.rotateCommittee: the chain is already controlled by %s", newAddress.String())
+	}
+	ctx.State().Set(VarChainAddress, codec.EncodeAddress(newAddress))
+	ctx.Event(fmt.Sprintf("[rotate] address: %s -> %s", prevAddress.String(), newAddress.String()))
+
+	chainID := ctx.ContractID().ChainID()
+	for _, target := range GetKnownChains(ctx.State()) {
+		announced := ctx.PostRequest(vmtypes.PostRequestParams{
+			TargetContractID: coretypes.NewContractID(target, Interface.Hname()),
+			EntryPoint:       coretypes.Hn(FuncSetChainAddress),
+			Params: codec.MakeDict(map[string]interface{}{
+				ParamChainID:      chainID,
+				ParamChainAddress: newAddress,
+			}),
+		})
+		if !announced {
+			ctx.Event(fmt.Sprintf("[rotate] not enough funds to announce the address to the chain %s", target.String()))
+		}
+	}
+	return nil, nil
+}
+
+// setChainAddress sets the current address of another chain in the directory of chain addresses.
+// The requests to the chain are sent to that address. The address is set by the root contract of the chain itself,
+// which announces the rotation of its committee, or by the chain owner
+// Input:
+// - ParamChainID coretypes.ChainID the chain
+// - ParamChainAddress address.Address the current address of the chain
+func setChainAddress(ctx vmtypes.Sandbox) (dict.Dict, error) {
+	chainID, ok, err := codec.DecodeChainID(ctx.Params().MustGet(ParamChainID))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("parameter 'chain ID' undefined")
+	}
+	chainAddress, ok, err := codec.DecodeAddress(ctx.Params().MustGet(ParamChainAddress))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("parameter 'chain address' undefined")
+	}
+	if chainID == ctx.ContractID().ChainID() {
+		return nil, fmt.Errorf("root.setChainAddress: the address of the own chain is set by rotateCommittee")
+	}
+	announcer := coretypes.NewAgentIDFromContractID(coretypes.NewContractID(chainID, Interface.Hname()))
+	if ctx.Caller() != announcer && !CheckAuthorizationByChainOwner(ctx.State(), ctx.Caller()) {
+		return nil, fmt.Errorf("root.setChainAddress: not authorized")
+	}
+	collections.NewMap(ctx.State(), VarChainAddresses).MustSetAt(chainID[:], codec.EncodeAddress(chainAddress))
+	ctx.Event(fmt.Sprintf("[setChainAddress] chain %s: %s", chainID.String(), chainAddress.String()))
+	return nil, nil
+}
+
+// getChainAddresses view returns the directory of chain addresses: the current addresses of other chains,
+// which the chain exchanged requests with
+// Output:
+// - VarChainAddresses: a map chain ID -> address
+func getChainAddresses(ctx vmtypes.SandboxView) (dict.Dict, error) {
+	ret := dict.New()
+	dst := collections.NewMap(ret, VarChainAddresses)
+	collections.NewMapReadOnly(ctx.State(), VarChainAddresses).MustIterate(func(elemKey []byte, value []byte) bool {
+		dst.MustSetAt(elemKey, value)
+		return true
+	})
+	return ret, nil
+}
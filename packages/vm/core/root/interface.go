@@ -5,6 +5,7 @@ import (
 	"errors"
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	valuetransaction "github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
 	"github.com/iotaledger/wasp/packages/coretypes"
 	"io"
 
@@ -30,16 +31,87 @@ var (
 
 func init() {
 	Interface.WithFunctions(initialize, []contract.ContractFunctionInterface{
-		contract.Func(FuncDeployContract, deployContract),
-		contract.ViewFunc(FuncFindContract, findContract),
+		contract.Func(FuncDeployContract, deployContract).
+			WithParams(
+				contract.Field(ParamName, contract.TypeString),
+				contract.Field(ParamProgramHash, contract.TypeHash),
+				contract.Field(ParamDescription, contract.TypeString),
+				contract.Field(ParamABI, contract.TypeBytes),
+			),
+		contract.Func(FuncUpgradeContract, upgradeContract).
+			WithParams(
+				contract.Field(ParamHname, contract.TypeHname),
+				contract.Field(ParamProgramHash, contract.TypeHash),
+				contract.Field(ParamDescription, contract.TypeString),
+				contract.Field(ParamABI, contract.TypeBytes),
+			),
+		contract.ViewFunc(FuncGetContractHistory, getContractHistory).
+			WithParams(contract.Field(ParamHname, contract.TypeHname)),
+		contract.ViewFunc(FuncFindContract, findContract).
+			WithParams(contract.Field(ParamHname, contract.TypeHname)).
+			WithResults(contract.Field(ParamData, contract.TypeBytes)),
 		contract.Func(FuncClaimChainOwnership, claimChainOwnership),
-		contract.Func(FuncDelegateChainOwnership, delegateChainOwnership),
-		contract.ViewFunc(FuncGetChainInfo, getChainInfo),
-		contract.ViewFunc(FuncGetFeeInfo, getFeeInfo),
-		contract.Func(FuncSetDefaultFee, setDefaultFee),
-		contract.Func(FuncSetContractFee, setContractFee),
-		contract.Func(FuncGrantDeploy, grantDeployPermission),
-		contract.Func(FuncRevokeDeploy, revokeDeployPermission),
+		contract.Func(FuncDelegateChainOwnership, delegateChainOwnership).
+			WithParams(contract.Field(ParamChainOwner, contract.TypeAgentID)),
+		contract.ViewFunc(FuncGetChainInfo, getChainInfo).
+			WithResults(
+				contract.Field(VarChainID, contract.TypeChainID),
+				contract.Field(VarChainOwnerID, contract.TypeAgentID),
+				contract.Field(VarChainColor, contract.TypeColor),
+				contract.Field(VarChainAddress, contract.TypeAddress),
+				contract.Field(VarDescription, contract.TypeString),
+				contract.Field(VarFeeColor, contract.TypeColor),
+				contract.Field(VarDefaultOwnerFee, contract.TypeInt64),
+				contract.Field(VarDefaultValidatorFee, contract.TypeInt64),
+			),
+		contract.ViewFunc(FuncGetFeeInfo, getFeeInfo).
+			WithParams(contract.Field(ParamHname, contract.TypeHname)).
+			WithResults(
+				contract.Field(ParamFeeColor, contract.TypeColor),
+				contract.Field(ParamOwnerFee, contract.TypeInt64),
+				contract.Field(ParamValidatorFee, contract.TypeInt64),
+				contract.Field(ParamGasPerToken, contract.TypeInt64),
+			),
+		contract.Func(FuncSetDefaultFee, setDefaultFee).
+			WithParams(
+				contract.Field(ParamOwnerFee, contract.TypeInt64),
+				contract.Field(ParamValidatorFee, contract.TypeInt64),
+				contract.Field(ParamGasPerToken, contract.TypeInt64),
+			),
+		contract.Func(FuncSetContractFee, setContractFee).
+			WithParams(
+				contract.Field(ParamHname, contract.TypeHname),
+				contract.Field(ParamOwnerFee, contract.TypeInt64),
+				contract.Field(ParamValidatorFee, contract.TypeInt64),
+			),
+		contract.Func(FuncGrantDeploy, grantDeployPermission).
+			WithParams(contract.Field(ParamDeployer, contract.TypeAgentID)),
+		contract.Func(FuncRevokeDeploy, revokeDeployPermission).
+			WithParams(contract.Field(ParamDeployer, contract.TypeAgentID)),
+		contract.ViewFunc(FuncGetCrossChainRequests, getCrossChainRequests),
+		contract.Func(FuncRotateCommittee, rotateCommittee).
+			WithParams(contract.Field(ParamChainAddress, contract.TypeAddress)),
+		contract.Func(FuncSetChainAddress, setChainAddress).
+			WithParams(
+				contract.Field(ParamChainID, contract.TypeChainID),
+				contract.Field(ParamChainAddress, contract.TypeAddress),
+			),
+		contract.ViewFunc(FuncGetChainAddresses, getChainAddresses),
+		contract.ViewFunc(FuncGetContractABI, getContractABI).
+			WithParams(contract.Field(ParamHname, contract.TypeHname)).
+			WithResults(contract.Field(ParamData, contract.TypeBytes)),
+		contract.Func(FuncSetBlockLimits, setBlockLimits).
+			WithParams(
+				contract.Field(ParamMaxBlockRequests, contract.TypeInt64),
+				contract.Field(ParamMaxBlockMutationSize, contract.TypeInt64),
+				contract.Field(ParamMaxRequestsPerSender, contract.TypeInt64),
+			),
+		contract.ViewFunc(FuncGetBlockLimits, getBlockLimits).
+			WithResults(
+				contract.Field(ParamMaxBlockRequests, contract.TypeInt64),
+				contract.Field(ParamMaxBlockMutationSize, contract.TypeInt64),
+				contract.Field(ParamMaxRequestsPerSender, contract.TypeInt64),
+			),
 	})
 }
 
@@ -53,10 +125,20 @@ const (
 	VarFeeColor              = "f"
 	VarDefaultOwnerFee       = "do"
 	VarDefaultValidatorFee   = "dv"
+	VarGasPerToken           = "g"
 	VarChainOwnerIDDelegated = "n"
 	VarContractRegistry      = "r"
 	VarDescription           = "d"
 	VarDeployAuthorisations  = "dep"
+	VarCrossChainRequests    = "x"
+	VarCrossChainNonce       = "xn"
+	VarCrossChainUnresolved  = "xu"
+	VarChainAddresses        = "ca"
+	VarContractHistory       = "h"
+	VarContractABI           = "abi"
+	VarMaxBlockRequests      = "mr"
+	VarMaxBlockMutationSize  = "ms"
+	VarMaxRequestsPerSender  = "mp"
 )
 
 // param variables
@@ -73,12 +155,28 @@ const (
 	ParamFeeColor     = "$$feecolor$$"
 	ParamOwnerFee     = "$$ownerfee$$"
 	ParamValidatorFee = "$$validatorfee$$"
+	ParamGasPerToken  = "$$gaspertoken$$"
 	ParamDeployer     = "$$deployer$$"
+	ParamABI          = "$$abi$$"
+
+	ParamMaxBlockRequests     = "$$maxblockrequests$$"
+	ParamMaxBlockMutationSize = "$$maxblockmutationsize$$"
+	ParamMaxRequestsPerSender = "$$maxrequestspersender$$"
+)
+
+// parameters of the receipt, the request posted back to the callback entry point of the sender contract.
+// Other parameters of the receipt are the result of the call
+const (
+	ParamReceiptRequestID = "$$receipt.reqid$$"
+	ParamReceiptNonce     = "$$receipt.nonce$$"
+	ParamReceiptError     = "$$receipt.error$$"
 )
 
 // function names
 const (
 	FuncDeployContract         = "deployContract"
+	FuncUpgradeContract        = "upgradeContract"
+	FuncGetContractHistory     = "getContractHistory"
 	FuncFindContract           = "findContract"
 	FuncGetChainInfo           = "getChainInfo"
 	FuncDelegateChainOwnership = "delegateChainOwnership"
@@ -88,6 +186,13 @@ const (
 	FuncSetContractFee         = "setContractFee"
 	FuncGrantDeploy            = "grantDeployPermission"
 	FuncRevokeDeploy           = "revokeDeployPermission"
+	FuncGetCrossChainRequests  = "getCrossChainRequests"
+	FuncRotateCommittee        = "rotateCommittee"
+	FuncSetChainAddress        = "setChainAddress"
+	FuncGetChainAddresses      = "getChainAddresses"
+	FuncGetContractABI         = "getContractABI"
+	FuncSetBlockLimits         = "setBlockLimits"
+	FuncGetBlockLimits         = "getBlockLimits"
 )
 
 // ContractRecord is a structure which contains metadata of the deployed contract instance
@@ -113,6 +218,17 @@ type ContractRecord struct {
 	Creator coretypes.AgentID
 }
 
+// BlockLimits are the limits of one block set by the chain owner. 0 means no limit
+type BlockLimits struct {
+	// maximum number of requests in the block
+	MaxRequests int64
+	// maximum total size of keys and values mutated by the requests of the block.
+	// The request which exceeds the limit is still included, the rest is left in the backlog
+	MaxMutationSize int64
+	// maximum number of requests of the same sender in the block, so that one sender can't starve others
+	MaxRequestsPerSender int64
+}
+
 // ChainInfo is an API structure which contains main properties of the chain in on place
 type ChainInfo struct {
 	ChainID             coretypes.ChainID
@@ -125,6 +241,39 @@ type ChainInfo struct {
 	DefaultValidatorFee int64
 }
 
+// ContractUpgrade is the record of the contract version history. It is stored with each upgrade of the contract
+type ContractUpgrade struct {
+	// program hash before the upgrade
+	From hashing.HashValue
+	// program hash after the upgrade
+	To hashing.HashValue
+	// timestamp of the upgrade
+	Timestamp int64
+	// agentID of the caller of upgradeContract
+	UpgradedBy coretypes.AgentID
+}
+
+// CrossChainRequest is the record of the request posted with the callback, waiting for the receipt
+type CrossChainRequest struct {
+	// Hname of the contract which posted the request
+	Sender coretypes.Hname
+	// The target of the request
+	Target     coretypes.ContractID
+	EntryPoint coretypes.Hname
+	// The entry point of the sender which receives the receipt
+	Callback coretypes.Hname
+	// Timestamp of the request
+	Timestamp int64
+	// RequestID of the posted request. The index is known when the request is posted, the transaction ID
+	// only when the state transaction of the block is confirmed, until then it is all zeros
+	RequestID coretypes.RequestID
+}
+
+// Resolved returns true if the ID of the state transaction which posted the request is known
+func (p *CrossChainRequest) Resolved() bool {
+	return *p.RequestID.TransactionID() != valuetransaction.ID{}
+}
+
 func (p *ContractRecord) Hname() coretypes.Hname {
 	return coretypes.Hn(p.Name)
 }
@@ -198,3 +347,101 @@ func NewContractRecord(itf *contract.ContractInterface, creator coretypes.AgentI
 func (p *ContractRecord) HasCreator() bool {
 	return p.Creator != coretypes.AgentID{}
 }
+
+// serde
+func (p *CrossChainRequest) Write(w io.Writer) error {
+	if err := p.Sender.Write(w); err != nil {
+		return err
+	}
+	if err := p.Target.Write(w); err != nil {
+		return err
+	}
+	if err := p.EntryPoint.Write(w); err != nil {
+		return err
+	}
+	if err := p.Callback.Write(w); err != nil {
+		return err
+	}
+	if err := util.WriteInt64(w, p.Timestamp); err != nil {
+		return err
+	}
+	if _, err := w.Write(p.RequestID[:]); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (p *CrossChainRequest) Read(r io.Reader) error {
+	if err := p.Sender.Read(r); err != nil {
+		return err
+	}
+	if err := p.Target.Read(r); err != nil {
+		return err
+	}
+	if err := p.EntryPoint.Read(r); err != nil {
+		return err
+	}
+	if err := p.Callback.Read(r); err != nil {
+		return err
+	}
+	if err := util.ReadInt64(r, &p.Timestamp); err != nil {
+		return err
+	}
+	if err := p.RequestID.Read(r); err != nil {
+		return err
+	}
+	return nil
+}
+
+func EncodeCrossChainRequest(p *CrossChainRequest) []byte {
+	return util.MustBytes(p)
+}
+
+func DecodeCrossChainRequest(data []byte) (*CrossChainRequest, error) {
+	ret := new(CrossChainRequest)
+	err := ret.Read(bytes.NewReader(data))
+	return ret, err
+}
+
+// serde
+func (p *ContractUpgrade) Write(w io.Writer) error {
+	if _, err := w.Write(p.From[:]); err != nil {
+		return err
+	}
+	if _, err := w.Write(p.To[:]); err != nil {
+		return err
+	}
+	if err := util.WriteInt64(w, p.Timestamp); err != nil {
+		return err
+	}
+	if _, err := w.Write(p.UpgradedBy[:]); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (p *ContractUpgrade) Read(r io.Reader) error {
+	if err := util.ReadHashValue(r, &p.From); err != nil {
+		return err
+	}
+	if err := util.ReadHashValue(r, &p.To); err != nil {
+		return err
+	}
+	if err := util.ReadInt64(r, &p.Timestamp); err != nil {
+		return err
+	}
+	if err := coretypes.ReadAgentID(r, &p.UpgradedBy); err != nil {
+		return err
+	}
+	return nil
+}
+
+func EncodeContractUpgrade(p *ContractUpgrade) []byte {
+	return util.MustBytes(p)
+}
+
+func DecodeContractUpgrade(data []byte) (*ContractUpgrade, error) {
+	ret := new(ContractUpgrade)
+	err := ret.Read(bytes.NewReader(data))
+	return ret, err
+}
@@ -1,14 +1,26 @@
 package root
 
 import (
+	"bytes"
 	"fmt"
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	valuetransaction "github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
 	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/hashing"
 	"github.com/iotaledger/wasp/packages/kv"
 	"github.com/iotaledger/wasp/packages/kv/codec"
 	"github.com/iotaledger/wasp/packages/kv/collections"
 	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/kv/subrealm"
+	"github.com/iotaledger/wasp/packages/util"
+	"github.com/iotaledger/wasp/packages/vm/contract"
+	"github.com/iotaledger/wasp/packages/vm/core/accounts"
+	"github.com/iotaledger/wasp/packages/vm/core/blob"
+	"github.com/iotaledger/wasp/packages/vm/core/eventlog"
+	"github.com/iotaledger/wasp/packages/vm/core/governance"
 	"github.com/iotaledger/wasp/packages/vm/vmtypes"
+	"sort"
 )
 
 // FindContract is an internal utility function which finds a contract in the KVStore
@@ -83,6 +95,67 @@ func GetChainInfo(state kv.KVStoreReader) (*ChainInfo, error) {
 	return ret, nil
 }
 
+// GetChainAddress returns the current address of the chain, i.e. the address of the committee which controls the chain.
+// Unlike other functions, it takes the whole state of the chain, not the partition of the 'root' contract.
+// Returns false if the chain is not initialized yet
+func GetChainAddress(chainState kv.KVStore) (address.Address, bool, error) {
+	return codec.DecodeAddress(subrealm.New(chainState, kv.Key(Interface.Hname().Bytes())).MustGet(VarChainAddress))
+}
+
+// GetOtherChainAddress returns the current address of another chain, as known to the chain.
+// The address of the chain ID is returned for the chain which is not in the directory of chain addresses
+// or which didn't announce the rotation of its committee
+func GetOtherChainAddress(state kv.KVStoreReader, chainID coretypes.ChainID) address.Address {
+	addr, ok, err := codec.DecodeAddress(collections.NewMapReadOnly(state, VarChainAddresses).MustGetAt(chainID[:]))
+	if err != nil || !ok {
+		return address.Address(chainID)
+	}
+	return addr
+}
+
+// FindChainIDByAddress finds the chain which is controlled by the address in the directory of chain addresses.
+// The address is taken for the chain ID if it is not in the directory, i.e. the chain was never rotated
+func FindChainIDByAddress(state kv.KVStoreReader, addr address.Address) coretypes.ChainID {
+	ret := coretypes.ChainID(addr)
+	collections.NewMapReadOnly(state, VarChainAddresses).MustIterate(func(elemKey []byte, value []byte) bool {
+		if !bytes.Equal(value, addr[:]) {
+			return true
+		}
+		chainID, err := coretypes.NewChainIDFromBytes(elemKey)
+		if err != nil {
+			return true
+		}
+		ret = chainID
+		return false
+	})
+	return ret
+}
+
+// AddKnownChain puts the chain to the directory of chain addresses, unless it is already there.
+// The chain announces the rotation of its committee to the chains in the directory
+func AddKnownChain(state kv.KVStore, chainID coretypes.ChainID, addr address.Address) {
+	addresses := collections.NewMap(state, VarChainAddresses)
+	if addresses.MustHasAt(chainID[:]) {
+		return
+	}
+	addresses.MustSetAt(chainID[:], codec.EncodeAddress(addr))
+}
+
+// GetKnownChains returns the chains in the directory of chain addresses, sorted by the chain ID
+func GetKnownChains(state kv.KVStoreReader) []coretypes.ChainID {
+	ret := make([]coretypes.ChainID, 0)
+	collections.NewMapReadOnly(state, VarChainAddresses).MustIterateKeys(func(elemKey []byte) bool {
+		if chainID, err := coretypes.NewChainIDFromBytes(elemKey); err == nil {
+			ret = append(ret, chainID)
+		}
+		return true
+	})
+	sort.Slice(ret, func(i, j int) bool {
+		return bytes.Compare(ret[i][:], ret[j][:]) < 0
+	})
+	return ret
+}
+
 // GetFeeInfo is an internal utility function which returns fee info for the contract
 // It is called from within the 'root' contract as well as VMContext and viewcontext objects
 // It is not exposed to the sandbox
@@ -137,6 +210,31 @@ func GetDefaultFeeInfo(state kv.KVStoreReader) (balance.Color, int64, int64, err
 	return feeColor, defaultOwnerFee, defaultValidatorFee, nil
 }
 
+// GetBlockLimits returns the limits of the block set by the chain owner
+func GetBlockLimits(state kv.KVStoreReader) *BlockLimits {
+	ret := &BlockLimits{}
+	var err error
+	if ret.MaxRequests, _, err = codec.DecodeInt64(state.MustGet(VarMaxBlockRequests)); err != nil {
+		panic(err)
+	}
+	if ret.MaxMutationSize, _, err = codec.DecodeInt64(state.MustGet(VarMaxBlockMutationSize)); err != nil {
+		panic(err)
+	}
+	if ret.MaxRequestsPerSender, _, err = codec.DecodeInt64(state.MustGet(VarMaxRequestsPerSender)); err != nil {
+		panic(err)
+	}
+	return ret
+}
+
+// GetGasPerToken returns how much gas one fee token buys. 0 means gas is not charged
+func GetGasPerToken(state kv.KVStoreReader) int64 {
+	ret, _, err := codec.DecodeInt64(state.MustGet(VarGasPerToken))
+	if err != nil {
+		panic(err)
+	}
+	return ret
+}
+
 // DecodeContractRegistry encodes the whole contract registry from the map into a Go map.
 func DecodeContractRegistry(contractRegistry *collections.ImmutableMap) (map[coretypes.Hname]*ContractRecord, error) {
 	ret := make(map[coretypes.Hname]*ContractRecord)
@@ -185,6 +283,41 @@ func storeAndInitContract(ctx vmtypes.Sandbox, rec *ContractRecord, initParams d
 	return err
 }
 
+// isAuthorizedToUpgrade checks if caller is authorized to upgrade the contract: the chain owner or the creator
+func isAuthorizedToUpgrade(ctx vmtypes.Sandbox, rec *ContractRecord) bool {
+	if ctx.Caller() == ctx.ChainOwnerID() {
+		return true
+	}
+	return rec.HasCreator() && ctx.Caller() == rec.Creator
+}
+
+// isCoreContract checks if the contract is one of the core contracts, which can't be upgraded
+func isCoreContract(hname coretypes.Hname) bool {
+	switch hname {
+	case Interface.Hname(), accounts.Interface.Hname(), blob.Interface.Hname(), eventlog.Interface.Hname(), governance.Interface.Hname():
+		return true
+	}
+	return false
+}
+
+func getContractHistoryArray(state kv.KVStore, hname coretypes.Hname) *collections.Array {
+	return collections.NewArray(state, VarContractHistory+string(hname.Bytes()))
+}
+
+// GetContractHistory returns the upgrades of the contract, the oldest first
+func GetContractHistory(state kv.KVStoreReader, hname coretypes.Hname) ([]*ContractUpgrade, error) {
+	history := collections.NewArrayReadOnly(state, VarContractHistory+string(hname.Bytes()))
+	ret := make([]*ContractUpgrade, history.MustLen())
+	for i := range ret {
+		rec, err := DecodeContractUpgrade(history.MustGetAt(uint16(i)))
+		if err != nil {
+			return nil, err
+		}
+		ret[i] = rec
+	}
+	return ret, nil
+}
+
 // isAuthorizedToDeploy checks if caller is authorized to deploy smart contract
 func isAuthorizedToDeploy(ctx vmtypes.Sandbox) bool {
 	if ctx.Caller() == ctx.ChainOwnerID() {
@@ -197,3 +330,134 @@ func isAuthorizedToDeploy(ctx vmtypes.Sandbox) bool {
 	}
 	return collections.NewMap(ctx.State(), VarDeployAuthorisations).MustHasAt(ctx.Caller().Bytes())
 }
+
+// AddCrossChainRequest stores the record of the request posted with the callback.
+// The record is unresolved until ResolveCrossChainRequests sets the ID of the state transaction.
+// Returns the nonce which identifies the record when the receipt arrives
+func AddCrossChainRequest(state kv.KVStore, rec *CrossChainRequest) uint32 {
+	nonce, _, err := codec.DecodeInt64(state.MustGet(VarCrossChainNonce))
+	if err != nil {
+		panic(err)
+	}
+	nonce++
+	state.Set(VarCrossChainNonce, codec.EncodeInt64(nonce))
+	collections.NewMap(state, VarCrossChainRequests).MustSetAt(util.Uint32To4Bytes(uint32(nonce)), EncodeCrossChainRequest(rec))
+	collections.NewArray(state, VarCrossChainUnresolved).MustPush(util.Uint32To4Bytes(uint32(nonce)))
+	return uint32(nonce)
+}
+
+// ResolveCrossChainRequests sets the transaction ID of the requests posted in the previous block.
+// It must be called in the next block, when the state transaction of the previous block is known
+func ResolveCrossChainRequests(state kv.KVStore, stateTxID valuetransaction.ID) {
+	unresolved := collections.NewArray(state, VarCrossChainUnresolved)
+	n := unresolved.MustLen()
+	if n == 0 {
+		return
+	}
+	requests := collections.NewMap(state, VarCrossChainRequests)
+	for i := uint16(0); i < n; i++ {
+		key := unresolved.MustGetAt(i)
+		data := requests.MustGetAt(key)
+		if data == nil {
+			// the request was not posted
+			continue
+		}
+		rec, err := DecodeCrossChainRequest(data)
+		if err != nil {
+			panic(err)
+		}
+		rec.RequestID = coretypes.NewRequestID(stateTxID, rec.RequestID.Index())
+		requests.MustSetAt(key, EncodeCrossChainRequest(rec))
+	}
+	unresolved.MustErase()
+}
+
+// DeleteCrossChainRequest deletes the record of the request which was not posted
+func DeleteCrossChainRequest(state kv.KVStore, nonce uint32) {
+	collections.NewMap(state, VarCrossChainRequests).MustDelAt(util.Uint32To4Bytes(nonce))
+}
+
+// RemoveCrossChainRequest deletes the record when the receipt arrives. The receipt must be sent
+// by the target of the request to the callback of the sender and must refer to the ID of the posted request,
+// otherwise the record is not deleted and false is returned
+func RemoveCrossChainRequest(state kv.KVStore, nonce uint32, requestID coretypes.RequestID, receiptSender coretypes.ContractID, receiptTarget coretypes.Hname, receiptEntryPoint coretypes.Hname) bool {
+	requests := collections.NewMap(state, VarCrossChainRequests)
+	data := requests.MustGetAt(util.Uint32To4Bytes(nonce))
+	if data == nil {
+		return false
+	}
+	rec, err := DecodeCrossChainRequest(data)
+	if err != nil {
+		panic(err)
+	}
+	if rec.Target != receiptSender || rec.Sender != receiptTarget || rec.Callback != receiptEntryPoint {
+		return false
+	}
+	if !rec.Resolved() || rec.RequestID != requestID {
+		return false
+	}
+	requests.MustDelAt(util.Uint32To4Bytes(nonce))
+	return true
+}
+
+// DecodeCrossChainRequests decodes the outstanding cross-chain requests from the map into a Go map nonce -> record
+func DecodeCrossChainRequests(requests *collections.ImmutableMap) (map[uint32]*CrossChainRequest, error) {
+	ret := make(map[uint32]*CrossChainRequest)
+	var err error
+	requests.MustIterate(func(k []byte, v []byte) bool {
+		var nonce uint32
+		nonce, err = util.Uint32From4Bytes(k)
+		if err != nil {
+			return false
+		}
+		var rec *CrossChainRequest
+		rec, err = DecodeCrossChainRequest(v)
+		if err != nil {
+			return false
+		}
+		ret[nonce] = rec
+		return true
+	})
+	return ret, err
+}
+
+// GetContractABI returns the JSON encoded ABI of the contract or nil if it is not known.
+// ABIs of core contracts are not stored in the state, they are taken from the contract interfaces
+func GetContractABI(state kv.KVStoreReader, hname coretypes.Hname) []byte {
+	for _, itf := range []*contract.ContractInterface{Interface, blob.Interface, accounts.Interface, eventlog.Interface, governance.Interface} {
+		if itf.Hname() == hname {
+			return itf.ABI().Bytes()
+		}
+	}
+	return collections.NewMapReadOnly(state, VarContractABI).MustGetAt(hname.Bytes())
+}
+
+func setContractABI(state kv.KVStore, hname coretypes.Hname, abi []byte) {
+	abis := collections.NewMap(state, VarContractABI)
+	if abi == nil {
+		abis.MustDelAt(hname.Bytes())
+		return
+	}
+	abis.MustSetAt(hname.Bytes(), abi)
+}
+
+// getProgramABI returns the ABI of the program passed in the ParamABI or stored in the program blob.
+// Returns nil if the ABI is not available, for example for programs of builtin examples
+func getProgramABI(ctx vmtypes.Sandbox, progHash hashing.HashValue) ([]byte, error) {
+	abi := ctx.Params().MustGet(ParamABI)
+	if abi == nil {
+		res, err := ctx.Call(blob.Interface.Hname(), coretypes.Hn(blob.FuncGetBlobField), codec.MakeDict(map[string]interface{}{
+			blob.ParamHash:  progHash,
+			blob.ParamField: blob.VarFieldProgramABI,
+		}), nil)
+		if err != nil {
+			// no blob or the blob has no ABI
+			return nil, nil
+		}
+		abi = res.MustGet(blob.ParamBytes)
+	}
+	if _, err := contract.ABIFromBytes(abi); err != nil {
+		return nil, err
+	}
+	return abi, nil
+}
@@ -0,0 +1,100 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package testcore
+
+import (
+	"testing"
+
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/solo"
+	"github.com/iotaledger/wasp/packages/vm/contract"
+	"github.com/iotaledger/wasp/packages/vm/core/accounts"
+	"github.com/iotaledger/wasp/packages/vm/core/root"
+	"github.com/iotaledger/wasp/packages/vm/examples/inccounter"
+	"github.com/stretchr/testify/require"
+)
+
+func TestABICoreContracts(t *testing.T) {
+	glb := solo.New(t, false, false)
+	chain := glb.NewChain(nil, "chain1")
+	defer chain.WaitForEmptyBacklog()
+
+	abi, err := chain.GetContractABI(accounts.Interface.Name)
+	require.NoError(t, err)
+	require.NotNil(t, abi)
+	require.EqualValues(t, len(accounts.Interface.Functions), len(abi.Functions))
+
+	f, ok := abi.GetFunction(accounts.FuncBalance)
+	require.True(t, ok)
+	require.True(t, f.View)
+	p, ok := f.GetParam(accounts.ParamAgentID)
+	require.True(t, ok)
+	require.EqualValues(t, contract.TypeAgentID, p.Type)
+
+	f, ok = abi.GetFunction(accounts.FuncDeposit)
+	require.True(t, ok)
+	require.False(t, f.View)
+
+	_, err = chain.GetContractABI("nonexistent")
+	require.Error(t, err)
+}
+
+func TestABIDeployExample(t *testing.T) {
+	glb := solo.New(t, false, false)
+	chain := glb.NewChain(nil, "chain1")
+	defer chain.WaitForEmptyBacklog()
+
+	err := chain.DeployContract(nil, "testInc", inccounter.Interface.ProgramHash, inccounter.VarCounter, 17)
+	require.NoError(t, err)
+
+	abi, err := chain.GetContractABI("testInc")
+	require.NoError(t, err)
+	require.NotNil(t, abi)
+	f, ok := abi.GetFunction(inccounter.FuncGetCounter)
+	require.True(t, ok)
+	res, ok := f.GetResult(inccounter.VarCounter)
+	require.True(t, ok)
+
+	// the result of the view is decoded by the ABI
+	ret, err := chain.CallView("testInc", inccounter.FuncGetCounter)
+	require.NoError(t, err)
+	s, err := res.DecodeToString(ret.MustGet(inccounter.VarCounter))
+	require.NoError(t, err)
+	require.EqualValues(t, "17", s)
+
+	// the param is encoded by the ABI
+	f, ok = abi.GetFunction(inccounter.FuncIncCounter)
+	require.True(t, ok)
+	p, ok := f.GetParam(inccounter.VarCounter)
+	require.True(t, ok)
+	inc, err := p.EncodeFromString("3")
+	require.NoError(t, err)
+	req := solo.NewCall("testInc", inccounter.FuncIncCounter, inccounter.VarCounter, inc)
+	_, err = chain.PostRequest(req, nil)
+	require.NoError(t, err)
+	ret, err = chain.CallView("testInc", inccounter.FuncGetCounter)
+	require.NoError(t, err)
+	counter, _, _ := codec.DecodeInt64(ret.MustGet(inccounter.VarCounter))
+	require.EqualValues(t, 20, counter)
+}
+
+func TestABIExplicit(t *testing.T) {
+	glb := solo.New(t, false, false)
+	chain := glb.NewChain(nil, "chain1")
+	defer chain.WaitForEmptyBacklog()
+
+	err := chain.DeployContract(nil, "testInc", inccounter.Interface.ProgramHash, root.ParamABI, []byte("not an ABI"))
+	require.Error(t, err)
+
+	abi := &contract.ABI{Functions: []contract.FunctionABI{{
+		Name:   inccounter.FuncIncCounter,
+		Params: []contract.FieldABI{contract.Field(inccounter.VarCounter, contract.TypeInt64)},
+	}}}
+	err = chain.DeployContract(nil, "testInc", inccounter.Interface.ProgramHash, root.ParamABI, abi.Bytes())
+	require.NoError(t, err)
+
+	abiBack, err := chain.GetContractABI("testInc")
+	require.NoError(t, err)
+	require.EqualValues(t, abi.Bytes(), abiBack.Bytes())
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
 	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/kv/codec"
 	"github.com/iotaledger/wasp/packages/solo"
 	"github.com/iotaledger/wasp/packages/testutil"
 	"github.com/iotaledger/wasp/packages/vm/core/accounts"
@@ -95,3 +96,75 @@ func TestAccountsDepositWithdrawToChainFail(t *testing.T) {
 	chain.AssertAccountBalance(newOwnerAgentID, balance.ColorIOTA, 42+2)
 	glb.AssertAddressBalance(newOwner.Address(), balance.ColorIOTA, testutil.RequestFundsAmount-42-2)
 }
+
+func TestAccountsApproveTransferFrom(t *testing.T) {
+	glb := solo.New(t, false, false)
+	chain := glb.NewChain(nil, "chain1")
+
+	owner := glb.NewSignatureSchemeWithFunds()
+	ownerAgentID := coretypes.NewAgentIDFromAddress(owner.Address())
+	spender := glb.NewSignatureSchemeWithFunds()
+	spenderAgentID := coretypes.NewAgentIDFromAddress(spender.Address())
+	targetAgentID := coretypes.NewRandomAgentID()
+
+	req := solo.NewCall(accounts.Interface.Name, accounts.FuncDeposit).
+		WithTransfer(balance.ColorIOTA, 42)
+	_, err := chain.PostRequest(req, owner)
+	require.NoError(t, err)
+	chain.AssertAccountBalance(ownerAgentID, balance.ColorIOTA, 42+1)
+
+	req = solo.NewCall(accounts.Interface.Name, accounts.FuncApprove,
+		accounts.ParamSpender, spenderAgentID,
+		accounts.ParamColor, balance.ColorIOTA,
+		accounts.ParamAmount, 20,
+	)
+	_, err = chain.PostRequest(req, owner)
+	require.NoError(t, err)
+	checkAllowance(t, chain, ownerAgentID, spenderAgentID, 20)
+
+	req = solo.NewCall(accounts.Interface.Name, accounts.FuncTransferFrom,
+		accounts.ParamOwner, ownerAgentID,
+		accounts.ParamAgentID, targetAgentID,
+		accounts.ParamColor, balance.ColorIOTA,
+		accounts.ParamAmount, 15,
+	)
+	_, err = chain.PostRequest(req, spender)
+	require.NoError(t, err)
+	chain.AssertAccountBalance(ownerAgentID, balance.ColorIOTA, 42+2-15)
+	chain.AssertAccountBalance(targetAgentID, balance.ColorIOTA, 15)
+	checkAllowance(t, chain, ownerAgentID, spenderAgentID, 5)
+	chain.CheckAccountLedger()
+
+	// exceeds the allowance
+	req = solo.NewCall(accounts.Interface.Name, accounts.FuncTransferFrom,
+		accounts.ParamOwner, ownerAgentID,
+		accounts.ParamColor, balance.ColorIOTA,
+		accounts.ParamAmount, 6,
+	)
+	_, err = chain.PostRequest(req, spender)
+	require.Error(t, err)
+	chain.AssertAccountBalance(ownerAgentID, balance.ColorIOTA, 42+2-15)
+	checkAllowance(t, chain, ownerAgentID, spenderAgentID, 5)
+
+	// only the owner's allowance is spent
+	_, err = chain.PostRequest(req, glb.NewSignatureSchemeWithFunds())
+	require.Error(t, err)
+
+	recs, err := chain.GetEventLogRecordsString(accounts.Interface.Name)
+	require.NoError(t, err)
+	require.Contains(t, recs, "[approve]")
+	require.Contains(t, recs, "[transferFrom]")
+	chain.CheckAccountLedger()
+}
+
+func checkAllowance(t *testing.T, chain *solo.Chain, owner, spender coretypes.AgentID, expected int64) {
+	res, err := chain.CallView(accounts.Interface.Name, accounts.FuncAllowance,
+		accounts.ParamOwner, owner,
+		accounts.ParamSpender, spender,
+		accounts.ParamColor, balance.ColorIOTA,
+	)
+	require.NoError(t, err)
+	amount, _, err := codec.DecodeInt64(res.MustGet(accounts.ParamAmount))
+	require.NoError(t, err)
+	require.EqualValues(t, expected, amount)
+}
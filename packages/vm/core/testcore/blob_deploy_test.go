@@ -117,7 +117,7 @@ func TestDeployGrant(t *testing.T) {
 	require.NoError(t, err)
 
 	_, contacts := chain.GetInfo()
-	require.EqualValues(t, 5, len(contacts))
+	require.EqualValues(t, 6, len(contacts))
 
 	err = chain.DeployWasmContract(user1, "testInccounter2", wasmFile)
 	require.NoError(t, err)
@@ -142,7 +142,7 @@ func TestRevokeDeploy(t *testing.T) {
 	require.NoError(t, err)
 
 	_, contacts := chain.GetInfo()
-	require.EqualValues(t, 5, len(contacts))
+	require.EqualValues(t, 6, len(contacts))
 
 	req = solo.NewCall(root.Interface.Name, root.FuncRevokeDeploy,
 		root.ParamDeployer, user1AgentID,
@@ -154,7 +154,7 @@ func TestRevokeDeploy(t *testing.T) {
 	require.Error(t, err)
 
 	_, contacts = chain.GetInfo()
-	require.EqualValues(t, 5, len(contacts))
+	require.EqualValues(t, 6, len(contacts))
 }
 
 func TestDeployGrantFail(t *testing.T) {
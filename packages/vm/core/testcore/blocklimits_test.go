@@ -0,0 +1,64 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package testcore
+
+import (
+	"testing"
+
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/solo"
+	"github.com/iotaledger/wasp/packages/vm/core/root"
+	"github.com/stretchr/testify/require"
+)
+
+func checkBlockLimits(chain *solo.Chain, maxRequests, maxMutationSize, maxPerSender int64) {
+	res, err := chain.CallView(root.Interface.Name, root.FuncGetBlockLimits)
+	require.NoError(chain.Env.T, err)
+	for key, expected := range map[kv.Key]int64{
+		root.ParamMaxBlockRequests:     maxRequests,
+		root.ParamMaxBlockMutationSize: maxMutationSize,
+		root.ParamMaxRequestsPerSender: maxPerSender,
+	} {
+		v, _, err := codec.DecodeInt64(res.MustGet(key))
+		require.NoError(chain.Env.T, err)
+		require.EqualValues(chain.Env.T, expected, v)
+	}
+}
+
+func TestBlockLimits(t *testing.T) {
+	glb := solo.New(t, false, false)
+	chain := glb.NewChain(nil, "chain1")
+	checkBlockLimits(chain, 0, 0, 0)
+
+	user := glb.NewSignatureSchemeWithFunds()
+	req := solo.NewCall(root.Interface.Name, root.FuncSetBlockLimits, root.ParamMaxBlockRequests, 10)
+	_, err := chain.PostRequest(req, user)
+	require.Error(t, err)
+	checkBlockLimits(chain, 0, 0, 0)
+
+	req = solo.NewCall(root.Interface.Name, root.FuncSetBlockLimits,
+		root.ParamMaxBlockRequests, 10,
+		root.ParamMaxRequestsPerSender, 2,
+	)
+	_, err = chain.PostRequest(req, nil)
+	require.NoError(t, err)
+	checkBlockLimits(chain, 10, 0, 2)
+
+	req = solo.NewCall(root.Interface.Name, root.FuncSetBlockLimits,
+		root.ParamMaxBlockMutationSize, 1000,
+		root.ParamMaxBlockRequests, 0,
+	)
+	_, err = chain.PostRequest(req, nil)
+	require.NoError(t, err)
+	checkBlockLimits(chain, 0, 1000, 2)
+
+	req = solo.NewCall(root.Interface.Name, root.FuncSetBlockLimits, root.ParamMaxBlockRequests, -1)
+	_, err = chain.PostRequest(req, nil)
+	require.Error(t, err)
+
+	req = solo.NewCall(root.Interface.Name, root.FuncSetBlockLimits)
+	_, err = chain.PostRequest(req, nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,89 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package testcore
+
+import (
+	"testing"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/solo"
+	"github.com/iotaledger/wasp/packages/testutil"
+	"github.com/iotaledger/wasp/packages/vm/core/blob"
+	"github.com/iotaledger/wasp/packages/vm/core/root"
+	"github.com/stretchr/testify/require"
+)
+
+func checkGasPerToken(chain *solo.Chain, expected int64) {
+	ret, err := chain.CallView(root.Interface.Name, root.FuncGetFeeInfo, root.ParamHname, blob.Interface.Hname())
+	require.NoError(chain.Env.T, err)
+	gasPerToken, _, err := codec.DecodeInt64(ret.MustGet(root.ParamGasPerToken))
+	require.NoError(chain.Env.T, err)
+	require.EqualValues(chain.Env.T, expected, gasPerToken)
+}
+
+func TestGasDefault(t *testing.T) {
+	glb := solo.New(t, false, false)
+	chain := glb.NewChain(nil, "chain1")
+	checkGasPerToken(chain, 0)
+
+	req := solo.NewCall(root.Interface.Name, root.FuncSetDefaultFee, root.ParamGasPerToken, 1000)
+	_, err := chain.PostRequest(req, nil)
+	require.NoError(t, err)
+	checkGasPerToken(chain, 1000)
+
+	req = solo.NewCall(root.Interface.Name, root.FuncSetDefaultFee, root.ParamGasPerToken, -1)
+	_, err = chain.PostRequest(req, nil)
+	require.Error(t, err)
+	checkGasPerToken(chain, 1000)
+
+	req = solo.NewCall(root.Interface.Name, root.FuncSetDefaultFee, root.ParamGasPerToken, 0)
+	_, err = chain.PostRequest(req, nil)
+	require.NoError(t, err)
+	checkGasPerToken(chain, 0)
+}
+
+func TestGasFeeRefund(t *testing.T) {
+	glb := solo.New(t, false, false)
+	chain := glb.NewChain(nil, "chain1")
+
+	req := solo.NewCall(root.Interface.Name, root.FuncSetDefaultFee, root.ParamGasPerToken, 1000)
+	_, err := chain.PostRequest(req, nil)
+	require.NoError(t, err)
+	chain.AssertAccountBalance(chain.OriginatorAgentID, balance.ColorIOTA, 2)
+
+	user := glb.NewSignatureSchemeWithFunds()
+	userAgentID := coretypes.NewAgentIDFromAddress(user.Address())
+	req = solo.NewCall(blob.Interface.Name, blob.FuncStoreBlob,
+		blob.VarFieldVMType, "dummyType",
+		blob.VarFieldProgramBinary, "dummyBinary",
+	).WithTransfer(balance.ColorIOTA, 10)
+	_, err = chain.PostRequest(req, user)
+	require.NoError(t, err)
+
+	// less than 1000 gas burned: 1 token goes to the validator, 9 are refunded
+	glb.AssertAddressBalance(user.Address(), balance.ColorIOTA, testutil.RequestFundsAmount-11)
+	chain.AssertAccountBalance(userAgentID, balance.ColorIOTA, 10)
+	chain.AssertAccountBalance(chain.OriginatorAgentID, balance.ColorIOTA, 3)
+}
+
+func TestGasOutOfGas(t *testing.T) {
+	glb := solo.New(t, false, false)
+	chain := glb.NewChain(nil, "chain1")
+
+	req := solo.NewCall(blob.Interface.Name, blob.FuncStoreBlob,
+		blob.VarFieldVMType, "dummyType",
+		blob.VarFieldProgramBinary, "dummyBinary",
+	).WithGasLimit(10)
+	_, err := chain.PostRequest(req, nil)
+	require.Error(t, err)
+
+	req = solo.NewCall(blob.Interface.Name, blob.FuncStoreBlob,
+		blob.VarFieldVMType, "dummyType",
+		blob.VarFieldProgramBinary, "dummyBinary",
+	)
+	_, err = chain.PostRequest(req, nil)
+	require.NoError(t, err)
+}
@@ -0,0 +1,316 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package testcore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address/signaturescheme"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/collections"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/solo"
+	"github.com/iotaledger/wasp/packages/util"
+	"github.com/iotaledger/wasp/packages/vm/core/accounts"
+	"github.com/iotaledger/wasp/packages/vm/core/governance"
+	"github.com/iotaledger/wasp/packages/vm/core/root"
+	"github.com/stretchr/testify/require"
+)
+
+func getProposal(t *testing.T, chain *solo.Chain, id int64) *governance.Proposal {
+	ret, err := chain.CallView(governance.Interface.Name, governance.FuncGetProposal, governance.ParamProposalID, id)
+	require.NoError(t, err)
+	p, err := governance.DecodeProposal(ret.MustGet(governance.ParamData))
+	require.NoError(t, err)
+	return p
+}
+
+func propose(t *testing.T, chain *solo.Chain, sigScheme signaturescheme.SignatureScheme, params ...interface{}) int64 {
+	req := solo.NewCall(governance.Interface.Name, governance.FuncPropose, params...)
+	ret, err := chain.PostRequest(req, sigScheme)
+	require.NoError(t, err)
+	id, ok, err := codec.DecodeInt64(ret.MustGet(governance.ParamProposalID))
+	require.NoError(t, err)
+	require.True(t, ok)
+	return id
+}
+
+func vote(chain *solo.Chain, sigScheme signaturescheme.SignatureScheme, id int64, yes bool) error {
+	v := 0
+	if yes {
+		v = 1
+	}
+	req := solo.NewCall(governance.Interface.Name, governance.FuncVote, governance.ParamProposalID, id, governance.ParamVote, v)
+	_, err := chain.PostRequest(req, sigScheme)
+	return err
+}
+
+// actionArgs returns the arguments of the proposed action, serialized for governance.ParamArgs
+func actionArgs(params ...interface{}) []byte {
+	args := dict.New()
+	for i := 0; i+1 < len(params); i += 2 {
+		args.Set(kv.Key(params[i].(string)), codec.Encode(params[i+1]))
+	}
+	return util.MustBytes(args)
+}
+
+// handOverToGovernance makes 'governance' the chain owner with the member list
+func handOverToGovernance(t *testing.T, chain *solo.Chain, members ...signaturescheme.SignatureScheme) {
+	for _, m := range members {
+		req := solo.NewCall(governance.Interface.Name, governance.FuncAddMember,
+			governance.ParamMember, coretypes.NewAgentIDFromAddress(m.Address()))
+		_, err := chain.PostRequest(req, nil)
+		require.NoError(t, err)
+	}
+	govAgentID := coretypes.NewAgentIDFromContractID(coretypes.NewContractID(chain.ChainID, governance.Interface.Hname()))
+	req := solo.NewCall(root.Interface.Name, root.FuncDelegateChainOwnership, root.ParamChainOwner, govAgentID)
+	_, err := chain.PostRequest(req, nil)
+	require.NoError(t, err)
+
+	id := propose(t, chain, members[0], governance.ParamEntryPoint, root.FuncClaimChainOwnership)
+	for _, m := range members {
+		require.NoError(t, vote(chain, m, id, true))
+		if getProposal(t, chain, id).Status != governance.ProposalOpen {
+			break
+		}
+	}
+	require.EqualValues(t, governance.ProposalExecuted, getProposal(t, chain, id).Status)
+	info, _ := chain.GetInfo()
+	require.EqualValues(t, govAgentID, info.ChainOwnerID)
+}
+
+func TestGovernanceBasic(t *testing.T) {
+	glb := solo.New(t, false, false)
+	chain := glb.NewChain(nil, "chain1")
+	defer chain.WaitForEmptyBacklog()
+
+	ret, err := chain.CallView(governance.Interface.Name, governance.FuncGetConfig)
+	require.NoError(t, err)
+	mode, _, _ := codec.DecodeInt64(ret.MustGet(governance.ParamVotingMode))
+	require.EqualValues(t, governance.VotingModeMembers, mode)
+	period, _, _ := codec.DecodeInt64(ret.MustGet(governance.ParamVotingPeriod))
+	require.EqualValues(t, governance.DefaultVotingPeriod, period)
+
+	user := glb.NewSignatureSchemeWithFunds()
+	req := solo.NewCall(governance.Interface.Name, governance.FuncAddMember,
+		governance.ParamMember, coretypes.NewAgentIDFromAddress(user.Address()))
+	_, err = chain.PostRequest(req, user)
+	require.Error(t, err)
+
+	req = solo.NewCall(governance.Interface.Name, governance.FuncPropose,
+		governance.ParamEntryPoint, root.FuncSetDefaultFee, governance.ParamArgs, actionArgs(root.ParamOwnerFee, 1000))
+	_, err = chain.PostRequest(req, user)
+	require.Error(t, err)
+}
+
+func TestGovernanceMembers(t *testing.T) {
+	glb := solo.New(t, false, false)
+	chain := glb.NewChain(nil, "chain1")
+	defer chain.WaitForEmptyBacklog()
+
+	m1 := glb.NewSignatureSchemeWithFunds()
+	m2 := glb.NewSignatureSchemeWithFunds()
+	m3 := glb.NewSignatureSchemeWithFunds()
+	handOverToGovernance(t, chain, m1, m2, m3)
+
+	// the former owner can't change fees anymore
+	req := solo.NewCall(root.Interface.Name, root.FuncSetDefaultFee, root.ParamOwnerFee, 1000)
+	_, err := chain.PostRequest(req, nil)
+	require.Error(t, err)
+
+	// target other than 'root' or 'governance' is not allowed
+	req = solo.NewCall(governance.Interface.Name, governance.FuncPropose,
+		governance.ParamEntryPoint, accounts.FuncWithdrawToAddress, governance.ParamTarget, accounts.Interface.Hname())
+	_, err = chain.PostRequest(req, m1)
+	require.Error(t, err)
+
+	id := propose(t, chain, m1,
+		governance.ParamEntryPoint, root.FuncSetDefaultFee,
+		governance.ParamDescription, "set owner fee",
+		governance.ParamArgs, actionArgs(root.ParamOwnerFee, 1000),
+	)
+	require.NoError(t, vote(chain, m1, id, true))
+	// double voting
+	require.Error(t, vote(chain, m1, id, true))
+	checkFees(chain, root.Interface.Name, 0, 0)
+
+	// majority of members: executed immediately
+	require.NoError(t, vote(chain, m2, id, true))
+	p := getProposal(t, chain, id)
+	require.EqualValues(t, governance.ProposalExecuted, p.Status)
+	require.EqualValues(t, 2, p.Yes)
+	checkFees(chain, root.Interface.Name, 1000, 0)
+
+	// closed proposal
+	require.Error(t, vote(chain, m3, id, true))
+	chain.CheckChain()
+}
+
+func TestGovernanceDeadline(t *testing.T) {
+	glb := solo.New(t, false, false)
+	chain := glb.NewChain(nil, "chain1")
+	defer chain.WaitForEmptyBacklog()
+
+	m1 := glb.NewSignatureSchemeWithFunds()
+	m2 := glb.NewSignatureSchemeWithFunds()
+	m3 := glb.NewSignatureSchemeWithFunds()
+	req := solo.NewCall(governance.Interface.Name, governance.FuncSetConfig, governance.ParamQuorum, 2)
+	_, err := chain.PostRequest(req, nil)
+	require.NoError(t, err)
+	handOverToGovernance(t, chain, m1, m2, m3)
+
+	// not enough votes for the quorum: rejected after the deadline
+	id := propose(t, chain, m1, governance.ParamEntryPoint, root.FuncSetDefaultFee, governance.ParamArgs, actionArgs(root.ParamOwnerFee, 1000))
+	require.NoError(t, vote(chain, m1, id, true))
+
+	req = solo.NewCall(governance.Interface.Name, governance.FuncExecuteProposal, governance.ParamProposalID, id)
+	_, err = chain.PostRequest(req, nil)
+	require.Error(t, err)
+
+	glb.AdvanceClockBy(time.Duration(governance.DefaultVotingPeriod+1) * time.Second)
+	require.Error(t, vote(chain, m2, id, true))
+	_, err = chain.PostRequest(req, nil)
+	require.NoError(t, err)
+	require.EqualValues(t, governance.ProposalRejected, getProposal(t, chain, id).Status)
+	checkFees(chain, root.Interface.Name, 0, 0)
+
+	// majority of members voted 'yes': executed before the deadline
+	id = propose(t, chain, m1, governance.ParamEntryPoint, root.FuncSetDefaultFee, governance.ParamArgs, actionArgs(root.ParamOwnerFee, 1000))
+	require.NoError(t, vote(chain, m1, id, true))
+	require.NoError(t, vote(chain, m2, id, false))
+	require.NoError(t, vote(chain, m3, id, true))
+	require.EqualValues(t, governance.ProposalExecuted, getProposal(t, chain, id).Status)
+	checkFees(chain, root.Interface.Name, 1000, 0)
+
+	// not enough 'yes' votes: rejected after the deadline
+	id = propose(t, chain, m2, governance.ParamEntryPoint, root.FuncSetDefaultFee, governance.ParamArgs, actionArgs(root.ParamOwnerFee, 500))
+	require.NoError(t, vote(chain, m2, id, true))
+	require.NoError(t, vote(chain, m3, id, false))
+	glb.AdvanceClockBy(time.Duration(governance.DefaultVotingPeriod+1) * time.Second)
+	req = solo.NewCall(governance.Interface.Name, governance.FuncExecuteProposal, governance.ParamProposalID, id)
+	_, err = chain.PostRequest(req, nil)
+	require.NoError(t, err)
+	require.EqualValues(t, governance.ProposalRejected, getProposal(t, chain, id).Status)
+	checkFees(chain, root.Interface.Name, 1000, 0)
+}
+
+func TestGovernanceTokens(t *testing.T) {
+	glb := solo.New(t, false, false)
+	chain := glb.NewChain(nil, "chain1")
+	defer chain.WaitForEmptyBacklog()
+
+	whale := glb.NewSignatureSchemeWithFunds()
+	minnow := glb.NewSignatureSchemeWithFunds()
+	noTokens := glb.NewSignatureSchemeWithFunds()
+
+	votingColor, err := glb.MintTokens(whale, 1000)
+	require.NoError(t, err)
+
+	req := solo.NewCall(governance.Interface.Name, governance.FuncSetConfig,
+		governance.ParamVotingMode, governance.VotingModeTokens,
+		governance.ParamVotingColor, votingColor,
+	)
+	_, err = chain.PostRequest(req, nil)
+	require.NoError(t, err)
+
+	req = solo.NewCall(accounts.Interface.Name, accounts.FuncDeposit).WithTransfer(votingColor, 600)
+	_, err = chain.PostRequest(req, whale)
+	require.NoError(t, err)
+	req = solo.NewCall(accounts.Interface.Name, accounts.FuncDeposit,
+		accounts.ParamAgentID, coretypes.NewAgentIDFromAddress(minnow.Address()),
+	).WithTransfer(votingColor, 100)
+	_, err = chain.PostRequest(req, whale)
+	require.NoError(t, err)
+
+	govAgentID := coretypes.NewAgentIDFromContractID(coretypes.NewContractID(chain.ChainID, governance.Interface.Hname()))
+	req = solo.NewCall(root.Interface.Name, root.FuncDelegateChainOwnership, root.ParamChainOwner, govAgentID)
+	_, err = chain.PostRequest(req, nil)
+	require.NoError(t, err)
+
+	// no tokens on the on-chain account: can't propose
+	req = solo.NewCall(governance.Interface.Name, governance.FuncPropose, governance.ParamEntryPoint, root.FuncClaimChainOwnership)
+	_, err = chain.PostRequest(req, noTokens)
+	require.Error(t, err)
+
+	id := propose(t, chain, minnow, governance.ParamEntryPoint, root.FuncClaimChainOwnership)
+	require.EqualValues(t, 700, getProposal(t, chain, id).TotalWeight)
+	require.NoError(t, vote(chain, minnow, id, true))
+	p := getProposal(t, chain, id)
+	require.EqualValues(t, governance.ProposalOpen, p.Status)
+
+	// tokens moved to another account after voting can't vote again
+	accomplice := glb.NewSignatureSchemeWithFunds()
+	req = solo.NewCall(accounts.Interface.Name, accounts.FuncWithdrawToAddress)
+	_, err = chain.PostRequest(req, minnow)
+	require.NoError(t, err)
+	req = solo.NewCall(accounts.Interface.Name, accounts.FuncDeposit,
+		accounts.ParamAgentID, coretypes.NewAgentIDFromAddress(accomplice.Address()),
+	).WithTransfer(votingColor, 100)
+	_, err = chain.PostRequest(req, minnow)
+	require.NoError(t, err)
+	chain.AssertAccountBalance(coretypes.NewAgentIDFromAddress(accomplice.Address()), votingColor, 100)
+	require.Error(t, vote(chain, accomplice, id, true))
+	require.EqualValues(t, 100, getProposal(t, chain, id).Yes)
+
+	require.NoError(t, vote(chain, whale, id, true))
+	p = getProposal(t, chain, id)
+	require.EqualValues(t, governance.ProposalExecuted, p.Status)
+	require.EqualValues(t, 700, p.Yes)
+
+	info, _ := chain.GetInfo()
+	require.EqualValues(t, govAgentID, info.ChainOwnerID)
+	chain.CheckChain()
+}
+
+func TestGovernanceSelfTargeted(t *testing.T) {
+	glb := solo.New(t, false, false)
+	chain := glb.NewChain(nil, "chain1")
+	defer chain.WaitForEmptyBacklog()
+
+	m1 := glb.NewSignatureSchemeWithFunds()
+	m2 := glb.NewSignatureSchemeWithFunds()
+	m3 := glb.NewSignatureSchemeWithFunds()
+	handOverToGovernance(t, chain, m1, m2, m3)
+
+	// the args of the action have the same names as the params of 'propose'
+	id := propose(t, chain, m1,
+		governance.ParamEntryPoint, governance.FuncSetConfig,
+		governance.ParamTarget, governance.Interface.Hname(),
+		governance.ParamArgs, actionArgs(governance.ParamQuorum, 3, governance.ParamVotingPeriod, 60),
+	)
+	require.EqualValues(t, governance.Interface.Hname(), getProposal(t, chain, id).Target)
+	require.NoError(t, vote(chain, m1, id, true))
+	require.NoError(t, vote(chain, m2, id, true))
+	require.EqualValues(t, governance.ProposalExecuted, getProposal(t, chain, id).Status)
+
+	ret, err := chain.CallView(governance.Interface.Name, governance.FuncGetConfig)
+	require.NoError(t, err)
+	quorum, _, _ := codec.DecodeInt64(ret.MustGet(governance.ParamQuorum))
+	require.EqualValues(t, 3, quorum)
+	period, _, _ := codec.DecodeInt64(ret.MustGet(governance.ParamVotingPeriod))
+	require.EqualValues(t, 60, period)
+
+	// the member list is changed by the proposal too
+	m4 := glb.NewSignatureSchemeWithFunds()
+	id = propose(t, chain, m1,
+		governance.ParamEntryPoint, governance.FuncAddMember,
+		governance.ParamTarget, governance.Interface.Hname(),
+		governance.ParamArgs, actionArgs(governance.ParamMember, coretypes.NewAgentIDFromAddress(m4.Address())),
+	)
+	// the majority of members is not enough until the votes reach the quorum
+	require.NoError(t, vote(chain, m1, id, true))
+	require.NoError(t, vote(chain, m2, id, true))
+	require.EqualValues(t, governance.ProposalOpen, getProposal(t, chain, id).Status)
+	require.NoError(t, vote(chain, m3, id, false))
+	require.EqualValues(t, governance.ProposalExecuted, getProposal(t, chain, id).Status)
+
+	ret, err = chain.CallView(governance.Interface.Name, governance.FuncGetConfig)
+	require.NoError(t, err)
+	members := collections.NewArrayReadOnly(ret, governance.ParamMember)
+	require.EqualValues(t, 4, members.MustLen())
+	chain.CheckChain()
+}
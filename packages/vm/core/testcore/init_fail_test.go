@@ -27,11 +27,11 @@ func TestFailRepeat(t *testing.T) {
 	err := chain.DeployContract(nil, test_init_fail.Name, test_init_fail.Interface.ProgramHash, test_init_fail.ParamFail, 1)
 	require.Error(t, err)
 	_, rec := chain.GetInfo()
-	require.EqualValues(t, 4, len(rec))
+	require.EqualValues(t, 5, len(rec))
 
 	// repeat must succeed
 	err = chain.DeployContract(nil, test_init_fail.Name, test_init_fail.Interface.ProgramHash)
 	require.NoError(t, err)
 	_, rec = chain.GetInfo()
-	require.EqualValues(t, 5, len(rec))
+	require.EqualValues(t, 6, len(rec))
 }
@@ -6,8 +6,12 @@ package testcore
 import (
 	"testing"
 
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address/signaturescheme"
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	"github.com/iotaledger/hive.go/crypto/ed25519"
 	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/solo"
+	"github.com/iotaledger/wasp/packages/testutil"
 	"github.com/iotaledger/wasp/packages/vm/core/accounts"
 	"github.com/iotaledger/wasp/packages/vm/core/blob"
 	"github.com/iotaledger/wasp/packages/vm/core/root"
@@ -47,7 +51,7 @@ func TestGetInfo(t *testing.T) {
 	require.EqualValues(t, chain.ChainColor, info.ChainColor)
 	require.EqualValues(t, chain.ChainAddress, info.ChainAddress)
 	require.EqualValues(t, chain.OriginatorAgentID, info.ChainOwnerID)
-	require.EqualValues(t, 4, len(contracts))
+	require.EqualValues(t, 5, len(contracts))
 
 	_, ok := contracts[root.Interface.Hname()]
 	require.True(t, ok)
@@ -74,7 +78,7 @@ func TestDeployExample(t *testing.T) {
 
 	require.EqualValues(t, chain.ChainID, info.ChainID)
 	require.EqualValues(t, chain.OriginatorAgentID, info.ChainOwnerID)
-	require.EqualValues(t, 5, len(contracts))
+	require.EqualValues(t, 6, len(contracts))
 
 	_, ok := contracts[root.Interface.Hname()]
 	require.True(t, ok)
@@ -113,7 +117,7 @@ func TestDeployDouble(t *testing.T) {
 
 	require.EqualValues(t, chain.ChainID, info.ChainID)
 	require.EqualValues(t, chain.OriginatorAgentID, info.ChainOwnerID)
-	require.EqualValues(t, 5, len(contracts))
+	require.EqualValues(t, 6, len(contracts))
 
 	_, ok := contracts[root.Interface.Hname()]
 	require.True(t, ok)
@@ -168,3 +172,48 @@ func TestChangeOwnerUnauthorized(t *testing.T) {
 	info, _ := chain.GetInfo()
 	require.EqualValues(t, chain.OriginatorAgentID, info.ChainOwnerID)
 }
+
+func TestRotateCommittee(t *testing.T) {
+	glb := solo.New(t, false, false)
+	chain := glb.NewChain(nil, "chain1")
+	defer chain.WaitForEmptyBacklog()
+
+	user := glb.NewSignatureSchemeWithFunds()
+	userAgentID := coretypes.NewAgentIDFromAddress(user.Address())
+	req := solo.NewCall(accounts.Interface.Name, accounts.FuncDeposit).
+		WithTransfer(balance.ColorIOTA, 42)
+	_, err := chain.PostRequest(req, user)
+	require.NoError(t, err)
+
+	prevAddress := chain.ChainAddress
+	totalIotas := glb.GetAddressBalance(prevAddress, balance.ColorIOTA)
+	newCommittee := signaturescheme.ED25519(ed25519.GenerateKeyPair())
+
+	// only the chain owner can rotate the committee
+	err = chain.RotateCommittee(user, newCommittee)
+	require.Error(t, err)
+	require.EqualValues(t, prevAddress, chain.ChainAddress)
+
+	err = chain.RotateCommittee(nil, newCommittee)
+	require.NoError(t, err)
+	require.EqualValues(t, newCommittee.Address(), chain.ChainAddress)
+
+	info, _ := chain.GetInfo()
+	require.EqualValues(t, chain.ChainID, info.ChainID)
+	require.EqualValues(t, newCommittee.Address(), info.ChainAddress)
+
+	// the chain token and all funds were moved to the new address
+	glb.AssertAddressBalance(prevAddress, chain.ChainColor, 0)
+	glb.AssertAddressBalance(prevAddress, balance.ColorIOTA, 0)
+	glb.AssertAddressBalance(newCommittee.Address(), chain.ChainColor, 1)
+	// plus request tokens of both rotateCommittee requests
+	glb.AssertAddressBalance(newCommittee.Address(), balance.ColorIOTA, totalIotas+2)
+
+	// the chain continues with the new committee
+	req = solo.NewCall(accounts.Interface.Name, accounts.FuncWithdrawToAddress)
+	_, err = chain.PostRequest(req, user)
+	require.NoError(t, err)
+	chain.AssertAccountBalance(userAgentID, balance.ColorIOTA, 0)
+	glb.AssertAddressBalance(user.Address(), balance.ColorIOTA, testutil.RequestFundsAmount)
+	chain.CheckChain()
+}
@@ -3,8 +3,11 @@ package sandbox_tests
 import (
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
 	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/collections"
 	"github.com/iotaledger/wasp/packages/solo"
 	"github.com/iotaledger/wasp/packages/vm/core/accounts"
+	"github.com/iotaledger/wasp/packages/vm/core/root"
 	"github.com/iotaledger/wasp/packages/vm/core/testcore/sandbox_tests/test_sandbox_sc"
 	"github.com/stretchr/testify/require"
 	"testing"
@@ -87,3 +90,115 @@ func Test2Chains(t *testing.T) {
 	chain2.AssertAccountBalance(accountsAgentID1, balance.ColorIOTA, 1) // !!!! TODO
 	chain2.AssertAccountBalance(accountsAgentID2, balance.ColorIOTA, 0)
 }
+
+func Test2ChainsReceipt(t *testing.T) {
+	env := solo.New(t, false, false)
+	chain1 := env.NewChain(nil, "ch1")
+	chain2 := env.NewChain(nil, "ch2")
+
+	err := chain1.DeployContract(nil, test_sandbox_sc.Interface.Name, test_sandbox_sc.Interface.ProgramHash)
+	require.NoError(t, err)
+	err = chain2.DeployContract(nil, test_sandbox_sc.Interface.Name, test_sandbox_sc.Interface.ProgramHash)
+	require.NoError(t, err)
+
+	userWallet := env.NewSignatureSchemeWithFunds()
+
+	checkReceipts := func(expectedCounter int64, expectedOutstanding int) {
+		res, err := chain1.CallView(test_sandbox_sc.Name, test_sandbox_sc.FuncGetInt,
+			test_sandbox_sc.ParamIntParamName, test_sandbox_sc.VarReceiptCounter,
+		)
+		require.NoError(t, err)
+		counter, _, err := codec.DecodeInt64(res.MustGet(test_sandbox_sc.VarReceiptCounter))
+		require.NoError(t, err)
+		require.EqualValues(t, expectedCounter, counter)
+
+		res, err = chain1.CallView(root.Interface.Name, root.FuncGetCrossChainRequests,
+			root.ParamHname, test_sandbox_sc.Interface.Hname(),
+		)
+		require.NoError(t, err)
+		outstanding, err := root.DecodeCrossChainRequests(collections.NewMapReadOnly(res, root.VarCrossChainRequests))
+		require.NoError(t, err)
+		require.EqualValues(t, expectedOutstanding, len(outstanding))
+	}
+
+	for i, ep := range []string{test_sandbox_sc.FuncDoNothing, test_sandbox_sc.FuncPanicFullEP} {
+		req := solo.NewCall(test_sandbox_sc.Name, test_sandbox_sc.FuncPostWithReceipt,
+			test_sandbox_sc.ParamChainID, chain2.ChainID,
+			test_sandbox_sc.ParamCallOption, ep,
+		).WithTransfer(
+			balance.ColorIOTA, 1,
+		)
+		_, err = chain1.PostRequest(req, userWallet)
+		require.NoError(t, err)
+
+		chain2.WaitForEmptyBacklog()
+		chain1.WaitForEmptyBacklog()
+		checkReceipts(int64(i+1), 0)
+	}
+	recs, err := chain1.GetEventLogRecordsString(test_sandbox_sc.Name)
+	require.NoError(t, err)
+	require.Contains(t, recs, "''")
+	require.Contains(t, recs, test_sandbox_sc.MsgFullPanic)
+
+	chain1.CheckAccountLedger()
+	chain2.CheckAccountLedger()
+}
+
+func Test2ChainsForgedReceipt(t *testing.T) {
+	env := solo.New(t, false, false)
+	chain1 := env.NewChain(nil, "ch1")
+
+	err := chain1.DeployContract(nil, test_sandbox_sc.Interface.Name, test_sandbox_sc.Interface.ProgramHash)
+	require.NoError(t, err)
+
+	userWallet := env.NewSignatureSchemeWithFunds()
+
+	// the request to the chain which doesn't exist waits for the receipt forever
+	req := solo.NewCall(test_sandbox_sc.Name, test_sandbox_sc.FuncPostWithReceipt,
+		test_sandbox_sc.ParamChainID, coretypes.ChainID{1, 2, 3},
+		test_sandbox_sc.ParamCallOption, test_sandbox_sc.FuncDoNothing,
+	).WithTransfer(
+		balance.ColorIOTA, 1,
+	)
+	_, err = chain1.PostRequest(req, userWallet)
+	require.NoError(t, err)
+	chain1.WaitForEmptyBacklog()
+	// the ID of the request is known in the next block
+	_, err = chain1.PostRequest(solo.NewCall(test_sandbox_sc.Name, test_sandbox_sc.FuncDoNothing), userWallet)
+	require.NoError(t, err)
+
+	res, err := chain1.CallView(root.Interface.Name, root.FuncGetCrossChainRequests)
+	require.NoError(t, err)
+	outstanding, err := root.DecodeCrossChainRequests(collections.NewMapReadOnly(res, root.VarCrossChainRequests))
+	require.NoError(t, err)
+	require.EqualValues(t, 1, len(outstanding))
+	var nonce uint32
+	var rec *root.CrossChainRequest
+	for n, r := range outstanding {
+		nonce, rec = n, r
+	}
+	require.True(t, rec.Resolved())
+
+	// the wallet posts the receipt with the nonce and the ID of the outstanding request
+	req = solo.NewCall(test_sandbox_sc.Name, test_sandbox_sc.FuncReceiptCallback,
+		root.ParamReceiptRequestID, rec.RequestID,
+		root.ParamReceiptNonce, int64(nonce),
+	)
+	_, err = chain1.PostRequest(req, userWallet)
+	// the receipt params are stripped, the callback fails
+	require.Error(t, err)
+
+	res, err = chain1.CallView(test_sandbox_sc.Name, test_sandbox_sc.FuncGetInt,
+		test_sandbox_sc.ParamIntParamName, test_sandbox_sc.VarReceiptCounter,
+	)
+	require.NoError(t, err)
+	counter, _, err := codec.DecodeInt64(res.MustGet(test_sandbox_sc.VarReceiptCounter))
+	require.NoError(t, err)
+	require.EqualValues(t, 0, counter)
+
+	res, err = chain1.CallView(root.Interface.Name, root.FuncGetCrossChainRequests)
+	require.NoError(t, err)
+	outstanding, err = root.DecodeCrossChainRequests(collections.NewMapReadOnly(res, root.VarCrossChainRequests))
+	require.NoError(t, err)
+	require.EqualValues(t, 1, len(outstanding))
+}
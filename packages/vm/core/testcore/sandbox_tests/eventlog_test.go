@@ -252,3 +252,137 @@ func TestChainLogMultiple(t *testing.T) {
 	require.EqualValues(t, 1, strings.Count(strTest, "[Event]"))
 	require.EqualValues(t, 1, strings.Count(strTest, "33333"))
 }
+
+func emitEvents(t *testing.T, chain *solo.Chain, from, to int) {
+	for i := from; i <= to; i++ {
+		req := solo.NewCall(SandboxSCName, test_sandbox_sc.FuncEmitEvent,
+			test_sandbox_sc.VarCounter, i,
+		)
+		_, err := chain.PostRequest(req, nil)
+		require.NoError(t, err)
+	}
+}
+
+func TestEventsStructured(t *testing.T) {
+	if RUN_WASM {
+		t.SkipNow()
+	}
+	_, chain := setupChain(t, nil)
+	setupTestSandboxSC(t, chain, nil)
+	emitEvents(t, chain, 1, 5)
+
+	events, err := chain.GetEvents(SandboxSCName)
+	require.NoError(t, err)
+	require.EqualValues(t, 5, len(events))
+	for i, e := range events {
+		require.EqualValues(t, test_sandbox_sc.VarCounter, e.Name)
+		require.EqualValues(t, coretypes.Hn(SandboxSCName), e.Contract)
+		counter, _, err := codec.DecodeInt64(e.Payload.MustGet(test_sandbox_sc.VarCounter))
+		require.NoError(t, err)
+		require.EqualValues(t, i+1, counter)
+		if i > 0 {
+			require.Greater(t, e.BlockIndex, events[i-1].BlockIndex)
+		}
+	}
+
+	events, err = chain.GetEvents(SandboxSCName,
+		eventlog.ParamEventName, test_sandbox_sc.VarCounter,
+		eventlog.ParamTopic, test_sandbox_sc.VarParity,
+		eventlog.ParamTopicValue, "odd",
+	)
+	require.NoError(t, err)
+	require.EqualValues(t, 3, len(events))
+
+	events, err = chain.GetEvents(SandboxSCName, eventlog.ParamEventName, "unknown")
+	require.NoError(t, err)
+	require.EqualValues(t, 0, len(events))
+
+	_, err = chain.GetEvents(SandboxSCName, eventlog.ParamTopic, test_sandbox_sc.VarParity)
+	require.Error(t, err)
+}
+
+func TestEventsPagination(t *testing.T) {
+	if RUN_WASM {
+		t.SkipNow()
+	}
+	_, chain := setupChain(t, nil)
+	setupTestSandboxSC(t, chain, nil)
+	emitEvents(t, chain, 1, 5)
+
+	all, err := chain.GetEvents(SandboxSCName)
+	require.NoError(t, err)
+	require.EqualValues(t, 5, len(all))
+
+	res, err := chain.CallView(eventlog.Interface.Name, eventlog.FuncGetEvents,
+		eventlog.ParamContractHname, coretypes.Hn(SandboxSCName),
+		eventlog.ParamMaxRecords, 2,
+	)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, collections.NewArrayReadOnly(res, eventlog.ParamRecords).MustLen())
+	require.True(t, res.MustHas(eventlog.ParamCursor))
+
+	// paging with small pages returns the same events
+	paged, err := chain.GetEvents(SandboxSCName, eventlog.ParamMaxRecords, 2)
+	require.NoError(t, err)
+	require.EqualValues(t, all, paged)
+
+	// block interval
+	events, err := chain.GetEvents(SandboxSCName,
+		eventlog.ParamFromBlock, all[1].BlockIndex,
+		eventlog.ParamToBlock, all[3].BlockIndex,
+	)
+	require.NoError(t, err)
+	require.EqualValues(t, all[1:4], events)
+}
+
+func TestEventsRetention(t *testing.T) {
+	if RUN_WASM {
+		t.SkipNow()
+	}
+	_, chain := setupChain(t, nil)
+	setupTestSandboxSC(t, chain, nil)
+
+	user := chain.Env.NewSignatureSchemeWithFunds()
+	req := solo.NewCall(eventlog.Interface.Name, eventlog.FuncSetRetention,
+		eventlog.ParamMaxEvents, 3,
+	)
+	_, err := chain.PostRequest(req, user)
+	require.Error(t, err)
+
+	_, err = chain.PostRequest(req, nil)
+	require.NoError(t, err)
+
+	res, err := chain.CallView(eventlog.Interface.Name, eventlog.FuncGetRetention)
+	require.NoError(t, err)
+	maxEvents, _, err := codec.DecodeInt64(res.MustGet(eventlog.ParamMaxEvents))
+	require.NoError(t, err)
+	require.EqualValues(t, 3, maxEvents)
+
+	emitEvents(t, chain, 1, 5)
+	events, err := chain.GetEvents(SandboxSCName)
+	require.NoError(t, err)
+	require.EqualValues(t, 3, len(events))
+	counter, _, err := codec.DecodeInt64(events[0].Payload.MustGet(test_sandbox_sc.VarCounter))
+	require.NoError(t, err)
+	require.EqualValues(t, 3, counter)
+
+	// the indices are pruned too
+	events, err = chain.GetEvents(SandboxSCName,
+		eventlog.ParamEventName, test_sandbox_sc.VarCounter,
+		eventlog.ParamTopic, test_sandbox_sc.VarParity,
+		eventlog.ParamTopicValue, "odd",
+	)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, len(events))
+
+	req = solo.NewCall(eventlog.Interface.Name, eventlog.FuncSetRetention,
+		eventlog.ParamMaxEvents, 0,
+		eventlog.ParamRetentionBlocks, 1,
+	)
+	_, err = chain.PostRequest(req, nil)
+	require.NoError(t, err)
+	emitEvents(t, chain, 6, 6)
+	events, err = chain.GetEvents(SandboxSCName)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, len(events))
+}
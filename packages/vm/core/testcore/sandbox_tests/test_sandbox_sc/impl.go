@@ -69,6 +69,23 @@ func testEventLogGenericData(ctx vmtypes.Sandbox) (dict.Dict, error) {
 	return nil, nil
 }
 
+// testEmitEvent emits the structured event 'counter' with the counter in the payload and its parity as the topic
+func testEmitEvent(ctx vmtypes.Sandbox) (dict.Dict, error) {
+	inc, _, err := codec.DecodeInt64(ctx.Params().MustGet(VarCounter))
+	if err != nil {
+		return nil, err
+	}
+	parity := "even"
+	if inc%2 != 0 {
+		parity = "odd"
+	}
+	ctx.EmitEvent(VarCounter,
+		codec.MakeDict(map[string]interface{}{VarParity: parity}),
+		codec.MakeDict(map[string]interface{}{VarCounter: inc}),
+	)
+	return nil, nil
+}
+
 func testEventLogEventData(ctx vmtypes.Sandbox) (dict.Dict, error) {
 	ctx.Event("[Event] - Testing Event...")
 	return nil, nil
@@ -8,6 +8,7 @@ import (
 	"github.com/iotaledger/wasp/packages/kv/codec"
 	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/iotaledger/wasp/packages/vm/core/accounts"
+	"github.com/iotaledger/wasp/packages/vm/core/root"
 	"github.com/iotaledger/wasp/packages/vm/vmtypes"
 )
 
@@ -31,3 +32,42 @@ func withdrawToChain(ctx vmtypes.Sandbox) (dict.Dict, error) {
 	ctx.Log().Infof("%s: success", FuncWithdrawToChain)
 	return nil, nil
 }
+
+// posts request to the entry point ParamCallOption of the test_sandbox on the chain ParamChainID
+// with the receipt to FuncReceiptCallback
+func postWithReceipt(ctx vmtypes.Sandbox) (dict.Dict, error) {
+	ctx.Log().Infof(FuncPostWithReceipt)
+	targetChain, ok, err := codec.DecodeChainID(ctx.Params().MustGet(ParamChainID))
+	if err != nil || !ok {
+		ctx.Log().Panicf("wrong parameter '%s'", ParamChainID)
+	}
+	entryPoint, ok, err := codec.DecodeString(ctx.Params().MustGet(ParamCallOption))
+	if err != nil || !ok {
+		ctx.Log().Panicf("wrong parameter '%s'", ParamCallOption)
+	}
+	succ := ctx.PostRequest(vmtypes.PostRequestParams{
+		TargetContractID: coretypes.NewContractID(targetChain, ctx.ContractID().Hname()),
+		EntryPoint:       coretypes.Hn(entryPoint),
+		Callback:         coretypes.Hn(FuncReceiptCallback),
+	})
+	if !succ {
+		return nil, fmt.Errorf("failed to post request")
+	}
+	return nil, nil
+}
+
+// counts receipts and publishes the error of the receipt as the event
+func receiptCallback(ctx vmtypes.Sandbox) (dict.Dict, error) {
+	reqID, ok, err := codec.DecodeRequestID(ctx.Params().MustGet(root.ParamReceiptRequestID))
+	if err != nil || !ok {
+		ctx.Log().Panicf("wrong parameter '%s'", root.ParamReceiptRequestID)
+	}
+	receiptErr, _, err := codec.DecodeString(ctx.Params().MustGet(root.ParamReceiptError))
+	if err != nil {
+		ctx.Log().Panicf("wrong parameter '%s'", root.ParamReceiptError)
+	}
+	counter, _, _ := codec.DecodeInt64(ctx.State().MustGet(VarReceiptCounter))
+	ctx.State().Set(VarReceiptCounter, codec.EncodeInt64(counter+1))
+	ctx.Event(fmt.Sprintf("receipt of %s: '%s'", reqID.String(), receiptErr))
+	return nil, nil
+}
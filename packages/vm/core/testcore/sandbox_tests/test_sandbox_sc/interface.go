@@ -30,6 +30,7 @@ func init() {
 		contract.Func(FuncEventLogGenericData, testEventLogGenericData),
 		contract.Func(FuncEventLogEventData, testEventLogEventData),
 		contract.Func(FuncEventLogDeploy, testEventLogDeploy),
+		contract.Func(FuncEmitEvent, testEmitEvent),
 		contract.ViewFunc(FuncSandboxCall, testSandboxCall),
 
 		contract.Func(FuncCheckContextFromFullEP, testCheckContextFromFullEP),
@@ -45,6 +46,8 @@ func init() {
 		contract.Func(FuncSendToAddress, sendToAddress),
 
 		contract.Func(FuncWithdrawToChain, withdrawToChain),
+		contract.Func(FuncPostWithReceipt, postWithReceipt),
+		contract.Func(FuncReceiptCallback, receiptCallback),
 		contract.Func(FuncCallOnChain, callOnChain),
 		contract.Func(FuncSetInt, setInt),
 		contract.ViewFunc(FuncGetInt, getInt),
@@ -60,6 +63,7 @@ const (
 	FuncEventLogGenericData = "testEventLogGenericData"
 	FuncEventLogEventData   = "testEventLogEventData"
 	FuncEventLogDeploy      = "testEventLogDeploy"
+	FuncEmitEvent           = "testEmitEvent"
 
 	//Function sandbox test
 	FuncChainOwnerIDView = "testChainOwnerIDView"
@@ -78,6 +82,8 @@ const (
 	FuncCallPanicViewEPFromView = "testCallPanicViewEPFromView"
 
 	FuncWithdrawToChain = "withdrawToChain"
+	FuncPostWithReceipt = "postWithReceipt"
+	FuncReceiptCallback = "receiptCallback"
 
 	FuncDoNothing     = "doNothing"
 	FuncSendToAddress = "sendToAddress"
@@ -90,10 +96,12 @@ const (
 
 	//Variables
 	VarCounter              = "counter"
+	VarParity               = "parity"
 	VarChainOwner           = "chainOwner"
 	VarContractID           = "contractID"
 	VarSandboxCall          = "sandboxCall"
 	VarContractNameDeployed = "exampleDeployTR"
+	VarReceiptCounter       = "receiptCounter"
 
 	// parameters
 	ParamAddress         = "address"
@@ -0,0 +1,85 @@
+// smart contract programs for testing of the contract upgrade
+package test_upgrade
+
+import (
+	"fmt"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/vm/contract"
+	"github.com/iotaledger/wasp/packages/vm/examples"
+	"github.com/iotaledger/wasp/packages/vm/vmtypes"
+)
+
+const (
+	description = "Dummy contract for testing of upgrades"
+
+	FuncInc        = "inc"
+	FuncGetCounter = "getCounter"
+
+	VarCounter = "counter"
+	VarVersion = "version"
+
+	ParamFail = "migrateFailParam"
+)
+
+var (
+	// InterfaceV1 has no 'migrate' entry point
+	InterfaceV1 = &contract.ContractInterface{
+		Name:        "test_upgrade_v1",
+		Description: description,
+		ProgramHash: hashing.HashStrings("test_upgrade_v1"),
+	}
+	// InterfaceV2 sets version to 2 in the 'migrate' entry point
+	InterfaceV2 = &contract.ContractInterface{
+		Name:        "test_upgrade_v2",
+		Description: description,
+		ProgramHash: hashing.HashStrings("test_upgrade_v2"),
+	}
+)
+
+func init() {
+	InterfaceV1.WithFunctions(initialize, []contract.ContractFunctionInterface{
+		contract.Func(FuncInc, inc),
+		contract.ViewFunc(FuncGetCounter, getCounter),
+	})
+	examples.AddProcessor(InterfaceV1)
+
+	InterfaceV2.WithFunctions(initialize, []contract.ContractFunctionInterface{
+		contract.Func(coretypes.FuncMigrate, migrate),
+		contract.Func(FuncInc, inc),
+		contract.ViewFunc(FuncGetCounter, getCounter),
+	})
+	examples.AddProcessor(InterfaceV2)
+}
+
+func initialize(ctx vmtypes.Sandbox) (dict.Dict, error) {
+	ctx.State().Set(VarVersion, codec.EncodeInt64(1))
+	return nil, nil
+}
+
+func migrate(ctx vmtypes.Sandbox) (dict.Dict, error) {
+	if p, err := ctx.Params().Get(ParamFail); err == nil && p != nil {
+		return nil, fmt.Errorf("failing on purpose")
+	}
+	ctx.State().Set(VarVersion, codec.EncodeInt64(2))
+	return nil, nil
+}
+
+func inc(ctx vmtypes.Sandbox) (dict.Dict, error) {
+	counter, _, _ := codec.DecodeInt64(ctx.State().MustGet(VarCounter))
+	ctx.State().Set(VarCounter, codec.EncodeInt64(counter+1))
+	return nil, nil
+}
+
+// returns VarCounter and VarVersion
+func getCounter(ctx vmtypes.SandboxView) (dict.Dict, error) {
+	counter, _, _ := codec.DecodeInt64(ctx.State().MustGet(VarCounter))
+	version, _, _ := codec.DecodeInt64(ctx.State().MustGet(VarVersion))
+	ret := dict.New()
+	ret.Set(VarCounter, codec.EncodeInt64(counter))
+	ret.Set(VarVersion, codec.EncodeInt64(version))
+	return ret, nil
+}
@@ -0,0 +1,123 @@
+package testcore
+
+import (
+	"testing"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/collections"
+	"github.com/iotaledger/wasp/packages/solo"
+	"github.com/iotaledger/wasp/packages/vm/core/accounts"
+	"github.com/iotaledger/wasp/packages/vm/core/root"
+	"github.com/iotaledger/wasp/packages/vm/core/testcore/test_upgrade"
+	"github.com/stretchr/testify/require"
+)
+
+const upgradeName = "test_upgrade"
+
+func checkCounterVersion(t *testing.T, chain *solo.Chain, counter, version int64) {
+	res, err := chain.CallView(upgradeName, test_upgrade.FuncGetCounter)
+	require.NoError(t, err)
+	c, _, err := codec.DecodeInt64(res.MustGet(test_upgrade.VarCounter))
+	require.NoError(t, err)
+	require.EqualValues(t, counter, c)
+	v, _, err := codec.DecodeInt64(res.MustGet(test_upgrade.VarVersion))
+	require.NoError(t, err)
+	require.EqualValues(t, version, v)
+}
+
+func getContractHistory(t *testing.T, chain *solo.Chain) []*root.ContractUpgrade {
+	res, err := chain.CallView(root.Interface.Name, root.FuncGetContractHistory, root.ParamHname, coretypes.Hn(upgradeName))
+	require.NoError(t, err)
+	arr := collections.NewArrayReadOnly(res, root.VarContractHistory)
+	ret := make([]*root.ContractUpgrade, arr.MustLen())
+	for i := range ret {
+		ret[i], err = root.DecodeContractUpgrade(arr.MustGetAt(uint16(i)))
+		require.NoError(t, err)
+	}
+	return ret
+}
+
+func TestUpgradeMigrate(t *testing.T) {
+	glb := solo.New(t, false, false)
+	chain := glb.NewChain(nil, "chain1")
+	err := chain.DeployContract(nil, upgradeName, test_upgrade.InterfaceV1.ProgramHash)
+	require.NoError(t, err)
+
+	_, err = chain.PostRequest(solo.NewCall(upgradeName, test_upgrade.FuncInc), nil)
+	require.NoError(t, err)
+	checkCounterVersion(t, chain, 1, 1)
+
+	err = chain.UpgradeContract(nil, upgradeName, test_upgrade.InterfaceV2.ProgramHash)
+	require.NoError(t, err)
+	// state is kept, migrate was called
+	checkCounterVersion(t, chain, 1, 2)
+	rec, err := chain.FindContract(upgradeName)
+	require.NoError(t, err)
+	require.EqualValues(t, test_upgrade.InterfaceV2.ProgramHash, rec.ProgramHash)
+
+	_, err = chain.PostRequest(solo.NewCall(upgradeName, test_upgrade.FuncInc), nil)
+	require.NoError(t, err)
+	checkCounterVersion(t, chain, 2, 2)
+
+	// back to the program without migrate
+	err = chain.UpgradeContract(nil, upgradeName, test_upgrade.InterfaceV1.ProgramHash)
+	require.NoError(t, err)
+	checkCounterVersion(t, chain, 2, 2)
+
+	history := getContractHistory(t, chain)
+	require.EqualValues(t, 2, len(history))
+	require.EqualValues(t, test_upgrade.InterfaceV1.ProgramHash, history[0].From)
+	require.EqualValues(t, test_upgrade.InterfaceV2.ProgramHash, history[0].To)
+	require.EqualValues(t, chain.OriginatorAgentID, history[0].UpgradedBy)
+	require.EqualValues(t, test_upgrade.InterfaceV1.ProgramHash, history[1].To)
+
+	recs, err := chain.GetEventLogRecordsString(root.Interface.Name)
+	require.NoError(t, err)
+	require.Contains(t, recs, "[upgrade]")
+}
+
+func TestUpgradeMigrateFail(t *testing.T) {
+	glb := solo.New(t, false, false)
+	chain := glb.NewChain(nil, "chain1")
+	err := chain.DeployContract(nil, upgradeName, test_upgrade.InterfaceV1.ProgramHash)
+	require.NoError(t, err)
+
+	err = chain.UpgradeContract(nil, upgradeName, test_upgrade.InterfaceV2.ProgramHash, test_upgrade.ParamFail, 1)
+	require.Error(t, err)
+	rec, err := chain.FindContract(upgradeName)
+	require.NoError(t, err)
+	require.EqualValues(t, test_upgrade.InterfaceV1.ProgramHash, rec.ProgramHash)
+	checkCounterVersion(t, chain, 0, 1)
+	require.EqualValues(t, 0, len(getContractHistory(t, chain)))
+}
+
+func TestUpgradeNotAuthorized(t *testing.T) {
+	glb := solo.New(t, false, false)
+	chain := glb.NewChain(nil, "chain1")
+	err := chain.DeployContract(nil, upgradeName, test_upgrade.InterfaceV1.ProgramHash)
+	require.NoError(t, err)
+
+	user := glb.NewSignatureSchemeWithFunds()
+	err = chain.UpgradeContract(user, upgradeName, test_upgrade.InterfaceV2.ProgramHash)
+	require.Error(t, err)
+	checkCounterVersion(t, chain, 0, 1)
+}
+
+func TestUpgradeCoreContract(t *testing.T) {
+	glb := solo.New(t, false, false)
+	chain := glb.NewChain(nil, "chain1")
+	err := chain.UpgradeContract(nil, accounts.Interface.Name, test_upgrade.InterfaceV1.ProgramHash)
+	require.Error(t, err)
+}
+
+func TestMigrateNotFromRoot(t *testing.T) {
+	glb := solo.New(t, false, false)
+	chain := glb.NewChain(nil, "chain1")
+	err := chain.DeployContract(nil, upgradeName, test_upgrade.InterfaceV2.ProgramHash)
+	require.NoError(t, err)
+
+	_, err = chain.PostRequest(solo.NewCall(upgradeName, coretypes.FuncMigrate), nil)
+	require.Error(t, err)
+	checkCounterVersion(t, chain, 0, 1)
+}
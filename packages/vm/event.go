@@ -11,21 +11,39 @@ import (
 type ContractEventPublisher struct {
 	contractID coretypes.ContractID
 	log        *logger.Logger
+	collect    func(ev *publisher.ContractEvent)
 }
 
-func NewContractEventPublisher(contractID coretypes.ContractID, log *logger.Logger) ContractEventPublisher {
+// NewContractEventPublisher creates the publisher of the events of the contract.
+// The events are passed to 'collect' to be published as chain events when the block is committed.
+// If 'collect' is nil, the events are only logged and published as 'vmmsg'
+func NewContractEventPublisher(contractID coretypes.ContractID, log *logger.Logger, collect func(ev *publisher.ContractEvent)) ContractEventPublisher {
 	return ContractEventPublisher{
 		contractID: contractID,
 		log:        log,
+		collect:    collect,
 	}
 }
 
 func (c ContractEventPublisher) Publish(msg string) {
 	c.log.Info(c.contractID.String() + "/event " + msg)
 	publisher.Publish("vmmsg", c.contractID.ChainID().String(), c.contractID.Hname().String(), msg)
+	c.collectChainEvent(msg)
 }
 
 func (c ContractEventPublisher) Publishf(format string, args ...interface{}) {
 	c.log.Infof(c.contractID.String()+"/event "+format, args...)
 	publisher.Publish("vmmsg", c.contractID.ChainID().String(), c.contractID.Hname().String(), fmt.Sprintf(format, args...))
+	c.collectChainEvent(fmt.Sprintf(format, args...))
+}
+
+func (c ContractEventPublisher) collectChainEvent(msg string) {
+	if c.collect == nil {
+		return
+	}
+	c.collect(&publisher.ContractEvent{
+		ChainID: c.contractID.ChainID(),
+		Hname:   c.contractID.Hname(),
+		Message: msg,
+	})
 }
@@ -27,7 +27,7 @@ func deployErc20(t *testing.T) *solo.Chain {
 	)
 	require.NoError(t, err)
 	_, rec := chain.GetInfo()
-	require.EqualValues(t, 5, len(rec))
+	require.EqualValues(t, 6, len(rec))
 
 	res, err := chain.CallView(erc20name, "total_supply")
 	require.NoError(t, err)
@@ -24,7 +24,7 @@ func TestDeployErc20(t *testing.T) {
 	)
 	require.NoError(t, err)
 	_, rec := chain.GetInfo()
-	require.EqualValues(t, 5, len(rec))
+	require.EqualValues(t, 6, len(rec))
 
 	_, err = chain.FindContract(erc20name)
 	require.NoError(t, err)
@@ -36,7 +36,7 @@ func TestDeployErc20(t *testing.T) {
 	)
 	require.Error(t, err)
 	_, rec = chain.GetInfo()
-	require.EqualValues(t, 5, len(rec))
+	require.EqualValues(t, 6, len(rec))
 }
 
 func TestDeployErc20Fail1(t *testing.T) {
@@ -45,7 +45,7 @@ func TestDeployErc20Fail1(t *testing.T) {
 	err := chain.DeployWasmContract(nil, erc20name, erc20file)
 	require.Error(t, err)
 	_, rec := chain.GetInfo()
-	require.EqualValues(t, 4, len(rec))
+	require.EqualValues(t, 5, len(rec))
 }
 
 func TestDeployErc20Fail2(t *testing.T) {
@@ -56,7 +56,7 @@ func TestDeployErc20Fail2(t *testing.T) {
 	)
 	require.Error(t, err)
 	_, rec := chain.GetInfo()
-	require.EqualValues(t, 4, len(rec))
+	require.EqualValues(t, 5, len(rec))
 }
 
 func TestDeployErc20Fail3(t *testing.T) {
@@ -69,7 +69,7 @@ func TestDeployErc20Fail3(t *testing.T) {
 	)
 	require.Error(t, err)
 	_, rec := chain.GetInfo()
-	require.EqualValues(t, 4, len(rec))
+	require.EqualValues(t, 5, len(rec))
 }
 
 func TestDeployErc20Fail3Repeat(t *testing.T) {
@@ -82,7 +82,7 @@ func TestDeployErc20Fail3Repeat(t *testing.T) {
 	)
 	require.Error(t, err)
 	_, rec := chain.GetInfo()
-	require.EqualValues(t, 4, len(rec))
+	require.EqualValues(t, 5, len(rec))
 
 	// repeat after failure
 	err = chain.DeployWasmContract(nil, erc20name, erc20file,
@@ -91,7 +91,7 @@ func TestDeployErc20Fail3Repeat(t *testing.T) {
 	)
 	require.NoError(t, err)
 	_, rec = chain.GetInfo()
-	require.EqualValues(t, 5, len(rec))
+	require.EqualValues(t, 6, len(rec))
 
 	_, err = chain.FindContract(erc20name)
 	require.NoError(t, err)
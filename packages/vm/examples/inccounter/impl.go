@@ -26,11 +26,18 @@ var (
 
 func init() {
 	Interface.WithFunctions(initialize, []contract.ContractFunctionInterface{
-		contract.Func(FuncIncCounter, incCounter),
+		contract.Func(FuncIncCounter, incCounter).
+			WithParams(contract.Field(VarCounter, contract.TypeInt64)),
 		contract.Func(FuncIncAndRepeatOnceAfter5s, incCounterAndRepeatOnce),
-		contract.Func(FuncIncAndRepeatMany, incCounterAndRepeatMany),
-		contract.Func(FuncSpawn, spawn),
-		contract.ViewFunc(FuncGetCounter, getCounter),
+		contract.Func(FuncIncAndRepeatMany, incCounterAndRepeatMany).
+			WithParams(contract.Field(VarNumRepeats, contract.TypeInt64)),
+		contract.Func(FuncSpawn, spawn).
+			WithParams(
+				contract.Field(VarName, contract.TypeString),
+				contract.Field(VarDescription, contract.TypeString),
+			),
+		contract.ViewFunc(FuncGetCounter, getCounter).
+			WithResults(contract.Field(VarCounter, contract.TypeInt64)),
 	})
 }
 
@@ -2,10 +2,12 @@ package runvm
 
 import (
 	"fmt"
-	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
 	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/metrics"
 	"github.com/iotaledger/wasp/packages/vm/statetxbuilder"
 	"github.com/iotaledger/wasp/packages/vm/vmcontext"
+	"github.com/iotaledger/wasp/packages/vm/vmtypes"
 	"time"
 
 	"github.com/iotaledger/wasp/packages/hashing"
@@ -19,11 +21,21 @@ func RunComputationsAsync(ctx *vm.VMTask) error {
 		return fmt.Errorf("RunComputationsAsync: must be at least 1 request")
 	}
 
-	txb, err := statetxbuilder.New(address.Address(ctx.ChainID), ctx.Color, ctx.Balances)
+	txb, err := statetxbuilder.New(ctx.Address, ctx.Color, ctx.Balances)
 	if err != nil {
 		ctx.Log.Debugf("statetxbuilder.New: %v", err)
 		return err
 	}
+	// the balances come from the leader. The VM panics if a request token is missing, so check it up front
+	tokens := make(map[balance.Color]int64)
+	for _, req := range ctx.Requests {
+		tokens[balance.Color(req.Tx.ID())]++
+	}
+	for col, n := range tokens {
+		if txb.Balance(col) < n {
+			return fmt.Errorf("RunComputationsAsync: request token %s is missing in the balances", col.String())
+		}
+	}
 
 	// TODO 1 graceful shutdown of the running VM task (with daemon)
 	// TODO 2 timeout for VM. Gas limit
@@ -41,9 +53,29 @@ func runTask(task *vm.VMTask, txb *statetxbuilder.Builder) {
 		"state index", task.VirtualState.BlockIndex(),
 		"num req", len(task.Requests),
 	)
+	// the solid state must not change while the task reads it. It is released before the task finishes,
+	// because the state transition may be committed by the OnFinish handler.
+	// Releasing is idempotent, the deferred call only releases it if the task panics
+	release := task.VirtualState.AcquireSolid()
+	defer release()
+
+	onFinish := func(result dict.Dict, resultErr error, err error) {
+		release()
+		task.OnFinish(result, resultErr, err)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			if r != vmtypes.ErrWatchdog {
+				panic(r)
+			}
+			onFinish(nil, nil, fmt.Errorf("runTask: %v", r))
+		}
+	}()
+
 	vmctx, err := vmcontext.NewVMContext(task, txb)
 	if err != nil {
-		task.OnFinish(nil, nil, fmt.Errorf("runTask.createVMContext: %v", err))
+		onFinish(nil, nil, fmt.Errorf("runTask.createVMContext: %v", err))
 		return
 	}
 
@@ -55,11 +87,29 @@ func runTask(task *vm.VMTask, txb *statetxbuilder.Builder) {
 	// loop over the batch of requests and run each request on the VM.
 	// the result accumulates in the VMContext and in the list of stateUpdates
 	timestamp := task.Timestamp
-	for _, reqRef := range task.Requests {
+	mutationSize := 0
+	for i, reqRef := range task.Requests {
+		if i > 0 {
+			// the block is closed when the state mutations reach the limit. The rest of requests stays in the backlog
+			if maxSize := vmctx.BlockLimits().MaxMutationSize; maxSize > 0 && int64(mutationSize) >= maxSize {
+				task.Log.Infof("runTask: block mutation size limit %d reached, %d requests of %d left for the next block",
+					maxSize, len(task.Requests)-i, len(task.Requests))
+				break
+			}
+		}
+		started := time.Now()
 		vmctx.RunTheRequest(reqRef, timestamp)
 		lastStateUpdate, lastResult, lastErr = vmctx.GetResult()
+		metrics.ObserveRequest(&task.ChainID, time.Since(started), lastErr)
+		task.ResultRequests = append(task.ResultRequests, vm.RequestResult{
+			RequestID: *reqRef.RequestID(),
+			Result:    lastResult,
+			Error:     lastErr,
+			Events:    vmctx.GetEvents(),
+		})
 
 		stateUpdates = append(stateUpdates, lastStateUpdate)
+		mutationSize += lastStateUpdate.Mutations().Size()
 		if timestamp != 0 {
 			// increasing (nonempty) timestamp for 1 nanosecond for each request in the batch
 			// the reason is to provide a different timestamp for each VM call and remain deterministic
@@ -70,15 +120,16 @@ func runTask(task *vm.VMTask, txb *statetxbuilder.Builder) {
 	// create block from state updates.
 	task.ResultBlock, err = state.NewBlock(stateUpdates)
 	if err != nil {
-		task.OnFinish(nil, nil, fmt.Errorf("RunVM.NewBlock: %v", err))
+		onFinish(nil, nil, fmt.Errorf("RunVM.NewBlock: %v", err))
 		return
 	}
 	task.ResultBlock.WithBlockIndex(task.VirtualState.BlockIndex() + 1)
 
 	// calculate resulting state hash
 	vsClone := task.VirtualState.Clone()
+	vsClone.Variables().SetReadCache(vmctx.ReadCache())
 	if err = vsClone.ApplyBlock(task.ResultBlock); err != nil {
-		task.OnFinish(nil, nil, fmt.Errorf("RunVM.ApplyBlock: %v", err))
+		onFinish(nil, nil, fmt.Errorf("RunVM.ApplyBlock: %v", err))
 		return
 	}
 	stateHash := vsClone.Hash()
@@ -88,16 +139,19 @@ func runTask(task *vm.VMTask, txb *statetxbuilder.Builder) {
 		vsClone.Timestamp(),
 	)
 	if err != nil {
-		task.OnFinish(nil, nil, fmt.Errorf("RunVM.FinalizeTransactionEssence: %v", err))
+		onFinish(nil, nil, fmt.Errorf("RunVM.FinalizeTransactionEssence: %v", err))
 		return
 	}
 	// Note: can't take tx ID!!
+	cacheStats := vmctx.ReadCache().Stats()
 	task.Log.Debugw("runTask OUT",
 		"batch size", task.ResultBlock.Size(),
 		"block index", task.ResultBlock.StateIndex(),
 		"variable state hash", stateHash.String(),
 		"tx essence hash", hashing.HashData(task.ResultTransaction.EssenceBytes()).String(),
 		"tx finalTimestamp", time.Unix(0, task.ResultTransaction.MustState().Timestamp()),
+		"read cache hits", cacheStats.Hits,
+		"read cache misses", cacheStats.Misses,
 	)
-	task.OnFinish(lastResult, lastErr, nil)
+	onFinish(lastResult, lastErr, nil)
 }
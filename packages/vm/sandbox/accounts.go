@@ -6,6 +6,7 @@ package sandbox
 import (
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
 	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/vm/vmtypes"
 )
 
 func (s *sandbox) IncomingTransfer() coretypes.ColoredBalances {
@@ -21,5 +22,6 @@ func (s *sandbox) Balances() coretypes.ColoredBalances {
 }
 
 func (s *sandbox) MoveTokens(target coretypes.AgentID, col balance.Color, amount int64) bool {
+	s.vmctx.BurnGas(vmtypes.GasTransfer)
 	return s.vmctx.DoMoveBalance(target, col, amount)
 }
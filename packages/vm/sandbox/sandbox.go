@@ -48,7 +48,7 @@ func (s *sandbox) Params() dict.Dict {
 }
 
 func (s *sandbox) State() kv.KVStore {
-	return s.vmctx.State()
+	return meteredState{KVStore: s.vmctx.State(), vmctx: s.vmctx}
 }
 
 func (s *sandbox) Caller() coretypes.AgentID {
@@ -58,11 +58,13 @@ func (s *sandbox) Caller() coretypes.AgentID {
 // CreateContract deploys contract by the binary hash
 // and calls "init" endpoint (constructor) with provided parameters
 func (s *sandbox) DeployContract(programHash hashing.HashValue, name string, description string, initParams dict.Dict) error {
+	s.vmctx.BurnGas(vmtypes.GasDeployContract)
 	return s.vmctx.CreateContract(programHash, name, description, initParams)
 }
 
 // Call calls an entry point of contact, passes parameters and funds
 func (s *sandbox) Call(contractHname coretypes.Hname, entryPoint coretypes.Hname, params dict.Dict, transfer coretypes.ColoredBalances) (dict.Dict, error) {
+	s.vmctx.BurnGas(vmtypes.GasCall)
 	return s.vmctx.Call(contractHname, entryPoint, params, transfer)
 }
 
@@ -75,14 +77,17 @@ func (s *sandbox) GetEntropy() hashing.HashValue {
 }
 
 func (s *sandbox) TransferToAddress(targetAddr address.Address, transfer coretypes.ColoredBalances) bool {
+	s.vmctx.BurnGas(vmtypes.GasTransfer)
 	return s.vmctx.TransferToAddress(targetAddr, transfer)
 }
 
 func (s *sandbox) TransferCrossChain(targetAgentID coretypes.AgentID, targetChainID coretypes.ChainID, transfer coretypes.ColoredBalances) bool {
+	s.vmctx.BurnGas(vmtypes.GasPostRequest)
 	return s.vmctx.TransferCrossChain(targetAgentID, targetChainID, transfer)
 }
 
 func (s *sandbox) PostRequest(par vmtypes.PostRequestParams) bool {
+	s.vmctx.BurnGas(vmtypes.GasPostRequest)
 	return s.vmctx.PostRequest(par)
 }
 
@@ -91,7 +96,23 @@ func (s *sandbox) Log() vmtypes.LogInterface {
 }
 
 func (s *sandbox) Event(msg string) {
+	s.vmctx.BurnGas(vmtypes.GasEvent)
 	s.Log().Infof("eventlog::%s -> '%s'", s.vmctx.CurrentContractHname(), msg)
 	s.vmctx.StoreToEventLog(s.vmctx.CurrentContractHname(), []byte(msg))
 	s.vmctx.EventPublisher().Publish(msg)
 }
+
+func (s *sandbox) EmitEvent(name string, topics dict.Dict, payload dict.Dict) {
+	s.vmctx.BurnGas(vmtypes.GasEvent)
+	msg := s.vmctx.StoreEvent(s.vmctx.CurrentContractHname(), name, topics, payload)
+	s.Log().Infof("eventlog::%s -> %s", s.vmctx.CurrentContractHname(), msg)
+	s.vmctx.EventPublisher().Publish(msg)
+}
+
+func (s *sandbox) BurnGas(amount int64) {
+	s.vmctx.BurnGas(amount)
+}
+
+func (s *sandbox) GasRemaining() int64 {
+	return s.vmctx.GasRemaining()
+}
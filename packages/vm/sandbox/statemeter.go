@@ -0,0 +1,32 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package sandbox
+
+import (
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/vm/vmcontext"
+	"github.com/iotaledger/wasp/packages/vm/vmtypes"
+)
+
+// meteredState burns gas for each write to the state of the contract
+type meteredState struct {
+	kv.KVStore
+	vmctx *vmcontext.VMContext
+}
+
+func (s meteredState) Set(key kv.Key, value []byte) {
+	s.vmctx.BurnGas(int64(len(key)+len(value)) * vmtypes.GasPerByteStored)
+	s.KVStore.Set(key, value)
+}
+
+func (s meteredState) Del(key kv.Key) {
+	s.vmctx.BurnGas(vmtypes.GasStateDelete)
+	s.KVStore.Del(key)
+}
+
+// DelPrefix burns the same gas as a single delete, regardless of the number of deleted keys
+func (s meteredState) DelPrefix(prefix kv.Key) {
+	s.vmctx.BurnGas(vmtypes.GasStateDelete)
+	s.KVStore.DelPrefix(prefix)
+}
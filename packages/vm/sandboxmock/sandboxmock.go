@@ -0,0 +1,389 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+// Package 'sandboxmock' implements vmtypes.Sandbox and vmtypes.SandboxView over the in-memory state
+// for unit testing of smart contract functions without a chain.
+//
+// The context of the call (caller, params, incoming transfer, timestamp, entropy etc) is scripted by the test.
+// Side effects of the call which leave the contract (posted requests, transfers, events, calls to other contracts)
+// are recorded for assertions. Calls to other contracts return results stubbed by the test with OnCall.
+//
+// For the tests which need the real VM, the ledger and other contracts, use 'solo'
+package sandboxmock
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/coretypes/cbalances"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/vm/contract"
+	"github.com/iotaledger/wasp/packages/vm/core/eventlog"
+	"github.com/iotaledger/wasp/packages/vm/vmtypes"
+)
+
+// CallStub returns the result of the call to other contract
+type CallStub func(params dict.Dict, transfer coretypes.ColoredBalances) (dict.Dict, error)
+
+// Call is the record of the call to other contract
+type Call struct {
+	Target     coretypes.Hname
+	EntryPoint coretypes.Hname
+	Params     dict.Dict
+	Transfer   coretypes.ColoredBalances
+}
+
+// Transfer is the record of tokens sent to the address on the L1 ledger
+type Transfer struct {
+	Address  address.Address
+	Transfer coretypes.ColoredBalances
+}
+
+// CrossChainTransfer is the record of tokens sent to the account on another chain
+type CrossChainTransfer struct {
+	TargetAgentID coretypes.AgentID
+	TargetChainID coretypes.ChainID
+	Transfer      coretypes.ColoredBalances
+}
+
+// Deployment is the record of the contract deployed by the call
+type Deployment struct {
+	ProgramHash hashing.HashValue
+	Name        string
+	Description string
+	InitParams  dict.Dict
+}
+
+type callKey struct {
+	target     coretypes.Hname
+	entryPoint coretypes.Hname
+}
+
+// Sandbox is the mocked context of the call to a smart contract function
+type Sandbox struct {
+	T *testing.T
+
+	// scripted context of the call
+	ChainOwner     coretypes.AgentID
+	Creator        coretypes.AgentID
+	Contract       coretypes.ContractID
+	CallerID       coretypes.AgentID
+	ReqID          coretypes.RequestID
+	Timestamp      int64
+	Entropy        hashing.HashValue
+	Args           dict.Dict
+	Incoming       coretypes.ColoredBalances
+	ContractTokens map[balance.Color]int64
+	GasBudget      int64
+	// the state of the contract. Is kept between calls
+	StateDict dict.Dict
+
+	// recorded side effects of the calls
+	Calls               []Call
+	PostedRequests      []vmtypes.PostRequestParams
+	Transfers           []Transfer
+	CrossChainTransfers []CrossChainTransfer
+	Deployments         []Deployment
+	Events              []string
+	StructuredEvents    []eventlog.Event
+
+	stubs map[callKey]CallStub
+}
+
+// New creates the mocked sandbox of the contract with empty state.
+// The owner of the chain and the caller is the creator of the contract unless set otherwise
+func New(t *testing.T, contractID coretypes.ContractID) *Sandbox {
+	creator := coretypes.NewRandomAgentID()
+	return &Sandbox{
+		T:              t,
+		ChainOwner:     creator,
+		Creator:        creator,
+		Contract:       contractID,
+		CallerID:       creator,
+		Args:           dict.New(),
+		Incoming:       cbalances.NewFromMap(nil),
+		ContractTokens: make(map[balance.Color]int64),
+		GasBudget:      vmtypes.MaxGasPerRequest,
+		StateDict:      dict.New(),
+		stubs:          make(map[callKey]CallStub),
+	}
+}
+
+// WithCaller sets the caller of the next call
+func (s *Sandbox) WithCaller(agentID coretypes.AgentID) *Sandbox {
+	s.CallerID = agentID
+	return s
+}
+
+// WithParams sets params of the next call in the form of pairs 'name', 'value', same as in solo.NewCall
+func (s *Sandbox) WithParams(params ...interface{}) *Sandbox {
+	if len(params)%2 != 0 {
+		panic("WithParams: len(params) % 2 != 0")
+	}
+	par := make(map[string]interface{})
+	for i := 0; i < len(params)/2; i++ {
+		key, ok := params[2*i].(string)
+		if !ok {
+			panic("WithParams: string expected")
+		}
+		par[key] = params[2*i+1]
+	}
+	s.Args = codec.MakeDict(par)
+	return s
+}
+
+// WithIncomingTransfer sets tokens transferred by the next call. They are added to the balances of the contract
+func (s *Sandbox) WithIncomingTransfer(transfer map[balance.Color]int64) *Sandbox {
+	s.Incoming = cbalances.NewFromMap(transfer)
+	for col, bal := range transfer {
+		s.ContractTokens[col] += bal
+	}
+	return s
+}
+
+// WithTimestamp sets the timestamp of the context, in nanoseconds
+func (s *Sandbox) WithTimestamp(ts int64) *Sandbox {
+	s.Timestamp = ts
+	return s
+}
+
+// WithEntropy sets the random data of the context
+func (s *Sandbox) WithEntropy(entropy hashing.HashValue) *Sandbox {
+	s.Entropy = entropy
+	return s
+}
+
+// OnCall stubs the result of calls to the entry point of other contract
+func (s *Sandbox) OnCall(target, entryPoint coretypes.Hname, stub CallStub) *Sandbox {
+	s.stubs[callKey{target, entryPoint}] = stub
+	return s
+}
+
+// CallFunction calls the full entry point of the contract in the mocked context
+func (s *Sandbox) CallFunction(i *contract.ContractInterface, funName string) (dict.Dict, error) {
+	ep, ok := i.GetEntryPoint(coretypes.Hn(funName))
+	if !ok {
+		return nil, fmt.Errorf("can't find entry point '%s' in contract '%s'", funName, i.Name)
+	}
+	return ep.Call(s)
+}
+
+// CallView calls the view entry point of the contract in the mocked context
+func (s *Sandbox) CallView(i *contract.ContractInterface, funName string) (dict.Dict, error) {
+	ep, ok := i.GetEntryPoint(coretypes.Hn(funName))
+	if !ok {
+		return nil, fmt.Errorf("can't find entry point '%s' in contract '%s'", funName, i.Name)
+	}
+	return ep.CallView(s.View())
+}
+
+// implementation of vmtypes.Sandbox
+
+func (s *Sandbox) ChainOwnerID() coretypes.AgentID {
+	return s.ChainOwner
+}
+
+func (s *Sandbox) ContractCreator() coretypes.AgentID {
+	return s.Creator
+}
+
+func (s *Sandbox) ContractID() coretypes.ContractID {
+	return s.Contract
+}
+
+func (s *Sandbox) GetTimestamp() int64 {
+	return s.Timestamp
+}
+
+func (s *Sandbox) Params() dict.Dict {
+	return s.Args
+}
+
+func (s *Sandbox) State() kv.KVStore {
+	return s.StateDict
+}
+
+func (s *Sandbox) Caller() coretypes.AgentID {
+	return s.CallerID
+}
+
+func (s *Sandbox) DeployContract(programHash hashing.HashValue, name string, description string, initParams dict.Dict) error {
+	s.BurnGas(vmtypes.GasDeployContract)
+	s.Deployments = append(s.Deployments, Deployment{
+		ProgramHash: programHash,
+		Name:        name,
+		Description: description,
+		InitParams:  initParams,
+	})
+	return nil
+}
+
+func (s *Sandbox) Call(target coretypes.Hname, entryPoint coretypes.Hname, params dict.Dict, transfer coretypes.ColoredBalances) (dict.Dict, error) {
+	s.BurnGas(vmtypes.GasCall)
+	if !s.debit(transfer) {
+		return nil, fmt.Errorf("sandboxmock.Call: not enough tokens")
+	}
+	return s.call(target, entryPoint, params, transfer)
+}
+
+func (s *Sandbox) call(target coretypes.Hname, entryPoint coretypes.Hname, params dict.Dict, transfer coretypes.ColoredBalances) (dict.Dict, error) {
+	s.Calls = append(s.Calls, Call{
+		Target:     target,
+		EntryPoint: entryPoint,
+		Params:     params,
+		Transfer:   transfer,
+	})
+	stub, ok := s.stubs[callKey{target, entryPoint}]
+	if !ok {
+		return nil, fmt.Errorf("sandboxmock: call to %s::%s is not stubbed", target, entryPoint)
+	}
+	return stub(params, transfer)
+}
+
+func (s *Sandbox) RequestID() coretypes.RequestID {
+	return s.ReqID
+}
+
+func (s *Sandbox) GetEntropy() hashing.HashValue {
+	return s.Entropy
+}
+
+func (s *Sandbox) Balances() coretypes.ColoredBalances {
+	return cbalances.NewFromMap(s.ContractTokens)
+}
+
+func (s *Sandbox) IncomingTransfer() coretypes.ColoredBalances {
+	return s.Incoming
+}
+
+func (s *Sandbox) Balance(col balance.Color) int64 {
+	return s.ContractTokens[col]
+}
+
+func (s *Sandbox) MoveTokens(target coretypes.AgentID, col balance.Color, amount int64) bool {
+	s.BurnGas(vmtypes.GasTransfer)
+	transfer := cbalances.NewFromMap(map[balance.Color]int64{col: amount})
+	if !s.debit(transfer) {
+		return false
+	}
+	s.CrossChainTransfers = append(s.CrossChainTransfers, CrossChainTransfer{
+		TargetAgentID: target,
+		TargetChainID: s.Contract.ChainID(),
+		Transfer:      transfer,
+	})
+	return true
+}
+
+func (s *Sandbox) TransferToAddress(addr address.Address, transfer coretypes.ColoredBalances) bool {
+	s.BurnGas(vmtypes.GasTransfer)
+	if !s.debit(transfer) {
+		return false
+	}
+	s.Transfers = append(s.Transfers, Transfer{
+		Address:  addr,
+		Transfer: transfer,
+	})
+	return true
+}
+
+func (s *Sandbox) TransferCrossChain(targetAgentID coretypes.AgentID, targetChainID coretypes.ChainID, transfer coretypes.ColoredBalances) bool {
+	s.BurnGas(vmtypes.GasPostRequest)
+	if !s.debit(transfer) {
+		return false
+	}
+	s.CrossChainTransfers = append(s.CrossChainTransfers, CrossChainTransfer{
+		TargetAgentID: targetAgentID,
+		TargetChainID: targetChainID,
+		Transfer:      transfer,
+	})
+	return true
+}
+
+func (s *Sandbox) PostRequest(par vmtypes.PostRequestParams) bool {
+	s.BurnGas(vmtypes.GasPostRequest)
+	if !s.debit(par.Transfer) {
+		return false
+	}
+	s.PostedRequests = append(s.PostedRequests, par)
+	return true
+}
+
+func (s *Sandbox) Log() vmtypes.LogInterface {
+	return mockLog{s.T}
+}
+
+func (s *Sandbox) Event(msg string) {
+	s.BurnGas(vmtypes.GasEvent)
+	s.Log().Infof("eventlog::%s -> '%s'", s.Contract.Hname(), msg)
+	s.Events = append(s.Events, msg)
+}
+
+func (s *Sandbox) EmitEvent(name string, topics dict.Dict, payload dict.Dict) {
+	s.BurnGas(vmtypes.GasEvent)
+	if len(topics) > eventlog.MaxTopics {
+		panic(fmt.Sprintf("sandboxmock.EmitEvent: too many topics in event '%s'", name))
+	}
+	e := eventlog.Event{
+		Contract:  s.Contract.Hname(),
+		Name:      name,
+		Topics:    topics,
+		Payload:   payload,
+		Timestamp: s.Timestamp,
+		RequestID: s.ReqID,
+	}
+	s.Log().Infof("eventlog::%s -> %s", s.Contract.Hname(), e.String())
+	s.StructuredEvents = append(s.StructuredEvents, e)
+}
+
+func (s *Sandbox) BurnGas(amount int64) {
+	s.GasBudget -= amount
+	if s.GasBudget < 0 {
+		panic(vmtypes.ErrOutOfGas)
+	}
+}
+
+func (s *Sandbox) GasRemaining() int64 {
+	return s.GasBudget
+}
+
+// debit takes tokens from the balances of the contract if there are enough of them
+func (s *Sandbox) debit(transfer coretypes.ColoredBalances) bool {
+	if transfer == nil {
+		return true
+	}
+	enough := true
+	transfer.Iterate(func(col balance.Color, bal int64) bool {
+		enough = s.ContractTokens[col] >= bal
+		return enough
+	})
+	if !enough {
+		return false
+	}
+	transfer.Iterate(func(col balance.Color, bal int64) bool {
+		s.ContractTokens[col] -= bal
+		return true
+	})
+	return true
+}
+
+type mockLog struct {
+	t *testing.T
+}
+
+func (l mockLog) Infof(format string, param ...interface{}) {
+	l.t.Logf(format, param...)
+}
+
+func (l mockLog) Debugf(format string, param ...interface{}) {
+	l.t.Logf(format, param...)
+}
+
+func (l mockLog) Panicf(format string, param ...interface{}) {
+	panic(fmt.Sprintf(format, param...))
+}
@@ -0,0 +1,98 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package sandboxmock
+
+import (
+	"testing"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/vm/core/accounts"
+	"github.com/iotaledger/wasp/packages/vm/core/governance"
+	"github.com/iotaledger/wasp/packages/vm/examples/inccounter"
+	"github.com/iotaledger/wasp/packages/vm/vmtypes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIncCounter(t *testing.T) {
+	ctx := New(t, inccounter.Interface.ContractID(coretypes.ChainID{}))
+
+	_, err := ctx.WithParams(inccounter.VarCounter, 17).CallFunction(inccounter.Interface, "init")
+	require.NoError(t, err)
+	require.Len(t, ctx.Events, 1)
+
+	_, err = ctx.WithParams().CallFunction(inccounter.Interface, inccounter.FuncIncCounter)
+	require.NoError(t, err)
+
+	ret, err := ctx.CallView(inccounter.Interface, inccounter.FuncGetCounter)
+	require.NoError(t, err)
+	counter, _, _ := codec.DecodeInt64(ret.MustGet(inccounter.VarCounter))
+	require.EqualValues(t, 18, counter)
+
+	_, err = ctx.CallFunction(inccounter.Interface, inccounter.FuncIncAndRepeatOnceAfter5s)
+	require.NoError(t, err)
+	require.Len(t, ctx.PostedRequests, 1)
+	require.EqualValues(t, coretypes.Hn(inccounter.FuncIncCounter), ctx.PostedRequests[0].EntryPoint)
+	require.EqualValues(t, ctx.ContractID(), ctx.PostedRequests[0].TargetContractID)
+	require.EqualValues(t, vmtypes.MaxGasPerRequest-vmtypes.GasEvent-vmtypes.GasPostRequest, ctx.GasRemaining())
+}
+
+func TestStubbedCall(t *testing.T) {
+	ctx := New(t, governance.Interface.ContractID(coretypes.ChainID{}))
+	votingColor := balance.Color{1}
+
+	_, err := ctx.WithCaller(coretypes.NewRandomAgentID()).
+		WithParams(governance.ParamVotingMode, governance.VotingModeTokens).
+		CallFunction(governance.Interface, governance.FuncSetConfig)
+	require.Error(t, err)
+
+	_, err = ctx.WithCaller(ctx.ChainOwner).
+		WithParams(
+			governance.ParamVotingMode, governance.VotingModeTokens,
+			governance.ParamVotingColor, votingColor,
+		).
+		CallFunction(governance.Interface, governance.FuncSetConfig)
+	require.NoError(t, err)
+
+	proposer := coretypes.NewRandomAgentID()
+	ctx.WithCaller(proposer).WithParams(governance.ParamEntryPoint, "claimChainOwnership")
+
+	// accounts contract is not stubbed
+	_, err = ctx.CallFunction(governance.Interface, governance.FuncPropose)
+	require.Error(t, err)
+
+	ctx.OnCall(accounts.Interface.Hname(), coretypes.Hn(accounts.FuncAccounts), func(dict.Dict, coretypes.ColoredBalances) (dict.Dict, error) {
+		return dict.Dict{kv.Key(proposer[:]): []byte{}}, nil
+	})
+	ctx.OnCall(accounts.Interface.Hname(), coretypes.Hn(accounts.FuncBalance), func(params dict.Dict, _ coretypes.ColoredBalances) (dict.Dict, error) {
+		agentID, _, err := codec.DecodeAgentID(params.MustGet(accounts.ParamAgentID))
+		require.NoError(t, err)
+		require.EqualValues(t, proposer, agentID)
+		return dict.Dict{kv.Key(votingColor[:]): codec.EncodeInt64(100)}, nil
+	})
+	_, err = ctx.CallFunction(governance.Interface, governance.FuncPropose)
+	require.NoError(t, err)
+	require.Len(t, ctx.Calls, 3)
+
+	p, err := governance.GetProposal(ctx.State(), 0)
+	require.NoError(t, err)
+	require.EqualValues(t, proposer, p.Proposer)
+	require.EqualValues(t, 100, p.TotalWeight)
+}
+
+func TestTransfers(t *testing.T) {
+	ctx := New(t, coretypes.NewContractID(coretypes.ChainID{}, coretypes.Hn("test")))
+	ctx.WithIncomingTransfer(map[balance.Color]int64{balance.ColorIOTA: 100})
+	require.EqualValues(t, 100, ctx.Balance(balance.ColorIOTA))
+
+	target := coretypes.NewRandomAgentID()
+	require.True(t, ctx.MoveTokens(target, balance.ColorIOTA, 60))
+	require.False(t, ctx.MoveTokens(target, balance.ColorIOTA, 60))
+	require.EqualValues(t, 40, ctx.Balance(balance.ColorIOTA))
+	require.Len(t, ctx.CrossChainTransfers, 1)
+	require.EqualValues(t, target, ctx.CrossChainTransfers[0].TargetAgentID)
+}
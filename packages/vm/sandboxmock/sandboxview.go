@@ -0,0 +1,61 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package sandboxmock
+
+import (
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/vm/vmtypes"
+)
+
+// sandboxView is the read only view of the mocked sandbox. Shares the state and stubs with the sandbox
+type sandboxView struct {
+	s *Sandbox
+}
+
+// View returns the mocked context of the call to the view function of the contract
+func (s *Sandbox) View() vmtypes.SandboxView {
+	return sandboxView{s}
+}
+
+func (v sandboxView) ChainOwnerID() coretypes.AgentID {
+	return v.s.ChainOwner
+}
+
+func (v sandboxView) ContractCreator() coretypes.AgentID {
+	return v.s.Creator
+}
+
+func (v sandboxView) ContractID() coretypes.ContractID {
+	return v.s.Contract
+}
+
+func (v sandboxView) GetTimestamp() int64 {
+	return v.s.Timestamp
+}
+
+func (v sandboxView) Params() dict.Dict {
+	return v.s.Args
+}
+
+func (v sandboxView) State() kv.KVStoreReader {
+	return v.s.StateDict
+}
+
+func (v sandboxView) WriteableState() kv.KVStore {
+	return v.s.StateDict
+}
+
+func (v sandboxView) Call(contractHname coretypes.Hname, entryPoint coretypes.Hname, params dict.Dict) (dict.Dict, error) {
+	return v.s.call(contractHname, entryPoint, params, nil)
+}
+
+func (v sandboxView) Balances() coretypes.ColoredBalances {
+	return v.s.Balances()
+}
+
+func (v sandboxView) Log() vmtypes.LogInterface {
+	return v.s.Log()
+}
@@ -58,7 +58,12 @@ func (txb *Builder) SetStateParams(stateIndex uint32, stateHash *hashing.HashVal
 // AddRequestSectionWithTransfer adds request block with the request
 // token and adds respective outputs for the colored transfers
 func (txb *Builder) AddRequestSection(req *sctransaction.RequestSection) error {
-	targetAddr := address.Address(req.Target().ChainID())
+	return txb.AddRequestSectionToAddress(req, address.Address(req.Target().ChainID()))
+}
+
+// AddRequestSectionToAddress adds request block with the request token and the transfer sent to the
+// address of the target chain. The address differs from the chain ID if the committee of the target chain was rotated
+func (txb *Builder) AddRequestSectionToAddress(req *sctransaction.RequestSection, targetAddr address.Address) error {
 	var err error
 	if err = txb.vtxb.MintColor(targetAddr, balance.ColorIOTA, 1); err != nil {
 		return err
@@ -70,6 +75,12 @@ func (txb *Builder) AddRequestSection(req *sctransaction.RequestSection) error {
 	return nil
 }
 
+// NumRequestSections returns the number of request sections added so far.
+// It is the index of the next request section in the transaction
+func (txb *Builder) NumRequestSections() int {
+	return len(txb.requestSections)
+}
+
 func (txb *Builder) TransferToAddress(targetAddr address.Address, transfer coretypes.ColoredBalances) error {
 	var err error
 	transfer.Iterate(func(col balance.Color, bal int64) bool {
@@ -90,6 +101,17 @@ func (txb *Builder) Erase1TokenToChain(col balance.Color) bool {
 	return txb.vtxb.EraseColor(txb.chainAddress, col, 1) == nil
 }
 
+// RotateTo makes the anchor transaction to hand the chain over to the new address:
+// the chain token, the outputs to the current chain address and the remainder are sent to the new address
+func (txb *Builder) RotateTo(newAddr address.Address) {
+	if newAddr == txb.chainAddress {
+		return
+	}
+	txb.vtxb.moveOutputs(txb.chainAddress, newAddr)
+	txb.vtxb.reminderAddr = newAddr
+	txb.chainAddress = newAddr
+}
+
 func (txb *Builder) Build() (*sctransaction.Transaction, error) {
 	txb.MustValidate()
 	return sctransaction.NewTransaction(
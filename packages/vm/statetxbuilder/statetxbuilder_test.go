@@ -78,3 +78,39 @@ func TestClone(t *testing.T) {
 
 	require.EqualValues(t, tx.ID(), tx1.ID())
 }
+
+func TestRotateTo(t *testing.T) {
+	chSig := signaturescheme.ED25519(ed25519.GenerateKeyPair())
+	chAddr := chSig.Address()
+	newAddr := signaturescheme.ED25519(ed25519.GenerateKeyPair()).Address()
+	col1, _, err := balance.ColorFromBytes(hashing.RandomHash(nil)[:])
+	require.NoError(t, err)
+	txid1, _, err := transaction.IDFromBytes(hashing.RandomHash(nil)[:])
+	require.NoError(t, err)
+
+	inps := map[transaction.ID][]*balance.Balance{
+		txid1: {
+			balance.New(col1, 1),
+			balance.New(balance.ColorIOTA, 3),
+		},
+	}
+	b, err := New(chAddr, col1, inps)
+	require.NoError(t, err)
+
+	b.RotateTo(newAddr)
+	b.MustValidate()
+
+	tx, err := b.Build()
+	require.NoError(t, err)
+	tx.Sign(chSig)
+
+	prop, err := tx.Properties()
+	require.NoError(t, err)
+	require.EqualValues(t, newAddr, prop.ChainAddress())
+
+	outs, ok := tx.Outputs().Get(newAddr)
+	require.True(t, ok)
+	require.Len(t, outs.([]*balance.Balance), 2)
+	_, ok = tx.Outputs().Get(chAddr)
+	require.False(t, ok)
+}
@@ -212,6 +212,18 @@ func (vtxb *vtxBuilder) addToOutputs(targetAddr address.Address, col balance.Col
 	cmap[col] = b + amount
 }
 
+// moveOutputs merges all outputs to one address into outputs to another address
+func (vtxb *vtxBuilder) moveOutputs(fromAddr, toAddr address.Address) {
+	cmap, ok := vtxb.outputBalances[fromAddr]
+	if !ok {
+		return
+	}
+	delete(vtxb.outputBalances, fromAddr)
+	for col, amount := range cmap {
+		vtxb.addToOutputs(toAddr, col, amount)
+	}
+}
+
 // MoveTokens move token without changing color
 func (vtxb *vtxBuilder) MoveTokens(targetAddr address.Address, col balance.Color, amount int64) error {
 	if vtxb.GetInputBalance(col) < amount {
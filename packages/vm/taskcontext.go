@@ -2,12 +2,14 @@ package vm
 
 import (
 	"bytes"
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
 	valuetransaction "github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
 	"github.com/iotaledger/hive.go/logger"
 	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/hashing"
 	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/publisher"
 	"github.com/iotaledger/wasp/packages/sctransaction"
 	"github.com/iotaledger/wasp/packages/state"
 	"github.com/iotaledger/wasp/packages/util"
@@ -17,6 +19,10 @@ import (
 type RequestRefWithFreeTokens struct {
 	sctransaction.RequestRef
 	FreeTokens coretypes.ColoredBalances
+	// args of the request delivered off-tangle. Nil unless the request commits only to the hash of args
+	OffTangleArgs dict.Dict
+	// key of the sealed args, recovered by the committee from the decryption shares. Nil unless args are sealed
+	UnsealKey []byte
 }
 
 // task context (for batch of requests)
@@ -25,6 +31,8 @@ type VMTask struct {
 	// inputs (immutable)
 	ChainID coretypes.ChainID
 	Color   balance.Color
+	// current address of the chain. Same as ChainID unless the committee of the chain was rotated
+	Address address.Address
 	// deterministic source of entropy
 	Entropy            hashing.HashValue
 	Balances           map[valuetransaction.ID][]*balance.Balance
@@ -38,6 +46,17 @@ type VMTask struct {
 	// outputs
 	ResultTransaction *sctransaction.Transaction
 	ResultBlock       state.Block
+	// results of the requests included in the ResultBlock, in the order of the block
+	ResultRequests []RequestResult
+}
+
+// RequestResult is the result of the request run on the VM
+type RequestResult struct {
+	RequestID coretypes.RequestID
+	Result    dict.Dict
+	Error     error
+	// events emitted by the contracts while the request was run
+	Events []*publisher.ContractEvent
 }
 
 // BatchHash is used to uniquely identify the VM task
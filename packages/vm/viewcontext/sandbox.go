@@ -33,7 +33,7 @@ func newSandboxView(vctx *viewcontext, contractHname coretypes.Hname, params dic
 		contractHname: contractHname,
 		params:        params,
 		state:         contractStateSubpartition(vctx.state, contractHname),
-		events:        vm.NewContractEventPublisher(coretypes.NewContractID(vctx.chainID, contractHname), vctx.log),
+		events:        vm.NewContractEventPublisher(coretypes.NewContractID(vctx.chainID, contractHname), vctx.log, nil),
 	}
 }
 
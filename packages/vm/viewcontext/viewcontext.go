@@ -24,18 +24,58 @@ type viewcontext struct {
 	timestamp      int64
 	contractRecord *root.ContractRecord
 	log            *logger.Logger
+	release        func()
+	// index and hash of the state the views are called on. Known only for the contexts created from the DB
+	stateIndex uint32
+	stateHash  *hashing.HashValue
 }
 
+// NewFromDB creates the context to call views on the solid state of the chain.
+// The solid state does not change until the context is released with Release
 func NewFromDB(chainID coretypes.ChainID, proc *processors.ProcessorCache) (*viewcontext, error) {
+	release := state.AcquireSolidState(&chainID)
 	state_, _, ok, err := state.LoadSolidState(&chainID)
 
 	if err != nil {
+		release()
 		return nil, err
 	}
 	if !ok {
+		release()
 		return nil, fmt.Errorf("solid state not found for chain %s", chainID.String())
 	}
-	return New(chainID, state_.Variables(), state_.Timestamp(), proc, nil), nil
+	return newLeased(chainID, state_, proc, release), nil
+}
+
+// NewFromDBAt creates the context to call views on the past state of the chain after the block with the given index
+func NewFromDBAt(chainID coretypes.ChainID, blockIndex uint32, proc *processors.ProcessorCache) (*viewcontext, error) {
+	release := state.AcquireSolidState(&chainID)
+	state_, _, ok, err := state.LoadStateAt(&chainID, blockIndex)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	if !ok {
+		release()
+		return nil, fmt.Errorf("solid state not found for chain %s", chainID.String())
+	}
+	return newLeased(chainID, state_, proc, release), nil
+}
+
+func newLeased(chainID coretypes.ChainID, vs state.VirtualState, proc *processors.ProcessorCache, release func()) *viewcontext {
+	vs.Variables().SetReadCache(buffered.NewReadCache(buffered.DefaultReadCacheSize))
+	ret := New(chainID, vs.Variables(), vs.Timestamp(), proc, nil)
+	ret.release = release
+	ret.stateIndex = vs.BlockIndex()
+	ret.stateHash = vs.Hash()
+	return ret
+}
+
+// Release releases the solid state held by the context. The context must not be used after that
+func (v *viewcontext) Release() {
+	if v.release != nil {
+		v.release()
+	}
 }
 
 func New(chainID coretypes.ChainID, state kv.KVStore, ts int64, proc *processors.ProcessorCache, logSet *logger.Logger) *viewcontext {
@@ -53,6 +93,16 @@ func New(chainID coretypes.ChainID, state kv.KVStore, ts int64, proc *processors
 	}
 }
 
+// StateIndex returns the index of the state the views are called on
+func (v *viewcontext) StateIndex() uint32 {
+	return v.stateIndex
+}
+
+// StateHash returns the hash of the state the views are called on or nil if it is not known
+func (v *viewcontext) StateHash() *hashing.HashValue {
+	return v.stateHash
+}
+
 // CallView in viewcontext implements own panic catcher.
 func (v *viewcontext) CallView(contractHname coretypes.Hname, epCode coretypes.Hname, params dict.Dict) (dict.Dict, error) {
 	var ret dict.Dict
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/iotaledger/wasp/packages/hashing"
 	"github.com/iotaledger/wasp/packages/vm/core/root"
+	"github.com/iotaledger/wasp/packages/vm/vmtypes"
 
 	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/kv/dict"
@@ -12,7 +13,7 @@ import (
 
 var (
 	ErrContractNotFound   = errors.New("contract not found")
-	ErrEntryPointNotFound = errors.New("entry point not found")
+	ErrEntryPointNotFound = vmtypes.ErrEntryPointNotFound
 	ErrProcessorNotFound  = errors.New("VM not found. Internal error")
 	ErrNotEnoughFees      = errors.New("not enough fees")
 	ErrWrongRequestToken  = errors.New("wrong request token")
@@ -37,6 +38,7 @@ func (vmctx *VMContext) callByProgramHash(targetContract coretypes.Hname, epCode
 	if !ok {
 		return nil, ErrEntryPointNotFound
 	}
+	ep = ep.WithGasLimit(int(vmctx.GasRemaining()))
 	// distinguishing between two types of entry points. Passing different types of sandboxes
 	if ep.IsView() {
 		if epCode == coretypes.EntryPointInit {
@@ -55,10 +57,10 @@ func (vmctx *VMContext) callByProgramHash(targetContract coretypes.Hname, epCode
 	}
 	defer vmctx.popCallContext()
 
-	// prevent calling 'init' not from root contract or not while initializing root
-	if epCode == coretypes.EntryPointInit && targetContract != root.Interface.Hname() {
+	// prevent calling 'init' and 'migrate' not from root contract or not while initializing root
+	if isRootOnlyEntryPoint(epCode) && targetContract != root.Interface.Hname() {
 		if !vmctx.callerIsRoot() {
-			return nil, fmt.Errorf("attempt to callByProgramHash init not from the root contract")
+			return nil, fmt.Errorf("attempt to callByProgramHash %s not from the root contract", epCode.String())
 		}
 	}
 	return ep.Call(NewSandbox(vmctx))
@@ -73,6 +75,7 @@ func (vmctx *VMContext) callNonViewByProgramHash(targetContract coretypes.Hname,
 	if !ok {
 		return nil, ErrEntryPointNotFound
 	}
+	ep = ep.WithGasLimit(int(vmctx.GasRemaining()))
 
 	// distinguishing between two types of entry points. Passing different types of sandboxes
 	if ep.IsView() {
@@ -83,15 +86,19 @@ func (vmctx *VMContext) callNonViewByProgramHash(targetContract coretypes.Hname,
 	}
 	defer vmctx.popCallContext()
 
-	// prevent calling 'init' not from root contract or not while initializing root
-	if epCode == coretypes.EntryPointInit && targetContract != root.Interface.Hname() {
+	// prevent calling 'init' and 'migrate' not from root contract or not while initializing root
+	if isRootOnlyEntryPoint(epCode) && targetContract != root.Interface.Hname() {
 		if !vmctx.callerIsRoot() {
-			return nil, fmt.Errorf("attempt to callByProgramHash init not from the root contract")
+			return nil, fmt.Errorf("attempt to callByProgramHash %s not from the root contract", epCode.String())
 		}
 	}
 	return ep.Call(NewSandbox(vmctx))
 }
 
+func isRootOnlyEntryPoint(epCode coretypes.Hname) bool {
+	return epCode == coretypes.EntryPointInit || epCode == coretypes.EntryPointMigrate
+}
+
 func (vmctx *VMContext) callerIsRoot() bool {
 	caller := vmctx.Caller()
 	if caller.IsAddress() {
@@ -101,7 +108,7 @@ func (vmctx *VMContext) callerIsRoot() bool {
 }
 
 func (vmctx *VMContext) requesterIsChainOwner() bool {
-	return vmctx.chainOwnerID == vmctx.reqRef.SenderAgentID()
+	return vmctx.chainOwnerID == vmctx.senderAgentID
 }
 
 func (vmctx *VMContext) Params() dict.Dict {
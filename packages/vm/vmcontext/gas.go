@@ -0,0 +1,84 @@
+package vmcontext
+
+import (
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/coretypes/cbalances"
+	"github.com/iotaledger/wasp/packages/vm/vmtypes"
+)
+
+// initGasBudget sets gas budget of the request. 0 or too big gas limit means maximum allowed
+func (vmctx *VMContext) initGasBudget(gasLimit uint32) {
+	vmctx.gasBudget = vmtypes.MaxGasPerRequest
+	if gasLimit != 0 && int64(gasLimit) < vmctx.gasBudget {
+		vmctx.gasBudget = int64(gasLimit)
+	}
+	vmctx.gasBurned = 0
+	vmctx.gasFeeReserved = 0
+	vmctx.gasPerToken = 0
+}
+
+// BurnGas charges gas to the budget of the request. Panics with ErrOutOfGas when the budget is exceeded.
+// The panic is caught by the VM and the request is rolled back, however the gas is still paid
+func (vmctx *VMContext) BurnGas(amount int64) {
+	vmctx.gasBurned += amount
+	if vmctx.gasBurned > vmctx.gasBudget {
+		vmctx.gasBurned = vmctx.gasBudget
+		panic(vmtypes.ErrOutOfGas)
+	}
+}
+
+func (vmctx *VMContext) GasRemaining() int64 {
+	return vmctx.gasBudget - vmctx.gasBurned
+}
+
+func (vmctx *VMContext) GasBurned() int64 {
+	return vmctx.gasBurned
+}
+
+// mustReserveGasFee takes from the transfer the fee for the whole gas budget of the request.
+// The gas budget is limited by the fee tokens available in the transfer.
+// The fee for the unused gas is refunded to the sender after the call
+func (vmctx *VMContext) mustReserveGasFee(transfer coretypes.ColoredBalances) coretypes.ColoredBalances {
+	if vmctx.gasPerToken == 0 {
+		return transfer
+	}
+	affordable := transfer.Balance(vmctx.feeColor) * vmctx.gasPerToken
+	if affordable < vmctx.gasBudget {
+		vmctx.gasBudget = affordable
+	}
+	vmctx.gasFeeReserved = (vmctx.gasBudget + vmctx.gasPerToken - 1) / vmctx.gasPerToken
+	if vmctx.gasFeeReserved == 0 {
+		return transfer
+	}
+	remaining := map[balance.Color]int64{
+		vmctx.feeColor: -vmctx.gasFeeReserved,
+	}
+	transfer.AddToMap(remaining)
+	vmctx.log.Debugf("mustReserveGasFee: gas budget %d, reserved fee %d", vmctx.gasBudget, vmctx.gasFeeReserved)
+	return cbalances.NewFromMap(remaining)
+}
+
+// mustSettleGasFee accrues the fee for the gas burned to the validator and refunds the rest of
+// the reserved fee to the sender
+func (vmctx *VMContext) mustSettleGasFee() {
+	if vmctx.gasFeeReserved == 0 {
+		return
+	}
+	fee := (vmctx.gasBurned + vmctx.gasPerToken - 1) / vmctx.gasPerToken
+	if fee > vmctx.gasFeeReserved {
+		fee = vmctx.gasFeeReserved
+	}
+	if fee > 0 {
+		vmctx.creditToAccount(vmctx.validatorFeeTarget, cbalances.NewFromMap(map[balance.Color]int64{
+			vmctx.feeColor: fee,
+		}))
+	}
+	if refund := vmctx.gasFeeReserved - fee; refund > 0 {
+		vmctx.creditToAccount(vmctx.senderAgentID, cbalances.NewFromMap(map[balance.Color]int64{
+			vmctx.feeColor: refund,
+		}))
+	}
+	vmctx.log.Debugf("mustSettleGasFee: gas burned %d, fee %d", vmctx.gasBurned, fee)
+	vmctx.gasFeeReserved = 0
+}
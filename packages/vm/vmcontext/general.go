@@ -2,13 +2,16 @@ package vmcontext
 
 import (
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	valuetransaction "github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
 	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/coretypes/cbalances"
 	"github.com/iotaledger/wasp/packages/hashing"
 	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/publisher"
 	"github.com/iotaledger/wasp/packages/sctransaction"
 	"github.com/iotaledger/wasp/packages/util"
 	"github.com/iotaledger/wasp/packages/vm"
+	"github.com/iotaledger/wasp/packages/vm/core/root"
 	"github.com/iotaledger/wasp/packages/vm/vmtypes"
 )
 
@@ -75,11 +78,40 @@ func (vmctx *VMContext) PostRequest(par vmtypes.PostRequestParams) bool {
 		vmctx.log.Debugf("-- PostRequest: not enough funds")
 		return false
 	}
+	var nonce uint32
+	if par.Callback != 0 {
+		nonce = vmctx.addCrossChainRequest(&root.CrossChainRequest{
+			Sender:     vmctx.CurrentContractHname(),
+			Target:     par.TargetContractID,
+			EntryPoint: par.EntryPoint,
+			Callback:   par.Callback,
+			Timestamp:  vmctx.timestamp,
+			// the ID of the state transaction is set in the next block
+			RequestID: coretypes.NewRequestID(valuetransaction.ID{}, uint16(vmctx.txBuilder.NumRequestSections())),
+		})
+	}
 	reqSection := sctransaction.NewRequestSection(vmctx.CurrentContractHname(), par.TargetContractID, par.EntryPoint).
 		WithTimelock(par.TimeLock).
+		WithGasLimit(par.GasLimit).
+		WithCallback(par.Callback, nonce).
 		WithTransfer(par.Transfer).
 		WithArgs(par.Params)
-	return vmctx.txBuilder.AddRequestSection(reqSection) == nil
+	var err error
+	if par.TargetContractID.ChainID() == vmctx.chainID {
+		// requests to the own chain are sent to the current address of the chain
+		err = vmctx.txBuilder.AddRequestSectionToAddress(reqSection, vmctx.chainAddress)
+	} else {
+		// requests to other chains are sent to the address of the chain known from the directory of chain addresses
+		err = vmctx.txBuilder.AddRequestSectionToAddress(reqSection, vmctx.targetChainAddress(par.TargetContractID.ChainID()))
+	}
+	if err != nil {
+		if par.Callback != 0 {
+			// the request was not posted, so the receipt will never arrive
+			vmctx.deleteCrossChainRequest(nonce)
+		}
+		return false
+	}
+	return true
 }
 
 func (vmctx *VMContext) PostRequestToSelf(reqCode coretypes.Hname, params dict.Dict) bool {
@@ -102,7 +134,9 @@ func (vmctx *VMContext) PostRequestToSelfWithDelay(entryPoint coretypes.Hname, a
 }
 
 func (vmctx *VMContext) EventPublisher() vm.ContractEventPublisher {
-	return vm.NewContractEventPublisher(vmctx.CurrentContractID(), vmctx.log)
+	return vm.NewContractEventPublisher(vmctx.CurrentContractID(), vmctx.log, func(ev *publisher.ContractEvent) {
+		vmctx.events = append(vmctx.events, ev)
+	})
 }
 
 func (vmctx *VMContext) RequestID() coretypes.RequestID {
@@ -1,10 +1,14 @@
 package vmcontext
 
 import (
+	"fmt"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
 	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/coretypes/cbalances"
 	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/iotaledger/wasp/packages/vm/core/accounts"
 	"github.com/iotaledger/wasp/packages/vm/core/blob"
 	"github.com/iotaledger/wasp/packages/vm/core/eventlog"
@@ -70,6 +74,81 @@ func (vmctx *VMContext) getFeeInfo() (balance.Color, int64, int64) {
 	return root.GetFeeInfoByContractRecord(vmctx.State(), vmctx.contractRecord)
 }
 
+func (vmctx *VMContext) getGasPerToken() int64 {
+	vmctx.pushCallContext(root.Interface.Hname(), nil, nil)
+	defer vmctx.popCallContext()
+
+	return root.GetGasPerToken(vmctx.State())
+}
+
+// BlockLimits returns the limits of the block set by the chain owner
+func (vmctx *VMContext) BlockLimits() *root.BlockLimits {
+	vmctx.pushCallContext(root.Interface.Hname(), nil, nil)
+	defer vmctx.popCallContext()
+
+	return root.GetBlockLimits(vmctx.State())
+}
+
+func (vmctx *VMContext) addCrossChainRequest(rec *root.CrossChainRequest) uint32 {
+	vmctx.pushCallContext(root.Interface.Hname(), nil, nil)
+	defer vmctx.popCallContext()
+
+	return root.AddCrossChainRequest(vmctx.State(), rec)
+}
+
+func (vmctx *VMContext) deleteCrossChainRequest(nonce uint32) {
+	vmctx.pushCallContext(root.Interface.Hname(), nil, nil)
+	defer vmctx.popCallContext()
+
+	root.DeleteCrossChainRequest(vmctx.State(), nonce)
+}
+
+func (vmctx *VMContext) resolveCrossChainRequests() {
+	vmctx.pushCallContext(root.Interface.Hname(), nil, nil)
+	defer vmctx.popCallContext()
+
+	root.ResolveCrossChainRequests(vmctx.State(), vmctx.prevStateTxID)
+}
+
+func (vmctx *VMContext) removeCrossChainRequest(nonce uint32, requestID coretypes.RequestID, receiptSender coretypes.ContractID) bool {
+	vmctx.pushCallContext(root.Interface.Hname(), nil, nil)
+	defer vmctx.popCallContext()
+
+	req := vmctx.reqRef.RequestSection()
+	return root.RemoveCrossChainRequest(vmctx.State(), nonce, requestID, receiptSender, req.Target().Hname(), req.EntryPointCode())
+}
+
+// resolveSender returns the sender of the request. The address of the sender contract is the address
+// of its chain, which differs from the chain ID after the committee of the chain was rotated.
+// The chain of the sender contract is put to the directory of chain addresses
+func (vmctx *VMContext) resolveSender() coretypes.AgentID {
+	senderAddress := *vmctx.reqRef.SenderAddress()
+	if _, ok := vmctx.reqRef.Tx.State(); !ok {
+		return coretypes.NewAgentIDFromAddress(senderAddress)
+	}
+	senderChainID := vmctx.chainID
+	if senderAddress != vmctx.chainAddress {
+		vmctx.pushCallContext(root.Interface.Hname(), nil, nil)
+		defer vmctx.popCallContext()
+
+		senderChainID = root.FindChainIDByAddress(vmctx.State(), senderAddress)
+		if senderChainID != vmctx.chainID {
+			root.AddKnownChain(vmctx.State(), senderChainID, senderAddress)
+		}
+	}
+	return coretypes.NewAgentIDFromContractID(coretypes.NewContractID(senderChainID, vmctx.reqRef.SenderContractHname()))
+}
+
+// targetChainAddress returns the current address of another chain, to which the requests to the chain are sent.
+// The chain is put to the directory of chain addresses, so it is informed about the rotation of the committee
+func (vmctx *VMContext) targetChainAddress(chainID coretypes.ChainID) address.Address {
+	vmctx.pushCallContext(root.Interface.Hname(), nil, nil)
+	defer vmctx.popCallContext()
+
+	root.AddKnownChain(vmctx.State(), chainID, address.Address(chainID))
+	return root.GetOtherChainAddress(vmctx.State(), chainID)
+}
+
 func (vmctx *VMContext) getBinary(programHash hashing.HashValue) (string, []byte, error) {
 	vmtype, ok := hardcoded.LocateHardcodedProgram(programHash)
 	if ok {
@@ -117,3 +196,29 @@ func (vmctx *VMContext) StoreToEventLog(contract coretypes.Hname, data []byte) {
 	vmctx.log.Debugf("StoreToEventLog/%s: data: '%s'", contract.String(), string(data))
 	eventlog.AppendToLog(vmctx.State(), vmctx.timestamp, contract, data)
 }
+
+// StoreEvent appends the structured event to the 'eventlog'. Returns the string representation of the event
+func (vmctx *VMContext) StoreEvent(contract coretypes.Hname, name string, topics dict.Dict, payload dict.Dict) string {
+	vmctx.pushCallContext(eventlog.Interface.Hname(), nil, nil)
+	defer vmctx.popCallContext()
+
+	if topics == nil {
+		topics = dict.New()
+	}
+	if payload == nil {
+		payload = dict.New()
+	}
+	e := &eventlog.Event{
+		Contract:   contract,
+		Name:       name,
+		Topics:     topics,
+		Payload:    payload,
+		BlockIndex: vmctx.virtualState.BlockIndex() + 1,
+		Timestamp:  vmctx.timestamp,
+		RequestID:  vmctx.RequestID(),
+	}
+	if err := eventlog.AppendEvent(vmctx.State(), e); err != nil {
+		panic(fmt.Sprintf("StoreEvent: %v", err))
+	}
+	return e.String()
+}
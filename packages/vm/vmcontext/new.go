@@ -1,12 +1,15 @@
 package vmcontext
 
 import (
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
 	valuetransaction "github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
 	"github.com/iotaledger/hive.go/logger"
 	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv/buffered"
 	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/publisher"
 	"github.com/iotaledger/wasp/packages/state"
 	"github.com/iotaledger/wasp/packages/vm"
 	"github.com/iotaledger/wasp/packages/vm/core/root"
@@ -18,29 +21,43 @@ import (
 type VMContext struct {
 	// same for the block
 	chainID      coretypes.ChainID
+	chainAddress address.Address
 	chainOwnerID coretypes.AgentID
 	processors   *processors.ProcessorCache
 	balances     map[valuetransaction.ID][]*balance.Balance
 	txBuilder    *statetxbuilder.Builder // mutated
 	virtualState state.VirtualState      // mutated
 	log          *logger.Logger
+	// the state transaction of the previous block, the one which holds the chain token
+	prevStateTxID valuetransaction.ID
+	// the cross-chain requests of the previous block were resolved in the block
+	crossChainResolved bool
 	// fee related
 	validatorFeeTarget coretypes.AgentID // provided by validator
 	feeColor           balance.Color
 	ownerFee           int64
 	validatorFee       int64
+	gasPerToken        int64
+	// gas
+	gasBudget      int64
+	gasBurned      int64
+	gasFeeReserved int64
 	// transfer
 	remainingAfterFees coretypes.ColoredBalances
 	// request context
 	entropy        hashing.HashValue // mutates with each request
 	reqRef         vm.RequestRefWithFreeTokens
+	senderAgentID  coretypes.AgentID // the sender of the request, resolved with the directory of chain addresses
 	reqHname       coretypes.Hname
 	contractRecord *root.ContractRecord
 	timestamp      int64
 	stateUpdate    state.StateUpdate
 	lastError      error     // mutated
 	lastResult     dict.Dict // mutated. Used only by 'alone'
+	receiptValid   bool      // the request is the authentic receipt of the request posted by the chain
 	callStack      []*callContext
+	// events of the contracts emitted by the request. Published when the block is committed
+	events []*publisher.ContractEvent
 }
 
 type callContext struct {
@@ -58,18 +75,40 @@ type callContext struct {
 // All request tokens are handled for the whole block
 func NewVMContext(task *vm.VMTask, txb *statetxbuilder.Builder) (*VMContext, error) {
 	ret := &VMContext{
-		processors:   task.Processors,
-		chainID:      task.ChainID,
-		balances:     task.Balances,
-		txBuilder:    txb,
-		virtualState: task.VirtualState.Clone(),
-		log:          task.Log,
-		entropy:      task.Entropy,
-		callStack:    make([]*callContext, 0),
+		processors:         task.Processors,
+		chainID:            task.ChainID,
+		chainAddress:       task.Address,
+		balances:           task.Balances,
+		txBuilder:          txb,
+		virtualState:       task.VirtualState.Clone(),
+		log:                task.Log,
+		entropy:            task.Entropy,
+		validatorFeeTarget: task.ValidatorFeeTarget,
+		callStack:          make([]*callContext, 0),
 	}
+	for txid, bals := range task.Balances {
+		for _, b := range bals {
+			if b.Color == task.Color {
+				ret.prevStateTxID = txid
+			}
+		}
+	}
+	// the solid state does not change while the task runs, so DB reads are cached for the whole task
+	ret.virtualState.Variables().SetReadCache(buffered.NewReadCache(buffered.DefaultReadCacheSize))
 	return ret, nil
 }
 
+// ReadCache returns the cache of DB reads of the task
+func (vmctx *VMContext) ReadCache() *buffered.ReadCache {
+	return vmctx.virtualState.Variables().ReadCache()
+}
+
 func (vmctx *VMContext) GetResult() (state.StateUpdate, dict.Dict, error) {
 	return vmctx.stateUpdate, vmctx.lastResult, vmctx.lastError
 }
+
+// GetEvents returns the events emitted by the contracts during the last request.
+// Events of the calls which were rolled back are not included
+func (vmctx *VMContext) GetEvents() []*publisher.ContractEvent {
+	return vmctx.events
+}
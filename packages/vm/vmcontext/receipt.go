@@ -0,0 +1,109 @@
+package vmcontext
+
+import (
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	"github.com/iotaledger/wasp/packages/coretypes/cbalances"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/sctransaction"
+	"github.com/iotaledger/wasp/packages/vm/core/root"
+)
+
+// mustPostReceipt posts the result of the request back to the callback entry point of the sender contract,
+// if the sender asked for it. The request token accrued to the sender pays for the receipt.
+// Requests sent by wallets never get a receipt
+func (vmctx *VMContext) mustPostReceipt() {
+	req := vmctx.reqRef.RequestSection()
+	if req.Callback() == 0 {
+		return
+	}
+	if vmctx.senderAgentID.IsAddress() {
+		return
+	}
+	senderContractID := vmctx.senderAgentID.MustContractID()
+	if !vmctx.debitFromAccount(vmctx.senderAgentID, cbalances.NewFromMap(map[balance.Color]int64{
+		balance.ColorIOTA: 1,
+	})) {
+		vmctx.log.Errorf("mustPostReceipt: not enough funds for the request token of the receipt")
+		return
+	}
+	args := dict.New()
+	if vmctx.lastResult != nil {
+		args.Extend(vmctx.lastResult)
+	}
+	args.Set(root.ParamReceiptRequestID, codec.EncodeRequestID(*vmctx.reqRef.RequestID()))
+	args.Set(root.ParamReceiptNonce, codec.EncodeInt64(int64(req.CallbackNonce())))
+	switch {
+	case vmctx.contractRecord == nil:
+		args.Set(root.ParamReceiptError, codec.EncodeString(root.ErrContractNotFound.Error()))
+	case vmctx.lastError != nil:
+		args.Set(root.ParamReceiptError, codec.EncodeString(vmctx.lastError.Error()))
+	}
+	receipt := sctransaction.NewRequestSection(vmctx.reqHname, senderContractID, req.Callback()).WithArgs(args)
+	receiptAddress := vmctx.chainAddress
+	if senderContractID.ChainID() != vmctx.chainID {
+		receiptAddress = vmctx.targetChainAddress(senderContractID.ChainID())
+	}
+	if err := vmctx.txBuilder.AddRequestSectionToAddress(receipt, receiptAddress); err != nil {
+		vmctx.log.Panicf("mustPostReceipt: %v", err)
+	}
+	vmctx.log.Debugf("mustPostReceipt: receipt of %s posted to %s", vmctx.reqRef.RequestID().Short(), senderContractID.String())
+}
+
+// mustResolveCrossChainRequests sets the ID of the state transaction of the previous block to the records
+// of requests posted in that block. It is done once per block, by the first request
+func (vmctx *VMContext) mustResolveCrossChainRequests() {
+	if vmctx.crossChainResolved {
+		return
+	}
+	vmctx.crossChainResolved = true
+	vmctx.resolveCrossChainRequests()
+}
+
+// mustHandleReceipt deletes the record of the outstanding request when its receipt arrives.
+// The receipt is authentic if it is sent by the target of the request to the callback of the sender
+// and refers to the ID and the nonce of the request, posted by this chain and still waiting for the receipt.
+// The callback is called as any other request, but the receipt parameters are passed to it only if the receipt is authentic
+func (vmctx *VMContext) mustHandleReceipt() {
+	vmctx.receiptValid = false
+	args := vmctx.reqRef.RequestSection().Args()
+	nonce, ok, err := codec.DecodeInt64(args.MustGet(root.ParamReceiptNonce))
+	if err != nil || !ok {
+		return
+	}
+	requestID, ok, err := codec.DecodeRequestID(args.MustGet(root.ParamReceiptRequestID))
+	if err != nil || !ok {
+		return
+	}
+	if vmctx.senderAgentID.IsAddress() {
+		return
+	}
+	if vmctx.removeCrossChainRequest(uint32(nonce), requestID, vmctx.senderAgentID.MustContractID()) {
+		vmctx.receiptValid = true
+		vmctx.log.Debugf("mustHandleReceipt: received receipt of the outstanding request #%d", nonce)
+	}
+}
+
+// stripReceiptParams removes the receipt parameters from the args unless the request is the authentic receipt,
+// so the callback can't be fooled by a forged one
+func (vmctx *VMContext) stripReceiptParams(args dict.Dict) dict.Dict {
+	if vmctx.receiptValid || args == nil {
+		return args
+	}
+	receiptKeys := []kv.Key{root.ParamReceiptRequestID, root.ParamReceiptNonce, root.ParamReceiptError}
+	found := false
+	for _, k := range receiptKeys {
+		if args.MustHas(k) {
+			found = true
+		}
+	}
+	if !found {
+		return args
+	}
+	ret := args.Clone()
+	for _, k := range receiptKeys {
+		ret.Del(k)
+	}
+	return ret
+}
@@ -7,10 +7,12 @@ import (
 	"github.com/iotaledger/wasp/packages/coretypes/cbalances"
 	"github.com/iotaledger/wasp/packages/hashing"
 	"github.com/iotaledger/wasp/packages/kv/buffered"
+	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/iotaledger/wasp/packages/sctransaction"
 	"github.com/iotaledger/wasp/packages/state"
 	"github.com/iotaledger/wasp/packages/vm"
 	"github.com/iotaledger/wasp/packages/vm/core/root"
+	"github.com/iotaledger/wasp/packages/vm/vmtypes"
 )
 
 // runTheRequest:
@@ -25,6 +27,8 @@ func (vmctx *VMContext) RunTheRequest(reqRef vm.RequestRefWithFreeTokens, timest
 		vmctx.mustHandleFees()
 	}
 	vmctx.mustHandleFreeTokens()
+	vmctx.mustResolveCrossChainRequests()
+	vmctx.mustHandleReceipt()
 	defer vmctx.finalizeRequestCall()
 
 	if vmctx.contractRecord == nil {
@@ -34,12 +38,17 @@ func (vmctx *VMContext) RunTheRequest(reqRef vm.RequestRefWithFreeTokens, timest
 	// snapshot state baseline for rollback in case of panic
 	snapshotTxBuilder := vmctx.txBuilder.Clone()
 	snapshotStateUpdate := vmctx.stateUpdate.Clone()
+	snapshotEvents := len(vmctx.events)
 
 	vmctx.lastError = nil
 	func() {
 		// panic catcher for the whole call from request to the VM
 		defer func() {
 			if r := recover(); r != nil {
+				if r == vmtypes.ErrWatchdog {
+					// not deterministic, the whole batch fails
+					panic(r)
+				}
 				vmctx.lastResult = nil
 				vmctx.lastError = fmt.Errorf("recovered from panic in VM: %v", r)
 				if dberr, ok := r.(buffered.DBError); ok {
@@ -56,6 +65,7 @@ func (vmctx *VMContext) RunTheRequest(reqRef vm.RequestRefWithFreeTokens, timest
 		// treating panic and error returned from request the same way
 		vmctx.txBuilder = snapshotTxBuilder
 		vmctx.stateUpdate = snapshotStateUpdate
+		vmctx.events = vmctx.events[:snapshotEvents]
 
 		vmctx.mustHandleFallback()
 	}
@@ -73,11 +83,11 @@ func (vmctx *VMContext) mustHandleRequestToken() {
 		vmctx.log.Panicf("mustHandleRequestToken: can't erase request token: %s", reqColor.String())
 	}
 	// always accrue 1 uncolored iota to the sender on-chain. This makes completely fee-less requests possible
-	vmctx.creditToAccount(vmctx.reqRef.SenderAgentID(), cbalances.NewFromMap(map[balance.Color]int64{
+	vmctx.creditToAccount(vmctx.senderAgentID, cbalances.NewFromMap(map[balance.Color]int64{
 		balance.ColorIOTA: 1,
 	}))
 	vmctx.remainingAfterFees = vmctx.reqRef.RequestSection().Transfer()
-	vmctx.log.Debugf("mustHandleFees: 1 request token accrued to the sender: %s\n", vmctx.reqRef.SenderAgentID())
+	vmctx.log.Debugf("mustHandleFees: 1 request token accrued to the sender: %s\n", vmctx.senderAgentID)
 }
 
 // mustHandleFees:
@@ -86,21 +96,29 @@ func (vmctx *VMContext) mustHandleRequestToken() {
 func (vmctx *VMContext) mustHandleFees() {
 	transfer := vmctx.reqRef.RequestSection().Transfer()
 	totalFee := vmctx.ownerFee + vmctx.validatorFee
-	if totalFee == 0 || vmctx.requesterIsChainOwner() {
+	if vmctx.requesterIsChainOwner() || (totalFee == 0 && vmctx.gasPerToken == 0) {
 		// no fees enabled or the caller is the chain owner
 		vmctx.log.Debugf("mustHandleFees: no fees charged\n")
 		vmctx.remainingAfterFees = transfer
 		return
 	}
+	if totalFee == 0 {
+		vmctx.remainingAfterFees = vmctx.mustReserveGasFee(transfer)
+		return
+	}
 	// handle fees
 	if transfer.Balance(vmctx.feeColor) < totalFee {
 		// TODO more sophisticated policy, for example taking fees to chain owner, the rest returned to sender
 		// fallback: not enough fees. Accrue everything to the sender
-		sender := vmctx.reqRef.SenderAgentID()
+		sender := vmctx.senderAgentID
 		vmctx.creditToAccount(sender, transfer)
 		vmctx.lastError = fmt.Errorf("mustHandleFees: not enough fees for request %s. Transfer accrued to %s",
 			vmctx.reqRef.RequestID().Short(), sender.String())
 		vmctx.remainingAfterFees = cbalances.NewFromMap(nil)
+		if vmctx.gasPerToken > 0 {
+			// gas is not paid for
+			vmctx.gasBudget = 0
+		}
 		return
 	}
 	// enough fees. Split between owner and validator
@@ -119,7 +137,7 @@ func (vmctx *VMContext) mustHandleFees() {
 		vmctx.feeColor: -totalFee,
 	}
 	transfer.AddToMap(remaining)
-	vmctx.remainingAfterFees = cbalances.NewFromMap(remaining)
+	vmctx.remainingAfterFees = vmctx.mustReserveGasFee(cbalances.NewFromMap(remaining))
 }
 
 // mustHandleFreeTokens free tokens accrued to the chain owner
@@ -134,7 +152,7 @@ func (vmctx *VMContext) mustHandleFreeTokens() {
 // -- if sender is address, sent to that address
 // -- otherwise accrue to the sender on-chain
 func (vmctx *VMContext) mustHandleFallback() {
-	sender := vmctx.reqRef.SenderAgentID()
+	sender := vmctx.senderAgentID
 	if sender.IsAddress() {
 		err := vmctx.txBuilder.TransferToAddress(sender.MustAddress(), vmctx.remainingAfterFees)
 		if err != nil {
@@ -150,12 +168,62 @@ func (vmctx *VMContext) mustCallFromRequest() {
 	req := vmctx.reqRef.RequestSection()
 	vmctx.log.Debugf("mustCallFromRequest: %s -- %s\n", vmctx.reqRef.RequestID().String(), req.String())
 
+	args := req.Args()
+	if req.IsOffTangle() {
+		if args, vmctx.lastError = vmctx.offTangleArgs(req); vmctx.lastError != nil {
+			return
+		}
+	}
+	if sctransaction.IsSealedArgs(args) {
+		if args, vmctx.lastError = vmctx.unsealArgs(args); vmctx.lastError != nil {
+			return
+		}
+	}
+	args = vmctx.stripReceiptParams(args)
 	// calling only non vew entry points. Calling the view will trigger error and fallback
 	vmctx.lastResult, vmctx.lastError = vmctx.callNonViewByProgramHash(
-		vmctx.reqHname, req.EntryPointCode(), req.Args(), vmctx.remainingAfterFees, vmctx.contractRecord.ProgramHash)
+		vmctx.reqHname, req.EntryPointCode(), args, vmctx.remainingAfterFees, vmctx.contractRecord.ProgramHash)
+}
+
+// offTangleArgs returns args of the request delivered off-tangle and checks them against the hash in the request
+func (vmctx *VMContext) offTangleArgs(req *sctransaction.RequestSection) (dict.Dict, error) {
+	h, err := req.OffTangleArgsHash()
+	if err != nil {
+		return nil, err
+	}
+	if vmctx.reqRef.OffTangleArgs == nil {
+		return nil, fmt.Errorf("off-tangle args of the request %s are not available", vmctx.reqRef.RequestID().Short())
+	}
+	if sctransaction.HashOffTangleArgs(vmctx.reqRef.OffTangleArgs) != h {
+		return nil, fmt.Errorf("off-tangle args of the request %s don't match the hash", vmctx.reqRef.RequestID().Short())
+	}
+	return vmctx.reqRef.OffTangleArgs, nil
+}
+
+// unsealArgs decrypts args of the request encrypted to the committee.
+// The error becomes part of the state (receipt, event log), so all reasons of the failure are
+// reported as the same error on every node. The reason itself is only logged
+func (vmctx *VMContext) unsealArgs(args dict.Dict) (dict.Dict, error) {
+	ret, err := func() (dict.Dict, error) {
+		if vmctx.reqRef.UnsealKey == nil {
+			return nil, fmt.Errorf("key is not recovered by the committee")
+		}
+		sealed, err := sctransaction.SealedArgsFromDict(args)
+		if err != nil {
+			return nil, err
+		}
+		return sealed.Unseal(vmctx.reqRef.UnsealKey)
+	}()
+	if err != nil {
+		vmctx.log.Warnf("unsealArgs: request %s: %v", vmctx.reqRef.RequestID().Short(), err)
+		return nil, fmt.Errorf("sealed args of the request %s can't be unsealed", vmctx.reqRef.RequestID().Short())
+	}
+	return ret, nil
 }
 
 func (vmctx *VMContext) finalizeRequestCall() {
+	vmctx.mustSettleGasFee()
+	vmctx.mustPostReceipt()
 	vmctx.mustRequestToEventLog(vmctx.lastError)
 	vmctx.virtualState.ApplyStateUpdate(vmctx.stateUpdate)
 
@@ -189,6 +257,7 @@ func (vmctx *VMContext) mustGetBaseValues() {
 	}
 	vmctx.chainOwnerID = info.ChainOwnerID
 	vmctx.feeColor, vmctx.ownerFee, vmctx.validatorFee = vmctx.getFeeInfo()
+	vmctx.gasPerToken = vmctx.getGasPerToken()
 }
 
 // initRequestContext initializes VMContext for request and returns  if contract exists
@@ -200,9 +269,12 @@ func (vmctx *VMContext) initRequestContext(reqRef vm.RequestRefWithFreeTokens, t
 	vmctx.timestamp = timestamp
 	vmctx.stateUpdate = state.NewStateUpdate(reqRef.RequestID()).WithTimestamp(timestamp)
 	vmctx.callStack = vmctx.callStack[:0]
+	vmctx.events = nil
 	vmctx.entropy = hashing.HashData(vmctx.entropy[:])
 	vmctx.remainingAfterFees = cbalances.NewFromMap(nil)
+	vmctx.initGasBudget(reqRef.RequestSection().GasLimit())
 
+	vmctx.senderAgentID = vmctx.resolveSender()
 	vmctx.contractRecord, _ = vmctx.findContractByHname(vmctx.reqHname)
 }
 
@@ -217,6 +289,12 @@ func (vmctx *VMContext) FinalizeTransactionEssence(blockIndex uint32, stateHash
 	if err != nil {
 		return nil, err
 	}
+	// if the committee was rotated, the chain token and funds are moved to the new address
+	if info, err := vmctx.getChainInfo(); err == nil && info.ChainAddress != vmctx.chainAddress {
+		vmctx.log.Infof("committee rotation: chain is moved from %s to %s",
+			vmctx.chainAddress.String(), info.ChainAddress.String())
+		vmctx.txBuilder.RotateTo(info.ChainAddress)
+	}
 	tx, err := vmctx.txBuilder.Build()
 	if err != nil {
 		return nil, err
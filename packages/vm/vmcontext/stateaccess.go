@@ -46,15 +46,16 @@ func (s stateWrapper) Has(name kv.Key) (bool, error) {
 
 func (s stateWrapper) Iterate(prefix kv.Key, f func(kv.Key, []byte) bool) error {
 	prefix = s.addContractSubPartition(prefix)
-	seen, done := s.stateUpdate.Mutations().IterateValues(prefix, func(key kv.Key, value []byte) bool {
+	muts := s.stateUpdate.Mutations()
+	done := muts.IterateValues(prefix, func(key kv.Key, value []byte) bool {
 		return f(key[len(s.contractSubPartitionPrefix):], value)
 	})
 	if done {
 		return nil
 	}
 	return s.virtualState.Variables().Iterate(prefix, func(key kv.Key, value []byte) bool {
-		_, ok := seen[key]
-		if ok {
+		if muts.Latest(key) != nil {
+			// already seen or deleted
 			return true
 		}
 		return f(key[len(s.contractSubPartitionPrefix):], value)
@@ -63,15 +64,16 @@ func (s stateWrapper) Iterate(prefix kv.Key, f func(kv.Key, []byte) bool) error
 
 func (s stateWrapper) IterateKeys(prefix kv.Key, f func(key kv.Key) bool) error {
 	prefix = s.addContractSubPartition(prefix)
-	seen, done := s.stateUpdate.Mutations().IterateValues(prefix, func(key kv.Key, value []byte) bool {
+	muts := s.stateUpdate.Mutations()
+	done := muts.IterateValues(prefix, func(key kv.Key, value []byte) bool {
 		return f(key[len(s.contractSubPartitionPrefix):])
 	})
 	if done {
 		return nil
 	}
 	return s.virtualState.Variables().IterateKeys(prefix, func(key kv.Key) bool {
-		_, ok := seen[key]
-		if ok {
+		if muts.Latest(key) != nil {
+			// already seen or deleted
 			return true
 		}
 		return f(key[len(s.contractSubPartitionPrefix):])
@@ -92,6 +94,11 @@ func (s stateWrapper) Del(name kv.Key) {
 	s.stateUpdate.Mutations().Add(buffered.NewMutationDel(name))
 }
 
+func (s stateWrapper) DelPrefix(prefix kv.Key) {
+	prefix = s.addContractSubPartition(prefix)
+	s.stateUpdate.Mutations().Add(buffered.NewMutationDelPrefix(prefix))
+}
+
 func (s stateWrapper) Set(name kv.Key, value []byte) {
 	name = s.addContractSubPartition(name)
 	s.stateUpdate.Mutations().Add(buffered.NewMutationSet(name, value))
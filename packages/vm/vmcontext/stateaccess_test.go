@@ -77,3 +77,38 @@ func TestIterate(t *testing.T) {
 		return true
 	})
 }
+
+func TestDelPrefix(t *testing.T) {
+	db := mapdb.NewMapDB()
+
+	chainID := coretypes.ChainID{1, 3, 3, 7}
+
+	virtualState := state.NewVirtualState(db, &chainID)
+	hname := coretypes.Hn("test")
+
+	// variables of the contract in the virtual state
+	prev := state.NewStateUpdate(nil)
+	newStateWrapper(hname, virtualState, prev).Set("x1", []byte{1})
+	newStateWrapper(hname, virtualState, prev).Set("y", []byte{2})
+	virtualState.ApplyStateUpdate(prev)
+
+	stateUpdate := state.NewStateUpdate(nil)
+	s := newStateWrapper(hname, virtualState, stateUpdate)
+	s.Set("x2", []byte{3})
+	s.DelPrefix("x")
+
+	assert.False(t, s.MustHas("x1"))
+	assert.False(t, s.MustHas("x2"))
+	assert.True(t, s.MustHas("y"))
+
+	n := 0
+	s.MustIterateKeys("", func(k kv.Key) bool {
+		assert.EqualValues(t, "y", string(k))
+		n++
+		return true
+	})
+	assert.Equal(t, 1, n)
+
+	// deletion of the prefix is a single mutation
+	assert.Equal(t, 2, stateUpdate.Mutations().Len())
+}
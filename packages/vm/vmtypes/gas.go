@@ -0,0 +1,37 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package vmtypes
+
+import "errors"
+
+// gas costs of the sandbox operations
+const (
+	// GasPerHostCall is burned by each call from the Wasm code to the host
+	GasPerHostCall = 1
+	// GasPerByteStored is burned for each byte of key and value written to the state
+	GasPerByteStored = 1
+	// GasStateDelete is burned for each deleted key
+	GasStateDelete = 10
+	// GasCall is burned for each call to another contract
+	GasCall = 100
+	// GasEvent is burned for each event published by the contract
+	GasEvent = 100
+	// GasTransfer is burned for each move of tokens between accounts or to the address
+	GasTransfer = 200
+	// GasPostRequest is burned for each request posted by the contract
+	GasPostRequest = 500
+	// GasDeployContract is burned for each contract deployment
+	GasDeployContract = 10000
+)
+
+// MaxGasPerRequest is the gas budget of the request, which does not specify its own gas limit.
+// Gas limit of the request can't be bigger than that
+const MaxGasPerRequest = 10000000
+
+// ErrOutOfGas is the reason of the panic when the request exceeds its gas budget
+var ErrOutOfGas = errors.New("out of gas")
+
+// ErrWatchdog is the reason of the panic when the Wasm code runs longer than the wall clock watchdog allows.
+// The watchdog is not deterministic, so the panic fails the whole batch instead of the request
+var ErrWatchdog = errors.New("VM watchdog timeout")
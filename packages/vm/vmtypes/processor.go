@@ -19,7 +19,9 @@ type Processor interface {
 }
 
 // EntryPoint is an abstract interface by which VM is called by passing
-// the Sandbox interface
+// the Sandbox interface.
+// WithGasLimit is called by the VM before each call with the remaining gas budget of the request.
+// The processor must not consume more than that. Sandbox operations are metered by the VM itself
 type EntryPoint interface {
 	WithGasLimit(int) EntryPoint
 	Call(ctx Sandbox) (dict.Dict, error)
@@ -29,6 +31,8 @@ type EntryPoint interface {
 
 var ErrWrongTypeEntryPoint = fmt.Errorf("wrong type of entry point")
 
+var ErrEntryPointNotFound = fmt.Errorf("entry point not found")
+
 // nilEntryPoint is the entry point implementation which does nothing when called
 type nilEntryPoint bool
 
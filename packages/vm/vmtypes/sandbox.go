@@ -72,14 +72,26 @@ type Sandbox interface {
 	// Event publishes "vmmsg" message through Publisher on nanomsg
 	// it also logs locally, but it is not the same thing
 	Event(msg string)
+	// EmitEvent stores the structured event in the 'eventlog' contract, indexed by the name of the event
+	// and by the value of each topic (at most eventlog.MaxTopics). The event is also published as "vmmsg" message
+	EmitEvent(name string, topics dict.Dict, payload dict.Dict)
+
+	// BurnGas charges gas to the budget of the request. Panics with ErrOutOfGas when the budget is exceeded
+	BurnGas(amount int64)
+	// GasRemaining is the gas left in the budget of the request
+	GasRemaining() int64
 }
 
 type PostRequestParams struct {
 	TargetContractID coretypes.ContractID
 	EntryPoint       coretypes.Hname
 	TimeLock         uint32
+	GasLimit         uint32
 	Params           dict.Dict
 	Transfer         coretypes.ColoredBalances
+	// Callback is the entry point of the calling contract which receives the receipt of the request
+	// when the target has processed it. 0 means no receipt. See root.ParamReceiptRequestID
+	Callback coretypes.Hname
 }
 
 type LogInterface interface {
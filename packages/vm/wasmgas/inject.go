@@ -0,0 +1,384 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+// Package wasmgas instruments Wasm modules with deterministic gas metering.
+// A mutable i64 global, exported as GasGlobal, holds the gas left. The gas is charged at the entry
+// of each function and at the start of each iteration of each loop: the cost is the number of
+// instructions in the function or in the loop body (instructions of nested loops excluded).
+// The code traps with `unreachable` as soon as the gas left is negative.
+// The host sets the gas budget to the global before the call and reads the gas left after it.
+// The count of instructions is the same on every node, so is the result of the call
+package wasmgas
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// GasGlobal is the name of the exported global with the gas left
+const GasGlobal = "__wasp_gas_left"
+
+const (
+	sectionCustom    = 0
+	sectionImport    = 2
+	sectionGlobal    = 6
+	sectionExport    = 7
+	sectionCode      = 10
+	sectionDataCount = 12
+
+	importKindGlobal = 3
+	exportKindGlobal = 3
+)
+
+// order of the known sections in the module. The data count section precedes the code section
+var sectionOrder = map[byte]int{
+	1: 1, 2: 2, 3: 3, 4: 4, 5: 5, 6: 6, 7: 7, 8: 8, 9: 9, sectionDataCount: 10, sectionCode: 11, 11: 12,
+}
+
+var wasmHeader = []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+
+type section struct {
+	id      byte
+	payload []byte
+}
+
+// Inject returns the module instrumented with gas metering
+func Inject(wasm []byte) ([]byte, error) {
+	if len(wasm) < len(wasmHeader) || !bytes.Equal(wasm[:len(wasmHeader)], wasmHeader) {
+		return nil, errors.New("wasmgas: not a Wasm module")
+	}
+	sections, err := readSections(wasm[len(wasmHeader):])
+	if err != nil {
+		return nil, err
+	}
+	numGlobals, err := countGlobals(sections)
+	if err != nil {
+		return nil, err
+	}
+	gasGlobal := numGlobals
+
+	sections = upsertSection(sections, sectionGlobal, func(payload []byte) ([]byte, error) {
+		// i64, mutable, initialized with 0
+		return appendToVector(payload, []byte{0x7e, 0x01, 0x42, 0x00, 0x0b})
+	})
+	sections = upsertSection(sections, sectionExport, func(payload []byte) ([]byte, error) {
+		entry := appendName(nil, GasGlobal)
+		entry = append(entry, exportKindGlobal)
+		entry = appendU32(entry, gasGlobal)
+		return appendToVector(payload, entry)
+	})
+	hasCode := false
+	for _, s := range sections {
+		if s.id != sectionCode {
+			continue
+		}
+		hasCode = true
+		if s.payload, err = injectCode(s.payload, gasGlobal); err != nil {
+			return nil, err
+		}
+	}
+	if !hasCode {
+		return nil, errors.New("wasmgas: module without code")
+	}
+
+	ret := append([]byte{}, wasmHeader...)
+	for _, s := range sections {
+		if s.id == sectionCustom {
+			// custom sections (names, debug info) would refer to the original code
+			continue
+		}
+		ret = append(ret, s.id)
+		ret = appendU32(ret, uint32(len(s.payload)))
+		ret = append(ret, s.payload...)
+	}
+	return ret, nil
+}
+
+func readSections(data []byte) ([]*section, error) {
+	r := &reader{data: data}
+	ret := make([]*section, 0)
+	for !r.eof() {
+		id, err := r.byte()
+		if err != nil {
+			return nil, err
+		}
+		size, err := r.u32()
+		if err != nil {
+			return nil, err
+		}
+		payload, err := r.bytes(int(size))
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, &section{id: id, payload: payload})
+	}
+	return ret, nil
+}
+
+// countGlobals returns the number of imported and defined globals, which is the index of the next global
+func countGlobals(sections []*section) (uint32, error) {
+	var ret uint32
+	for _, s := range sections {
+		switch s.id {
+		case sectionImport:
+			n, err := countImportedGlobals(s.payload)
+			if err != nil {
+				return 0, err
+			}
+			ret += n
+		case sectionGlobal:
+			n, err := (&reader{data: s.payload}).u32()
+			if err != nil {
+				return 0, err
+			}
+			ret += n
+		}
+	}
+	return ret, nil
+}
+
+func countImportedGlobals(payload []byte) (uint32, error) {
+	r := &reader{data: payload}
+	n, err := r.u32()
+	if err != nil {
+		return 0, err
+	}
+	var ret uint32
+	for i := uint32(0); i < n; i++ {
+		// module and field names
+		for j := 0; j < 2; j++ {
+			size, err := r.u32()
+			if err != nil {
+				return 0, err
+			}
+			if _, err = r.bytes(int(size)); err != nil {
+				return 0, err
+			}
+		}
+		kind, err := r.byte()
+		if err != nil {
+			return 0, err
+		}
+		switch kind {
+		case 0: // function: type index
+			_, err = r.u32()
+		case 1: // table: reference type and limits
+			if _, err = r.byte(); err == nil {
+				err = r.limits()
+			}
+		case 2: // memory: limits
+			err = r.limits()
+		case importKindGlobal: // global: value type and mutability
+			_, err = r.bytes(2)
+			ret++
+		default:
+			err = fmt.Errorf("wasmgas: unknown import kind %d", kind)
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	return ret, nil
+}
+
+// upsertSection modifies the payload of the section or inserts the section with the payload modified from
+// the empty vector at its place in the order of sections
+func upsertSection(sections []*section, id byte, modify func(payload []byte) ([]byte, error)) []*section {
+	for _, s := range sections {
+		if s.id == id {
+			payload, err := modify(s.payload)
+			if err != nil {
+				// can't happen with the count read from the same payload by countGlobals
+				panic(err)
+			}
+			s.payload = payload
+			return sections
+		}
+	}
+	payload, err := modify([]byte{0x00})
+	if err != nil {
+		panic(err)
+	}
+	pos := len(sections)
+	for i, s := range sections {
+		if s.id != sectionCustom && sectionOrder[s.id] > sectionOrder[id] {
+			pos = i
+			break
+		}
+	}
+	ret := make([]*section, 0, len(sections)+1)
+	ret = append(ret, sections[:pos]...)
+	ret = append(ret, &section{id: id, payload: payload})
+	return append(ret, sections[pos:]...)
+}
+
+// appendToVector increments the count of the vector and appends the encoded element to it
+func appendToVector(payload []byte, elem []byte) ([]byte, error) {
+	r := &reader{data: payload}
+	n, err := r.u32()
+	if err != nil {
+		return nil, err
+	}
+	ret := appendU32(nil, n+1)
+	ret = append(ret, payload[r.pos:]...)
+	return append(ret, elem...), nil
+}
+
+func injectCode(payload []byte, gasGlobal uint32) ([]byte, error) {
+	r := &reader{data: payload}
+	n, err := r.u32()
+	if err != nil {
+		return nil, err
+	}
+	ret := appendU32(nil, n)
+	for i := uint32(0); i < n; i++ {
+		size, err := r.u32()
+		if err != nil {
+			return nil, err
+		}
+		body, err := r.bytes(int(size))
+		if err != nil {
+			return nil, err
+		}
+		body, err = injectFunction(body, gasGlobal)
+		if err != nil {
+			return nil, fmt.Errorf("wasmgas: function #%d: %v", i, err)
+		}
+		ret = appendU32(ret, uint32(len(body)))
+		ret = append(ret, body...)
+	}
+	if !r.eof() {
+		return nil, errors.New("wasmgas: trailing bytes in code section")
+	}
+	return ret, nil
+}
+
+type instruction struct {
+	start, end int
+	opcode     byte
+	// frame is the index of the frame which is charged for the instruction
+	frame int
+	// meter is the index of the frame which is charged after this instruction (loop), -1 if none
+	meter int
+}
+
+func injectFunction(body []byte, gasGlobal uint32) ([]byte, error) {
+	r := &reader{data: body}
+	// local declarations
+	n, err := r.u32()
+	if err != nil {
+		return nil, err
+	}
+	for i := uint32(0); i < n; i++ {
+		if _, err = r.u32(); err != nil {
+			return nil, err
+		}
+		if _, err = r.byte(); err != nil {
+			return nil, err
+		}
+	}
+	codeStart := r.pos
+
+	// frame 0 is the function itself. Each loop opens a new frame. Blocks and ifs stay in the frame of the
+	// enclosing loop or function, so they are marked with -1 on the control stack
+	costs := []int64{0}
+	frames := []int{0}
+	control := []int{0}
+	instructions := make([]*instruction, 0)
+	for len(control) > 0 {
+		if r.eof() {
+			return nil, errors.New("unexpected end of code")
+		}
+		ins := &instruction{start: r.pos, frame: frames[len(frames)-1], meter: -1}
+		ins.opcode, err = r.byte()
+		if err != nil {
+			return nil, err
+		}
+		if err = r.skipImmediates(ins.opcode); err != nil {
+			return nil, err
+		}
+		ins.end = r.pos
+		instructions = append(instructions, ins)
+
+		switch ins.opcode {
+		case 0x02, 0x04: // block, if
+			control = append(control, -1)
+		case 0x03: // loop
+			costs = append(costs, 0)
+			frame := len(costs) - 1
+			frames = append(frames, frame)
+			control = append(control, frame)
+			ins.meter = frame
+		case 0x0b: // end
+			if control[len(control)-1] >= 0 {
+				frames = frames[:len(frames)-1]
+			}
+			control = control[:len(control)-1]
+			continue
+		}
+		costs[ins.frame]++
+	}
+	if !r.eof() {
+		return nil, errors.New("trailing bytes after the end of the function")
+	}
+
+	ret := append([]byte{}, body[:codeStart]...)
+	ret = appendMeter(ret, gasGlobal, costs[0])
+	for _, ins := range instructions {
+		ret = append(ret, body[ins.start:ins.end]...)
+		if ins.meter >= 0 {
+			ret = appendMeter(ret, gasGlobal, costs[ins.meter])
+		}
+	}
+	return ret, nil
+}
+
+// appendMeter appends the code which subtracts the cost from the gas global and traps if the result is negative.
+// The code leaves the operand stack as it was
+func appendMeter(code []byte, gasGlobal uint32, cost int64) []byte {
+	if cost == 0 {
+		return code
+	}
+	code = append(code, 0x23) // global.get
+	code = appendU32(code, gasGlobal)
+	code = append(code, 0x42) // i64.const
+	code = appendS64(code, cost)
+	code = append(code, 0x7d) // i64.sub
+	code = append(code, 0x24) // global.set
+	code = appendU32(code, gasGlobal)
+	code = append(code, 0x23) // global.get
+	code = appendU32(code, gasGlobal)
+	code = append(code, 0x42, 0x00) // i64.const 0
+	code = append(code, 0x53)       // i64.lt_s
+	code = append(code, 0x04, 0x40) // if
+	code = append(code, 0x00)       // unreachable
+	return append(code, 0x0b)       // end
+}
+
+func appendName(buf []byte, name string) []byte {
+	buf = appendU32(buf, uint32(len(name)))
+	return append(buf, name...)
+}
+
+func appendU32(buf []byte, v uint32) []byte {
+	for {
+		b := byte(v & 0x7f)
+		v >>= 7
+		if v == 0 {
+			return append(buf, b)
+		}
+		buf = append(buf, b|0x80)
+	}
+}
+
+func appendS64(buf []byte, v int64) []byte {
+	for {
+		b := byte(v & 0x7f)
+		v >>= 7
+		if (v == 0 && b&0x40 == 0) || (v == -1 && b&0x40 != 0) {
+			return append(buf, b)
+		}
+		buf = append(buf, b|0x80)
+	}
+}
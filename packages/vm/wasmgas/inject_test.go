@@ -0,0 +1,121 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package wasmgas
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// module with one function:
+//
+//	(func (param i32) (result i32)
+//	  local.get 0
+//	  drop
+//	  loop
+//	    local.get 0
+//	    i32.const 1
+//	    i32.sub
+//	    local.tee 0
+//	    br_if 0
+//	  end
+//	  i32.const 7)
+var testModule = []byte{
+	0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00,
+	// type section: (i32) -> i32
+	0x01, 0x06, 0x01, 0x60, 0x01, 0x7f, 0x01, 0x7f,
+	// function section
+	0x03, 0x02, 0x01, 0x00,
+	// code section
+	0x0a, 0x15, 0x01, 0x13, 0x00,
+	0x20, 0x00, 0x1a,
+	0x03, 0x40,
+	0x20, 0x00, 0x41, 0x01, 0x6b, 0x22, 0x00, 0x0d, 0x00,
+	0x0b,
+	0x41, 0x07,
+	0x0b,
+}
+
+func TestInjectFunction(t *testing.T) {
+	ret, err := Inject(testModule)
+	require.NoError(t, err)
+
+	sections, err := readSections(ret[len(wasmHeader):])
+	require.NoError(t, err)
+	ids := make([]byte, 0)
+	for _, s := range sections {
+		ids = append(ids, s.id)
+	}
+	// the global and export sections are inserted in order
+	require.EqualValues(t, []byte{1, 3, sectionGlobal, sectionExport, sectionCode}, ids)
+	require.EqualValues(t, []byte{0x01, 0x7e, 0x01, 0x42, 0x00, 0x0b}, sections[2].payload)
+
+	// the function is charged 4 instructions: local.get, drop, loop, i32.const
+	// the loop is charged 5 instructions per iteration: local.get, i32.const, i32.sub, local.tee, br_if
+	code := sections[4].payload
+	entry := appendMeter(nil, 0, 4)
+	iteration := appendMeter(nil, 0, 5)
+	require.True(t, bytes.Contains(code, append([]byte{0x00}, entry...)))
+	require.True(t, bytes.Contains(code, append([]byte{0x03, 0x40}, iteration...)))
+	requireValidCode(t, code)
+}
+
+// requireValidCode checks that the instrumented functions decode up to their end
+func requireValidCode(t *testing.T, code []byte) {
+	_, err := injectCode(code, 0)
+	require.NoError(t, err)
+}
+
+func TestInjectErrors(t *testing.T) {
+	_, err := Inject([]byte("not wasm"))
+	require.Error(t, err)
+
+	// truncated code
+	_, err = Inject(testModule[:len(testModule)-3])
+	require.Error(t, err)
+
+	// SIMD instruction is not supported
+	simd := append([]byte{}, testModule...)
+	simd[len(wasmHeader)+8+4+5+3] = 0xfd
+	_, err = Inject(simd)
+	require.Error(t, err)
+}
+
+func TestAppendS64(t *testing.T) {
+	require.EqualValues(t, []byte{0x00}, appendS64(nil, 0))
+	require.EqualValues(t, []byte{0x3f}, appendS64(nil, 63))
+	require.EqualValues(t, []byte{0xc0, 0x00}, appendS64(nil, 64))
+	require.EqualValues(t, []byte{0x7f}, appendS64(nil, -1))
+	require.EqualValues(t, []byte{0x80, 0x7f}, appendS64(nil, -128))
+}
+
+func TestInjectContracts(t *testing.T) {
+	files, err := filepath.Glob("../../../tools/cluster/tests/wasptest_new/wasm/*.wasm")
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+	for _, file := range files {
+		wasm, err := ioutil.ReadFile(file)
+		require.NoError(t, err)
+		ret, err := Inject(wasm)
+		require.NoError(t, err, file)
+
+		sections, err := readSections(ret[len(wasmHeader):])
+		require.NoError(t, err)
+		numGlobals, err := countGlobals(sections)
+		require.NoError(t, err)
+		for _, s := range sections {
+			switch s.id {
+			case sectionExport:
+				require.True(t, bytes.Contains(s.payload, appendU32(appendName(nil, GasGlobal), exportKindGlobal)), file)
+				require.True(t, bytes.HasSuffix(s.payload, appendU32(nil, numGlobals-1)), file)
+			case sectionCode:
+				requireValidCode(t, s.payload)
+			}
+		}
+	}
+}
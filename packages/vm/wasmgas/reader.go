@@ -0,0 +1,185 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package wasmgas
+
+import (
+	"errors"
+	"fmt"
+)
+
+var errUnexpectedEnd = errors.New("wasmgas: unexpected end of data")
+
+type reader struct {
+	data []byte
+	pos  int
+}
+
+func (r *reader) eof() bool {
+	return r.pos >= len(r.data)
+}
+
+func (r *reader) byte() (byte, error) {
+	if r.eof() {
+		return 0, errUnexpectedEnd
+	}
+	b := r.data[r.pos]
+	r.pos++
+	return b, nil
+}
+
+func (r *reader) bytes(n int) ([]byte, error) {
+	if n < 0 || len(r.data)-r.pos < n {
+		return nil, errUnexpectedEnd
+	}
+	ret := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return ret, nil
+}
+
+func (r *reader) u32() (uint32, error) {
+	var ret uint32
+	for shift := uint(0); shift < 35; shift += 7 {
+		b, err := r.byte()
+		if err != nil {
+			return 0, err
+		}
+		ret |= uint32(b&0x7f) << shift
+		if b&0x80 == 0 {
+			return ret, nil
+		}
+	}
+	return 0, errors.New("wasmgas: u32 too long")
+}
+
+// sleb skips a signed LEB128 of at most 64 bits
+func (r *reader) sleb() error {
+	for i := 0; i < 10; i++ {
+		b, err := r.byte()
+		if err != nil {
+			return err
+		}
+		if b&0x80 == 0 {
+			return nil
+		}
+	}
+	return errors.New("wasmgas: integer too long")
+}
+
+func (r *reader) limits() error {
+	flags, err := r.byte()
+	if err != nil {
+		return err
+	}
+	if _, err = r.u32(); err != nil {
+		return err
+	}
+	if flags&0x01 != 0 {
+		_, err = r.u32()
+	}
+	return err
+}
+
+func (r *reader) skipU32s(n int) error {
+	for i := 0; i < n; i++ {
+		if _, err := r.u32(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// skipImmediates skips the immediate arguments of the instruction
+func (r *reader) skipImmediates(opcode byte) error {
+	var err error
+	switch {
+	case opcode <= 0x01, opcode == 0x05, opcode == 0x0b, opcode == 0x0f, opcode == 0x1a, opcode == 0x1b:
+		// unreachable, nop, else, end, return, drop, select
+	case opcode >= 0x02 && opcode <= 0x04:
+		// block, loop, if: the block type is 0x40, a value type or a type index as signed LEB128
+		err = r.sleb()
+	case opcode == 0x0c, opcode == 0x0d, opcode == 0x10:
+		// br, br_if, call
+		_, err = r.u32()
+	case opcode == 0x0e:
+		// br_table
+		var n uint32
+		if n, err = r.u32(); err == nil {
+			err = r.skipU32s(int(n) + 1)
+		}
+	case opcode == 0x11:
+		// call_indirect: type index and table index
+		err = r.skipU32s(2)
+	case opcode == 0x1c:
+		// select with value types
+		var n uint32
+		if n, err = r.u32(); err == nil {
+			_, err = r.bytes(int(n))
+		}
+	case opcode >= 0x20 && opcode <= 0x26:
+		// local.get, local.set, local.tee, global.get, global.set, table.get, table.set
+		_, err = r.u32()
+	case opcode >= 0x28 && opcode <= 0x3e:
+		// loads and stores: alignment and offset
+		err = r.skipU32s(2)
+	case opcode == 0x3f, opcode == 0x40:
+		// memory.size, memory.grow
+		_, err = r.byte()
+	case opcode == 0x41, opcode == 0x42:
+		// i32.const, i64.const
+		err = r.sleb()
+	case opcode == 0x43:
+		_, err = r.bytes(4)
+	case opcode == 0x44:
+		_, err = r.bytes(8)
+	case opcode >= 0x45 && opcode <= 0xc4:
+		// numeric instructions, including the sign extension ones
+	case opcode == 0xd0:
+		// ref.null: reference type
+		_, err = r.byte()
+	case opcode == 0xd1:
+		// ref.is_null
+	case opcode == 0xd2:
+		// ref.func
+		_, err = r.u32()
+	case opcode == 0xfc:
+		err = r.skipMiscImmediates()
+	default:
+		return fmt.Errorf("wasmgas: unsupported opcode 0x%02x", opcode)
+	}
+	return err
+}
+
+// skipMiscImmediates skips the immediates of the instructions with the 0xfc prefix
+func (r *reader) skipMiscImmediates() error {
+	sub, err := r.u32()
+	if err != nil {
+		return err
+	}
+	switch {
+	case sub <= 7:
+		// saturating truncations
+		return nil
+	case sub == 8:
+		// memory.init: data index and memory index
+		if _, err = r.u32(); err != nil {
+			return err
+		}
+		_, err = r.byte()
+	case sub == 9, sub == 13, sub >= 15 && sub <= 17:
+		// data.drop, elem.drop, table.grow, table.size, table.fill
+		_, err = r.u32()
+	case sub == 10:
+		// memory.copy: two memory indices
+		_, err = r.bytes(2)
+	case sub == 11:
+		// memory.fill: memory index
+		_, err = r.byte()
+	case sub == 12, sub == 14:
+		// table.init, table.copy
+		err = r.skipU32s(2)
+	default:
+		return fmt.Errorf("wasmgas: unsupported opcode 0xfc %d", sub)
+	}
+	return err
+}
@@ -5,6 +5,7 @@ package wasmhost
 
 import (
 	"errors"
+
 	"github.com/iotaledger/hive.go/logger"
 	"github.com/iotaledger/wasp/packages/coretypes"
 )
@@ -15,6 +16,7 @@ type WasmHost struct {
 	codeToFunc  map[uint32]string
 	funcToCode  map[string]uint32
 	funcToIndex map[string]int32
+	gasBurner   func(amount int64)
 }
 
 func (host *WasmHost) InitVM(vm WasmVM, useBase58Keys bool) error {
@@ -33,6 +35,23 @@ func (host *WasmHost) FunctionFromCode(code uint32) string {
 	return host.codeToFunc[code]
 }
 
+// SetGasBurner sets the function which is charged for each call from the Wasm code to the host.
+// nil disables gas metering, for example in view calls
+func (host *WasmHost) SetGasBurner(burner func(amount int64)) {
+	host.gasBurner = burner
+}
+
+// SetGasLimit limits the number of the Wasm instructions the next call to the Wasm code may execute
+func (host *WasmHost) SetGasLimit(limit int64) {
+	host.vm.SetGasLimit(limit)
+}
+
+func (host *WasmHost) burnGas(amount int64) {
+	if host.gasBurner != nil {
+		host.gasBurner(amount)
+	}
+}
+
 func (host *WasmHost) IsView(function string) bool {
 	return (host.funcToIndex[function] & 0x8000) != 0
 }
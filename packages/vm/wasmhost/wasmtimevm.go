@@ -5,9 +5,19 @@ package wasmhost
 
 import (
 	"errors"
+	"sync/atomic"
+	"time"
+
 	"github.com/bytecodealliance/wasmtime-go"
+	"github.com/iotaledger/wasp/packages/vm/vmtypes"
+	"github.com/iotaledger/wasp/packages/vm/wasmgas"
 )
 
+// WatchdogTimeout is the wall clock time after which the running Wasm code is interrupted.
+// The gas limit bounds the Wasm code deterministically, so the watchdog fires only when the node is
+// unable to run the code in time. It fails the whole batch, because other nodes may run the code in time
+const WatchdogTimeout = time.Minute
+
 type WasmTimeVM struct {
 	WasmVmBase
 	instance *wasmtime.Instance
@@ -15,12 +25,26 @@ type WasmTimeVM struct {
 	memory   *wasmtime.Memory
 	module   *wasmtime.Module
 	store    *wasmtime.Store
+	// gas is the global of the instrumented code with the gas left
+	gas *wasmtime.Global
+	// interrupt aborts the running Wasm code when the watchdog fires
+	interrupt *wasmtime.InterruptHandle
+	// watchdogFired is set to 1 by the watchdog
+	watchdogFired int32
 }
 
 func NewWasmTimeVM() *WasmTimeVM {
 	vm := &WasmTimeVM{}
-	vm.store = wasmtime.NewStore(wasmtime.NewEngine())
+	config := wasmtime.NewConfig()
+	config.SetInterruptable(true)
+	vm.store = wasmtime.NewStore(wasmtime.NewEngineWithConfig(config))
 	vm.linker = wasmtime.NewLinker(vm.store)
+	var err error
+	vm.interrupt, err = vm.store.InterruptHandle()
+	if err != nil {
+		// can't happen with interruptable engine
+		panic(err)
+	}
 	return vm
 }
 
@@ -94,7 +118,10 @@ func (vm *WasmTimeVM) LinkHost(impl WasmVM, host *WasmHost) error {
 }
 
 func (vm *WasmTimeVM) LoadWasm(wasmData []byte) error {
-	var err error
+	wasmData, err := wasmgas.Inject(wasmData)
+	if err != nil {
+		return err
+	}
 	vm.module, err = wasmtime.NewModule(vm.store.Engine, wasmData)
 	if err != nil {
 		return err
@@ -111,6 +138,11 @@ func (vm *WasmTimeVM) LoadWasm(wasmData []byte) error {
 	if vm.memory == nil {
 		return errors.New("not a memory type")
 	}
+	gas := vm.instance.GetExport(wasmgas.GasGlobal)
+	if gas == nil || gas.Global() == nil {
+		return errors.New("no gas global export")
+	}
+	vm.gas = gas.Global()
 	return nil
 }
 
@@ -129,8 +161,28 @@ func (vm *WasmTimeVM) RunScFunction(index int32) error {
 		return errors.New("unknown export function: 'on_call_entrypoint'")
 	}
 	frame := vm.PreCall()
+	budget := vm.gasBudget()
+	// the call may be nested in another call to the same code
+	saved := vm.gas.Get().I64()
+	if err := vm.gas.Set(wasmtime.ValI64(budget)); err != nil {
+		return err
+	}
+	timer := time.AfterFunc(WatchdogTimeout, func() {
+		atomic.StoreInt32(&vm.watchdogFired, 1)
+		vm.interrupt.Interrupt()
+	})
 	_, err := export.Func().Call(index)
+	timer.Stop()
+	if atomic.CompareAndSwapInt32(&vm.watchdogFired, 1, 0) {
+		panic(vmtypes.ErrWatchdog)
+	}
+	left := vm.gas.Get().I64()
+	if err := vm.gas.Set(wasmtime.ValI64(saved)); err != nil {
+		return err
+	}
 	vm.PostCall(frame)
+	// the instrumented code traps as soon as the gas left is negative, then burning panics with out of gas
+	vm.host.burnGas(budget - left)
 	return err
 }
 
@@ -6,6 +6,9 @@ package wasmhost
 import (
 	"encoding/binary"
 	"fmt"
+	"math"
+
+	"github.com/iotaledger/wasp/packages/vm/vmtypes"
 )
 
 type WasmVM interface {
@@ -15,6 +18,7 @@ type WasmVM interface {
 	RunScFunction(index int32) error
 	UnsafeMemory() []byte
 	SaveMemory()
+	SetGasLimit(limit int64)
 }
 
 type WasmVmBase struct {
@@ -23,6 +27,7 @@ type WasmVmBase struct {
 	memoryCopy    []byte
 	memoryDirty   bool
 	memoryNonZero int
+	gasLimit      int64
 }
 
 func (vm *WasmVmBase) LinkHost(impl WasmVM, host *WasmHost) error {
@@ -32,8 +37,24 @@ func (vm *WasmVmBase) LinkHost(impl WasmVM, host *WasmHost) error {
 	return nil
 }
 
+// SetGasLimit limits the number of the Wasm instructions the next call may execute. 0 means no limit
+func (vm *WasmVmBase) SetGasLimit(limit int64) {
+	vm.gasLimit = limit
+}
+
+// gasBudget returns the gas limit of the call and resets the limit for the next call
+func (vm *WasmVmBase) gasBudget() int64 {
+	limit := vm.gasLimit
+	vm.gasLimit = 0
+	if limit <= 0 {
+		return math.MaxInt64
+	}
+	return limit
+}
+
 func (vm *WasmVmBase) HostFdWrite(fd int32, iovs int32, size int32, written int32) int32 {
 	host := vm.host
+	host.burnGas(vmtypes.GasPerHostCall)
 	host.TraceAll("HostFdWrite(...)")
 	// very basic implementation that expects fd to be stdout and iovs to be only one element
 	ptr := vm.impl.UnsafeMemory()
@@ -46,6 +67,7 @@ func (vm *WasmVmBase) HostFdWrite(fd int32, iovs int32, size int32, written int3
 
 func (vm *WasmVmBase) HostGetBytes(objId int32, keyId int32, stringRef int32, size int32) int32 {
 	host := vm.host
+	host.burnGas(vmtypes.GasPerHostCall)
 	host.TraceAll("HostGetBytes(o%d,k%d,r%d,s%d)", objId, keyId, stringRef, size)
 
 	// negative size means only check for existence
@@ -79,18 +101,21 @@ func (vm *WasmVmBase) HostGetBytes(objId int32, keyId int32, stringRef int32, si
 
 func (vm *WasmVmBase) HostGetInt(objId int32, keyId int32) int64 {
 	host := vm.host
+	host.burnGas(vmtypes.GasPerHostCall)
 	host.TraceAll("HostGetInt(o%d,k%d)", objId, keyId)
 	return host.GetInt(objId, keyId)
 }
 
 func (vm *WasmVmBase) HostGetIntRef(objId int32, keyId int32, intRef int32) {
 	host := vm.host
+	host.burnGas(vmtypes.GasPerHostCall)
 	host.TraceAll("HostGetIntRef(o%d,k%d,r%d)", objId, keyId, intRef)
 	vm.vmSetInt(intRef, host.GetInt(objId, keyId))
 }
 
 func (vm *WasmVmBase) HostGetKeyId(keyRef int32, size int32) int32 {
 	host := vm.host
+	host.burnGas(vmtypes.GasPerHostCall)
 	host.TraceAll("HostGetKeyId(r%d,s%d)", keyRef, size)
 	// non-negative size means original key was a string
 	if size >= 0 {
@@ -105,12 +130,14 @@ func (vm *WasmVmBase) HostGetKeyId(keyRef int32, size int32) int32 {
 
 func (vm *WasmVmBase) HostGetObjectId(objId int32, keyId int32, typeId int32) int32 {
 	host := vm.host
+	host.burnGas(vmtypes.GasPerHostCall)
 	host.TraceAll("HostGetObjectId(o%d,k%d,t%d)", objId, keyId, typeId)
 	return host.GetObjectId(objId, keyId, typeId)
 }
 
 func (vm *WasmVmBase) HostSetBytes(objId int32, keyId int32, stringRef int32, size int32) {
 	host := vm.host
+	host.burnGas(vmtypes.GasPerHostCall)
 	host.TraceAll("HostSetBytes(o%d,k%d,r%d,s%d)", objId, keyId, stringRef, size)
 	bytes := vm.vmGetBytes(stringRef, size)
 	if objId < 0 {
@@ -122,12 +149,14 @@ func (vm *WasmVmBase) HostSetBytes(objId int32, keyId int32, stringRef int32, si
 
 func (vm *WasmVmBase) HostSetInt(objId int32, keyId int32, value int64) {
 	host := vm.host
+	host.burnGas(vmtypes.GasPerHostCall)
 	host.TraceAll("HostSetInt(o%d,k%d,v%d)", objId, keyId, value)
 	host.SetInt(objId, keyId, value)
 }
 
 func (vm *WasmVmBase) HostSetIntRef(objId int32, keyId int32, intRef int32) {
 	host := vm.host
+	host.burnGas(vmtypes.GasPerHostCall)
 	host.TraceAll("HostSetIntRef(o%d,k%d,r%d)", objId, keyId, intRef)
 	host.SetInt(objId, keyId, vm.vmGetInt(intRef))
 }
@@ -222,12 +222,14 @@ func (o *ScDict) SetInt(keyId int32, value int64) {
 	switch keyId {
 	case wasmhost.KeyLength:
 		if o.kvStore != nil {
-			//TODO this goes wrong for state, should clear map tree instead
-			o.kvStore = dict.New()
-			//if (o.typeId & wasmhost.OBJTYPE_ARRAY) != 0 {
-			//	key := o.NestedKey()[1:]
-			//	o.kvStore.Del(kv.Key(key))
-			//}
+			// clear the whole map tree
+			if o.isRoot {
+				o.kvStore.DelPrefix(kv.EmptyPrefix)
+			} else {
+				key := o.NestedKey()[1:]
+				o.kvStore.DelPrefix(kv.Key(key + "."))
+				o.kvStore.Del(kv.Key(key))
+			}
 		}
 		o.objects = make(map[int32]int32)
 		o.length = 0
@@ -64,6 +64,11 @@ func (host *wasmProcessor) call(ctx vmtypes.Sandbox, ctxView vmtypes.SandboxView
 	host.ctx = ctx
 	host.ctxView = ctxView
 	host.nesting++
+	if ctx != nil {
+		host.SetGasBurner(ctx.BurnGas)
+	} else {
+		host.SetGasBurner(nil)
+	}
 
 	defer func() {
 		host.nesting--
@@ -73,6 +78,11 @@ func (host *wasmProcessor) call(ctx vmtypes.Sandbox, ctxView vmtypes.SandboxView
 		}
 		host.ctx = saveCtx
 		host.ctxView = saveCtxView
+		if saveCtx != nil {
+			host.SetGasBurner(saveCtx.BurnGas)
+		} else {
+			host.SetGasBurner(nil)
+		}
 	}()
 
 	testMode, _ := host.params().Has("testMode")
@@ -128,7 +138,10 @@ func (host *wasmProcessor) IsView() bool {
 	return host.WasmHost.IsView(host.function)
 }
 
-func (host *wasmProcessor) WithGasLimit(_ int) vmtypes.EntryPoint {
+// WithGasLimit bounds the number of the Wasm instructions by the gas budget.
+// The instructions executed and the calls to the host burn the budget
+func (host *wasmProcessor) WithGasLimit(limit int) vmtypes.EntryPoint {
+	host.SetGasLimit(int64(limit))
 	return host
 }
 
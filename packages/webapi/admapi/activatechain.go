@@ -7,6 +7,7 @@ import (
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
 	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/registry"
+	"github.com/iotaledger/wasp/packages/util/auth"
 	"github.com/iotaledger/wasp/packages/webapi/httperrors"
 	"github.com/iotaledger/wasp/packages/webapi/routes"
 	"github.com/iotaledger/wasp/plugins/chains"
@@ -15,11 +16,11 @@ import (
 )
 
 func addChainEndpoints(adm echoswagger.ApiGroup) {
-	adm.POST(routes.ActivateChain(":chainID"), handleActivateChain).
+	adm.POST(routes.ActivateChain(":chainID"), handleActivateChain, requireRole(auth.RoleOperator)).
 		AddParamPath("", "chainID", "ChainID (base58)").
 		SetSummary("Activate a chain")
 
-	adm.POST(routes.DeactivateChain(":chainID"), handleDeactivateChain).
+	adm.POST(routes.DeactivateChain(":chainID"), handleDeactivateChain, requireRole(auth.RoleOperator)).
 		AddParamPath("", "chainID", "ChainID (base58)").
 		SetSummary("Deactivate a chain")
 }
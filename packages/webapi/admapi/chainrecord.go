@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	"github.com/iotaledger/wasp/packages/util/auth"
 	"github.com/iotaledger/wasp/packages/webapi/model"
 	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/registry"
@@ -20,18 +21,19 @@ func addChainRecordEndpoints(adm echoswagger.ApiGroup) {
 		Color:          model.NewColor(&balance.Color{5, 6, 7, 8}),
 		CommitteeNodes: []string{"wasp1:4000", "wasp2:4000"},
 		Active:         false,
+		AccessNodes:    []string{"wasp3:4000"},
 	}
 
-	adm.POST(routes.PutChainRecord(), handlePutChainRecord).
+	adm.POST(routes.PutChainRecord(), handlePutChainRecord, requireRole(auth.RoleOperator)).
 		SetSummary("Create a new chain record").
 		AddParamBody(example, "ChainRecord", "Chain record", true)
 
-	adm.GET(routes.GetChainRecord(":chainID"), handleGetChainRecord).
+	adm.GET(routes.GetChainRecord(":chainID"), handleGetChainRecord, requireRole(auth.RoleReadOnly)).
 		SetSummary("Find the chain record for the given chain ID").
 		AddParamPath("", "chainID", "ChainID (base58)").
 		AddResponse(http.StatusOK, "Chain Record", example, nil)
 
-	adm.GET(routes.ListChainRecords(), handleGetChainRecordList).
+	adm.GET(routes.ListChainRecords(), handleGetChainRecordList, requireRole(auth.RoleReadOnly)).
 		SetSummary("Get the list of chain records in the node").
 		AddResponse(http.StatusOK, "Chain Record", []model.ChainRecord{example}, nil)
 }
@@ -13,6 +13,7 @@ import (
 
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
 	"github.com/iotaledger/wasp/packages/tcrypto"
+	"github.com/iotaledger/wasp/packages/util/auth"
 	"github.com/iotaledger/wasp/packages/webapi/httperrors"
 	"github.com/iotaledger/wasp/packages/webapi/model"
 	"github.com/iotaledger/wasp/packages/webapi/routes"
@@ -38,12 +39,12 @@ func addDKSharesEndpoints(adm echoswagger.ApiGroup) {
 		PeerIndex:    nil,
 	}
 
-	adm.POST(routes.DKSharesPost(), handleDKSharesPost).
+	adm.POST(routes.DKSharesPost(), handleDKSharesPost, requireRole(auth.RoleOperator)).
 		AddParamBody(requestExample, "DKSharesPostRequest", "Request parameters", true).
 		AddResponse(http.StatusOK, "DK shares info", infoExample, nil).
 		SetSummary("Generate a new distributed key")
 
-	adm.GET(routes.DKSharesGet(":sharedAddress"), handleDKSharesGet).
+	adm.GET(routes.DKSharesGet(":sharedAddress"), handleDKSharesGet, requireRole(auth.RoleOperator)).
 		AddParamPath("", "sharedAddress", "Address of the DK share (base58)").
 		AddResponse(http.StatusOK, "DK shares info", infoExample, nil).
 		SetSummary("Get distributed key properties")
@@ -9,6 +9,7 @@ import (
 	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/iotaledger/wasp/packages/kv/subrealm"
 	"github.com/iotaledger/wasp/packages/state"
+	"github.com/iotaledger/wasp/packages/util/auth"
 	"github.com/iotaledger/wasp/packages/webapi/httperrors"
 	"github.com/iotaledger/wasp/packages/webapi/model"
 	"github.com/iotaledger/wasp/packages/webapi/routes"
@@ -17,11 +18,23 @@ import (
 )
 
 func addStateEndpoints(adm echoswagger.ApiGroup) {
-	adm.GET(routes.DumpState(":contractID"), handleDumpSCState).
+	adm.GET(routes.DumpState(":contractID"), handleDumpSCState, requireRole(auth.RoleReadOnly)).
 		AddParamPath("", "contractID", "ContractID").
 		AddResponse(http.StatusOK, "State dump", model.SCStateDump{}, nil).
 		SetSummary("Dump the whole contract state").
 		SetDescription("This may be a dangerous operation if the state is too large. Only for testing use!")
+
+	adm.GET(routes.StateSnapshot(":chainID"), handleGetSnapshot, requireRole(auth.RoleReadOnly)).
+		AddParamPath("", "chainID", "ChainID (base58)").
+		AddResponse(http.StatusOK, "State snapshot", model.StateSnapshot{}, nil).
+		SetSummary("Export the snapshot of the solid state of the chain").
+		SetDescription("The snapshot contains all state variables of the chain and may be large")
+
+	adm.PUT(routes.StateSnapshot(":chainID"), handlePutSnapshot, requireRole(auth.RoleOperator)).
+		AddParamPath("", "chainID", "ChainID (base58)").
+		AddParamBody(model.StateSnapshot{}, "StateSnapshot", "State snapshot", true).
+		SetSummary("Import the snapshot of the solid state of the chain").
+		SetDescription("The snapshot is verified against its state hash. The node must have no state of the chain yet, and the chain must not be active")
 }
 
 func handleDumpSCState(c echo.Context) error {
@@ -31,6 +44,7 @@ func handleDumpSCState(c echo.Context) error {
 	}
 
 	chainID := contractID.ChainID()
+	defer state.AcquireSolidState(&chainID)()
 	virtualState, _, ok, err := state.LoadSolidState(&chainID)
 	if err != nil {
 		return err
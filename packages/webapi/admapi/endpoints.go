@@ -2,9 +2,11 @@ package admapi
 
 import (
 	"net"
-	"strings"
+	"net/http"
 
 	"github.com/iotaledger/hive.go/logger"
+	"github.com/iotaledger/wasp/packages/util/auth"
+	"github.com/iotaledger/wasp/packages/webapi/httperrors"
 	"github.com/labstack/echo/v4"
 	"github.com/pangpanglabs/echoswagger/v2"
 )
@@ -15,21 +17,27 @@ func initLogger() {
 	log = logger.NewLogger("webapi/adm")
 }
 
-func AddEndpoints(adm echoswagger.ApiGroup, adminWhitelist []net.IP) {
+// key of the authenticated auth.Identity in the echo context
+const identityKey = "identity"
+
+// AddEndpoints adds the admin endpoints. If tokenAuth is nil, access is allowed by the IP whitelist
+func AddEndpoints(adm echoswagger.ApiGroup, adminWhitelist []net.IP, tokenAuth *auth.TokenAuth) {
 	initLogger()
 
-	adm.EchoGroup().Use(protected(adminWhitelist))
+	adm.EchoGroup().Use(protected(adminWhitelist, tokenAuth))
 
 	addShutdownEndpoint(adm)
 	addChainRecordEndpoints(adm)
 	addChainEndpoints(adm)
 	addStateEndpoints(adm)
 	addDKSharesEndpoints(adm)
+	addPeeringEndpoints(adm)
 }
 
-// allow only if the remote address is private or in whitelist
-// TODO this is a very basic/limited form of protection
-func protected(whitelist []net.IP) echo.MiddlewareFunc {
+// protected authenticates the caller and writes every admin call to the audit log.
+// With token auth the caller must present a valid token. Otherwise the remote address
+// must be loopback or in the whitelist and the caller is given the operator role
+func protected(whitelist []net.IP, tokenAuth *auth.TokenAuth) echo.MiddlewareFunc {
 	isAllowed := func(ip net.IP) bool {
 		if ip.IsLoopback() {
 			return true
@@ -41,17 +49,63 @@ func protected(whitelist []net.IP) echo.MiddlewareFunc {
 		}
 		return false
 	}
+	authenticate := func(c echo.Context) *auth.Identity {
+		if tokenAuth != nil {
+			id, err := tokenAuth.Authenticate(c.Request())
+			if err != nil {
+				log.Debugf("authentication failed for %s: %v", c.Request().RemoteAddr, err)
+				return nil
+			}
+			return id
+		}
+		host, _, err := net.SplitHostPort(c.Request().RemoteAddr)
+		if err != nil {
+			return nil
+		}
+		ip := net.ParseIP(host)
+		if ip == nil || !isAllowed(ip) {
+			return nil
+		}
+		return &auth.Identity{Subject: host, Role: auth.RoleOperator}
+	}
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			id := authenticate(c)
+			if id == nil {
+				log.Warnf("AUDIT blocking request from %s: %s %s", c.Request().RemoteAddr, c.Request().Method, c.Request().RequestURI)
+				return echo.ErrUnauthorized
+			}
+			c.Set(identityKey, id)
+			err := next(c)
+			log.Infof("AUDIT %s %s by '%s' (%s) from %s: %d",
+				c.Request().Method, c.Request().RequestURI, id.Subject, id.Role, c.Request().RemoteAddr, responseStatus(c, err))
+			return err
+		}
+	}
+}
+
+// requireRole allows the call only if the role of the caller is sufficient
+func requireRole(role string) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
-			parts := strings.Split(c.Request().RemoteAddr, ":")
-			if len(parts) == 2 {
-				ip := net.ParseIP(parts[0])
-				if ip != nil && isAllowed(ip) {
-					return next(c)
-				}
+			id, ok := c.Get(identityKey).(*auth.Identity)
+			if !ok || !id.HasRole(role) {
+				return echo.ErrForbidden
 			}
-			log.Warnf("Blocking request from %s: %s %s", c.Request().RemoteAddr, c.Request().Method, c.Request().RequestURI)
-			return echo.ErrUnauthorized
+			return next(c)
 		}
 	}
 }
+
+// responseStatus is the status of the response which will be sent for the result of the handler
+func responseStatus(c echo.Context, err error) int {
+	switch err := err.(type) {
+	case nil:
+		return c.Response().Status
+	case *echo.HTTPError:
+		return err.Code
+	case *httperrors.HTTPError:
+		return err.Code
+	}
+	return http.StatusInternalServerError
+}
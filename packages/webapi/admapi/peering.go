@@ -0,0 +1,138 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package admapi
+
+// Endpoints for maintaining the list of trusted peering nodes.
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/iotaledger/wasp/packages/peering"
+	"github.com/iotaledger/wasp/packages/util/auth"
+	"github.com/iotaledger/wasp/packages/webapi/httperrors"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/packages/webapi/routes"
+	"github.com/iotaledger/wasp/plugins/dkg"
+	peering_plugin "github.com/iotaledger/wasp/plugins/peering"
+	"github.com/iotaledger/wasp/plugins/registry"
+	"github.com/labstack/echo/v4"
+	"github.com/pangpanglabs/echoswagger/v2"
+)
+
+func addPeeringEndpoints(adm echoswagger.ApiGroup) {
+	example := model.PeeringTrustedNode{
+		PubKey: "8oQ9xHWvfnShRxB22avvjbMyAumZ7EvRLuZtDqa3bPT4",
+		NetID:  "wasp1:4000",
+	}
+
+	adm.GET(routes.PeeringSelfGet(), handlePeeringSelfGet, requireRole(auth.RoleReadOnly)).
+		AddResponse(http.StatusOK, "This node as a peer", example, nil).
+		SetSummary("Basic peer info of the current node")
+
+	adm.GET(routes.PeeringTrustedList(), handlePeeringTrustedList, requireRole(auth.RoleReadOnly)).
+		AddResponse(http.StatusOK, "A list of trusted peers", []model.PeeringTrustedNode{example}, nil).
+		SetSummary("Get a list of trusted peers")
+
+	adm.GET(routes.PeeringTrustedGet(":pubKey"), handlePeeringTrustedGet, requireRole(auth.RoleReadOnly)).
+		AddParamPath("", "pubKey", "Public key of the trusted peer (base58)").
+		AddResponse(http.StatusOK, "Trusted peer info", example, nil).
+		SetSummary("Get trusted peer info")
+
+	adm.PUT(routes.PeeringTrustedPut(), handlePeeringTrustedPut, requireRole(auth.RoleOperator)).
+		AddParamBody(example, "PeeringTrustedNode", "Info of the peer to trust", true).
+		AddResponse(http.StatusOK, "Trusted peer info", example, nil).
+		SetSummary("Trust the specified peer, the NetID of an already trusted peer is updated")
+
+	adm.DELETE(routes.PeeringTrustedDelete(":pubKey"), handlePeeringTrustedDelete, requireRole(auth.RoleOperator)).
+		AddParamPath("", "pubKey", "Public key of the trusted peer (base58)").
+		AddResponse(http.StatusOK, "Trusted peer info", example, nil).
+		SetSummary("Distrust the specified peer")
+}
+
+func handlePeeringSelfGet(c echo.Context) error {
+	self := peering_plugin.DefaultNetworkProvider().Self()
+	resp, err := model.NewPeeringTrustedNode(self.PubKey(), self.NetID())
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+func handlePeeringTrustedList(c echo.Context) error {
+	trustedPeers, err := registry.DefaultRegistry().TrustedPeers()
+	if err != nil {
+		return err
+	}
+	resp := make([]*model.PeeringTrustedNode, len(trustedPeers))
+	for i := range trustedPeers {
+		if resp[i], err = model.NewPeeringTrustedNode(trustedPeers[i].PubKey, trustedPeers[i].NetID); err != nil {
+			return err
+		}
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+func handlePeeringTrustedGet(c echo.Context) error {
+	pubKey, err := model.DecodePeeringPubKey(c.Param("pubKey"), dkg.DefaultNode().GroupSuite())
+	if err != nil {
+		return httperrors.BadRequest(fmt.Sprintf("Invalid pubKey: %v", c.Param("pubKey")))
+	}
+	trustedPeers, err := registry.DefaultRegistry().TrustedPeers()
+	if err != nil {
+		return err
+	}
+	for _, tp := range trustedPeers {
+		if tp.PubKey.Equal(pubKey) {
+			resp, err := model.NewPeeringTrustedNode(tp.PubKey, tp.NetID)
+			if err != nil {
+				return err
+			}
+			return c.JSON(http.StatusOK, resp)
+		}
+	}
+	return httperrors.NotFound(fmt.Sprintf("Trusted peer not found: %v", c.Param("pubKey")))
+}
+
+func handlePeeringTrustedPut(c echo.Context) error {
+	var req model.PeeringTrustedNode
+	if err := c.Bind(&req); err != nil {
+		return httperrors.BadRequest("Invalid request body")
+	}
+	if req.NetID == "" {
+		return httperrors.BadRequest("NetID is missing")
+	}
+	tp, err := req.TrustedPeer(dkg.DefaultNode().GroupSuite())
+	if err != nil {
+		return httperrors.BadRequest(fmt.Sprintf("Invalid pubKey: %v", req.PubKey))
+	}
+	var trusted *peering.TrustedPeer
+	if trusted, err = registry.DefaultRegistry().TrustPeer(tp.PubKey, tp.NetID); err != nil {
+		return err
+	}
+	resp, err := model.NewPeeringTrustedNode(trusted.PubKey, trusted.NetID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+func handlePeeringTrustedDelete(c echo.Context) error {
+	pubKey, err := model.DecodePeeringPubKey(c.Param("pubKey"), dkg.DefaultNode().GroupSuite())
+	if err != nil {
+		return httperrors.BadRequest(fmt.Sprintf("Invalid pubKey: %v", c.Param("pubKey")))
+	}
+	var distrusted *peering.TrustedPeer
+	if distrusted, err = registry.DefaultRegistry().DistrustPeer(pubKey); err != nil {
+		return err
+	}
+	if distrusted == nil {
+		return httperrors.NotFound(fmt.Sprintf("Trusted peer not found: %v", c.Param("pubKey")))
+	}
+	resp, err := model.NewPeeringTrustedNode(distrusted.PubKey, distrusted.NetID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, resp)
+}
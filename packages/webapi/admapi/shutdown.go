@@ -3,6 +3,7 @@ package admapi
 import (
 	"net/http"
 
+	"github.com/iotaledger/wasp/packages/util/auth"
 	"github.com/iotaledger/wasp/packages/webapi/routes"
 	"github.com/iotaledger/wasp/plugins/gracefulshutdown"
 	"github.com/labstack/echo/v4"
@@ -10,7 +11,7 @@ import (
 )
 
 func addShutdownEndpoint(adm echoswagger.ApiGroup) {
-	adm.GET(routes.Shutdown(), handleShutdown).
+	adm.GET(routes.Shutdown(), handleShutdown, requireRole(auth.RoleOperator)).
 		SetSummary("Shut down the node")
 }
 
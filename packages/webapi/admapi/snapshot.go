@@ -0,0 +1,70 @@
+package admapi
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/sctransaction"
+	"github.com/iotaledger/wasp/packages/state"
+	"github.com/iotaledger/wasp/packages/webapi/httperrors"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/plugins/chains"
+	"github.com/iotaledger/wasp/plugins/nodeconn"
+	"github.com/labstack/echo/v4"
+)
+
+// anchorTxTimeout is how long to wait for the anchor transaction of the imported snapshot from the node
+const anchorTxTimeout = 10 * time.Second
+
+func handleGetSnapshot(c echo.Context) error {
+	chainID, err := coretypes.NewChainIDFromBase58(c.Param("chainID"))
+	if err != nil {
+		return httperrors.BadRequest(fmt.Sprintf("Invalid chain id: %s", c.Param("chainID")))
+	}
+	snapshot, err := state.TakeSnapshot(&chainID)
+	if err != nil {
+		return httperrors.NotFound(err.Error())
+	}
+	return c.JSON(http.StatusOK, &model.StateSnapshot{
+		ChainID:    model.NewChainID(&chainID),
+		BlockIndex: snapshot.BlockIndex,
+		StateTxID:  model.NewValueTxID(&snapshot.StateTxID),
+		Data:       snapshot.Bytes(),
+	})
+}
+
+func handlePutSnapshot(c echo.Context) error {
+	chainID, err := coretypes.NewChainIDFromBase58(c.Param("chainID"))
+	if err != nil {
+		return httperrors.BadRequest(fmt.Sprintf("Invalid chain id: %s", c.Param("chainID")))
+	}
+	var req model.StateSnapshot
+	if err := c.Bind(&req); err != nil {
+		return httperrors.BadRequest("Invalid request body")
+	}
+	snapshot, err := state.NewSnapshotFromBytes(req.Data)
+	if err != nil {
+		return httperrors.BadRequest(fmt.Sprintf("Invalid snapshot: %v", err))
+	}
+	if snapshot.ChainID != chainID {
+		return httperrors.BadRequest(fmt.Sprintf("Snapshot of the chain %s can't be imported to the chain %s", snapshot.ChainID, chainID))
+	}
+	if chains.GetChain(chainID) != nil {
+		return httperrors.Conflict(fmt.Sprintf("Chain %s is active", chainID))
+	}
+	vtx, err := nodeconn.GetConfirmedTransaction(&snapshot.StateTxID, anchorTxTimeout)
+	if err != nil {
+		return httperrors.NotFound(fmt.Sprintf("Anchor transaction of the snapshot: %v", err))
+	}
+	anchor, err := sctransaction.ParseValueTransaction(vtx)
+	if err != nil {
+		return httperrors.BadRequest(fmt.Sprintf("Anchor transaction of the snapshot: %v", err))
+	}
+	if err := state.ImportSnapshot(snapshot, anchor); err != nil {
+		return httperrors.BadRequest(err.Error())
+	}
+	log.Infof("imported snapshot of the chain %s, block #%d", chainID, snapshot.BlockIndex)
+	return c.NoContent(http.StatusOK)
+}
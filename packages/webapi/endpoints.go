@@ -4,7 +4,9 @@ import (
 	"net"
 
 	"github.com/iotaledger/hive.go/logger"
+	"github.com/iotaledger/wasp/packages/util/auth"
 	"github.com/iotaledger/wasp/packages/webapi/admapi"
+	"github.com/iotaledger/wasp/packages/webapi/events"
 	"github.com/iotaledger/wasp/packages/webapi/info"
 	"github.com/iotaledger/wasp/packages/webapi/request"
 	"github.com/iotaledger/wasp/packages/webapi/state"
@@ -13,7 +15,7 @@ import (
 
 var log *logger.Logger
 
-func Init(server echoswagger.ApiRoot, adminWhitelist []net.IP) {
+func Init(server echoswagger.ApiRoot, adminWhitelist []net.IP, tokenAuth *auth.TokenAuth) {
 	log = logger.NewLogger("WebAPI")
 
 	server.SetRequestContentType("application/json")
@@ -23,8 +25,9 @@ func Init(server echoswagger.ApiRoot, adminWhitelist []net.IP) {
 	info.AddEndpoints(pub)
 	request.AddEndpoints(pub)
 	state.AddEndpoints(pub)
+	events.AddEndpoints(pub)
 
 	adm := server.Group("admin", "").SetDescription("Admin endpoints")
-	admapi.AddEndpoints(adm, adminWhitelist)
+	admapi.AddEndpoints(adm, adminWhitelist, tokenAuth)
 	log.Infof("added web api endpoints")
 }
@@ -0,0 +1,121 @@
+package events
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/publisher"
+	"github.com/iotaledger/wasp/packages/webapi/httperrors"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/packages/webapi/routes"
+	"github.com/labstack/echo/v4"
+	"github.com/pangpanglabs/echoswagger/v2"
+	"golang.org/x/net/websocket"
+)
+
+// eventBufferSize is the number of events queued for the slow client before events are dropped
+const eventBufferSize = 100
+
+func AddEndpoints(server echoswagger.ApiRouter) {
+	server.GET(routes.ChainEvents(":chainID"), handleChainEvents).
+		SetSummary("Stream the events of the chain over WebSocket").
+		AddParamPath("", "chainID", "ChainID (base58)").
+		AddParamQuery("", "types", "Comma-separated types of the events to stream: state, request, contract, chainrec. All if empty", false).
+		AddParamQuery("", "contract", "Hname of the contract (hex). If set, only events of that contract are streamed", false).
+		AddResponse(http.StatusOK, "Stream of events (one JSON object per WebSocket message)", model.ChainEvent{}, nil)
+}
+
+type filter struct {
+	chainID  model.ChainID
+	types    map[string]bool
+	contract *coretypes.Hname
+}
+
+func (f *filter) accept(ev *model.ChainEvent) bool {
+	if ev.ChainID != f.chainID {
+		return false
+	}
+	if len(f.types) > 0 && !f.types[ev.Type] {
+		return false
+	}
+	if f.contract != nil && ev.Type == model.ChainEventContract {
+		return ev.Contract.Hname == f.contract.String()
+	}
+	return true
+}
+
+func parseFilter(c echo.Context) (*filter, error) {
+	chainID, err := coretypes.NewChainIDFromBase58(c.Param("chainID"))
+	if err != nil {
+		return nil, httperrors.BadRequest(fmt.Sprintf("Invalid chain ID: %+v", c.Param("chainID")))
+	}
+	ret := &filter{chainID: model.NewChainID(&chainID), types: make(map[string]bool)}
+	if types := c.QueryParam("types"); types != "" {
+		for _, t := range strings.Split(types, ",") {
+			switch t {
+			case model.ChainEventStateTransition, model.ChainEventRequestCompleted, model.ChainEventContract, model.ChainEventChainRecord:
+				ret.types[t] = true
+			default:
+				return nil, httperrors.BadRequest(fmt.Sprintf("Invalid event type: %+v", t))
+			}
+		}
+	}
+	if contract := c.QueryParam("contract"); contract != "" {
+		hname, err := coretypes.HnameFromString(contract)
+		if err != nil {
+			return nil, httperrors.BadRequest(fmt.Sprintf("Invalid contract hname: %+v", contract))
+		}
+		ret.contract = &hname
+	}
+	return ret, nil
+}
+
+func handleChainEvents(c echo.Context) error {
+	f, err := parseFilter(c)
+	if err != nil {
+		return err
+	}
+
+	websocket.Handler(func(ws *websocket.Conn) {
+		defer ws.Close()
+
+		queue := make(chan *model.ChainEvent, eventBufferSize)
+		closure := events.NewClosure(func(ev interface{}) {
+			msg := model.NewChainEvent(ev)
+			if msg == nil || !f.accept(msg) {
+				return
+			}
+			select {
+			case queue <- msg:
+			default:
+				c.Logger().Warnf("[WebSocket] event dropped for %s: client too slow", c.Request().RemoteAddr)
+			}
+		})
+		publisher.ChainEvent.Attach(closure)
+		defer publisher.ChainEvent.Detach(closure)
+
+		// the client is not expected to send anything. Reading detects the closed connection
+		closed := make(chan struct{})
+		go func() {
+			var discard []byte
+			for websocket.Message.Receive(ws, &discard) == nil {
+			}
+			close(closed)
+		}()
+
+		for {
+			select {
+			case msg := <-queue:
+				if err := websocket.JSON.Send(ws, msg); err != nil {
+					return
+				}
+			case <-closed:
+				return
+			}
+		}
+	}).ServeHTTP(c.Response(), c.Request())
+	return nil
+}
@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// BacklogEntry is the request waiting in the backlog of the chain
+type BacklogEntry struct {
+	RequestID string    `swagger:"desc(Request ID (base58))"`
+	Sender    string    `swagger:"desc(Agent ID of the sender)"`
+	Fee       int64     `swagger:"desc(Tokens of the fee color attached to the request)"`
+	Arrival   time.Time `swagger:"desc(Time when the request was received by the node)"`
+}
+
+type BacklogResponse struct {
+	Requests []*BacklogEntry `swagger:"desc(Requests in the order in which they are selected into blocks: by fee, then by arrival time)"`
+}
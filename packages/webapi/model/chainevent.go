@@ -0,0 +1,105 @@
+package model
+
+import (
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/publisher"
+)
+
+// types of the chain events
+const (
+	ChainEventStateTransition  = "state"
+	ChainEventRequestCompleted = "request"
+	ChainEventContract         = "contract"
+	ChainEventChainRecord      = "chainrec"
+)
+
+// ChainEvent is the JSON message streamed by the events WebSocket endpoint.
+// Exactly one of the event fields is set, according to Type
+type ChainEvent struct {
+	Type             string                 `swagger:"desc(Type of the event: state, request, contract or chainrec)"`
+	ChainID          ChainID                `swagger:"desc(ChainID (base58-encoded))"`
+	StateTransition  *StateTransitionEvent  `json:",omitempty" swagger:"desc(Set for the state event)"`
+	RequestCompleted *RequestCompletedEvent `json:",omitempty" swagger:"desc(Set for the request event)"`
+	Contract         *ContractEvent         `json:",omitempty" swagger:"desc(Set for the contract event)"`
+	ChainRecord      *ChainRecordEvent      `json:",omitempty" swagger:"desc(Set for the chainrec event)"`
+}
+
+type StateTransitionEvent struct {
+	BlockIndex uint32             `swagger:"desc(Index of the new solid state)"`
+	BlockSize  uint16             `swagger:"desc(Number of requests in the block)"`
+	StateTxID  ValueTxID          `swagger:"desc(ID of the anchor transaction (base58-encoded))"`
+	StateHash  *hashing.HashValue `swagger:"desc(Hash of the new state)"`
+	Timestamp  int64              `swagger:"desc(Timestamp of the block in nanoseconds)"`
+}
+
+type RequestCompletedEvent struct {
+	RequestID  coretypes.RequestID `swagger:"desc(ID of the request (base58-encoded))"`
+	BlockIndex uint32              `swagger:"desc(Index of the block the request was run for)"`
+	Result     dict.Dict           `swagger:"desc(Result of the call)"`
+	Error      string              `json:",omitempty" swagger:"desc(Error message, if the call failed)"`
+}
+
+type ContractEvent struct {
+	Hname   string `swagger:"desc(Hname of the contract (hex))"`
+	Message string `swagger:"desc(Message of the event)"`
+}
+
+type ChainRecordEvent struct {
+	Color  Color `swagger:"desc(Color of the chain (base58-encoded))"`
+	Active bool  `swagger:"desc(Whether the chain is active)"`
+}
+
+// NewChainEvent converts the event from the publisher to its JSON representation.
+// Returns nil for unknown events
+func NewChainEvent(ev interface{}) *ChainEvent {
+	switch ev := ev.(type) {
+	case *publisher.StateTransition:
+		stateHash := ev.StateHash
+		return &ChainEvent{
+			Type:    ChainEventStateTransition,
+			ChainID: NewChainID(&ev.ChainID),
+			StateTransition: &StateTransitionEvent{
+				BlockIndex: ev.BlockIndex,
+				BlockSize:  ev.BlockSize,
+				StateTxID:  NewValueTxID(&ev.StateTxID),
+				StateHash:  &stateHash,
+				Timestamp:  ev.Timestamp,
+			},
+		}
+	case *publisher.RequestCompleted:
+		ret := &ChainEvent{
+			Type:    ChainEventRequestCompleted,
+			ChainID: NewChainID(&ev.ChainID),
+			RequestCompleted: &RequestCompletedEvent{
+				RequestID:  ev.RequestID,
+				BlockIndex: ev.BlockIndex,
+				Result:     ev.Result,
+			},
+		}
+		if ev.Error != nil {
+			ret.RequestCompleted.Error = ev.Error.Error()
+		}
+		return ret
+	case *publisher.ContractEvent:
+		return &ChainEvent{
+			Type:    ChainEventContract,
+			ChainID: NewChainID(&ev.ChainID),
+			Contract: &ContractEvent{
+				Hname:   ev.Hname.String(),
+				Message: ev.Message,
+			},
+		}
+	case *publisher.ChainRecordChanged:
+		return &ChainEvent{
+			Type:    ChainEventChainRecord,
+			ChainID: NewChainID(&ev.ChainID),
+			ChainRecord: &ChainRecordEvent{
+				Color:  NewColor(&ev.Color),
+				Active: ev.Active,
+			},
+		}
+	}
+	return nil
+}
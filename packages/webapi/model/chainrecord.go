@@ -9,6 +9,7 @@ type ChainRecord struct {
 	Color          Color    `swagger:"desc(Chain color (base58-encoded))"`
 	CommitteeNodes []string `swagger:"desc(List of committee nodes (network IDs))"`
 	Active         bool     `swagger:"desc(Whether or not the chain is active)"`
+	AccessNodes    []string `swagger:"desc(List of access nodes (network IDs))"`
 }
 
 func NewChainRecord(bd *registry.ChainRecord) *ChainRecord {
@@ -17,6 +18,7 @@ func NewChainRecord(bd *registry.ChainRecord) *ChainRecord {
 		Color:          NewColor(&bd.Color),
 		CommitteeNodes: bd.CommitteeNodes[:],
 		Active:         bd.Active,
+		AccessNodes:    bd.AccessNodes[:],
 	}
 }
 
@@ -26,5 +28,6 @@ func (bd *ChainRecord) ChainRecord() *registry.ChainRecord {
 		Color:          bd.Color.Color(),
 		CommitteeNodes: bd.CommitteeNodes[:],
 		Active:         bd.Active,
+		AccessNodes:    bd.AccessNodes[:],
 	}
 }
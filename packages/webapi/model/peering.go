@@ -0,0 +1,47 @@
+package model
+
+import (
+	"github.com/iotaledger/wasp/packages/peering"
+	"github.com/mr-tron/base58"
+	"go.dedis.ch/kyber/v3"
+)
+
+// PeeringTrustedNode describes a node in the list of the trusted peering nodes.
+// The same structure is used to describe the identity of the node itself.
+type PeeringTrustedNode struct {
+	PubKey string `json:"pubKey" swagger:"desc(Public key of the node (base58-encoded).)"`
+	NetID  string `json:"netID" swagger:"desc(NetID of the node.)"`
+}
+
+func NewPeeringTrustedNode(pubKey kyber.Point, netID string) (*PeeringTrustedNode, error) {
+	b, err := pubKey.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return &PeeringTrustedNode{
+		PubKey: base58.Encode(b),
+		NetID:  netID,
+	}, nil
+}
+
+// TrustedPeer decodes the public key using the specified group.
+func (tn *PeeringTrustedNode) TrustedPeer(group kyber.Group) (*peering.TrustedPeer, error) {
+	pubKey, err := DecodePeeringPubKey(tn.PubKey, group)
+	if err != nil {
+		return nil, err
+	}
+	return &peering.TrustedPeer{PubKey: pubKey, NetID: tn.NetID}, nil
+}
+
+// DecodePeeringPubKey decodes the base58-encoded public key of a peering node.
+func DecodePeeringPubKey(pubKeyStr string, group kyber.Group) (kyber.Point, error) {
+	b, err := base58.Decode(pubKeyStr)
+	if err != nil {
+		return nil, err
+	}
+	pubKey := group.Point()
+	if err = pubKey.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return pubKey, nil
+}
@@ -10,4 +10,8 @@ type RequestStatusResponse struct {
 	IsProcessed bool `swagger:"desc(True if the request has been processed)"`
 }
 
+type OffTangleArgsResponse struct {
+	Hash string `swagger:"desc(Hash of the args (base58). Is committed in the request instead of the args)"`
+}
+
 const WaitRequestProcessedDefaultTimeout = 30 * time.Second
@@ -0,0 +1,8 @@
+package model
+
+// SealingKey is the shared public key of the committee which currently controls the chain.
+// Request args are sealed to it
+type SealingKey struct {
+	Address      string `json:"address" swagger:"desc(Address of the chain, controlled by the key)"`
+	SharedPubKey string `json:"sharedPubKey" swagger:"desc(Shared public key (base64-encoded))"`
+}
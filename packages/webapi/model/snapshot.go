@@ -0,0 +1,8 @@
+package model
+
+type StateSnapshot struct {
+	ChainID    ChainID   `swagger:"desc(ChainID (base58-encoded))"`
+	BlockIndex uint32    `swagger:"desc(Index of the block of the snapshot)"`
+	StateTxID  ValueTxID `swagger:"desc(ID of the anchor transaction of the block (base58-encoded))"`
+	Data       []byte    `swagger:"desc(Binary snapshot of the solid state (base64-encoded))"`
+}
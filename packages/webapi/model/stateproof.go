@@ -0,0 +1,39 @@
+package model
+
+import (
+	"fmt"
+
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/state"
+)
+
+type StateProof struct {
+	Key         []byte             `swagger:"desc(Key of the state variable)"`
+	Value       []byte             `swagger:"desc(Value of the state variable (null if absent))"`
+	StateIndex  uint32             `swagger:"desc(Index of the solid state)"`
+	StateHash   *hashing.HashValue `swagger:"desc(Hash of the solid state, as committed to the anchor transaction)"`
+	UpdatesHash *hashing.HashValue `swagger:"desc(Hash of the chain of state updates)"`
+	MerkleRoot  *hashing.HashValue `swagger:"desc(Root of the Merkle tree of state variables)"`
+	StateTxId   ValueTxID          `swagger:"desc(ID of the anchor transaction of the state (base58-encoded))"`
+	Proof       []byte             `swagger:"desc(Merkle proof of the key/value pair)"`
+}
+
+// Verify checks the proof against the Merkle root and the Merkle root against the state hash.
+// The state hash itself must be checked by the caller against the anchor transaction
+func (p *StateProof) Verify() error {
+	if p.StateHash == nil || p.UpdatesHash == nil || p.MerkleRoot == nil {
+		return fmt.Errorf("incomplete state proof")
+	}
+	if state.StateHashFromMerkleRoot(p.UpdatesHash, p.StateIndex, p.MerkleRoot) != *p.StateHash {
+		return fmt.Errorf("Merkle root is not committed to the state hash")
+	}
+	proof, err := state.NewMerkleProofFromBytes(p.Proof)
+	if err != nil {
+		return err
+	}
+	if !state.VerifyProof(p.MerkleRoot, kv.Key(p.Key), p.Value, proof) {
+		return fmt.Errorf("Merkle proof is invalid for key %x", p.Key)
+	}
+	return nil
+}
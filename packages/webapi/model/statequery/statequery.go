@@ -18,6 +18,8 @@ import (
 type Request struct {
 	QueryGeneralData bool
 	KeyQueries       []*KeyQuery
+	// if not nil, the query is run on the past state after the given block instead of the solid state
+	BlockIndex *uint32
 }
 
 type Results struct {
@@ -122,6 +124,11 @@ func (q *Request) AddGeneralData() {
 	q.QueryGeneralData = true
 }
 
+// AtBlockIndex makes the query run on the past state after the given block
+func (q *Request) AtBlockIndex(blockIndex uint32) {
+	q.BlockIndex = &blockIndex
+}
+
 func (q *Request) AddScalar(key kv.Key) {
 	q.KeyQueries = append(q.KeyQueries, &KeyQuery{
 		Key:    []byte(key),
@@ -0,0 +1,12 @@
+package model
+
+import (
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+)
+
+type ViewResult struct {
+	Result     dict.Dict          `swagger:"desc(Result of the view call)"`
+	StateIndex uint32             `swagger:"desc(Index of the state the view was called on)"`
+	StateHash  *hashing.HashValue `swagger:"desc(Hash of the state the view was called on)"`
+}
@@ -0,0 +1,34 @@
+package request
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/webapi/httperrors"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/plugins/chains"
+	"github.com/labstack/echo/v4"
+)
+
+func handleBacklog(c echo.Context) error {
+	chainID, err := coretypes.NewChainIDFromBase58(c.Param("chainID"))
+	if err != nil {
+		return httperrors.BadRequest(fmt.Sprintf("Invalid chain ID %+v: %s", c.Param("chainID"), err.Error()))
+	}
+	ch := chains.GetChain(chainID)
+	if ch == nil {
+		return httperrors.NotFound(fmt.Sprintf("Chain not found: %+v", chainID.String()))
+	}
+	backlog := ch.GetBacklog()
+	ret := model.BacklogResponse{Requests: make([]*model.BacklogEntry, len(backlog))}
+	for i, e := range backlog {
+		ret.Requests[i] = &model.BacklogEntry{
+			RequestID: e.RequestID.Base58(),
+			Sender:    e.Sender.String(),
+			Fee:       e.Fee,
+			Arrival:   e.Arrival,
+		}
+	}
+	return c.JSON(http.StatusOK, ret)
+}
@@ -0,0 +1,39 @@
+package request
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/iotaledger/wasp/packages/chain"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/sctransaction"
+	"github.com/iotaledger/wasp/packages/webapi/httperrors"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/plugins/chains"
+	"github.com/labstack/echo/v4"
+)
+
+// maxOffTangleArgsBodySize limits the size of the uploaded off-tangle args
+const maxOffTangleArgsBodySize = "1M"
+
+func handlePutOffTangleArgs(c echo.Context) error {
+	chainID, err := coretypes.NewChainIDFromBase58(c.Param("chainID"))
+	if err != nil {
+		return httperrors.BadRequest(fmt.Sprintf("Invalid chain ID %+v: %s", c.Param("chainID"), err.Error()))
+	}
+	ch := chains.GetChain(chainID)
+	if ch == nil {
+		return httperrors.NotFound(fmt.Sprintf("Chain not found: %+v", chainID.String()))
+	}
+	args := dict.New()
+	// not using c.Bind, because it would also bind path params into the dict
+	if err := json.NewDecoder(c.Request().Body).Decode(&args); err != nil {
+		return httperrors.BadRequest("Invalid request body")
+	}
+	ch.ReceiveMessage(&chain.OffTangleArgsMsg{Args: args})
+	return c.JSON(http.StatusOK, model.OffTangleArgsResponse{
+		Hash: sctransaction.HashOffTangleArgs(args).String(),
+	})
+}
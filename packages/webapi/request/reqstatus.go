@@ -8,11 +8,13 @@ import (
 	"github.com/iotaledger/hive.go/events"
 	"github.com/iotaledger/wasp/packages/chain"
 	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/iotaledger/wasp/packages/webapi/httperrors"
 	"github.com/iotaledger/wasp/packages/webapi/model"
 	"github.com/iotaledger/wasp/packages/webapi/routes"
 	"github.com/iotaledger/wasp/plugins/chains"
 	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
 	"github.com/pangpanglabs/echoswagger/v2"
 )
 
@@ -28,6 +30,23 @@ func AddEndpoints(server echoswagger.ApiRouter) {
 		AddParamPath("", "chainID", "ChainID (base58)").
 		AddParamPath("", "reqID", "Request ID (base58)").
 		AddParamBody(model.WaitRequestProcessedParams{}, "Params", "Optional parameters", false)
+
+	server.POST(routes.PutOffTangleArgs(":chainID"), handlePutOffTangleArgs, middleware.BodyLimit(maxOffTangleArgsBodySize)).
+		SetSummary("Upload off-tangle args of a request. The args are gossiped to the committee of the chain. "+
+			"Args which are not referenced by a request are deleted after some time").
+		AddParamPath("", "chainID", "ChainID (base58)").
+		AddParamBody(dict.JSONDict{}, "Args", "Args of the request", true).
+		AddResponse(http.StatusOK, "Hash of the args", model.OffTangleArgsResponse{}, nil)
+
+	server.GET(routes.SealingKey(":chainID"), handleSealingKey).
+		SetSummary("Get the public key of the committee of the chain, used to seal (encrypt to the committee) request args").
+		AddParamPath("", "chainID", "ChainID (base58)").
+		AddResponse(http.StatusOK, "Sealing key", model.SealingKey{}, nil)
+
+	server.GET(routes.RequestBacklog(":chainID"), handleBacklog).
+		SetSummary("Get the requests waiting in the backlog of the chain, in the order of priority").
+		AddParamPath("", "chainID", "ChainID (base58)").
+		AddResponse(http.StatusOK, "Backlog", model.BacklogResponse{}, nil)
 }
 
 func handleRequestStatus(c echo.Context) error {
@@ -0,0 +1,38 @@
+package request
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/webapi/httperrors"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/plugins/chains"
+	"github.com/iotaledger/wasp/plugins/registry"
+	"github.com/labstack/echo/v4"
+)
+
+func handleSealingKey(c echo.Context) error {
+	chainID, err := coretypes.NewChainIDFromBase58(c.Param("chainID"))
+	if err != nil {
+		return httperrors.BadRequest(fmt.Sprintf("Invalid chain ID %+v: %s", c.Param("chainID"), err.Error()))
+	}
+	ch := chains.GetChain(chainID)
+	if ch == nil {
+		return httperrors.NotFound(fmt.Sprintf("Chain not found: %+v", chainID.String()))
+	}
+	addr := ch.Address()
+	dks, err := registry.DefaultRegistry().LoadDKShare(&addr)
+	if err != nil {
+		return httperrors.NotFound(fmt.Sprintf("Key of the chain %s not found: %v", chainID.String(), err))
+	}
+	pub, err := dks.SharedPublic.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, &model.SealingKey{
+		Address:      addr.String(),
+		SharedPubKey: base64.StdEncoding.EncodeToString(pub),
+	})
+}
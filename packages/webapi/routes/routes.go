@@ -8,6 +8,10 @@ func CallView(contractID string, hname string) string {
 	return "/contract/" + contractID + "/callview/" + hname
 }
 
+func CallViewWithState(contractID string, hname string) string {
+	return "/contract/" + contractID + "/callviewstate/" + hname
+}
+
 func RequestStatus(chainID string, reqID string) string {
 	return "/chain/" + chainID + "/request/" + reqID + "/status"
 }
@@ -16,10 +20,30 @@ func WaitRequestProcessed(chainID string, reqID string) string {
 	return "/chain/" + chainID + "/request/" + reqID + "/wait"
 }
 
+func PutOffTangleArgs(chainID string) string {
+	return "/chain/" + chainID + "/request/args"
+}
+
+func SealingKey(chainID string) string {
+	return "/chain/" + chainID + "/sealingkey"
+}
+
+func RequestBacklog(chainID string) string {
+	return "/chain/" + chainID + "/request/backlog"
+}
+
 func StateQuery(chainID string) string {
 	return "/chain/" + chainID + "/state/query"
 }
 
+func StateProof(chainID string, key string) string {
+	return "/chain/" + chainID + "/state/proof/" + key
+}
+
+func ChainEvents(chainID string) string {
+	return "/chain/" + chainID + "/events"
+}
+
 func ActivateChain(chainID string) string {
 	return "/adm/chain/" + chainID + "/activate"
 }
@@ -52,6 +76,30 @@ func DumpState(contractID string) string {
 	return "/adm/contract/" + contractID + "/dumpstate"
 }
 
+func StateSnapshot(chainID string) string {
+	return "/adm/chain/" + chainID + "/snapshot"
+}
+
 func Shutdown() string {
 	return "/adm/shutdown"
 }
+
+func PeeringSelfGet() string {
+	return "/adm/peering/self"
+}
+
+func PeeringTrustedList() string {
+	return "/adm/peering/trusted"
+}
+
+func PeeringTrustedPut() string {
+	return "/adm/peering/trusted"
+}
+
+func PeeringTrustedGet(pubKey string) string {
+	return "/adm/peering/trusted/" + pubKey
+}
+
+func PeeringTrustedDelete(pubKey string) string {
+	return "/adm/peering/trusted/" + pubKey
+}
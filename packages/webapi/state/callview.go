@@ -4,12 +4,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/kv"
 	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/iotaledger/wasp/packages/vm/viewcontext"
 	"github.com/iotaledger/wasp/packages/webapi/httperrors"
+	"github.com/iotaledger/wasp/packages/webapi/model"
 	"github.com/iotaledger/wasp/packages/webapi/routes"
 	"github.com/iotaledger/wasp/plugins/chains"
 	"github.com/labstack/echo/v4"
@@ -18,6 +20,7 @@ import (
 
 func AddEndpoints(server echoswagger.ApiRouter) {
 	addStateQueryEndpoint(server)
+	addStateProofEndpoint(server)
 
 	dictExample := dict.Dict{
 		kv.Key("key1"): []byte("value1"),
@@ -28,13 +31,38 @@ func AddEndpoints(server echoswagger.ApiRouter) {
 		AddParamPath("", "contractID", "ContractID (base58-encoded)").
 		AddParamPath("getInfo", "fname", "Function name").
 		AddParamBody(dictExample, "params", "Parameters", false).
+		AddParamQuery(uint32(0), "blockIndex", "Call the view on the past state after the given block (default: the solid state)", false).
 		AddResponse(http.StatusOK, "Result", dictExample, nil)
+
+	server.GET(routes.CallViewWithState(":contractID", ":fname"), handleCallViewWithState).
+		SetSummary("Call a view function on a contract, return the result with the index and hash of the state").
+		AddParamPath("", "contractID", "ContractID (base58-encoded)").
+		AddParamPath("getInfo", "fname", "Function name").
+		AddParamBody(dictExample, "params", "Parameters", false).
+		AddParamQuery(uint32(0), "blockIndex", "Call the view on the past state after the given block (default: the solid state)", false).
+		AddResponse(http.StatusOK, "Result and state", model.ViewResult{}, nil)
 }
 
 func handleCallView(c echo.Context) error {
+	ret, err := callView(c)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, ret.Result)
+}
+
+func handleCallViewWithState(c echo.Context) error {
+	ret, err := callView(c)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, ret)
+}
+
+func callView(c echo.Context) (*model.ViewResult, error) {
 	contractID, err := coretypes.NewContractIDFromBase58(c.Param("contractID"))
 	if err != nil {
-		return httperrors.BadRequest(fmt.Sprintf("Invalid contract ID: %+v", c.Param("contractID")))
+		return nil, httperrors.BadRequest(fmt.Sprintf("Invalid contract ID: %+v", c.Param("contractID")))
 	}
 
 	fname := c.Param("fname")
@@ -43,24 +71,42 @@ func handleCallView(c echo.Context) error {
 	// for some reason c.Bind(&params) doesn't work
 	if c.Request().Body != nil {
 		if err := json.NewDecoder(c.Request().Body).Decode(&params); err != nil {
-			return httperrors.BadRequest("Invalid request body")
+			return nil, httperrors.BadRequest("Invalid request body")
 		}
 	}
 
 	chain := chains.GetChain(contractID.ChainID())
 	if chain == nil {
-		return httperrors.NotFound(fmt.Sprintf("Chain not found: %s", contractID.ChainID()))
-	}
-
-	vctx, err := viewcontext.NewFromDB(*chain.ID(), chain.Processors())
-	if err != nil {
-		return fmt.Errorf(fmt.Sprintf("Failed to create context: %v", err))
+		return nil, httperrors.NotFound(fmt.Sprintf("Chain not found: %s", contractID.ChainID()))
 	}
 
-	ret, err := vctx.CallView(contractID.Hname(), coretypes.Hn(fname), params)
-	if err != nil {
-		return httperrors.BadRequest(fmt.Sprintf("View call failed: %v", err))
+	ret := &model.ViewResult{}
+	if c.QueryParam("blockIndex") == "" {
+		vctx, err := viewcontext.NewFromDB(*chain.ID(), chain.Processors())
+		if err != nil {
+			return nil, fmt.Errorf(fmt.Sprintf("Failed to create context: %v", err))
+		}
+		defer vctx.Release()
+		ret.Result, err = vctx.CallView(contractID.Hname(), coretypes.Hn(fname), params)
+		if err != nil {
+			return nil, httperrors.BadRequest(fmt.Sprintf("View call failed: %v", err))
+		}
+		ret.StateIndex, ret.StateHash = vctx.StateIndex(), vctx.StateHash()
+	} else {
+		blockIndex, err := strconv.ParseUint(c.QueryParam("blockIndex"), 10, 32)
+		if err != nil {
+			return nil, httperrors.BadRequest(fmt.Sprintf("Invalid block index: %+v", c.QueryParam("blockIndex")))
+		}
+		vctx, err := viewcontext.NewFromDBAt(*chain.ID(), uint32(blockIndex), chain.Processors())
+		if err != nil {
+			return nil, httperrors.NotFound(fmt.Sprintf("State #%d not available: %v", blockIndex, err))
+		}
+		defer vctx.Release()
+		ret.Result, err = vctx.CallView(contractID.Hname(), coretypes.Hn(fname), params)
+		if err != nil {
+			return nil, httperrors.BadRequest(fmt.Sprintf("View call failed: %v", err))
+		}
+		ret.StateIndex, ret.StateHash = vctx.StateIndex(), vctx.StateHash()
 	}
-
-	return c.JSON(http.StatusOK, ret)
+	return ret, nil
 }
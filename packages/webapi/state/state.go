@@ -36,10 +36,21 @@ func handleStateQuery(c echo.Context) error {
 		return httperrors.BadRequest("Failed parsing query request params")
 	}
 
-	// TODO serialize access to solid state
-	state, batch, exist, err := state.LoadSolidState(&chainID)
-	if err != nil {
-		return err
+	// the solid state must not change while the response is assembled
+	defer state.AcquireSolidState(&chainID)()
+	var vs state.VirtualState
+	var batch state.Block
+	var exist bool
+	if req.BlockIndex == nil {
+		vs, batch, exist, err = state.LoadSolidState(&chainID)
+		if err != nil {
+			return err
+		}
+	} else {
+		vs, batch, exist, err = state.LoadStateAt(&chainID, *req.BlockIndex)
+		if err != nil {
+			return httperrors.NotFound(fmt.Sprintf("State #%d not available: %v", *req.BlockIndex, err))
+		}
 	}
 	if !exist {
 		return httperrors.NotFound(fmt.Sprintf("State not found with address %s", chainID.String()))
@@ -48,14 +59,14 @@ func handleStateQuery(c echo.Context) error {
 	ret := &statequery.Results{
 		KeyQueryResults: make([]*statequery.QueryResult, len(req.KeyQueries)),
 
-		StateIndex: state.BlockIndex(),
-		Timestamp:  time.Unix(0, state.Timestamp()),
-		StateHash:  state.Hash(),
+		StateIndex: vs.BlockIndex(),
+		Timestamp:  time.Unix(0, vs.Timestamp()),
+		StateHash:  vs.Hash(),
 		StateTxId:  model.NewValueTxID(&txid),
 		Requests:   make([]*coretypes.RequestID, len(batch.RequestIDs())),
 	}
 	copy(ret.Requests, batch.RequestIDs())
-	vars := state.Variables()
+	vars := vs.Variables()
 	for i, q := range req.KeyQueries {
 		result, err := q.Execute(vars)
 		if err != nil {
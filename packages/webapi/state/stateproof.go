@@ -0,0 +1,80 @@
+package state
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/state"
+	"github.com/iotaledger/wasp/packages/webapi/httperrors"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/packages/webapi/routes"
+	"github.com/labstack/echo/v4"
+	"github.com/pangpanglabs/echoswagger/v2"
+)
+
+func addStateProofEndpoint(server echoswagger.ApiRouter) {
+	server.GET(routes.StateProof(":chainID", ":key"), handleStateProof).
+		SetSummary("Get the Merkle proof of a state variable").
+		AddParamPath("", "chainID", "ChainID (base58)").
+		AddParamPath("", "key", "Key of the state variable (hex)").
+		AddParamQuery(uint32(0), "blockIndex", "Prove the variable in the past state after the given block (default: the solid state)", false).
+		AddResponse(http.StatusOK, "Merkle proof", model.StateProof{}, nil)
+}
+
+func handleStateProof(c echo.Context) error {
+	chainID, err := coretypes.NewChainIDFromBase58(c.Param("chainID"))
+	if err != nil {
+		return httperrors.BadRequest(fmt.Sprintf("Invalid chain ID: %+v", c.Param("chainID")))
+	}
+	key, err := hex.DecodeString(c.Param("key"))
+	if err != nil {
+		return httperrors.BadRequest(fmt.Sprintf("Invalid key: %+v", c.Param("key")))
+	}
+
+	// the solid state must not change while the response is assembled
+	defer state.AcquireSolidState(&chainID)()
+	var vs state.VirtualState
+	var batch state.Block
+	var exist bool
+	if c.QueryParam("blockIndex") == "" {
+		vs, batch, exist, err = state.LoadSolidState(&chainID)
+		if err != nil {
+			return err
+		}
+	} else {
+		blockIndex, err := strconv.ParseUint(c.QueryParam("blockIndex"), 10, 32)
+		if err != nil {
+			return httperrors.BadRequest(fmt.Sprintf("Invalid block index: %+v", c.QueryParam("blockIndex")))
+		}
+		vs, batch, exist, err = state.LoadStateAt(&chainID, uint32(blockIndex))
+		if err != nil {
+			return httperrors.NotFound(fmt.Sprintf("State #%d not available: %v", blockIndex, err))
+		}
+	}
+	if !exist {
+		return httperrors.NotFound(fmt.Sprintf("State not found with address %s", chainID.String()))
+	}
+	value, err := vs.Variables().Get(kv.Key(key))
+	if err != nil {
+		return err
+	}
+	proof, err := vs.GetProof(kv.Key(key))
+	if err != nil {
+		return err
+	}
+	txid := batch.StateTransactionID()
+	return c.JSON(http.StatusOK, &model.StateProof{
+		Key:         key,
+		Value:       value,
+		StateIndex:  vs.BlockIndex(),
+		StateHash:   vs.Hash(),
+		UpdatesHash: vs.UpdatesHash(),
+		MerkleRoot:  vs.MerkleRoot(),
+		StateTxId:   model.NewValueTxID(&txid),
+		Proof:       proof.Bytes(),
+	})
+}
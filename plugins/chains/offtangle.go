@@ -0,0 +1,60 @@
+package chains
+
+import (
+	"time"
+
+	"github.com/iotaledger/hive.go/daemon"
+	"github.com/iotaledger/wasp/packages/hashing"
+	registry_pkg "github.com/iotaledger/wasp/packages/registry"
+)
+
+const (
+	// off-tangle args, not referenced by requests in backlogs, are kept for this time after the upload
+	offTangleArgsTTL = 1 * time.Hour
+	// how often off-tangle args are pruned
+	offTangleArgsPrunePeriod = 10 * time.Minute
+)
+
+func runOffTangleArgsPruning() error {
+	return daemon.BackgroundWorker(PluginName+" off-tangle args pruning", func(shutdownSignal <-chan struct{}) {
+		ticker := time.NewTicker(offTangleArgsPrunePeriod)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-shutdownSignal:
+				return
+			case <-ticker.C:
+				pruneOffTangleArgs()
+			}
+		}
+	})
+}
+
+// pruneOffTangleArgs deletes expired off-tangle args, except those referenced by requests
+// in the backlog of any active chain
+func pruneOffTangleArgs() {
+	referenced := make(map[hashing.HashValue]bool)
+	chainsMutex.RLock()
+	for _, c := range chains {
+		if c.IsDismissed() {
+			continue
+		}
+		for _, e := range c.GetBacklog() {
+			if e.OffTangleArgsHash != nil {
+				referenced[*e.OffTangleArgsHash] = true
+			}
+		}
+	}
+	chainsMutex.RUnlock()
+
+	n, err := registry_pkg.PruneOffTangleArgs(time.Now().Add(-offTangleArgsTTL), func(h *hashing.HashValue) bool {
+		return referenced[*h]
+	})
+	if err != nil {
+		log.Errorf("pruneOffTangleArgs: %v", err)
+		return
+	}
+	if n > 0 {
+		log.Debugf("pruned %d expired off-tangle args", n)
+	}
+}
@@ -5,13 +5,18 @@ import (
 	"sync"
 
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
 	"github.com/iotaledger/wasp/packages/coretypes"
 
 	"github.com/iotaledger/hive.go/daemon"
+	"github.com/iotaledger/hive.go/events"
 	"github.com/iotaledger/hive.go/logger"
 	"github.com/iotaledger/hive.go/node"
 	"github.com/iotaledger/wasp/packages/chain"
+	"github.com/iotaledger/wasp/packages/parameters"
 	registry_pkg "github.com/iotaledger/wasp/packages/registry"
+	"github.com/iotaledger/wasp/packages/state"
+	"github.com/iotaledger/wasp/plugins/database"
 	"github.com/iotaledger/wasp/plugins/nodeconn"
 	"github.com/iotaledger/wasp/plugins/peering"
 	"github.com/iotaledger/wasp/plugins/registry"
@@ -24,6 +29,8 @@ var (
 
 	chains      = make(map[coretypes.ChainID]chain.Chain)
 	chainsMutex = &sync.RWMutex{}
+	// forwarders of the previous addresses of the chains, run by the node of the previous committee
+	forwarders = make(map[address.Address]chain.Forwarder)
 )
 
 func Init() *node.Plugin {
@@ -32,6 +39,7 @@ func Init() *node.Plugin {
 
 func configure(_ *node.Plugin) {
 	log = logger.NewLogger(PluginName)
+	state.SetHistoryRetention(parameters.GetInt(parameters.StateHistoryRetention))
 }
 
 func run(_ *node.Plugin) {
@@ -66,6 +74,9 @@ func run(_ *node.Plugin) {
 			for _, com := range chains {
 				com.Dismiss()
 			}
+			for _, fwd := range forwarders {
+				fwd.Close()
+			}
 			log.Infof("shutdown signal received: dismissing committees.. Done")
 		}()
 	})
@@ -73,12 +84,16 @@ func run(_ *node.Plugin) {
 		log.Error(err)
 		return
 	}
+	if err := runOffTangleArgsPruning(); err != nil {
+		log.Error(err)
+	}
 }
 
 // ActivateChain activates chain on the Wasp node:
 // - creates chain object
 // - insert it into the runtime registry
 // - subscribes for related transactions in he IOTA node
+// - starts forwarding the tokens from the address of the chain ID, if the node was in the committee of the chain before rotation
 func ActivateChain(chr *registry_pkg.ChainRecord) error {
 	chainsMutex.Lock()
 	defer chainsMutex.Unlock()
@@ -87,16 +102,34 @@ func ActivateChain(chr *registry_pkg.ChainRecord) error {
 		return fmt.Errorf("cannot activate chain for deactivated chain record")
 	}
 
-	_, ok := chains[chr.ChainID]
-	if ok {
-		log.Debugf("chain is already active: %s", chr.ChainID.String())
-		return nil
+	if c, ok := chains[chr.ChainID]; ok {
+		if !c.IsDismissed() {
+			log.Debugf("chain is already active: %s", chr.ChainID.String())
+			return nil
+		}
+		// the chain was dismissed, for example after the committee rotation. It can be activated again
+		delete(chains, chr.ChainID)
+		unsubscribeChain(c)
 	}
+	startForwarder(chr, address.Address(chr.ChainID))
 	// create new chain object
-	c := chain.New(chr, log, peering.DefaultNetworkProvider(), registry.DefaultRegistry(), func() {
-		nodeconn.Subscribe((address.Address)(chr.ChainID), chr.Color)
-	})
+	var c chain.Chain
+	c = chain.New(chr, log, peering.DefaultNetworkProvider(), registry.DefaultRegistry(),
+		nodeconn.ChainConnection(), database.GetPartition(&chr.ChainID), func() {
+			// the address of the chain differs from the chain ID if the committee was rotated.
+			// Requests may still be sent to the address of the chain ID, so the chain keeps listening to it
+			nodeconn.Subscribe(c.Address(), chr.Color)
+			if chainIDAddress := address.Address(chr.ChainID); chainIDAddress != c.Address() {
+				nodeconn.Subscribe(chainIDAddress, chr.Color)
+			}
+		})
 	if c != nil {
+		c.EventRotated().Attach(events.NewClosure(func(from address.Address) {
+			chainsMutex.Lock()
+			defer chainsMutex.Unlock()
+
+			startForwarder(chr, from)
+		}))
 		chains[chr.ChainID] = c
 		log.Infof("activated chain:\n%s", chr.String())
 	} else {
@@ -105,11 +138,47 @@ func ActivateChain(chr *registry_pkg.ChainRecord) error {
 	return nil
 }
 
+// startForwarder starts forwarding the tokens sent to the previous address of the chain to the current one,
+// if the node is in the committee which controls the previous address. Must be called with chainsMutex locked
+func startForwarder(chr *registry_pkg.ChainRecord, from address.Address) {
+	if _, ok := forwarders[from]; ok {
+		return
+	}
+	fwd := chain.NewForwarder(chr, from, log, peering.DefaultNetworkProvider(), registry.DefaultRegistry(),
+		nodeconn.ChainConnection(), database.GetPartition(&chr.ChainID))
+	if fwd == nil {
+		return
+	}
+	forwarders[from] = fwd
+	nodeconn.Subscribe(from, chr.Color)
+	// the tokens which arrived before the forwarder was started
+	if err := nodeconn.RequestOutputsFromNode(&from); err != nil {
+		log.Warnf("failed to request outputs of %s: %v", from.String(), err)
+	}
+	log.Infof("started forwarder of the chain %s from %s", chr.ChainID.String(), from.String())
+}
+
+// unsubscribeChain stops listening to the addresses of the dismissed chain, unless they are used by a forwarder
+func unsubscribeChain(c chain.Chain) {
+	for _, addr := range []address.Address{c.Address(), address.Address(*c.ID())} {
+		if _, ok := forwarders[addr]; !ok {
+			nodeconn.Unsubscribe(addr)
+		}
+	}
+}
+
 // DeactivateChain deactivates chain in the node
 func DeactivateChain(chr *registry_pkg.ChainRecord) error {
 	chainsMutex.Lock()
 	defer chainsMutex.Unlock()
 
+	for from, fwd := range forwarders {
+		if *fwd.ChainID() == chr.ChainID {
+			fwd.Close()
+			delete(forwarders, from)
+			nodeconn.Unsubscribe(from)
+		}
+	}
 	c, ok := chains[chr.ChainID]
 	if !ok || c.IsDismissed() {
 		log.Debugf("chain is not active: %s", chr.ChainID.String())
@@ -128,8 +197,48 @@ func GetChain(chainID coretypes.ChainID) chain.Chain {
 	ret, ok := chains[chainID]
 	if ok && ret.IsDismissed() {
 		delete(chains, chainID)
-		nodeconn.Unsubscribe((address.Address)(chainID))
+		unsubscribeChain(ret)
 		return nil
 	}
 	return ret
 }
+
+// GetForwarder returns the forwarder of the previous address of a chain or nil if it doesn't exist
+func GetForwarder(from address.Address) chain.Forwarder {
+	chainsMutex.RLock()
+	defer chainsMutex.RUnlock()
+
+	return forwarders[from]
+}
+
+// GetChainByAddress returns active chain object, controlled by the address, or nil if it doesn't exist.
+// The address of the chain is equal to the chain ID unless the committee of the chain was rotated
+func GetChainByAddress(addr address.Address) chain.Chain {
+	return findChain(func(c chain.Chain) bool {
+		return c.Address() == addr
+	})
+}
+
+// GetChainByColor returns active chain object with the chain token of the color or nil if it doesn't exist
+func GetChainByColor(color balance.Color) chain.Chain {
+	return findChain(func(c chain.Chain) bool {
+		return *c.Color() == color
+	})
+}
+
+func findChain(match func(c chain.Chain) bool) chain.Chain {
+	chainsMutex.RLock()
+	var ret chain.Chain
+	for _, c := range chains {
+		if match(c) {
+			ret = c
+			break
+		}
+	}
+	chainsMutex.RUnlock()
+	if ret == nil {
+		return nil
+	}
+	// removes the chain if it is dismissed
+	return GetChain(*ret.ID())
+}
@@ -25,6 +25,10 @@ const (
 	ObjectTypeProgramMetadata
 	ObjectTypeProgramCode
 	ObjectTypeNodeIdentity
+	ObjectTypeMerkleNode
+	ObjectTypeStateUndo
+	ObjectTypeOffTangleArgs
+	ObjectTypeTrustedPeer
 )
 
 type Partition struct {
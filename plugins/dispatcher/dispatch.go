@@ -19,7 +19,9 @@ func dispatchState(tx *sctransaction.Transaction) {
 		// not state transaction
 		return
 	}
-	cmt := chains.GetChain(*txProp.MustChainID())
+	// the chain is found by the color of the chain token, because the anchor transaction
+	// which rotates the committee moves the chain to another address
+	cmt := chains.GetChainByColor(*txProp.MustStateColor())
 	if cmt == nil {
 		return
 	}
@@ -35,16 +37,32 @@ func dispatchState(tx *sctransaction.Transaction) {
 
 func dispatchBalances(addr address.Address, bals map[valuetransaction.ID][]*balance.Balance) {
 	// pass to the committee by address
-	if cmt := chains.GetChain((coretypes.ChainID)(addr)); cmt != nil {
+	if cmt := chains.GetChainByAddress(addr); cmt != nil {
 		cmt.ReceiveMessage(chain.BalancesMsg{Balances: bals})
 	}
+	// pass to the forwarder of the previous address of the chain
+	if fwd := chains.GetForwarder(addr); fwd != nil {
+		fwd.ReceiveMessage(chain.BalancesMsg{Balances: bals})
+	}
 }
 
 func dispatchAddressUpdate(addr address.Address, balances map[valuetransaction.ID][]*balance.Balance, tx *sctransaction.Transaction) {
 	log.Debugw("dispatchAddressUpdate", "addr", addr.String())
 
-	cmt := chains.GetChain((coretypes.ChainID)(addr))
+	if fwd := chains.GetForwarder(addr); fwd != nil {
+		fwd.ReceiveMessage(chain.BalancesMsg{
+			Balances: balances,
+		})
+	}
+	cmt := chains.GetChainByAddress(addr)
 	if cmt == nil {
+		// after the committee of the chain was rotated, requests may still be sent to the address of the chain ID.
+		// The chain picks up the requests, while their tokens are forwarded to the current address
+		// of the chain by the previous committee
+		if cmt = chains.GetChain(coretypes.ChainID(addr)); cmt != nil {
+			dispatchRequests(cmt, tx, nil)
+			return
+		}
 		log.Debugw("committee not found", "addr", addr.String())
 		// wrong addressee
 		return
@@ -57,7 +75,7 @@ func dispatchAddressUpdate(addr address.Address, balances map[valuetransaction.I
 	})
 
 	txProp := tx.MustProperties() // was parsed before
-	if txProp.IsState() && *txProp.MustChainID() == (coretypes.ChainID)(addr) {
+	if txProp.IsState() && txProp.ChainAddress() == addr {
 		// it is a state update to addr. Send it
 		cmt.ReceiveMessage(&chain.StateTransactionMsg{
 			Transaction: tx,
@@ -65,6 +83,13 @@ func dispatchAddressUpdate(addr address.Address, balances map[valuetransaction.I
 		log.Debugf("state tx msg posted: %s", tx.ID().String())
 	}
 
+	if addr != address.Address(*cmt.ID()) {
+		// outputs to the address of the rotated chain are not checked when the transaction is parsed
+		if err := tx.ValidateRequestsToAddress(cmt.ID(), addr); err != nil {
+			log.Warnf("requests of tx %s ignored: %v", tx.ID().String(), err)
+			return
+		}
+	}
 	// send all requests to addr
 	// if there are any free tokens, they will be attached to the first message.
 	// otherwise they all will be nil
@@ -72,8 +97,13 @@ func dispatchAddressUpdate(addr address.Address, balances map[valuetransaction.I
 	if freeTokens != nil && freeTokens.Len() == 0 {
 		freeTokens = nil
 	}
+	dispatchRequests(cmt, tx, freeTokens)
+}
+
+// dispatchRequests sends the requests of the transaction to the chain
+func dispatchRequests(cmt chain.Chain, tx *sctransaction.Transaction, freeTokens coretypes.ColoredBalances) {
 	for i, reqBlk := range tx.Requests() {
-		if reqBlk.Target().ChainID() == (coretypes.ChainID)(addr) {
+		if reqBlk.Target().ChainID() == *cmt.ID() {
 			cmt.ReceiveMessage(&chain.RequestMsg{
 				Transaction: tx,
 				Index:       (uint16)(i),
@@ -86,7 +116,7 @@ func dispatchAddressUpdate(addr address.Address, balances map[valuetransaction.I
 
 func dispatchTxInclusionLevel(level byte, txid *valuetransaction.ID, addrs []address.Address) {
 	for _, addr := range addrs {
-		cmt := chains.GetChain((coretypes.ChainID)(addr))
+		cmt := chains.GetChainByAddress(addr)
 		if cmt == nil {
 			continue
 		}
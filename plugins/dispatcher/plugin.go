@@ -97,7 +97,9 @@ func processNodeMsg(msg interface{}) {
 		tx, err := sctransaction.ParseValueTransaction(msgt.Tx)
 		if err != nil {
 			log.Debugw("!!!! after parsing", "txid", msgt.Tx.ID().String(), "err", err)
-			// not a SC transaction. Ignore
+			// not a SC transaction. Only the balances of the address are updated,
+			// for example after the tokens were forwarded from the previous address of the chain
+			dispatchBalances(msgt.Address, msgt.Balances)
 			return
 		}
 		dispatchAddressUpdate(msgt.Address, msgt.Balances, tx)
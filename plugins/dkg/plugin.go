@@ -35,6 +35,7 @@ func Init(suite dkg_pkg.Suite) *hive_node.Plugin {
 			suite,
 			peeringProvider,
 			registry,
+			registry,
 			logger,
 		)
 	}
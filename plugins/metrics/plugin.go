@@ -0,0 +1,185 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/iotaledger/hive.go/daemon"
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/hive.go/logger"
+	"github.com/iotaledger/hive.go/node"
+	"github.com/iotaledger/wasp/packages/metrics"
+	"github.com/iotaledger/wasp/packages/parameters"
+	"github.com/iotaledger/wasp/packages/registry"
+	"github.com/iotaledger/wasp/plugins/database"
+	"github.com/iotaledger/wasp/plugins/nodeconn"
+	"github.com/iotaledger/wasp/plugins/peering"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// PluginName is the name of the Metrics plugin.
+const PluginName = "Metrics"
+
+// the size of DB partitions is calculated by iterating over all keys, so it is not done on each scrape
+const dbSizeUpdatePeriod = 1 * time.Minute
+
+var (
+	log          *logger.Logger
+	promRegistry *prometheus.Registry
+
+	dbPartitionKeys = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "wasp",
+		Subsystem: "db",
+		Name:      "partition_keys",
+		Help:      "Number of keys in the DB partition of the chain",
+	}, []string{"chain"})
+
+	dbPartitionBytes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "wasp",
+		Subsystem: "db",
+		Name:      "partition_bytes",
+		Help:      "Total size of keys and values in the DB partition of the chain",
+	}, []string{"chain"})
+)
+
+func Init() *node.Plugin {
+	return node.NewPlugin(PluginName, node.Disabled, configure, run)
+}
+
+func configure(_ *node.Plugin) {
+	log = logger.NewLogger(PluginName)
+
+	promRegistry = prometheus.NewRegistry()
+	promRegistry.MustRegister(
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+		newNodeCollector(),
+		dbPartitionKeys,
+		dbPartitionBytes,
+	)
+	promRegistry.MustRegister(metrics.Collectors()...)
+}
+
+func run(_ *node.Plugin) {
+	if err := daemon.BackgroundWorker("Metrics Server", worker, parameters.PriorityMetrics); err != nil {
+		log.Errorf("Error starting as daemon: %s", err)
+	}
+	if err := daemon.BackgroundWorker("Metrics DB size", dbSizeWorker, parameters.PriorityMetrics); err != nil {
+		log.Errorf("Error starting as daemon: %s", err)
+	}
+}
+
+func worker(shutdownSignal <-chan struct{}) {
+	stopped := make(chan struct{})
+	bindAddr := parameters.GetString(parameters.MetricsBindAddress)
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(promRegistry, promhttp.HandlerOpts{}))
+	server := &http.Server{Addr: bindAddr, Handler: mux}
+	go func() {
+		defer close(stopped)
+		log.Infof("%s started, bind-address=%s", PluginName, bindAddr)
+		if err := server.ListenAndServe(); err != nil {
+			if !errors.Is(err, http.ErrServerClosed) {
+				log.Errorf("Error serving: %s", err)
+			}
+		}
+	}()
+
+	// stop if we are shutting down or the server could not be started
+	select {
+	case <-shutdownSignal:
+	case <-stopped:
+	}
+
+	log.Infof("Stopping %s ...", PluginName)
+	defer log.Infof("Stopping %s ... done", PluginName)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		log.Errorf("Error stopping: %s", err)
+	}
+}
+
+func dbSizeWorker(shutdownSignal <-chan struct{}) {
+	for {
+		updateDBSizes()
+		select {
+		case <-shutdownSignal:
+			return
+		case <-time.After(dbSizeUpdatePeriod):
+		}
+	}
+}
+
+func updateDBSizes() {
+	updatePartitionSize("registry", database.GetRegistryPartition())
+
+	chainRecords, err := registry.GetChainRecords()
+	if err != nil {
+		log.Errorf("failed to load chain records: %v", err)
+		return
+	}
+	for _, chr := range chainRecords {
+		updatePartitionSize(chr.ChainID.String(), database.GetPartition(&chr.ChainID))
+	}
+}
+
+func updatePartitionSize(label string, partition kvstore.KVStore) {
+	keys, size := 0, 0
+	err := partition.Iterate(kvstore.EmptyPrefix, func(key kvstore.Key, value kvstore.Value) bool {
+		keys++
+		size += len(key) + len(value)
+		return true
+	})
+	if err != nil {
+		log.Errorf("failed to calculate the size of the partition %s: %v", label, err)
+		return
+	}
+	dbPartitionKeys.WithLabelValues(label).Set(float64(keys))
+	dbPartitionBytes.WithLabelValues(label).Set(float64(size))
+}
+
+// nodeCollector collects the state of the connections of the node at the time of the scrape
+type nodeCollector struct {
+	peerAlive         *prometheus.Desc
+	peerUsers         *prometheus.Desc
+	nodeconnConnected *prometheus.Desc
+}
+
+func newNodeCollector() *nodeCollector {
+	return &nodeCollector{
+		peerAlive: prometheus.NewDesc("wasp_peering_peer_alive",
+			"1 if the connection with the peer is alive, 0 otherwise", []string{"peer"}, nil),
+		peerUsers: prometheus.NewDesc("wasp_peering_peer_users",
+			"Number of committees and other components using the peer", []string{"peer"}, nil),
+		nodeconnConnected: prometheus.NewDesc("wasp_nodeconn_connected",
+			"1 if the node is connected to the Goshimmer node, 0 otherwise", nil, nil),
+	}
+}
+
+func (c *nodeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.peerAlive
+	ch <- c.peerUsers
+	ch <- c.nodeconnConnected
+}
+
+func (c *nodeCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, peer := range peering.DefaultNetworkProvider().PeerStatus() {
+		ch <- prometheus.MustNewConstMetric(c.peerAlive, prometheus.GaugeValue, boolToFloat(peer.IsAlive()), peer.NetID())
+		ch <- prometheus.MustNewConstMetric(c.peerUsers, prometheus.GaugeValue, float64(peer.NumUsers()), peer.NetID())
+	}
+	ch <- prometheus.MustNewConstMetric(c.nodeconnConnected, prometheus.GaugeValue, boolToFloat(nodeconn.IsConnected()))
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
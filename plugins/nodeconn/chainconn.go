@@ -0,0 +1,30 @@
+package nodeconn
+
+import (
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
+	valuetransaction "github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
+	"github.com/iotaledger/wasp/packages/chain"
+)
+
+// chainConnection is the connection to the Goshimmer node shared by all chains of the Wasp node.
+// Messages from the node are routed to the chains by the dispatcher
+type chainConnection struct{}
+
+var _ chain.NodeConnection = chainConnection{}
+
+// ChainConnection returns the connection to the node to be used by the chains
+func ChainConnection() chain.NodeConnection {
+	return chainConnection{}
+}
+
+func (chainConnection) PostTransaction(tx *valuetransaction.Transaction, fromSc *address.Address, fromLeader uint16) error {
+	return PostTransactionToNode(tx, fromSc, fromLeader)
+}
+
+func (chainConnection) RequestConfirmedTransaction(txid *valuetransaction.ID) error {
+	return RequestConfirmedTransactionFromNode(txid)
+}
+
+func (chainConnection) RequestInclusionLevel(txid *valuetransaction.ID, addr *address.Address) error {
+	return RequestInclusionLevelFromNode(txid, addr)
+}
@@ -0,0 +1,38 @@
+package nodeconn
+
+import (
+	"fmt"
+	"time"
+
+	valuetransaction "github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
+	"github.com/iotaledger/goshimmer/dapps/waspconn/packages/waspconn"
+	"github.com/iotaledger/hive.go/events"
+)
+
+// GetConfirmedTransaction requests the confirmed transaction from the node and waits for the response.
+// Error is returned if the transaction is not received within the timeout
+func GetConfirmedTransaction(txid *valuetransaction.ID, timeout time.Duration) (*valuetransaction.Transaction, error) {
+	received := make(chan *valuetransaction.Transaction, 1)
+	closure := events.NewClosure(func(msg interface{}) {
+		msgt, ok := msg.(*waspconn.WaspFromNodeConfirmedTransactionMsg)
+		if !ok || msgt.Tx.ID() != *txid {
+			return
+		}
+		select {
+		case received <- msgt.Tx:
+		default:
+		}
+	})
+	EventMessageReceived.Attach(closure)
+	defer EventMessageReceived.Detach(closure)
+
+	if err := RequestConfirmedTransactionFromNode(txid); err != nil {
+		return nil, err
+	}
+	select {
+	case tx := <-received:
+		return tx, nil
+	case <-time.After(timeout):
+		return nil, fmt.Errorf("confirmed transaction %s was not received from the node", txid.String())
+	}
+}
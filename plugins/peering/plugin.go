@@ -36,6 +36,7 @@ func Init(suite *pairing.SuiteBn256) *node.Plugin {
 			parameters.GetString(parameters.PeeringMyNetId),
 			parameters.GetInt(parameters.PeeringPort),
 			nodeKeyPair,
+			registry.DefaultRegistry(),
 			suite,
 			log,
 		)
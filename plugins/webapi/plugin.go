@@ -58,9 +58,14 @@ func configure(*node.Plugin) {
 		Format: `${time_rfc3339_nano} ${remote_ip} ${method} ${uri} ${status} error="${error}"` + "\n",
 	}))
 
-	auth.AddAuthentication(Server.Echo(), parameters.GetStringToString(parameters.WebAPIAuth))
+	authConfig := parameters.GetStringToString(parameters.WebAPIAuth)
+	auth.AddAuthentication(Server.Echo(), authConfig)
+	tokenAuth, err := auth.NewTokenAuth(authConfig)
+	if err != nil {
+		panic(err)
+	}
 
-	webapi.Init(Server, adminWhitelist())
+	webapi.Init(Server, adminWhitelist(), tokenAuth)
 }
 
 func customHTTPErrorHandler(err error, c echo.Context) {
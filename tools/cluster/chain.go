@@ -30,6 +30,7 @@ type Chain struct {
 	OriginatorSeed *seed.Seed
 
 	CommitteeNodes []int
+	AccessNodes    []int
 	Quorum         uint16
 	Address        address.Address
 
@@ -56,6 +57,14 @@ func (ch *Chain) PeeringHosts() []string {
 	return ch.Cluster.Config.PeeringHosts(ch.CommitteeNodes)
 }
 
+func (ch *Chain) AccessApiHosts() []string {
+	return ch.Cluster.Config.ApiHosts(ch.AccessNodes)
+}
+
+func (ch *Chain) AccessPeeringHosts() []string {
+	return ch.Cluster.Config.PeeringHosts(ch.AccessNodes)
+}
+
 func (ch *Chain) OriginatorAddress() *address.Address {
 	addr := ch.OriginatorSeed.Address(0).Address
 	return &addr
@@ -88,7 +97,7 @@ func (ch *Chain) SCClient(contractHname coretypes.Hname, sigScheme signaturesche
 }
 
 func (ch *Chain) CommitteeMultiClient() *multiclient.MultiClient {
-	return multiclient.New(ch.ApiHosts())
+	return multiclient.New(ch.ApiHosts()).WithToken(ch.Cluster.Config.Wasp.AuthToken)
 }
 
 func (ch *Chain) WithSCState(hname coretypes.Hname, f func(host string, blockIndex uint32, state dict.Dict) bool) bool {
@@ -32,7 +32,9 @@ import (
 	"github.com/iotaledger/wasp/packages/txutil"
 	"github.com/iotaledger/wasp/packages/util"
 	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/tools/cluster/mocknode"
 	"github.com/iotaledger/wasp/tools/cluster/templates"
+	"go.uber.org/zap"
 )
 
 type Cluster struct {
@@ -41,6 +43,7 @@ type Cluster struct {
 	Started bool
 
 	goshimmerCmd *exec.Cmd
+	mockNode     *mocknode.MockNode
 	waspCmds     []*exec.Cmd
 }
 
@@ -57,6 +60,9 @@ func (clu *Cluster) Level1Client() level1.Level1Client {
 	if clu.Config.Goshimmer.Provided {
 		return goshimmer.NewGoshimmerClient(clu.Config.goshimmerApiHost())
 	}
+	if clu.mockNode != nil {
+		return clu.mockNode
+	}
 	return testutil.NewGoshimmerUtxodbClient(clu.Config.goshimmerApiHost())
 }
 
@@ -71,12 +77,19 @@ func (clu *Cluster) DeployDefaultChain() (*Chain, error) {
 }
 
 func (clu *Cluster) DeployChain(description string, committeeNodes []int, quorum uint16) (*Chain, error) {
+	return clu.DeployChainWithAccessNodes(description, committeeNodes, nil, quorum)
+}
+
+// DeployChainWithAccessNodes deploys the chain to the committee nodes. The access nodes
+// follow the chain without taking part in the consensus
+func (clu *Cluster) DeployChainWithAccessNodes(description string, committeeNodes []int, accessNodes []int, quorum uint16) (*Chain, error) {
 	ownerSeed := seed.NewSeed()
 
 	chain := &Chain{
 		Description:    description,
 		OriginatorSeed: ownerSeed,
 		CommitteeNodes: committeeNodes,
+		AccessNodes:    accessNodes,
 		Quorum:         quorum,
 		Cluster:        clu,
 	}
@@ -96,6 +109,9 @@ func (clu *Cluster) DeployChain(description string, committeeNodes []int, quorum
 		Description:           description,
 		Textout:               os.Stdout,
 		Prefix:                "[cluster] ",
+		AccessApiHosts:        chain.AccessApiHosts(),
+		AccessPeeringHosts:    chain.AccessPeeringHosts(),
+		AuthToken:             clu.Config.Wasp.AuthToken,
 	})
 	if err != nil {
 		return nil, err
@@ -117,11 +133,11 @@ func (cluster *Cluster) IsNodeUp(i int) bool {
 }
 
 func (cluster *Cluster) MultiClient() *multiclient.MultiClient {
-	return multiclient.New(cluster.Config.ApiHosts())
+	return multiclient.New(cluster.Config.ApiHosts()).WithToken(cluster.Config.Wasp.AuthToken)
 }
 
 func (cluster *Cluster) WaspClient(nodeIndex int) *client.WaspClient {
-	return client.NewWaspClient(cluster.Config.ApiHost(nodeIndex))
+	return client.NewWaspClient(cluster.Config.ApiHost(nodeIndex)).WithToken(cluster.Config.Wasp.AuthToken)
 }
 
 func waspNodeDataPath(dataPath string, i int) string {
@@ -160,7 +176,7 @@ func (cluster *Cluster) InitDataPath(templatesPath string, dataPath string, remo
 		}
 	}
 
-	if !cluster.Config.Goshimmer.Provided {
+	if !cluster.Config.Goshimmer.Provided && !cluster.Config.Goshimmer.Mock {
 		err = initNodeConfig(
 			goshimmerDataPath(dataPath),
 			path.Join(templatesPath, "goshimmer-config-template.json"),
@@ -294,7 +310,12 @@ func (cluster *Cluster) start(dataPath string) error {
 
 	initOk := make(chan bool, cluster.Config.Wasp.NumNodes)
 
-	if !cluster.Config.Goshimmer.Provided {
+	if !cluster.Config.Goshimmer.Provided && cluster.Config.Goshimmer.Mock {
+		if err := cluster.startMockNode(); err != nil {
+			return err
+		}
+	}
+	if !cluster.Config.Goshimmer.Provided && !cluster.Config.Goshimmer.Mock {
 		cmd, err := cluster.startServer("goshimmer", goshimmerDataPath(dataPath), "goshimmer", initOk, "WebAPI started")
 		if err != nil {
 			return err
@@ -325,6 +346,46 @@ func (cluster *Cluster) start(dataPath string) error {
 		}
 	}
 	fmt.Printf("[cluster] started %d Wasp nodes\n", cluster.Config.Wasp.NumNodes)
+	return cluster.trustAll()
+}
+
+// startMockNode starts the in-memory Level 1 ledger in the cluster process, in place of the goshimmer node
+func (cluster *Cluster) startMockNode() error {
+	logCfg := zap.NewDevelopmentConfig()
+	logCfg.Level = zap.NewAtomicLevelAt(zap.InfoLevel)
+	log, err := logCfg.Build()
+	if err != nil {
+		return err
+	}
+	cluster.mockNode, err = mocknode.Start(cluster.Config.mockNodeConfig(), log.Sugar().Named("mocknode"))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("[cluster] started mock goshimmer node\n")
+	return nil
+}
+
+// trustAll makes each wasp node of the cluster trust all the other nodes,
+// so that they can pair and run the DKG.
+func (cluster *Cluster) trustAll() error {
+	allNodes := cluster.Config.AllNodes()
+	allPeers := make([]*model.PeeringTrustedNode, len(allNodes))
+	for ni := range allNodes {
+		var err error
+		if allPeers[ni], err = cluster.WaspClient(allNodes[ni]).PeeringSelfGet(); err != nil {
+			return err
+		}
+	}
+	for ni := range allNodes {
+		for pi := range allPeers {
+			if ni == pi {
+				continue
+			}
+			if _, err := cluster.WaspClient(allNodes[ni]).PeeringTrustedPut(allPeers[pi].PubKey, allPeers[pi].NetID); err != nil {
+				return err
+			}
+		}
+	}
 	return nil
 }
 
@@ -423,6 +484,10 @@ func (cluster *Cluster) Wait() {
 	for i := 0; i < cluster.Config.Wasp.NumNodes; i++ {
 		waitCmd(&cluster.waspCmds[i])
 	}
+	if cluster.mockNode != nil {
+		cluster.mockNode.Stop()
+		cluster.mockNode = nil
+	}
 }
 
 func waitCmd(cmd **exec.Cmd) {
@@ -5,13 +5,27 @@ import (
 	"fmt"
 	"io/ioutil"
 	"path"
+	"time"
 
+	"github.com/iotaledger/wasp/tools/cluster/mocknode"
 	"github.com/iotaledger/wasp/tools/cluster/templates"
 )
 
+// mockTxStreamPort is the waspconn port of the mock node, as in nodeconn.address of the wasp config template
+const mockTxStreamPort = 5000
+
 type GoshimmerConfig struct {
 	ApiPort  int
 	Provided bool
+
+	// Mock runs an in-memory Level 1 ledger in the cluster process instead of the goshimmer binary
+	Mock bool `json:",omitempty"`
+	// MockConfirmDelayMs is the delay in milliseconds before the mock ledger confirms a transaction
+	MockConfirmDelayMs int `json:",omitempty"`
+	// MockConfirmRandomize randomizes the confirmation delay around MockConfirmDelayMs
+	MockConfirmRandomize bool `json:",omitempty"`
+	// MockConfirmFirstInConflict confirms the first of the conflicting transactions instead of rejecting all of them
+	MockConfirmFirstInConflict bool `json:",omitempty"`
 }
 
 type WaspConfig struct {
@@ -22,6 +36,9 @@ type WaspConfig struct {
 	FirstPeeringPort   int
 	FirstNanomsgPort   int
 	FirstDashboardPort int
+
+	// AuthToken is the API key or JWT for the admin endpoints, if the nodes are configured with token auth
+	AuthToken string `json:",omitempty"`
 }
 
 type ClusterConfig struct {
@@ -41,6 +58,7 @@ func DefaultConfig() *ClusterConfig {
 		Goshimmer: GoshimmerConfig{
 			ApiPort:  8080,
 			Provided: false,
+			Mock:     true,
 		},
 	}
 }
@@ -147,7 +165,7 @@ func (c *ClusterConfig) DashboardPort(nodeIndex int) int {
 }
 
 func (c *ClusterConfig) GoshimmerConfigTemplateParams() *templates.GoshimmerConfigParams {
-	if c.Goshimmer.Provided {
+	if c.Goshimmer.Provided || c.Goshimmer.Mock {
 		panic("should not reach here")
 	}
 	return &templates.GoshimmerConfigParams{
@@ -155,6 +173,16 @@ func (c *ClusterConfig) GoshimmerConfigTemplateParams() *templates.GoshimmerConf
 	}
 }
 
+func (c *ClusterConfig) mockNodeConfig() *mocknode.Config {
+	return &mocknode.Config{
+		TxStreamPort:           mockTxStreamPort,
+		ApiPort:                c.Goshimmer.ApiPort,
+		ConfirmDelay:           time.Duration(c.Goshimmer.MockConfirmDelayMs) * time.Millisecond,
+		ConfirmRandomize:       c.Goshimmer.MockConfirmRandomize,
+		ConfirmFirstInConflict: c.Goshimmer.MockConfirmFirstInConflict,
+	}
+}
+
 func (c *ClusterConfig) WaspConfigTemplateParams(i int) *templates.WaspConfigParams {
 	return &templates.WaspConfigParams{
 		ApiPort:       c.ApiPort(i),
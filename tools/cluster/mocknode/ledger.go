@@ -0,0 +1,200 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package mocknode
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
+	"github.com/iotaledger/goshimmer/dapps/waspconn/packages/utxodb"
+	"github.com/iotaledger/goshimmer/dapps/waspconn/packages/waspconn"
+)
+
+const confirmLoopPeriod = 100 * time.Millisecond
+
+type pendingTransaction struct {
+	tx              *transaction.Transaction
+	confirmDeadline time.Time
+	hasConflicts    bool
+}
+
+// ledger wraps UTXODB and emulates the confirmation of transactions by the value tangle:
+// posted transactions are booked and confirmed after a delay. Conflicting transactions are rejected
+type ledger struct {
+	utxodb                 *utxodb.UtxoDB
+	confirmDelay           time.Duration
+	confirmRandomize       bool
+	confirmFirstInConflict bool
+	pending                map[transaction.ID]*pendingTransaction
+	rejected               map[transaction.ID]bool
+	mutex                  sync.Mutex
+	onBooked               func(tx *transaction.Transaction)
+	onConfirmed            func(tx *transaction.Transaction)
+	onRejected             func(tx *transaction.Transaction)
+	stopCh                 chan struct{}
+}
+
+func newLedger(cfg *Config, onBooked, onConfirmed, onRejected func(tx *transaction.Transaction)) *ledger {
+	l := &ledger{
+		utxodb:                 utxodb.New(),
+		confirmDelay:           cfg.ConfirmDelay,
+		confirmRandomize:       cfg.ConfirmRandomize,
+		confirmFirstInConflict: cfg.ConfirmFirstInConflict,
+		pending:                make(map[transaction.ID]*pendingTransaction),
+		rejected:               make(map[transaction.ID]bool),
+		onBooked:               onBooked,
+		onConfirmed:            onConfirmed,
+		onRejected:             onRejected,
+		stopCh:                 make(chan struct{}),
+	}
+	go l.confirmLoop()
+	return l
+}
+
+func (l *ledger) stop() {
+	close(l.stopCh)
+}
+
+// postTransaction validates the transaction and books it for the confirmation. Posting the same
+// transaction again is not a conflict. A transaction which conflicts with a pending one is rejected
+// immediately, the pending one is rejected at its deadline unless confirmFirstInConflict is set
+func (l *ledger) postTransaction(tx *transaction.Transaction) error {
+	l.mutex.Lock()
+	txid := tx.ID()
+	if _, ok := l.pending[txid]; ok || l.utxodb.IsConfirmed(&txid) {
+		l.mutex.Unlock()
+		return nil
+	}
+	if l.confirmDelay == 0 {
+		err := l.utxodb.AddTransaction(tx)
+		l.mutex.Unlock()
+		if err != nil {
+			return err
+		}
+		l.onConfirmed(tx)
+		return nil
+	}
+	if err := l.utxodb.ValidateTransaction(tx); err != nil {
+		l.mutex.Unlock()
+		return err
+	}
+	for pendingTxid, ptx := range l.pending {
+		if utxodb.AreConflicting(tx, ptx.tx) {
+			ptx.hasConflicts = true
+			l.rejected[txid] = true
+			l.mutex.Unlock()
+			l.onRejected(tx)
+			return fmt.Errorf("rejected: tx %s conflicts with pending tx %s", txid.String(), pendingTxid.String())
+		}
+	}
+	delay := l.confirmDelay
+	if l.confirmRandomize {
+		// uniformly distributed around the confirm delay
+		delay = l.confirmDelay/2 + time.Duration(rand.Int63n(int64(l.confirmDelay)))
+	}
+	l.pending[txid] = &pendingTransaction{
+		tx:              tx,
+		confirmDeadline: time.Now().Add(delay),
+	}
+	l.mutex.Unlock()
+	l.onBooked(tx)
+	return nil
+}
+
+func (l *ledger) confirmLoop() {
+	for {
+		select {
+		case <-l.stopCh:
+			return
+		case <-time.After(confirmLoopPeriod):
+			confirmed, rejected := l.takeMatured()
+			for _, tx := range confirmed {
+				l.onConfirmed(tx)
+			}
+			for _, tx := range rejected {
+				l.onRejected(tx)
+			}
+		}
+	}
+}
+
+// takeMatured removes the transactions with passed deadline from the pending ones
+// and adds them to the ledger, unless they are conflicting
+func (l *ledger) takeMatured() ([]*transaction.Transaction, []*transaction.Transaction) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	confirmed := make([]*transaction.Transaction, 0)
+	rejected := make([]*transaction.Transaction, 0)
+	now := time.Now()
+	for txid, ptx := range l.pending {
+		if ptx.confirmDeadline.After(now) {
+			continue
+		}
+		delete(l.pending, txid)
+		if ptx.hasConflicts && !l.confirmFirstInConflict {
+			l.rejected[txid] = true
+			rejected = append(rejected, ptx.tx)
+			continue
+		}
+		if err := l.utxodb.AddTransaction(ptx.tx); err != nil {
+			l.rejected[txid] = true
+			rejected = append(rejected, ptx.tx)
+			continue
+		}
+		confirmed = append(confirmed, ptx.tx)
+	}
+	return confirmed, rejected
+}
+
+func (l *ledger) requestFunds(target address.Address) error {
+	l.mutex.Lock()
+	tx, err := l.utxodb.RequestFunds(target)
+	l.mutex.Unlock()
+	if err != nil {
+		return err
+	}
+	l.onConfirmed(tx)
+	return nil
+}
+
+func (l *ledger) isConfirmed(txid *transaction.ID) bool {
+	return l.utxodb.IsConfirmed(txid)
+}
+
+// isRejected returns true if the transaction was rejected. Such a transaction will never be confirmed
+func (l *ledger) isRejected(txid *transaction.ID) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.rejected[*txid]
+}
+
+func (l *ledger) getConfirmedTransaction(txid *transaction.ID) *transaction.Transaction {
+	tx, _ := l.utxodb.GetTransaction(*txid)
+	return tx
+}
+
+func (l *ledger) getConfirmedAddressOutputs(addr address.Address) map[transaction.OutputID][]*balance.Balance {
+	return l.utxodb.GetAddressOutputs(addr)
+}
+
+func (l *ledger) getTxInclusionLevel(txid *transaction.ID) byte {
+	if l.isConfirmed(txid) {
+		return waspconn.TransactionInclusionLevelConfirmed
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.rejected[*txid] {
+		return waspconn.TransactionInclusionLevelRejected
+	}
+	if _, ok := l.pending[*txid]; ok {
+		return waspconn.TransactionInclusionLevelBooked
+	}
+	return waspconn.TransactionInclusionLevelUndef
+}
@@ -0,0 +1,204 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+// Package mocknode is an in-memory stand-in for the Goshimmer node with the waspconn dapp.
+// The ledger is kept in UTXODB. Wasp nodes connect to it with the waspconn protocol and clients
+// use it as level1.Level1Client, either in-process or with the UTXODB endpoints of the web API.
+// Transactions are confirmed after a configurable delay, conflicting transactions are rejected.
+package mocknode
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
+	"github.com/iotaledger/hive.go/logger"
+	"github.com/iotaledger/wasp/client/level1"
+	"github.com/labstack/echo/v4"
+)
+
+const waitForConfirmationPeriod = 100 * time.Millisecond
+
+type Config struct {
+	// TxStreamPort is the port for the waspconn connections. 0 means any free port
+	TxStreamPort int
+	// ApiPort is the port of the web API with the UTXODB endpoints. 0 means no web API
+	ApiPort int
+	// ConfirmDelay is the time between posting and confirmation of a transaction.
+	// 0 means the transactions are confirmed upon posting
+	ConfirmDelay time.Duration
+	// ConfirmRandomize makes the delay random in the range [ConfirmDelay/2, ConfirmDelay*3/2)
+	ConfirmRandomize bool
+	// ConfirmFirstInConflict confirms the first posted of the conflicting transactions.
+	// Otherwise all of them are rejected
+	ConfirmFirstInConflict bool
+}
+
+type MockNode struct {
+	ledger     *ledger
+	listener   net.Listener
+	webapi     *echo.Echo
+	conns      map[*waspConnector]struct{}
+	connsMutex sync.Mutex
+	stopped    chan struct{}
+	log        *logger.Logger
+}
+
+// MockNode implements level1.Level1Client
+var _ level1.Level1Client = &MockNode{}
+
+// Start starts the mock node listening for the waspconn connections and, if configured, the web API
+func Start(cfg *Config, log *logger.Logger) (*MockNode, error) {
+	m := &MockNode{
+		conns:   make(map[*waspConnector]struct{}),
+		stopped: make(chan struct{}),
+		log:     log,
+	}
+	var err error
+	if m.listener, err = net.Listen("tcp", fmt.Sprintf(":%d", cfg.TxStreamPort)); err != nil {
+		return nil, err
+	}
+	m.ledger = newLedger(cfg, m.transactionBooked, m.transactionConfirmed, m.transactionRejected)
+
+	if cfg.ApiPort != 0 {
+		m.webapi = m.newWebAPI()
+		if m.webapi.Listener, err = net.Listen("tcp", fmt.Sprintf(":%d", cfg.ApiPort)); err != nil {
+			_ = m.listener.Close()
+			m.ledger.stop()
+			return nil, err
+		}
+		go func() {
+			if err := m.webapi.Start(""); err != nil && err != http.ErrServerClosed {
+				m.log.Errorf("web API: %v", err)
+			}
+		}()
+		m.log.Infof("web API started at %s", m.webapi.Listener.Addr().String())
+	}
+	go m.acceptLoop()
+
+	m.log.Infof("mock node started. Waspconn port: %s, confirm delay: %v", m.TxStreamAddr(), cfg.ConfirmDelay)
+	return m, nil
+}
+
+// TxStreamAddr returns the address to be used as nodeconn.address by the Wasp nodes
+func (m *MockNode) TxStreamAddr() string {
+	return m.listener.Addr().String()
+}
+
+// Stop closes all the connections and stops the confirmations
+func (m *MockNode) Stop() {
+	close(m.stopped)
+	_ = m.listener.Close()
+	if m.webapi != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = m.webapi.Shutdown(ctx)
+	}
+	m.connsMutex.Lock()
+	for wconn := range m.conns {
+		wconn.close()
+	}
+	m.connsMutex.Unlock()
+	m.ledger.stop()
+	m.log.Infof("mock node stopped")
+}
+
+func (m *MockNode) acceptLoop() {
+	for {
+		conn, err := m.listener.Accept()
+		if err != nil {
+			select {
+			case <-m.stopped:
+			default:
+				m.log.Errorf("accept: %v", err)
+			}
+			return
+		}
+		wconn := newWaspConnector(conn, m.ledger, m.log)
+		m.connsMutex.Lock()
+		m.conns[wconn] = struct{}{}
+		m.connsMutex.Unlock()
+		m.log.Infof("accepted waspconn connection from %s", conn.RemoteAddr().String())
+
+		go func() {
+			wconn.run()
+			m.connsMutex.Lock()
+			delete(m.conns, wconn)
+			m.connsMutex.Unlock()
+		}()
+	}
+}
+
+func (m *MockNode) connections() []*waspConnector {
+	m.connsMutex.Lock()
+	defer m.connsMutex.Unlock()
+
+	ret := make([]*waspConnector, 0, len(m.conns))
+	for wconn := range m.conns {
+		ret = append(ret, wconn)
+	}
+	return ret
+}
+
+func (m *MockNode) transactionConfirmed(tx *transaction.Transaction) {
+	m.log.Debugf("confirmed tx %s", tx.ID().String())
+	for _, wconn := range m.connections() {
+		wconn.transactionConfirmed(tx)
+	}
+}
+
+func (m *MockNode) transactionBooked(tx *transaction.Transaction) {
+	for _, wconn := range m.connections() {
+		wconn.transactionBooked(tx)
+	}
+}
+
+func (m *MockNode) transactionRejected(tx *transaction.Transaction) {
+	m.log.Infof("rejected tx %s", tx.ID().String())
+	for _, wconn := range m.connections() {
+		wconn.transactionRejected(tx)
+	}
+}
+
+func (m *MockNode) RequestFunds(targetAddress *address.Address) error {
+	return m.ledger.requestFunds(*targetAddress)
+}
+
+func (m *MockNode) GetConfirmedAccountOutputs(address *address.Address) (map[transaction.OutputID][]*balance.Balance, error) {
+	return m.ledger.getConfirmedAddressOutputs(*address), nil
+}
+
+func (m *MockNode) PostTransaction(tx *transaction.Transaction) error {
+	return m.ledger.postTransaction(tx)
+}
+
+func (m *MockNode) PostAndWaitForConfirmation(tx *transaction.Transaction) error {
+	if err := m.ledger.postTransaction(tx); err != nil {
+		return err
+	}
+	return m.WaitForConfirmation(tx.ID())
+}
+
+// WaitForConfirmation blocks until the transaction is confirmed. It returns an error
+// if the transaction is rejected or the node is stopped
+func (m *MockNode) WaitForConfirmation(txid transaction.ID) error {
+	for {
+		if m.ledger.isConfirmed(&txid) {
+			return nil
+		}
+		if m.ledger.isRejected(&txid) {
+			return fmt.Errorf("transaction %s was rejected", txid.String())
+		}
+		select {
+		case <-m.stopped:
+			return fmt.Errorf("mock node stopped while waiting for transaction %s", txid.String())
+		case <-time.After(waitForConfirmationPeriod):
+		}
+	}
+}
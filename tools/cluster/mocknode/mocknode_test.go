@@ -0,0 +1,158 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package mocknode
+
+import (
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address/signaturescheme"
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
+	"github.com/iotaledger/goshimmer/dapps/waspconn/packages/waspconn"
+	"github.com/iotaledger/goshimmer/packages/tangle"
+	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/hive.go/netutil/buffconn"
+	"github.com/iotaledger/wasp/packages/testutil"
+	"github.com/iotaledger/wasp/packages/txutil/vtxbuilder"
+	"github.com/stretchr/testify/require"
+)
+
+func startMockNode(t *testing.T, cfg *Config) *MockNode {
+	m, err := Start(cfg, testutil.NewLogger(t))
+	require.NoError(t, err)
+	t.Cleanup(m.Stop)
+	return m
+}
+
+func transferTx(t *testing.T, m *MockNode, from signaturescheme.SignatureScheme, to address.Address, amount int64) *transaction.Transaction {
+	fromAddr := from.Address()
+	outs, err := m.GetConfirmedAccountOutputs(&fromAddr)
+	require.NoError(t, err)
+	txb, err := vtxbuilder.NewFromOutputBalances(outs)
+	require.NoError(t, err)
+	require.NoError(t, txb.MoveTokensToAddress(to, balance.ColorIOTA, amount))
+	tx := txb.Build(false)
+	tx.Sign(from)
+	return tx
+}
+
+func TestConfirmAfterDelay(t *testing.T) {
+	m := startMockNode(t, &Config{ConfirmDelay: 300 * time.Millisecond})
+
+	owner := signaturescheme.RandBLS()
+	ownerAddr := owner.Address()
+	require.NoError(t, m.RequestFunds(&ownerAddr))
+
+	target := signaturescheme.RandBLS().Address()
+	tx := transferTx(t, m, owner, target, 10)
+	txid := tx.ID()
+
+	require.NoError(t, m.PostTransaction(tx))
+	require.EqualValues(t, waspconn.TransactionInclusionLevelBooked, m.ledger.getTxInclusionLevel(&txid))
+	// posting the same transaction again is not a conflict
+	require.NoError(t, m.PostTransaction(tx))
+
+	require.NoError(t, m.WaitForConfirmation(txid))
+	require.EqualValues(t, waspconn.TransactionInclusionLevelConfirmed, m.ledger.getTxInclusionLevel(&txid))
+
+	outs, err := m.GetConfirmedAccountOutputs(&target)
+	require.NoError(t, err)
+	byColor, total := waspconn.OutputBalancesByColor(outs)
+	require.EqualValues(t, 10, total)
+	require.EqualValues(t, 10, byColor[balance.ColorIOTA])
+}
+
+func TestConflictRejected(t *testing.T) {
+	m := startMockNode(t, &Config{ConfirmDelay: 300 * time.Millisecond})
+
+	owner := signaturescheme.RandBLS()
+	ownerAddr := owner.Address()
+	require.NoError(t, m.RequestFunds(&ownerAddr))
+
+	tx1 := transferTx(t, m, owner, signaturescheme.RandBLS().Address(), 10)
+	tx2 := transferTx(t, m, owner, signaturescheme.RandBLS().Address(), 20)
+
+	require.NoError(t, m.PostTransaction(tx1))
+	require.Error(t, m.PostTransaction(tx2))
+
+	require.Error(t, m.WaitForConfirmation(tx1.ID()))
+	require.Error(t, m.WaitForConfirmation(tx2.ID()))
+}
+
+func TestConflictConfirmFirst(t *testing.T) {
+	m := startMockNode(t, &Config{
+		ConfirmDelay:           300 * time.Millisecond,
+		ConfirmRandomize:       true,
+		ConfirmFirstInConflict: true,
+	})
+
+	owner := signaturescheme.RandBLS()
+	ownerAddr := owner.Address()
+	require.NoError(t, m.RequestFunds(&ownerAddr))
+
+	tx1 := transferTx(t, m, owner, signaturescheme.RandBLS().Address(), 10)
+	tx2 := transferTx(t, m, owner, signaturescheme.RandBLS().Address(), 20)
+
+	require.NoError(t, m.PostTransaction(tx1))
+	require.Error(t, m.PostTransaction(tx2))
+
+	require.NoError(t, m.WaitForConfirmation(tx1.ID()))
+	require.Error(t, m.WaitForConfirmation(tx2.ID()))
+}
+
+func TestWaspConn(t *testing.T) {
+	m := startMockNode(t, &Config{})
+
+	conn, err := net.Dial("tcp", m.TxStreamAddr())
+	require.NoError(t, err)
+	bconn := buffconn.NewBufferedConnection(conn, tangle.MaxMessageSize)
+	defer bconn.Close()
+
+	received := make(chan interface{}, 10)
+	bconn.Events.ReceiveMessage.Attach(events.NewClosure(func(data []byte) {
+		msg, err := waspconn.DecodeMsg(data, true)
+		require.NoError(t, err)
+		received <- msg
+	}))
+	go func() { _ = bconn.Read() }()
+
+	send := func(msg interface{ Write(w io.Writer) error }) {
+		data, err := waspconn.EncodeMsg(msg)
+		require.NoError(t, err)
+		_, err = bconn.Write(data)
+		require.NoError(t, err)
+	}
+	receive := func() interface{} {
+		select {
+		case msg := <-received:
+			return msg
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout waiting for the message from the node")
+			return nil
+		}
+	}
+
+	addr := signaturescheme.RandBLS().Address()
+	send(&waspconn.WaspToNodeSubscribeMsg{
+		AddressesWithColors: []waspconn.AddressColor{{Address: addr, Color: balance.ColorIOTA}},
+	})
+	// the ping is answered after the subscription is processed
+	send(&waspconn.WaspPingMsg{Id: 1})
+	require.IsType(t, &waspconn.WaspPingMsg{}, receive())
+
+	require.NoError(t, m.RequestFunds(&addr))
+	upd, ok := receive().(*waspconn.WaspFromNodeAddressUpdateMsg)
+	require.True(t, ok)
+	require.Equal(t, addr, upd.Address)
+
+	txid := upd.Tx.ID()
+	send(&waspconn.WaspToNodeGetConfirmedTransactionMsg{TxId: txid})
+	conf, ok := receive().(*waspconn.WaspFromNodeConfirmedTransactionMsg)
+	require.True(t, ok)
+	require.Equal(t, txid, conf.Tx.ID())
+}
@@ -0,0 +1,300 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package mocknode
+
+import (
+	"io"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
+	"github.com/iotaledger/goshimmer/dapps/waspconn/packages/chopper"
+	"github.com/iotaledger/goshimmer/dapps/waspconn/packages/waspconn"
+	"github.com/iotaledger/goshimmer/packages/tangle"
+	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/hive.go/logger"
+	"github.com/iotaledger/hive.go/netutil/buffconn"
+)
+
+// waspConnector is the node side of the connection with one Wasp node.
+// It follows the waspconn dapp of Goshimmer, but takes the transactions from the mock ledger
+type waspConnector struct {
+	id             string
+	bconn          *buffconn.BufferedConnection
+	ledger         *ledger
+	subscriptions  map[address.Address]balance.Color
+	subscrMutex    sync.RWMutex
+	writeMutex     sync.Mutex
+	messageChopper *chopper.Chopper
+	log            *logger.Logger
+}
+
+func newWaspConnector(conn net.Conn, l *ledger, log *logger.Logger) *waspConnector {
+	wconn := &waspConnector{
+		bconn:          buffconn.NewBufferedConnection(conn, tangle.MaxMessageSize),
+		ledger:         l,
+		subscriptions:  make(map[address.Address]balance.Color),
+		messageChopper: chopper.NewChopper(),
+	}
+	wconn.id = "wasp_" + wconn.bconn.RemoteAddr().String()
+	wconn.log = log.Named(wconn.id)
+	wconn.bconn.Events.ReceiveMessage.Attach(events.NewClosure(func(data []byte) {
+		wconn.processMsgDataFromWasp(data)
+	}))
+	return wconn
+}
+
+// run reads the connection until it is closed
+func (wconn *waspConnector) run() {
+	if err := wconn.bconn.Read(); err != nil {
+		if err != io.EOF && !strings.Contains(err.Error(), "use of closed network connection") {
+			wconn.log.Warnw("Permanent error", "err", err)
+		}
+	}
+	wconn.messageChopper.Close()
+	_ = wconn.bconn.Close()
+	wconn.log.Debugf("closed waspconn")
+}
+
+func (wconn *waspConnector) close() {
+	_ = wconn.bconn.Close()
+}
+
+func (wconn *waspConnector) setId(id string) {
+	wconn.id = id
+	wconn.log.Infof("wasp connection id has been set to '%s' for '%s'", id, wconn.bconn.RemoteAddr().String())
+}
+
+func (wconn *waspConnector) processMsgDataFromWasp(data []byte) {
+	var msg interface{}
+	var err error
+	if msg, err = waspconn.DecodeMsg(data, false); err != nil {
+		wconn.log.Errorf("DecodeMsg: %v", err)
+		return
+	}
+	switch msgt := msg.(type) {
+	case *waspconn.WaspMsgChunk:
+		finalMsg, err := wconn.messageChopper.IncomingChunk(msgt.Data, tangle.MaxMessageSize, waspconn.ChunkMessageHeaderSize)
+		if err != nil {
+			wconn.log.Errorf("DecodeMsg: %v", err)
+			return
+		}
+		if finalMsg != nil {
+			wconn.processMsgDataFromWasp(finalMsg)
+		}
+
+	case *waspconn.WaspPingMsg:
+		if err := wconn.sendMsgToWasp(msgt); err != nil {
+			wconn.log.Errorf("responding to ping: %v", err)
+		}
+
+	case *waspconn.WaspToNodeTransactionMsg:
+		if err := wconn.ledger.postTransaction(msgt.Tx); err != nil {
+			wconn.log.Warnf("%v: %s", err, msgt.Tx.ID().String())
+			return
+		}
+		wconn.log.Infof("Wasp -> Tangle. txid: %s, from sc: %s, from leader: %d",
+			msgt.Tx.ID().String(), msgt.SCAddress.String(), msgt.Leader)
+
+	case *waspconn.WaspToNodeSubscribeMsg:
+		for _, addrCol := range msgt.AddressesWithColors {
+			wconn.subscribe(&addrCol.Address, &addrCol.Color)
+		}
+		go func() {
+			for _, addrCol := range msgt.AddressesWithColors {
+				wconn.pushBacklogToWasp(&addrCol.Address, &addrCol.Color)
+			}
+		}()
+
+	case *waspconn.WaspToNodeGetConfirmedTransactionMsg:
+		wconn.getConfirmedTransaction(&msgt.TxId)
+
+	case *waspconn.WaspToNodeGetTxInclusionLevelMsg:
+		wconn.getTxInclusionLevel(&msgt.TxId, &msgt.SCAddress)
+
+	case *waspconn.WaspToNodeGetOutputsMsg:
+		wconn.getAddressBalance(&msgt.Address)
+
+	case *waspconn.WaspToNodeSetIdMsg:
+		wconn.setId(msgt.Waspid)
+
+	default:
+		wconn.log.Errorf("unexpected message type %T", msg)
+	}
+}
+
+func (wconn *waspConnector) subscribe(addr *address.Address, color *balance.Color) {
+	wconn.subscrMutex.Lock()
+	defer wconn.subscrMutex.Unlock()
+
+	if _, ok := wconn.subscriptions[*addr]; !ok {
+		wconn.log.Infof("subscribed to address %s with color %s", addr.String(), color.String())
+		wconn.subscriptions[*addr] = *color
+	}
+}
+
+func (wconn *waspConnector) txSubscribedAddresses(tx *transaction.Transaction) []address.Address {
+	wconn.subscrMutex.RLock()
+	defer wconn.subscrMutex.RUnlock()
+
+	ret := make([]address.Address, 0)
+	tx.Outputs().ForEach(func(addr address.Address, _ []*balance.Balance) bool {
+		if _, ok := wconn.subscriptions[addr]; ok {
+			ret = append(ret, addr)
+		}
+		return true
+	})
+	return ret
+}
+
+// transactionConfirmed sends the outputs of each subscribed address in the outputs of the transaction
+func (wconn *waspConnector) transactionConfirmed(tx *transaction.Transaction) {
+	addrs := wconn.txSubscribedAddresses(tx)
+	for i := range addrs {
+		bals := waspconn.OutputsToBalances(wconn.ledger.getConfirmedAddressOutputs(addrs[i]))
+		if err := wconn.sendAddressUpdateToWasp(&addrs[i], bals, tx); err != nil {
+			wconn.log.Errorf("sendAddressUpdateToWasp: %v", err)
+			continue
+		}
+		wconn.log.Infof("confirmed tx -> Wasp: sc addr: %s, txid: %s", addrs[i].String(), tx.ID().String())
+	}
+}
+
+func (wconn *waspConnector) transactionBooked(tx *transaction.Transaction) {
+	wconn.sendTxInclusionLevelIfSubscribed(waspconn.TransactionInclusionLevelBooked, tx)
+}
+
+func (wconn *waspConnector) transactionRejected(tx *transaction.Transaction) {
+	wconn.sendTxInclusionLevelIfSubscribed(waspconn.TransactionInclusionLevelRejected, tx)
+}
+
+func (wconn *waspConnector) sendTxInclusionLevelIfSubscribed(level byte, tx *transaction.Transaction) {
+	addrs := wconn.txSubscribedAddresses(tx)
+	if len(addrs) == 0 {
+		return
+	}
+	txid := tx.ID()
+	if err := wconn.sendTxInclusionLevelToWasp(level, &txid, addrs); err != nil {
+		wconn.log.Errorf("sendTxInclusionLevelToWasp: %v", err)
+	}
+}
+
+func (wconn *waspConnector) getConfirmedTransaction(txid *transaction.ID) {
+	tx := wconn.ledger.getConfirmedTransaction(txid)
+	if tx == nil {
+		wconn.log.Warnf("getConfirmedTransaction: not found %s", txid.String())
+		return
+	}
+	if err := wconn.sendMsgToWasp(&waspconn.WaspFromNodeConfirmedTransactionMsg{Tx: tx}); err != nil {
+		wconn.log.Errorf("sendConfirmedTransactionToWasp: %v", err)
+	}
+}
+
+func (wconn *waspConnector) getTxInclusionLevel(txid *transaction.ID, addr *address.Address) {
+	level := wconn.ledger.getTxInclusionLevel(txid)
+	if level == waspconn.TransactionInclusionLevelUndef {
+		return
+	}
+	if err := wconn.sendTxInclusionLevelToWasp(level, txid, []address.Address{*addr}); err != nil {
+		wconn.log.Errorf("sendTxInclusionLevelToWasp: %v", err)
+	}
+}
+
+func (wconn *waspConnector) getAddressBalance(addr *address.Address) {
+	outputs := wconn.ledger.getConfirmedAddressOutputs(*addr)
+	if len(outputs) == 0 {
+		return
+	}
+	err := wconn.sendMsgToWasp(&waspconn.WaspFromNodeAddressOutputsMsg{
+		Address:  *addr,
+		Balances: waspconn.OutputsToBalances(outputs),
+	})
+	if err != nil {
+		wconn.log.Errorf("sendAddressOutputsToWasp: %v", err)
+	}
+}
+
+// pushBacklogToWasp sends the transactions which colored the tokens in the outputs of the address.
+// These are the requests to the chain which may still be unprocessed
+func (wconn *waspConnector) pushBacklogToWasp(addr *address.Address, scColor *balance.Color) {
+	outs := wconn.ledger.getConfirmedAddressOutputs(*addr)
+	if len(outs) == 0 {
+		return
+	}
+	balancesByTx := waspconn.OutputsToBalances(outs)
+	balancesByColor, _ := waspconn.OutputBalancesByColor(outs)
+
+	sentTxs := make([]string, 0)
+	for col, b := range balancesByColor {
+		if col == balance.ColorIOTA || col == balance.ColorNew {
+			continue
+		}
+		if col == *scColor && b == 1 {
+			// color of the scColor belongs to backlog only if more than 1 token
+			continue
+		}
+		txid := (transaction.ID)(col)
+		tx := wconn.ledger.getConfirmedTransaction(&txid)
+		if tx == nil {
+			wconn.log.Warnf("pushBacklogToWasp: can't find the origin tx for the color %s", txid.String())
+			continue
+		}
+		if err := wconn.sendAddressUpdateToWasp(addr, balancesByTx, tx); err != nil {
+			wconn.log.Errorf("pushBacklogToWasp: %v", err)
+			continue
+		}
+		sentTxs = append(sentTxs, txid.String())
+	}
+	wconn.log.Infof("pushed backlog to Wasp for addr %s. sent transactions: %+v", addr.String(), sentTxs)
+}
+
+func (wconn *waspConnector) sendAddressUpdateToWasp(addr *address.Address, balances map[transaction.ID][]*balance.Balance, tx *transaction.Transaction) error {
+	return wconn.sendMsgToWasp(&waspconn.WaspFromNodeAddressUpdateMsg{
+		Address:  *addr,
+		Balances: balances,
+		Tx:       tx,
+	})
+}
+
+func (wconn *waspConnector) sendTxInclusionLevelToWasp(level byte, txid *transaction.ID, addrs []address.Address) error {
+	return wconn.sendMsgToWasp(&waspconn.WaspFromNodeTransactionInclusionLevelMsg{
+		Level:               level,
+		TxId:                *txid,
+		SubscribedAddresses: addrs,
+	})
+}
+
+// sendMsgToWasp encodes the message and sends it, split into chunks if it is too long.
+// The chunks of one message must not be interleaved with other messages
+func (wconn *waspConnector) sendMsgToWasp(msg interface{ Write(io.Writer) error }) error {
+	data, err := waspconn.EncodeMsg(msg)
+	if err != nil {
+		return err
+	}
+	choppedData, chopped, err := wconn.messageChopper.ChopData(data, tangle.MaxMessageSize, waspconn.ChunkMessageHeaderSize)
+	if err != nil {
+		return err
+	}
+
+	wconn.writeMutex.Lock()
+	defer wconn.writeMutex.Unlock()
+
+	if !chopped {
+		_, err = wconn.bconn.Write(data)
+		return err
+	}
+	for _, piece := range choppedData {
+		dataToSend, err := waspconn.EncodeMsg(&waspconn.WaspMsgChunk{Data: piece})
+		if err != nil {
+			return err
+		}
+		if _, err = wconn.bconn.Write(dataToSend); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package mocknode
+
+// The UTXODB endpoints of the Goshimmer web API, so that the clients in utxodb mode
+// (testutil.NewGoshimmerUtxodbClient, wasp-cli with utxodb=true) work with the mock node.
+
+import (
+	"net/http"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
+	"github.com/iotaledger/goshimmer/dapps/waspconn/packages/apilib"
+	"github.com/labstack/echo/v4"
+	"github.com/mr-tron/base58"
+)
+
+func (m *MockNode) newWebAPI() *echo.Echo {
+	e := echo.New()
+	e.HideBanner = true
+	e.HidePort = true
+	e.GET("/utxodb/outputs/:address", m.handleGetAddressOutputs)
+	e.GET("/utxodb/confirmed/:txid", m.handleIsConfirmed)
+	e.POST("/utxodb/tx", m.handlePostTransaction)
+	e.GET("/utxodb/requestfunds/:address", m.handleRequestFunds)
+	return e
+}
+
+func (m *MockNode) handleGetAddressOutputs(c echo.Context) error {
+	addr, err := address.FromBase58(c.Param("address"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, &apilib.GetAccountOutputsResponse{Err: err.Error()})
+	}
+	out := make(map[string][]apilib.OutputBalance)
+	for outid, bals := range m.ledger.getConfirmedAddressOutputs(addr) {
+		outBals := make([]apilib.OutputBalance, len(bals))
+		for i, b := range bals {
+			outBals[i] = apilib.OutputBalance{
+				Value: b.Value,
+				Color: transaction.ID(b.Color).String(),
+			}
+		}
+		out[outid.String()] = outBals
+	}
+	return c.JSON(http.StatusOK, &apilib.GetAccountOutputsResponse{
+		Address: c.Param("address"),
+		Outputs: out,
+	})
+}
+
+func (m *MockNode) handleIsConfirmed(c echo.Context) error {
+	txid, err := transaction.IDFromBase58(c.Param("txid"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, &apilib.IsConfirmedResponse{Err: err.Error()})
+	}
+	return c.JSON(http.StatusOK, &apilib.IsConfirmedResponse{Confirmed: m.ledger.isConfirmed(&txid)})
+}
+
+func (m *MockNode) handlePostTransaction(c echo.Context) error {
+	var req apilib.PostTransactionRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, &apilib.PostTransactionResponse{Err: err.Error()})
+	}
+	txBytes, err := base58.Decode(req.Tx)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, &apilib.PostTransactionResponse{Err: err.Error()})
+	}
+	tx, _, err := transaction.FromBytes(txBytes)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, &apilib.PostTransactionResponse{Err: err.Error()})
+	}
+	if err = m.ledger.postTransaction(tx); err != nil {
+		m.log.Warnf("handlePostTransaction: txid %s err = %v", tx.ID().String(), err)
+		return c.JSON(http.StatusConflict, &apilib.PostTransactionResponse{Err: err.Error()})
+	}
+	return c.JSON(http.StatusOK, &apilib.PostTransactionResponse{})
+}
+
+func (m *MockNode) handleRequestFunds(c echo.Context) error {
+	addr, err := address.FromBase58(c.Param("address"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, &apilib.RequestFundsResponse{Err: err.Error()})
+	}
+	if err = m.ledger.requestFunds(addr); err != nil {
+		return c.JSON(http.StatusInternalServerError, &apilib.RequestFundsResponse{Err: err.Error()})
+	}
+	return c.JSON(http.StatusOK, &apilib.RequestFundsResponse{})
+}
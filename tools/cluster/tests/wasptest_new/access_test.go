@@ -0,0 +1,48 @@
+package wasptest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/vm/examples/inccounter"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccessNode(t *testing.T) {
+	setup(t, "test_cluster")
+
+	accessNode := 3
+	chain, err = clu.DeployChainWithAccessNodes("chain with access node", []int{0, 1, 2}, []int{accessNode}, 2)
+	check(err, t)
+
+	name := "inc"
+	contractID := deployInccounter42(t, name, 42)
+	t.Logf("-------------- deployed contract. Name: '%s' id: %s", name, contractID.String())
+
+	testOwner := wallet.WithIndex(1)
+	mySigScheme := testOwner.SigScheme()
+	myAddress := testOwner.Address()
+	err = requestFunds(clu, myAddress, "myAddress")
+	check(err, t)
+
+	myClient := chain.SCClient(contractID.Hname(), mySigScheme)
+
+	tx, err := myClient.PostRequest(inccounter.FuncIncCounter)
+	check(err, t)
+
+	err = chain.CommitteeMultiClient().WaitUntilAllRequestsProcessed(tx, 30*time.Second)
+	check(err, t)
+
+	// the access node syncs the state from the committee and serves the views
+	access := clu.WaspClient(accessNode)
+	err = access.WaitUntilAllRequestsProcessed(tx, 30*time.Second)
+	check(err, t)
+
+	ret, err := access.CallView(coretypes.NewContractID(chain.ChainID, contractID.Hname()), "getCounter", nil)
+	check(err, t)
+	counter, _, err := codec.DecodeInt64(ret.MustGet(inccounter.VarCounter))
+	check(err, t)
+	require.EqualValues(t, 43, counter)
+}
@@ -46,7 +46,7 @@ func TestDeployChain(t *testing.T) {
 		require.EqualValues(t, 1, blockIndex)
 		checkRoots(t, chain)
 		contractRegistry := collections.NewMapReadOnly(state, root.VarContractRegistry)
-		require.EqualValues(t, 4, contractRegistry.MustLen())
+		require.EqualValues(t, 5, contractRegistry.MustLen())
 		return true
 	})
 	checkRootsOutside(t, chain)
@@ -162,7 +162,7 @@ func TestDeployContractAndSpawn(t *testing.T) {
 		checkRoots(t, chain)
 
 		contractRegistry := collections.NewMapReadOnly(state, root.VarContractRegistry)
-		require.EqualValues(t, 5, contractRegistry.MustLen())
+		require.EqualValues(t, 6, contractRegistry.MustLen())
 		crBytes := contractRegistry.MustGetAt(hname.Bytes())
 		require.NotNil(t, crBytes)
 		cr, err := root.DecodeContractRecord(crBytes)
@@ -201,7 +201,7 @@ func TestDeployContractAndSpawn(t *testing.T) {
 		checkRoots(t, chain)
 
 		contractRegistry := collections.NewMapReadOnly(state, root.VarContractRegistry)
-		require.EqualValues(t, 6, contractRegistry.MustLen())
+		require.EqualValues(t, 7, contractRegistry.MustLen())
 		//--
 		crBytes := contractRegistry.MustGetAt(accounts.Interface.Hname().Bytes())
 		require.NotNil(t, crBytes)
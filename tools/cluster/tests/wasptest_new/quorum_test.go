@@ -0,0 +1,55 @@
+package wasptest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iotaledger/wasp/client/multiclient"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/vm/examples/inccounter"
+	"github.com/iotaledger/wasp/packages/webapi/model/statequery"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuorumCallView(t *testing.T) {
+	setup(t, "test_cluster")
+
+	chain, err = clu.DeployChain("chain for quorum reads", []int{0, 1, 2}, 2)
+	check(err, t)
+
+	name := "inc"
+	contractID := deployInccounter42(t, name, 42)
+	t.Logf("-------------- deployed contract. Name: '%s' id: %s", name, contractID.String())
+
+	tx, err := chain.OriginatorClient().PostRequest(contractID.Hname(), coretypes.Hn(inccounter.FuncIncCounter))
+	check(err, t)
+	err = chain.CommitteeMultiClient().WaitUntilAllRequestsProcessed(tx, 30*time.Second)
+	check(err, t)
+
+	// the node #3 does not run the chain
+	mc := clu.MultiClient()
+	require.EqualValues(t, 3, mc.Quorum)
+
+	ret, res, err := mc.CallView(coretypes.NewContractID(chain.ChainID, contractID.Hname()), "getCounter", nil)
+	check(err, t)
+	counter, _, err := codec.DecodeInt64(ret.MustGet(inccounter.VarCounter))
+	check(err, t)
+	require.EqualValues(t, 43, counter)
+	require.EqualValues(t, []int{0, 1, 2}, res.Agreed)
+	require.Empty(t, res.Disagreed)
+	require.Len(t, res.Failed, 1)
+	require.Contains(t, res.Failed, 3)
+
+	state, stateRes, err := mc.StateQuery(&chain.ChainID, &statequery.Request{})
+	check(err, t)
+	require.EqualValues(t, res.StateIndex, state.StateIndex)
+	require.EqualValues(t, res.StateHash, stateRes.StateHash)
+
+	mc.Quorum = 4
+	_, res, err = mc.CallView(coretypes.NewContractID(chain.ChainID, contractID.Hname()), "getCounter", nil)
+	require.Error(t, err)
+	_, ok := err.(*multiclient.QuorumError)
+	require.True(t, ok)
+	require.EqualValues(t, []int{0, 1, 2}, res.Agreed)
+}
@@ -40,6 +40,10 @@ func main() {
 		initFlags.IntVarP(&config.Wasp.FirstDashboardPort, "first-dashboard-port", "h", config.Wasp.FirstDashboardPort, "First wasp dashboard port")
 		initFlags.IntVarP(&config.Goshimmer.ApiPort, "goshimmer-api-port", "w", config.Goshimmer.ApiPort, "Goshimmer API port")
 		initFlags.BoolVarP(&config.Goshimmer.Provided, "goshimmer-provided", "g", config.Goshimmer.Provided, "If true, Goshimmer node will not be spawn")
+		initFlags.BoolVarP(&config.Goshimmer.Mock, "goshimmer-mock", "m", config.Goshimmer.Mock, "If true, an in-memory Level 1 ledger is run instead of the Goshimmer node")
+		initFlags.IntVar(&config.Goshimmer.MockConfirmDelayMs, "mock-confirm-delay", config.Goshimmer.MockConfirmDelayMs, "Milliseconds before the mock ledger confirms a transaction")
+		initFlags.BoolVar(&config.Goshimmer.MockConfirmRandomize, "mock-confirm-randomize", config.Goshimmer.MockConfirmRandomize, "Randomize the confirmation delay of the mock ledger")
+		initFlags.BoolVar(&config.Goshimmer.MockConfirmFirstInConflict, "mock-confirm-first-in-conflict", config.Goshimmer.MockConfirmFirstInConflict, "The mock ledger confirms the first of the conflicting transactions instead of rejecting all")
 
 		err := initFlags.Parse(os.Args[2:])
 		check(err)
@@ -0,0 +1,81 @@
+package chain
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/vm/contract"
+	"github.com/iotaledger/wasp/tools/wasp-cli/log"
+	"github.com/iotaledger/wasp/tools/wasp-cli/util"
+	"github.com/mr-tron/base58"
+	"github.com/spf13/pflag"
+)
+
+var rawParams bool
+
+func initABIFlags(flags *pflag.FlagSet) {
+	flags.BoolVarP(&rawParams, "raw", "", false, "don't use the ABI of the contract: params are given as <type> <key> <type> <value>, results are printed as JSON dict")
+}
+
+// getFunctionABI returns the ABI of the function of the contract, or nil if it is not known
+func getFunctionABI(contractHname coretypes.Hname, fname string) *contract.FunctionABI {
+	if rawParams {
+		return nil
+	}
+	abi, err := Client().GetContractABI(contractHname)
+	log.Check(err)
+	if abi == nil {
+		return nil
+	}
+	f, ok := abi.GetFunction(fname)
+	if !ok {
+		log.Fatal("function '%s' not found in the ABI of the contract", fname)
+	}
+	return f
+}
+
+// encodeParams encodes the params according to the ABI of the function as <name> <value> pairs.
+// Without the ABI the params are given as <type> <key> <type> <value>
+func encodeParams(f *contract.FunctionABI, params []string) dict.Dict {
+	if f == nil {
+		return util.EncodeParams(params)
+	}
+	if len(params)%2 != 0 {
+		log.Fatal("Params format: <name> <value> ...")
+	}
+	d := dict.New()
+	for i := 0; i < len(params)/2; i++ {
+		p, ok := f.GetParam(params[i*2])
+		if !ok {
+			log.Fatal("unknown param '%s' of function '%s'. Use --raw to pass params without the ABI", params[i*2], f.Name)
+		}
+		v, err := p.EncodeFromString(params[i*2+1])
+		log.Check(err)
+		d.Set(kv.Key(p.Name), v)
+	}
+	return d
+}
+
+// printResults prints the results decoded according to the ABI of the function.
+// Results unknown to the ABI are printed in base58
+func printResults(f *contract.FunctionABI, res dict.Dict) {
+	if f == nil {
+		util.PrintDictAsJson(res)
+		return
+	}
+	out := make(map[string]string)
+	for k, v := range res {
+		r, ok := f.GetResult(string(k))
+		if !ok {
+			out[string(k)] = base58.Encode(v)
+			continue
+		}
+		s, err := r.DecodeToString(v)
+		log.Check(err)
+		out[r.Name] = s
+	}
+	log.Check(json.NewEncoder(os.Stdout).Encode(out))
+}
@@ -4,15 +4,30 @@ import (
 	"os"
 
 	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/iotaledger/wasp/tools/wasp-cli/log"
-	"github.com/iotaledger/wasp/tools/wasp-cli/util"
+	"github.com/spf13/pflag"
 )
 
+var blockIndex int
+
+func initCallViewFlags(flags *pflag.FlagSet) {
+	flags.IntVarP(&blockIndex, "block", "", -1, "call the view on the past state after the given block index (default: latest)")
+}
+
 func callViewCmd(args []string) {
 	if len(args) < 2 {
 		log.Fatal("Usage: %s chain call-view <name> <funcname> [params]", os.Args[0])
 	}
-	r, err := SCClient(coretypes.Hn(args[0])).CallView(args[1], util.EncodeParams(args[2:]))
+	f := getFunctionABI(coretypes.Hn(args[0]), args[1])
+	params := encodeParams(f, args[2:])
+	var r dict.Dict
+	var err error
+	if blockIndex < 0 {
+		r, err = SCClient(coretypes.Hn(args[0])).CallView(args[1], params)
+	} else {
+		r, err = SCClient(coretypes.Hn(args[0])).CallViewAt(args[1], params, uint32(blockIndex))
+	}
 	log.Check(err)
-	util.PrintDictAsJson(r)
+	printResults(f, r)
 }
@@ -14,6 +14,10 @@ func InitCommands(commands map[string]func([]string), flags *pflag.FlagSet) {
 	fs := pflag.NewFlagSet("chain", pflag.ExitOnError)
 	initDeployFlags(fs)
 	initAliasFlags(fs)
+	initCallViewFlags(fs)
+	initPostRequestFlags(fs)
+	initABIFlags(fs)
+	initDeployContractFlags(fs)
 	flags.AddFlagSet(fs)
 }
 
@@ -31,6 +35,8 @@ var subcmds = map[string]func([]string){
 	"log":             logCmd,
 	"post-request":    postRequestCmd,
 	"call-view":       callViewCmd,
+	"snapshot":        snapshotCmd,
+	"rotate":          rotateCmd,
 }
 
 func chainCmd(args []string) {
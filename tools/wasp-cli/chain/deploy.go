@@ -11,11 +11,13 @@ import (
 )
 
 var committee []int
+var access []int
 var quorum int
 var description string
 
 func initDeployFlags(flags *pflag.FlagSet) {
 	flags.IntSliceVarP(&committee, "committee", "", []int{0, 1, 2, 3}, "committee indices")
+	flags.IntSliceVarP(&access, "access", "", nil, "access node indices")
 	flags.IntVarP(&quorum, "quorum", "", 3, "quorum")
 	flags.StringVarP(&description, "description", "", "", "description")
 }
@@ -33,6 +35,9 @@ func deployCmd(args []string) {
 		Description:           description,
 		Textout:               os.Stdout,
 		Prefix:                "",
+		AccessApiHosts:        config.CommitteeApi(access),
+		AccessPeeringHosts:    config.CommitteePeering(access),
+		AuthToken:             config.WaspToken(),
 	})
 	log.Check(err)
 
@@ -7,12 +7,20 @@ import (
 	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/kv/codec"
 	"github.com/iotaledger/wasp/packages/sctransaction"
+	"github.com/iotaledger/wasp/packages/vm/contract"
 	"github.com/iotaledger/wasp/packages/vm/core/blob"
 	"github.com/iotaledger/wasp/packages/vm/core/root"
 	"github.com/iotaledger/wasp/tools/wasp-cli/log"
 	"github.com/iotaledger/wasp/tools/wasp-cli/util"
+	"github.com/spf13/pflag"
 )
 
+var abiFile string
+
+func initDeployContractFlags(flags *pflag.FlagSet) {
+	flags.StringVarP(&abiFile, "abi", "", "", "JSON file with the ABI of the contract, stored in the blob together with the program")
+}
+
 func deployContractCmd(args []string) {
 	if len(args) != 4 {
 		log.Fatal("Usage: %s chain deploy-contract <vmtype> <name> <description> <filename>", os.Args[0])
@@ -28,6 +36,11 @@ func deployContractCmd(args []string) {
 		blob.VarFieldProgramDescription: description,
 		blob.VarFieldProgramBinary:      util.ReadFile(filename),
 	}
+	if abiFile != "" {
+		abi, err := contract.ABIFromBytes(util.ReadFile(abiFile))
+		log.Check(err)
+		blobFieldValues[blob.VarFieldProgramABI] = abi.Bytes()
+	}
 
 	util.WithSCTransaction(func() (*sctransaction.Transaction, error) {
 		return Client().PostRequest(
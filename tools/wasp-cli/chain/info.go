@@ -15,6 +15,7 @@ func infoCmd(args []string) {
 
 	log.Printf("Chain ID: %s\n", chain.ChainID)
 	log.Printf("Committee nodes: %+v\n", chain.CommitteeNodes)
+	log.Printf("Access nodes: %+v\n", chain.AccessNodes)
 	log.Printf("Active: %v\n", chain.Active)
 
 	if chain.Active {
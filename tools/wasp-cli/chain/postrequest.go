@@ -8,17 +8,31 @@ import (
 	"github.com/iotaledger/wasp/packages/sctransaction"
 	"github.com/iotaledger/wasp/tools/wasp-cli/log"
 	"github.com/iotaledger/wasp/tools/wasp-cli/util"
+	"github.com/spf13/pflag"
 )
 
+var (
+	sealed    bool
+	offTangle bool
+)
+
+func initPostRequestFlags(flags *pflag.FlagSet) {
+	flags.BoolVarP(&sealed, "sealed", "", false, "encrypt the request params so that only the committee nodes can read them")
+	flags.BoolVarP(&offTangle, "off-tangle", "", false, "upload the request params to the node and only post their hash to the Tangle")
+}
+
 func postRequestCmd(args []string) {
 	if len(args) < 2 {
 		log.Fatal("Usage: %s chain post-request <name> <funcname> [params]", os.Args[0])
 	}
+	params := encodeParams(getFunctionABI(coretypes.Hn(args[0]), args[1]), args[2:])
 	util.WithSCTransaction(func() (*sctransaction.Transaction, error) {
 		return SCClient(coretypes.Hn(args[0])).PostRequest(
 			args[1],
 			chainclient.PostRequestParams{
-				Args: util.EncodeParams(args[2:]),
+				Args:      params,
+				Sealed:    sealed,
+				OffTangle: offTangle,
 			},
 		)
 	})
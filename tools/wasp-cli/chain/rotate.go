@@ -0,0 +1,29 @@
+package chain
+
+import (
+	"os"
+
+	"github.com/iotaledger/wasp/packages/apilib"
+	"github.com/iotaledger/wasp/tools/wasp-cli/config"
+	"github.com/iotaledger/wasp/tools/wasp-cli/log"
+	"github.com/iotaledger/wasp/tools/wasp-cli/wallet"
+)
+
+func rotateCmd(args []string) {
+	chainID := GetCurrentChainID()
+
+	newAddr, err := apilib.RotateCommittee(apilib.RotateCommitteeParams{
+		Node:                  config.GoshimmerClient(),
+		ChainID:               chainID,
+		ApiHost:               config.WaspApi(),
+		CommitteeApiHosts:     config.CommitteeApi(committee),
+		CommitteePeeringHosts: config.CommitteePeering(committee),
+		N:                     uint16(len(committee)),
+		T:                     uint16(quorum),
+		OwnerSigScheme:        wallet.Load().SignatureScheme(),
+		Textout:               os.Stdout,
+		AuthToken:             config.WaspToken(),
+	})
+	log.Check(err)
+	log.Printf("Chain %s moved to the new committee. Chain address: %s\n", chainID, newAddr)
+}
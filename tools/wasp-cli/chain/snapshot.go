@@ -0,0 +1,49 @@
+package chain
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/iotaledger/wasp/packages/state"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/tools/wasp-cli/config"
+	"github.com/iotaledger/wasp/tools/wasp-cli/log"
+	"github.com/iotaledger/wasp/tools/wasp-cli/util"
+)
+
+func snapshotCmd(args []string) {
+	if len(args) != 2 {
+		log.Fatal("Usage: %s chain snapshot [export|import] <filename>", os.Args[0])
+	}
+	switch args[0] {
+	case "export":
+		snapshotExport(args[1])
+	case "import":
+		snapshotImport(args[1])
+	default:
+		log.Fatal("Usage: %s chain snapshot [export|import] <filename>", os.Args[0])
+	}
+}
+
+func snapshotExport(fname string) {
+	chainID := GetCurrentChainID()
+	res, err := config.WaspClient().GetStateSnapshot(&chainID)
+	log.Check(err)
+	log.Check(ioutil.WriteFile(fname, res.Data, 0644))
+	log.Printf("Snapshot of chain %s at block #%d saved to %s\n", chainID, res.BlockIndex, fname)
+}
+
+func snapshotImport(fname string) {
+	data := util.ReadFile(fname)
+	snapshot, err := state.NewSnapshotFromBytes(data)
+	log.Check(err)
+	log.Check(snapshot.Verify())
+
+	log.Check(config.WaspClient().PutStateSnapshot(&snapshot.ChainID, &model.StateSnapshot{
+		ChainID:    model.NewChainID(&snapshot.ChainID),
+		BlockIndex: snapshot.BlockIndex,
+		StateTxID:  model.NewValueTxID(&snapshot.StateTxID),
+		Data:       data,
+	}))
+	log.Printf("Snapshot of chain %s at block #%d imported\n", snapshot.ChainID, snapshot.BlockIndex)
+}
@@ -76,7 +76,12 @@ func GoshimmerClient() level1.Level1Client {
 }
 
 func WaspClient() *client.WaspClient {
-	return client.NewWaspClient(WaspApi())
+	return client.NewWaspClient(WaspApi()).WithToken(WaspToken())
+}
+
+// WaspToken is the API key or JWT for the admin endpoints of the Wasp nodes (wasp.token)
+func WaspToken() string {
+	return viper.GetString("wasp.token")
 }
 
 func WaspApi() string {
@@ -9,6 +9,7 @@ import (
 	"github.com/iotaledger/wasp/tools/wasp-cli/config"
 	"github.com/iotaledger/wasp/tools/wasp-cli/decode"
 	"github.com/iotaledger/wasp/tools/wasp-cli/log"
+	"github.com/iotaledger/wasp/tools/wasp-cli/peering"
 	"github.com/iotaledger/wasp/tools/wasp-cli/wallet"
 	"github.com/spf13/pflag"
 )
@@ -33,6 +34,7 @@ func main() {
 	wallet.InitCommands(commands, flags)
 	chain.InitCommands(commands, flags)
 	decode.InitCommands(commands, flags)
+	peering.InitCommands(commands, flags)
 
 	log.Check(flags.Parse(os.Args[1:]))
 
@@ -0,0 +1,40 @@
+package peering
+
+import (
+	"os"
+	"strings"
+
+	"github.com/iotaledger/wasp/tools/wasp-cli/log"
+	"github.com/spf13/pflag"
+)
+
+func InitCommands(commands map[string]func([]string), flags *pflag.FlagSet) {
+	commands["peering"] = peeringCmd
+}
+
+var subcmds = map[string]func([]string){
+	"info":         infoCmd,
+	"list-trusted": listTrustedCmd,
+	"trust":        trustCmd,
+	"distrust":     distrustCmd,
+}
+
+func peeringCmd(args []string) {
+	if len(args) < 1 {
+		usage()
+	}
+	subcmd, ok := subcmds[args[0]]
+	if !ok {
+		usage()
+	}
+	subcmd(args[1:])
+}
+
+func usage() {
+	cmdNames := make([]string, 0)
+	for k := range subcmds {
+		cmdNames = append(cmdNames, k)
+	}
+
+	log.Usage("%s peering [%s]\n", os.Args[0], strings.Join(cmdNames, "|"))
+}
@@ -0,0 +1,51 @@
+package peering
+
+import (
+	"os"
+
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/tools/wasp-cli/config"
+	"github.com/iotaledger/wasp/tools/wasp-cli/log"
+)
+
+func infoCmd(args []string) {
+	self, err := config.WaspClient().PeeringSelfGet()
+	log.Check(err)
+	log.Printf("PubKey: %s\n", self.PubKey)
+	log.Printf("NetID:  %s\n", self.NetID)
+}
+
+func listTrustedCmd(args []string) {
+	client := config.WaspClient()
+	trusted, err := client.PeeringTrustedList()
+	log.Check(err)
+	log.Printf("Total %d trusted peers in wasp node %s\n", len(trusted), client.BaseURL())
+	showTrustedList(trusted)
+}
+
+func trustCmd(args []string) {
+	if len(args) != 2 {
+		log.Usage("%s peering trust <pubKey> <netID>\n", os.Args[0])
+	}
+	trusted, err := config.WaspClient().PeeringTrustedPut(args[0], args[1])
+	log.Check(err)
+	log.Printf("Trusted peer %s with pubKey %s\n", trusted.NetID, trusted.PubKey)
+}
+
+func distrustCmd(args []string) {
+	if len(args) != 1 {
+		log.Usage("%s peering distrust <pubKey>\n", os.Args[0])
+	}
+	distrusted, err := config.WaspClient().PeeringTrustedDelete(args[0])
+	log.Check(err)
+	log.Printf("Distrusted peer %s with pubKey %s\n", distrusted.NetID, distrusted.PubKey)
+}
+
+func showTrustedList(trusted []*model.PeeringTrustedNode) {
+	header := []string{"pubKey", "netID"}
+	rows := make([][]string, len(trusted))
+	for i, t := range trusted {
+		rows[i] = []string{t.PubKey, t.NetID}
+	}
+	log.PrintTable(header, rows)
+}
@@ -20,7 +20,7 @@ func deactivateCmd(args []string) {
 	log.Check(err)
 	committee := parseIntList(args[1])
 
-	log.Check(multiclient.New(config.CommitteeApi(committee)).DeactivateChain(&scAddress))
+	log.Check(multiclient.New(config.CommitteeApi(committee)).WithToken(config.WaspToken()).DeactivateChain(&scAddress))
 }
 
 func deactivateUsage() {